@@ -0,0 +1,336 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/oscal-compass/compliance-to-policy-go/v2/framework/actions"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/settings"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/complytime/manual"
+)
+
+// attestOptions defines options for the "attest" subcommand
+type attestOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+
+	// all prompts for the manual checks with a current attestation too
+	all bool
+	// attestedBy is the person answering the manual checks, defaulting to the current user
+	attestedBy string
+	// validDays is the number of days the answers are valid for
+	validDays int
+}
+
+var attestExample = `
+# Answer the manual checks of the assessment plan that have no attestation, or an expired one.
+complyctl attest
+
+# Answer all manual checks again, valid for 90 days.
+complyctl attest --all --valid-days 90 --attested-by "Jane Doe"
+`
+
+// manualCheck is a check of the manual validation component of the assessment plan.
+type manualCheck struct {
+	rule  extensions.Rule
+	check extensions.Check
+}
+
+// attestCmd creates a new cobra.Command for the "attest" subcommand
+func attestCmd(common *option.Common) *cobra.Command {
+	attestOpts := &attestOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:   "attest [flags]",
+		Short: "Interactively answer the manual checks of the assessment plan.",
+		Long: fmt.Sprintf(`Interactively answer the manual checks of the assessment plan.
+
+Manual checks are the checks of the %q validation component, for controls that cannot be assessed
+automatically. The answers are recorded as attestations in %s in the workspace, and reported by the
+manual plugin when scanning. Checks without an attestation, or with an expired one, are prompted for.
+Press enter to keep the value in brackets, or enter - to clear it.`,
+			manual.PluginID, manual.AttestationsLocation),
+		Example:      attestExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runAttest(cmd, attestOpts)
+		},
+	}
+	cmd.Flags().BoolVar(&attestOpts.all, "all", false, "prompt for the manual checks with a current attestation too")
+	cmd.Flags().StringVar(&attestOpts.attestedBy, "attested-by", "", "person answering the manual checks, defaults to the current user")
+	cmd.Flags().IntVar(&attestOpts.validDays, "valid-days", manual.DefaultValidDays, "number of days the answers are valid for")
+	attestOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runAttest(cmd *cobra.Command, opts *attestOptions) error {
+	if opts.validDays <= 0 {
+		return errors.New("invalid command flags: \"--valid-days\" must be positive")
+	}
+	ap, _, err := loadPlan(opts.complyTimeOpts, validation.NewSchemaValidator())
+	if err != nil {
+		return err
+	}
+	inputContext, err := complytime.ActionsContextFromPlan(ap)
+	if err != nil {
+		return err
+	}
+	manualPolicy, err := planManualPolicy(cmd.Context(), inputContext)
+	if err != nil {
+		return err
+	}
+
+	attestationsPath := filepath.Join(opts.complyTimeOpts.UserWorkspace, manual.AttestationsLocation)
+	attestations, err := manual.ReadAttestations(attestationsPath)
+	if err != nil {
+		return err
+	}
+	attestedBy := opts.attestedBy
+	if attestedBy == "" {
+		if current, err := user.Current(); err == nil {
+			attestedBy = current.Username
+		}
+	}
+
+	now := time.Now()
+	checks := pendingManualChecks(manualPolicy, attestations, opts.all, now)
+	total := len(uniqueManualChecks(manualPolicy))
+	if len(checks) == 0 {
+		_, _ = fmt.Fprintf(opts.Out, "All %d manual checks have a current attestation in %s\n", total, attestationsPath)
+		return nil
+	}
+	_, _ = fmt.Fprintf(opts.Out, "%d of %d manual checks need an answer. Answers are recorded in %s\n", len(checks), total, attestationsPath)
+
+	prompter := &attestPrompter{in: bufio.NewScanner(cmd.InOrStdin()), out: opts.Out}
+	expires := now.AddDate(0, 0, opts.validDays).Format(time.DateOnly)
+	answered, err := promptAttestations(prompter, checks, attestations, opts.complyTimeOpts.UserWorkspace, attestedBy, expires, now, func() error {
+		if err := attestations.Write(attestationsPath); err != nil {
+			return fmt.Errorf("error writing attestations to %s: %w", attestationsPath, err)
+		}
+		return nil
+	})
+	_, _ = fmt.Fprintf(opts.Out, "\nRecorded %d attestations expiring on %s in %s\n", answered, expires, attestationsPath)
+	return err
+}
+
+// planManualPolicy returns the rules of the manual validation component of the assessment plan,
+// with the parameter values of the plan.
+func planManualPolicy(ctx context.Context, inputContext *actions.InputContext) (policy.Policy, error) {
+	title, err := inputContext.ProviderTitle(plugin.ID(manual.PluginID))
+	if errors.Is(err, actions.ErrMissingProvider) {
+		return nil, fmt.Errorf("the assessment plan has no %q validation component with manual checks", manual.PluginID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ruleSets, err := settings.ApplyToComponent(ctx, title, inputContext.Store(), inputContext.Settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the manual checks of the assessment plan: %w", err)
+	}
+	return ruleSets, nil
+}
+
+// uniqueManualChecks returns the checks of the policy, each once.
+func uniqueManualChecks(manualPolicy policy.Policy) []manualCheck {
+	var checks []manualCheck
+	seen := make(map[string]bool)
+	for _, ruleSet := range manualPolicy {
+		for _, check := range ruleSet.Checks {
+			if !seen[check.ID] {
+				seen[check.ID] = true
+				checks = append(checks, manualCheck{rule: ruleSet.Rule, check: check})
+			}
+		}
+	}
+	return checks
+}
+
+// pendingManualChecks returns the checks of the policy without an attestation, or with an expired
+// attestation at the given time. All checks are returned when all is set.
+func pendingManualChecks(manualPolicy policy.Policy, attestations *manual.Attestations, all bool, now time.Time) []manualCheck {
+	var pending []manualCheck
+	for _, check := range uniqueManualChecks(manualPolicy) {
+		attestation, found := attestations.Find(check.check.ID)
+		if all || !found || attestation.Expired(now) {
+			pending = append(pending, check)
+		}
+	}
+	return pending
+}
+
+// promptAttestations prompts for the answers of the checks and records them in the attestations,
+// saved after each answer. Skipped checks are left unchanged. Evidence files are relative to the
+// workspace. The number of recorded answers is returned, with an error when the input ends before
+// all checks are prompted for.
+func promptAttestations(prompter *attestPrompter, checks []manualCheck, attestations *manual.Attestations, workspace, attestedBy, expires string, now time.Time, save func() error) (int, error) {
+	answered := 0
+	for i, check := range checks {
+		_, _ = fmt.Fprintf(prompter.out, "\n[%d/%d] Rule %s", i+1, len(checks), check.rule.ID)
+		if check.rule.Description != "" {
+			_, _ = fmt.Fprintf(prompter.out, ": %s", check.rule.Description)
+		}
+		_, _ = fmt.Fprintf(prompter.out, "\nCheck %s", check.check.ID)
+		if check.check.Description != "" {
+			_, _ = fmt.Fprintf(prompter.out, ": %s", check.check.Description)
+		}
+		_, _ = fmt.Fprintln(prompter.out)
+
+		previous, found := attestations.Find(check.check.ID)
+		defaults := manual.Attestation{AttestedBy: attestedBy}
+		if found {
+			_, _ = fmt.Fprintf(prompter.out, "Current answer: %s by %s on %s, expiring on %s\n",
+				previous.Answer, previous.AttestedBy, previous.AttestedAt.Format(time.DateOnly), previous.Expires)
+			defaults = previous
+			if attestedBy != "" {
+				defaults.AttestedBy = attestedBy
+			}
+		}
+
+		answer, err := prompter.ask(fmt.Sprintf("Answer (%s, %s or skip)", manual.AnswerPass, manual.AnswerFail), defaults.Answer, func(value string) error {
+			if value != manual.AnswerPass && value != manual.AnswerFail && value != "skip" {
+				return fmt.Errorf("expected %s, %s or skip", manual.AnswerPass, manual.AnswerFail)
+			}
+			return nil
+		})
+		if err != nil {
+			return answered, err
+		}
+		if answer == "skip" {
+			continue
+		}
+		attester, err := prompter.ask("Attested by", defaults.AttestedBy, func(value string) error {
+			if value == "" {
+				return errors.New("the person answering the check is required")
+			}
+			return nil
+		})
+		if err != nil {
+			return answered, err
+		}
+		comment, err := prompter.ask("Comment", defaults.Comment, nil)
+		if err != nil {
+			return answered, err
+		}
+		evidenceList, err := prompter.ask("Evidence files or URLs, separated by commas", strings.Join(defaults.Evidence, ", "), func(value string) error {
+			_, err := parseEvidence(value, workspace)
+			return err
+		})
+		if err != nil {
+			return answered, err
+		}
+		evidence, _ := parseEvidence(evidenceList, workspace)
+
+		attestation := manual.Attestation{
+			Check:      check.check.ID,
+			Rule:       check.rule.ID,
+			Answer:     answer,
+			AttestedBy: attester,
+			AttestedAt: now.UTC().Truncate(time.Second),
+			Expires:    expires,
+			Comment:    comment,
+			Evidence:   evidence,
+		}
+		if err := attestations.Set(attestation); err != nil {
+			return answered, err
+		}
+		if err := save(); err != nil {
+			return answered, err
+		}
+		answered++
+	}
+	return answered, nil
+}
+
+// parseEvidence returns the evidence of a comma separated list of files and URLs. Files must exist,
+// and relative paths are resolved from the workspace like in manual.Attestation.EvidenceLinks. Files
+// in the workspace are returned relative to it, and other files as absolute paths.
+func parseEvidence(value, workspace string) ([]string, error) {
+	workspace, err := filepath.Abs(workspace)
+	if err != nil {
+		return nil, err
+	}
+	var evidence []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if strings.Contains(item, "://") {
+			evidence = append(evidence, item)
+			continue
+		}
+		path := filepath.Clean(item)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workspace, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("evidence file %s: %w", item, err)
+		}
+		if rel, err := filepath.Rel(workspace, path); err == nil && filepath.IsLocal(rel) {
+			path = rel
+		}
+		evidence = append(evidence, path)
+	}
+	return evidence, nil
+}
+
+// attestPrompter asks questions on the output and reads the answers from the input, one per line.
+type attestPrompter struct {
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+// ask prompts for a value until it is valid. The default value is returned for an empty answer,
+// and an empty value for "-".
+func (p *attestPrompter) ask(question, defaultValue string, validate func(string) error) (string, error) {
+	for {
+		if defaultValue != "" {
+			_, _ = fmt.Fprintf(p.out, "%s [%s]: ", question, defaultValue)
+		} else {
+			_, _ = fmt.Fprintf(p.out, "%s: ", question)
+		}
+		if !p.in.Scan() {
+			if err := p.in.Err(); err != nil {
+				return "", err
+			}
+			_, _ = fmt.Fprintln(p.out)
+			return "", errors.New("input ended before all manual checks were answered")
+		}
+		value := strings.TrimSpace(p.in.Text())
+		switch value {
+		case "":
+			value = defaultValue
+		case "-":
+			value = ""
+		}
+		if validate == nil {
+			return value, nil
+		}
+		if err := validate(value); err != nil {
+			_, _ = fmt.Fprintf(p.out, "Invalid answer: %v\n", err)
+			continue
+		}
+		return value, nil
+	}
+}
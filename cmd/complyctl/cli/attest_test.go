@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime/manual"
+)
+
+var manualTestPolicy = policy.Policy{
+	{
+		Rule:   extensions.Rule{ID: "security_awareness_training", Description: "Staff complete security training"},
+		Checks: []extensions.Check{{ID: "training_completed", Description: "Training records exist for all staff"}},
+	},
+	{
+		Rule:   extensions.Rule{ID: "physical_access"},
+		Checks: []extensions.Check{{ID: "visitor_log_reviewed"}, {ID: "badge_audit"}},
+	},
+	{
+		Rule:   extensions.Rule{ID: "physical_access_review"},
+		Checks: []extensions.Check{{ID: "visitor_log_reviewed"}},
+	},
+}
+
+func TestPendingManualChecks(t *testing.T) {
+	now := time.Now()
+	attestations := &manual.Attestations{Attestations: []manual.Attestation{
+		{Check: "training_completed", Answer: manual.AnswerPass, AttestedBy: "Jane Doe", AttestedAt: now, Expires: now.AddDate(0, 0, 1).Format(time.DateOnly)},
+		{Check: "badge_audit", Answer: manual.AnswerPass, AttestedBy: "Jane Doe", AttestedAt: now, Expires: now.Format(time.DateOnly)},
+	}}
+
+	checkIDs := func(checks []manualCheck) []string {
+		var ids []string
+		for _, check := range checks {
+			ids = append(ids, check.check.ID)
+		}
+		return ids
+	}
+	require.Equal(t, []string{"visitor_log_reviewed", "badge_audit"}, checkIDs(pendingManualChecks(manualTestPolicy, attestations, false, now)))
+	require.Equal(t, []string{"training_completed", "visitor_log_reviewed", "badge_audit"}, checkIDs(pendingManualChecks(manualTestPolicy, attestations, true, now)))
+}
+
+func TestPromptAttestations(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(workspace, "evidence"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "evidence", "training.pdf"), nil, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "roster.pdf"), nil, 0600))
+	evidencePath := filepath.Join(t.TempDir(), "policy.pdf")
+	require.NoError(t, os.WriteFile(evidencePath, nil, 0600))
+	now := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	attestations := &manual.Attestations{Attestations: []manual.Attestation{
+		{Check: "badge_audit", Answer: manual.AnswerPass, AttestedBy: "John Doe", AttestedAt: now.AddDate(-1, 0, 0), Expires: "2026-01-01", Comment: "Audited"},
+	}}
+
+	input := strings.Join([]string{
+		// training_completed: an invalid answer, then an invalid evidence file, then files in the
+		// workspace given by relative and absolute paths, a file outside of it and a URL
+		"yes", "pass", "", "All staff trained", "missing.pdf",
+		"evidence/training.pdf, " + filepath.Join(workspace, "roster.pdf") + ", " + evidencePath + ", https://example.com/report",
+		// visitor_log_reviewed
+		"skip",
+		// badge_audit keeps the previous answer and clears the comment
+		"", "", "-", "",
+	}, "\n") + "\n"
+	var out bytes.Buffer
+	saves := 0
+	prompter := &attestPrompter{in: bufio.NewScanner(strings.NewReader(input)), out: &out}
+	answered, err := promptAttestations(prompter, uniqueManualChecks(manualTestPolicy), attestations, workspace, "Jane Doe", "2027-10-19", now,
+		func() error { saves++; return nil })
+	require.NoError(t, err)
+	require.Equal(t, 2, answered)
+	require.Equal(t, 2, saves)
+
+	output := out.String()
+	require.Contains(t, output, "[1/3] Rule security_awareness_training: Staff complete security training\nCheck training_completed: Training records exist for all staff\n")
+	require.Contains(t, output, "Invalid answer: expected pass, fail or skip")
+	require.Contains(t, output, "Invalid answer: evidence file missing.pdf")
+	require.Contains(t, output, "Current answer: pass by John Doe on 2025-10-19, expiring on 2026-01-01")
+	require.Contains(t, output, "Attested by [Jane Doe]: ")
+
+	require.Len(t, attestations.Attestations, 2)
+	training, found := attestations.Find("training_completed")
+	require.True(t, found)
+	require.Equal(t, manual.Attestation{
+		Check:      "training_completed",
+		Rule:       "security_awareness_training",
+		Answer:     manual.AnswerPass,
+		AttestedBy: "Jane Doe",
+		AttestedAt: now,
+		Expires:    "2027-10-19",
+		Comment:    "All staff trained",
+		Evidence:   []string{"evidence/training.pdf", "roster.pdf", evidencePath, "https://example.com/report"},
+	}, training)
+	badgeAudit, _ := attestations.Find("badge_audit")
+	require.Equal(t, manual.AnswerPass, badgeAudit.Answer)
+	require.Equal(t, "Jane Doe", badgeAudit.AttestedBy)
+	require.Equal(t, "2027-10-19", badgeAudit.Expires)
+	require.Empty(t, badgeAudit.Comment)
+
+	prompter = &attestPrompter{in: bufio.NewScanner(strings.NewReader("fail\n")), out: &out}
+	answered, err = promptAttestations(prompter, uniqueManualChecks(manualTestPolicy), attestations, workspace, "Jane Doe", "2027-10-19", now,
+		func() error { return nil })
+	require.EqualError(t, err, "input ended before all manual checks were answered")
+	require.Equal(t, 0, answered)
+}
+
+func TestAttestCmd(t *testing.T) {
+	common := &option.Common{Output: option.Output{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}}}
+	cmd := attestCmd(common)
+	cmd.SetArgs([]string{"--valid-days", "0"})
+	require.EqualError(t, cmd.Execute(), `invalid command flags: "--valid-days" must be positive`)
+
+	cmd = attestCmd(common)
+	cmd.SetArgs([]string{"--workspace", "doesnotexist"})
+	require.ErrorContains(t, cmd.Execute(), "Did you run the plan command?")
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+)
+
+// configCmd creates a new cobra.Command for the "config" subcommand
+func configCmd(common *option.Common) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config [command]",
+		Short: "Manage the named contexts of the complyctl configuration file.",
+		Args:  cobra.NoArgs,
+		// The settings of contexts are not applied to the commands managing them,
+		// so a context that cannot be applied can still be fixed.
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			if err := option.ApplyEnvironment(cmd.Flags(), os.LookupEnv); err != nil {
+				return err
+			}
+			if err := setupLogging(cmd, common); err != nil {
+				return err
+			}
+			enableDebug(common)
+			return nil
+		},
+	}
+	cmd.AddCommand(
+		configGetCmd(common),
+		configSetCmd(common),
+		configUseContextCmd(common),
+	)
+	return cmd
+}
+
+// settableFlags returns the flags of all commands that can be set in a context, indexed by name.
+func settableFlags(root *cobra.Command) map[string]*pflag.Flag {
+	flags := make(map[string]*pflag.Flag)
+	addFlag := func(flag *pflag.Flag) {
+		if flag.Name == "help" || flag.Name == option.ContextFlag {
+			return
+		}
+		if _, found := flags[flag.Name]; !found {
+			flags[flag.Name] = flag
+		}
+	}
+	var visit func(cmd *cobra.Command)
+	visit = func(cmd *cobra.Command) {
+		cmd.PersistentFlags().VisitAll(addFlag)
+		cmd.LocalFlags().VisitAll(addFlag)
+		for _, child := range cmd.Commands() {
+			visit(child)
+		}
+	}
+	visit(root)
+	return flags
+}
+
+// settableFlagNames returns the names of the flags that can be set in a context in order.
+func settableFlagNames(flags map[string]*pflag.Flag) []string {
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
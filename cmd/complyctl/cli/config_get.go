@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/config"
+)
+
+// configGetOptions defines options for the "config get" subcommand
+type configGetOptions struct {
+	*option.Common
+
+	// key is the setting to print, all settings of the context are printed when empty
+	key string
+}
+
+// configGetCmd creates a new cobra.Command for the "config get" subcommand
+func configGetCmd(common *option.Common) *cobra.Command {
+	getOpts := &configGetOptions{
+		Common: common,
+	}
+	cmd := &cobra.Command{
+		Use:   "get [flags] [key]",
+		Short: "Print a setting, or all settings, of the current context or of the context given with --context.",
+		Example: "  complyctl config get\n" +
+			"  complyctl config get --context prod workspace",
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		PreRun: func(_ *cobra.Command, args []string) {
+			if len(args) == 1 {
+				getOpts.key = args[0]
+			}
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runConfigGet(getOpts)
+		},
+	}
+	return cmd
+}
+
+func runConfigGet(opts *configGetOptions) error {
+	configPath := config.DefaultPath()
+	cfg, err := config.Read(configPath)
+	if err != nil {
+		return err
+	}
+	name, context, err := cfg.Context(opts.Context)
+	if err != nil {
+		return fmt.Errorf("%w in %s", err, configPath)
+	}
+	if context == nil {
+		return fmt.Errorf("no current context in %s, select a context with --context or \"complyctl config use-context\"", configPath)
+	}
+
+	if opts.key == "" {
+		_, _ = fmt.Fprintf(opts.Out, "# Context %s of %s\n", name, configPath)
+		if len(context) == 0 {
+			return nil
+		}
+		data, err := yaml.Marshal(context)
+		if err != nil {
+			return err
+		}
+		_, _ = opts.Out.Write(data)
+		return nil
+	}
+
+	value, found, err := context.Get(opts.key)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%s is not set in context %s", opts.key, name)
+	}
+	_, _ = fmt.Fprintln(opts.Out, strings.Join(config.Values(value), ","))
+	return nil
+}
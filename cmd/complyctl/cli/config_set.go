@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/config"
+)
+
+// configSetOptions defines options for the "config set" subcommand
+type configSetOptions struct {
+	*option.Common
+
+	// key is the flag name, or plugins.<plugin-id>.<option>, of the setting
+	key string
+	// value is the value of the setting
+	value string
+	// unset removes the setting instead
+	unset bool
+}
+
+// configSetCmd creates a new cobra.Command for the "config set" subcommand
+func configSetCmd(common *option.Common) *cobra.Command {
+	setOpts := &configSetOptions{
+		Common: common,
+	}
+	cmd := &cobra.Command{
+		Use:   "set [flags] key [value]",
+		Short: "Set a setting of the current context, or of the context given with --context, which is created if it does not exist.",
+		Example: "  complyctl config set --context prod workspace /srv/complytime/prod\n" +
+			"  complyctl config set content-dir /srv/content,/srv/extra-content\n" +
+			"  complyctl config set plugins.openscap.datastream /usr/share/xml/scap/ssg/content/ssg-rhel9-ds.xml\n" +
+			"  complyctl config set --unset with-md",
+		SilenceUsage: true,
+		Args:         cobra.RangeArgs(1, 2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return settableFlagNames(settableFlags(cmd.Root())), cobra.ShellCompDirectiveNoFileComp
+		},
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			setOpts.key = args[0]
+			if len(args) == 2 {
+				setOpts.value = args[1]
+			}
+			if setOpts.unset && len(args) == 2 {
+				return errors.New("invalid command flags: \"--unset\" takes no value")
+			}
+			if !setOpts.unset && len(args) != 2 {
+				return errors.New("invalid command flags: a value is required unless \"--unset\" is given")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runConfigSet(setOpts, settableFlags(cmd.Root()))
+		},
+	}
+	cmd.Flags().BoolVar(&setOpts.unset, "unset", false, "remove the setting from the context")
+	return cmd
+}
+
+func runConfigSet(opts *configSetOptions, flags map[string]*pflag.Flag) error {
+	configPath := config.DefaultPath()
+	cfg, err := config.Read(configPath)
+	if err != nil {
+		return err
+	}
+	name := opts.Context
+	if name == "" {
+		name = cfg.CurrentContext
+	}
+	if name == "" {
+		return errors.New("no current context, select a context with --context or \"complyctl config use-context\"")
+	}
+
+	if opts.unset {
+		found, err := cfg.Unset(name, opts.key)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("%s is not set in context %s", opts.key, name)
+		}
+	} else {
+		value, err := settingValue(flags, opts.key, opts.value)
+		if err != nil {
+			return err
+		}
+		if err := cfg.Set(name, opts.key, value); err != nil {
+			return err
+		}
+	}
+	// The first context becomes the current context
+	if cfg.CurrentContext == "" {
+		cfg.CurrentContext = name
+	}
+	if err := cfg.Write(configPath); err != nil {
+		return fmt.Errorf("error writing configuration to %s: %w", configPath, err)
+	}
+	if opts.unset {
+		_, _ = fmt.Fprintf(opts.Out, "Unset %s in context %s\n", opts.key, name)
+	} else {
+		_, _ = fmt.Fprintf(opts.Out, "Set %s in context %s\n", opts.key, name)
+	}
+	return nil
+}
+
+// settingValue returns the value of a setting typed as the value of its flag, so invalid values
+// are reported when they are set. Values of repeatable flags are separated by commas.
+func settingValue(flags map[string]*pflag.Flag, key, value string) (any, error) {
+	if strings.HasPrefix(key, config.PluginsKey+".") {
+		return value, nil
+	}
+	flag, found := flags[key]
+	if !found {
+		return nil, fmt.Errorf("unknown setting %q, expected a flag name or %s.<plugin-id>.<option>", key, config.PluginsKey)
+	}
+	switch flag.Value.Type() {
+	case "bool":
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for %s: expected true or false", value, key)
+		}
+		return parsed, nil
+	case "int":
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for %s: expected an integer", value, key)
+		}
+		return parsed, nil
+	case "float64":
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for %s: expected a number", value, key)
+		}
+		return parsed, nil
+	case "stringArray", "stringSlice":
+		return strings.Split(value, ","), nil
+	}
+	return value, nil
+}
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/config"
+)
+
+// runConfig runs the config command with the arguments and returns its output.
+func runConfig(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	common := &option.Common{Output: option.Output{Out: &out, ErrOut: &out}}
+	root := &cobra.Command{Use: "complyctl", SilenceErrors: true, SilenceUsage: true}
+	common.BindFlags(root.PersistentFlags())
+	root.AddCommand(scanCmd(common), configCmd(common))
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs(args)
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestConfigCommands(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv(config.ConfigEnv, configPath)
+
+	_, err := runConfig(t, "config", "set", "workspace", "/srv/complytime")
+	require.ErrorContains(t, err, "no current context")
+	_, err = runConfig(t, "config", "set", "--context", "prod", "unknown-flag", "value")
+	require.EqualError(t, err, `unknown setting "unknown-flag", expected a flag name or plugins.<plugin-id>.<option>`)
+	_, err = runConfig(t, "config", "set", "--context", "prod", "with-md", "maybe")
+	require.EqualError(t, err, `invalid value "maybe" for with-md: expected true or false`)
+
+	for _, args := range [][]string{
+		{"--context", "prod", "workspace", "/srv/complytime/prod"},
+		{"--context", "prod", "content-dir", "/srv/content,/srv/extra-content"},
+		{"--context", "prod", "plugin-config", "/etc/complytime/prod.d"},
+		{"--context", "prod", "plugins.openscap.datastream", "ssg-rhel9-ds.xml"},
+		{"--context", "dev", "workspace", "/home/dev/complytime"},
+	} {
+		_, err = runConfig(t, append([]string{"config", "set"}, args...)...)
+		require.NoError(t, err)
+	}
+
+	cfg, err := config.Read(configPath)
+	require.NoError(t, err)
+	require.Equal(t, "prod", cfg.CurrentContext)
+	require.Equal(t, []string{"dev", "prod"}, cfg.ContextNames())
+
+	out, err := runConfig(t, "config", "get", "content-dir")
+	require.NoError(t, err)
+	require.Equal(t, "/srv/content,/srv/extra-content\n", out)
+	out, err = runConfig(t, "config", "get", "plugins.openscap.datastream")
+	require.NoError(t, err)
+	require.Equal(t, "ssg-rhel9-ds.xml\n", out)
+	_, err = runConfig(t, "config", "get", "with-md")
+	require.EqualError(t, err, "with-md is not set in context prod")
+	out, err = runConfig(t, "config", "get")
+	require.NoError(t, err)
+	require.Contains(t, out, "# Context prod of "+configPath)
+	require.Contains(t, out, "plugin-config: /etc/complytime/prod.d")
+
+	_, err = runConfig(t, "config", "use-context", "staging")
+	require.ErrorContains(t, err, `context "staging" does not exist`)
+	out, err = runConfig(t, "config", "use-context", "dev")
+	require.NoError(t, err)
+	require.Equal(t, "Switched to context dev\n", out)
+	out, err = runConfig(t, "config", "get", "workspace")
+	require.NoError(t, err)
+	require.Equal(t, "/home/dev/complytime\n", out)
+
+	_, err = runConfig(t, "config", "set", "--unset", "workspace", "/srv")
+	require.EqualError(t, err, `invalid command flags: "--unset" takes no value`)
+	_, err = runConfig(t, "config", "set", "--unset", "workspace")
+	require.NoError(t, err)
+	_, err = runConfig(t, "config", "get", "workspace")
+	require.EqualError(t, err, "workspace is not set in context dev")
+}
+
+func TestApplyConfigPrecedence(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv(config.ConfigEnv, configPath)
+	cfg := &config.Config{}
+	require.NoError(t, cfg.Set("prod", "workspace", "/srv/complytime/prod"))
+	require.NoError(t, cfg.Set("prod", "plugin-config", "/etc/complytime/prod.d"))
+	require.NoError(t, cfg.Set("prod", "with-md", true))
+	require.NoError(t, cfg.Set("prod", "content-dir", []string{"/srv/content", "/srv/extra-content"}))
+	require.NoError(t, cfg.Set("prod", "plugins.openscap.datastream", "ssg-rhel9-ds.xml"))
+	require.NoError(t, cfg.Set("dev", "workspace", "/home/dev/complytime"))
+	require.NoError(t, cfg.UseContext("prod"))
+	require.NoError(t, cfg.Write(configPath))
+
+	// resolve parses the arguments of the scan command and applies the configuration
+	resolve := func(t *testing.T, args ...string) (*cobra.Command, *option.Common, error) {
+		common := &option.Common{}
+		root := &cobra.Command{Use: "complyctl"}
+		common.BindFlags(root.PersistentFlags())
+		cmd := scanCmd(common)
+		root.AddCommand(cmd)
+		require.NoError(t, cmd.ParseFlags(args))
+		_, err := applyConfig(cmd, common)
+		return cmd, common, err
+	}
+	getString := func(cmd *cobra.Command, name string) string {
+		value, err := cmd.Flags().GetString(name)
+		require.NoError(t, err)
+		return value
+	}
+
+	// Context settings apply to flags without a value
+	cmd, common, err := resolve(t)
+	require.NoError(t, err)
+	require.Equal(t, "/srv/complytime/prod", getString(cmd, "workspace"))
+	require.Equal(t, "/etc/complytime/prod.d", getString(cmd, "plugin-config"))
+	require.Equal(t, []string{"/srv/content", "/srv/extra-content"}, common.ContentDirs)
+	withMd, err := cmd.Flags().GetBool("with-md")
+	require.NoError(t, err)
+	require.True(t, withMd)
+	require.Equal(t, map[string]map[string]string{"openscap": {"datastream": "ssg-rhel9-ds.xml"}}, common.PluginOverrides)
+
+	// Environment variables take precedence over the context
+	t.Setenv("COMPLYCTL_WORKSPACE", "/env/complytime")
+	t.Setenv("COMPLYCTL_CONTENT_DIR", "/env/content,/env/extra-content")
+	cmd, common, err = resolve(t)
+	require.NoError(t, err)
+	require.Equal(t, "/env/complytime", getString(cmd, "workspace"))
+	require.Equal(t, []string{"/env/content", "/env/extra-content"}, common.ContentDirs)
+	require.Equal(t, "/etc/complytime/prod.d", getString(cmd, "plugin-config"))
+
+	// Flags take precedence over environment variables
+	cmd, _, err = resolve(t, "--workspace", "/flag/complytime")
+	require.NoError(t, err)
+	require.Equal(t, "/flag/complytime", getString(cmd, "workspace"))
+
+	// The context is selected with --context or its environment variable
+	t.Setenv("COMPLYCTL_WORKSPACE", "")
+	cmd, common, err = resolve(t, "--context", "dev")
+	require.NoError(t, err)
+	require.Equal(t, "/home/dev/complytime", getString(cmd, "workspace"))
+	require.Empty(t, getString(cmd, "plugin-config"))
+	require.Empty(t, common.PluginOverrides)
+	t.Setenv("COMPLYCTL_CONTEXT", "staging")
+	_, _, err = resolve(t)
+	require.ErrorContains(t, err, `context "staging" does not exist`)
+
+	// Invalid values name their source
+	t.Setenv("COMPLYCTL_CONTEXT", "")
+	t.Setenv("COMPLYCTL_WITH_MD", "maybe")
+	_, _, err = resolve(t)
+	require.ErrorContains(t, err, "invalid environment variable COMPLYCTL_WITH_MD")
+}
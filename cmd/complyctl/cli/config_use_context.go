@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/config"
+)
+
+// configUseContextOptions defines options for the "config use-context" subcommand
+type configUseContextOptions struct {
+	*option.Common
+
+	// name is the name of the context to use
+	name string
+}
+
+// configUseContextCmd creates a new cobra.Command for the "config use-context" subcommand
+func configUseContextCmd(common *option.Common) *cobra.Command {
+	useOpts := &configUseContextOptions{
+		Common: common,
+	}
+	cmd := &cobra.Command{
+		Use:          "use-context [flags] name",
+		Short:        "Make a context the current context, used by all commands unless --context is given.",
+		Example:      "complyctl config use-context prod",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			cfg, err := config.Read(config.DefaultPath())
+			if err != nil || len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return cfg.ContextNames(), cobra.ShellCompDirectiveNoFileComp
+		},
+		PreRun: func(_ *cobra.Command, args []string) {
+			useOpts.name = args[0]
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runConfigUseContext(useOpts)
+		},
+	}
+	return cmd
+}
+
+func runConfigUseContext(opts *configUseContextOptions) error {
+	configPath := config.DefaultPath()
+	cfg, err := config.Read(configPath)
+	if err != nil {
+		return err
+	}
+	if err := cfg.UseContext(opts.name); err != nil {
+		return fmt.Errorf("%w in %s, create it with \"complyctl config set --context %s\"", err, configPath, opts.name)
+	}
+	if err := cfg.Write(configPath); err != nil {
+		return fmt.Errorf("error writing configuration to %s: %w", configPath, err)
+	}
+	_, _ = fmt.Fprintf(opts.Out, "Switched to context %s\n", opts.name)
+	return nil
+}
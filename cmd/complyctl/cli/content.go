@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+)
+
+// contentCmd creates a new cobra.Command for the "content" subcommand
+func contentCmd(common *option.Common) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "content [command]",
+		Short: "Manage and check the OSCAL content used by complyctl.",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(
+		contentInstallCmd(common),
+		contentLintCmd(common),
+		contentListCmd(common),
+		contentReindexCmd(common),
+		contentRemoveCmd(common),
+	)
+	return cmd
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/content"
+)
+
+// contentInstallOptions defines options for the "content install" subcommand
+type contentInstallOptions struct {
+	*option.Common
+
+	// source is the tarball or OCI image layout directory to install from
+	source string
+
+	// name overrides the bundle name from the source
+	name string
+
+	// tag selects the image in an OCI image layout
+	tag string
+
+	// system installs into the system content root instead of the user content root
+	system bool
+}
+
+var contentInstallExample = `
+# Install a bundle from a tarball into the user content root.
+complyctl content install ./anssi-content.tar.gz
+
+# Install the image tagged 1.0 from an OCI image layout into the system content root.
+complyctl content install ./anssi-layout --tag 1.0 --system
+`
+
+// contentInstallCmd creates a new cobra.Command for the "content install" subcommand
+func contentInstallCmd(common *option.Common) *cobra.Command {
+	installOpts := &contentInstallOptions{
+		Common: common,
+	}
+	cmd := &cobra.Command{
+		Use:          "install [flags] source",
+		Short:        "Install a content bundle from a tarball or an OCI image layout.",
+		Example:      contentInstallExample,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		PreRun: func(_ *cobra.Command, args []string) {
+			installOpts.source = args[0]
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runContentInstall(installOpts)
+		},
+	}
+	cmd.Flags().StringVarP(&installOpts.name, "name", "n", "", "name of the installed bundle, defaults to the OCI title annotation or archive name")
+	cmd.Flags().StringVarP(&installOpts.tag, "tag", "t", "", "tag of the image to install from an OCI image layout")
+	cmd.Flags().BoolVar(&installOpts.system, "system", false, "install into the system content root")
+	return cmd
+}
+
+func runContentInstall(opts *contentInstallOptions) error {
+	contentRoot, err := complytime.NewContentRoot(opts.system)
+	if err != nil {
+		return err
+	}
+	source, err := content.ReadSource(opts.source, opts.tag)
+	if err != nil {
+		return fmt.Errorf("error reading bundle from %s: %w", opts.source, err)
+	}
+	if opts.name != "" {
+		source.Name = opts.name
+	}
+	logger.Debug(fmt.Sprintf("Installing bundle %s from %s into %s", source.Name, opts.source, contentRoot.AppDir()))
+
+	bundle, err := content.Install(contentRoot, source, validation.NewSchemaValidator())
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(opts.Out, "Installed bundle %s with %d files in %s\n", bundleLabel(*bundle), len(bundle.Files), contentRoot.AppDir())
+	if len(bundle.Frameworks) > 0 {
+		_, _ = fmt.Fprintf(opts.Out, "Frameworks: %s\n", strings.Join(bundle.Frameworks, ", "))
+	}
+	return nil
+}
+
+// bundleLabel returns the bundle name with its version, if known.
+func bundleLabel(bundle content.Bundle) string {
+	if bundle.Version == "" {
+		return bundle.Name
+	}
+	return fmt.Sprintf("%s %s", bundle.Name, bundle.Version)
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+const (
+	lintFormatText = "text"
+	lintFormatJSON = "json"
+)
+
+// contentLintOptions defines options for the "content lint" subcommand
+type contentLintOptions struct {
+	*option.Common
+
+	// dir is the content directory to lint, defaulting to the content roots of the content path
+	dir string
+
+	// format is the output format of lint findings
+	format string
+}
+
+var contentLintExample = `
+# Lint the component definitions, catalogs, and profiles installed in the content roots.
+complyctl content lint
+
+# Lint content before installing it. Control sources are resolved from the given directory.
+complyctl content lint ./my-content
+
+# Print findings as JSON.
+complyctl content lint ./my-content --format json
+`
+
+// contentLintCmd creates a new cobra.Command for the "content lint" subcommand
+func contentLintCmd(common *option.Common) *cobra.Command {
+	lintOpts := &contentLintOptions{
+		Common: common,
+	}
+	cmd := &cobra.Command{
+		Use:          "lint [flags] [dir]",
+		Short:        "Check OSCAL content for schema and complyctl specific problems.",
+		Example:      contentLintExample,
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		PreRun: func(_ *cobra.Command, args []string) {
+			if len(args) == 1 {
+				lintOpts.dir = filepath.Clean(args[0])
+			}
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := validateContentLint(lintOpts); err != nil {
+				return err
+			}
+			return runContentLint(lintOpts)
+		},
+	}
+	cmd.Flags().StringVarP(&lintOpts.format, "format", "f", lintFormatText, "output format, one of: text, json")
+	return cmd
+}
+
+func validateContentLint(opts *contentLintOptions) error {
+	if opts.format != lintFormatText && opts.format != lintFormatJSON {
+		return fmt.Errorf("invalid output format %q, must be one of: %s, %s", opts.format, lintFormatText, lintFormatJSON)
+	}
+	return nil
+}
+
+func runContentLint(opts *contentLintOptions) error {
+	if opts.dir != "" {
+		logger.Debug(fmt.Sprintf("Linting content in %s", opts.dir))
+		findings, err := complytime.LintContent(opts.dir, validation.NewSchemaValidator())
+		if err != nil {
+			return err
+		}
+		return reportLintFindings(opts, findings, opts.dir)
+	}
+
+	// Without a directory, every content root of the content path is linted on its own
+	// and findings are reported with the path of the file.
+	contentPath := opts.ContentPath()
+	logger.Debug(fmt.Sprintf("Linting content in %s", contentPath))
+	var findings []complytime.LintFinding
+	for _, root := range contentPath {
+		if _, err := os.Stat(root.AppDir()); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		rootFindings, err := complytime.LintContent(root.AppDir(), validation.NewSchemaValidator())
+		if err != nil {
+			return err
+		}
+		for _, finding := range rootFindings {
+			finding.File = filepath.Join(root.AppDir(), finding.File)
+			findings = append(findings, finding)
+		}
+	}
+	return reportLintFindings(opts, findings, contentPath.String())
+}
+
+// reportLintFindings writes the findings and returns an error if any were found.
+func reportLintFindings(opts *contentLintOptions, findings []complytime.LintFinding, location string) error {
+	if err := writeLintFindings(opts.Out, opts.format, findings); err != nil {
+		return err
+	}
+	if len(findings) > 0 {
+		return fmt.Errorf("found %d problems in %s", len(findings), location)
+	}
+	return nil
+}
+
+// writeLintFindings writes the findings in the given format.
+func writeLintFindings(out io.Writer, format string, findings []complytime.LintFinding) error {
+	if format == lintFormatJSON {
+		if findings == nil {
+			findings = []complytime.LintFinding{}
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(findings)
+	}
+	for _, finding := range findings {
+		if _, err := fmt.Fprintln(out, finding.String()); err != nil {
+			return err
+		}
+	}
+	if len(findings) == 0 {
+		_, err := fmt.Fprintln(out, "No problems found")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestValidateContentLint(t *testing.T) {
+	require.NoError(t, validateContentLint(&contentLintOptions{format: lintFormatText}))
+	require.NoError(t, validateContentLint(&contentLintOptions{format: lintFormatJSON}))
+	require.EqualError(t, validateContentLint(&contentLintOptions{format: "yaml"}),
+		`invalid output format "yaml", must be one of: text, json`)
+}
+
+func TestWriteLintFindings(t *testing.T) {
+	findings := []complytime.LintFinding{
+		{File: "bundles/cd.json", Pointer: "/component-definition", Check: complytime.LintCheckFramework, Message: "no framework"},
+	}
+	tests := []struct {
+		name     string
+		format   string
+		findings []complytime.LintFinding
+		want     string
+	}{
+		{
+			name:     "Valid/TextNoFindings",
+			format:   lintFormatText,
+			findings: nil,
+			want:     "No problems found\n",
+		},
+		{
+			name:     "Valid/Text",
+			format:   lintFormatText,
+			findings: findings,
+			want:     "bundles/cd.json#/component-definition: [framework] no framework\n",
+		},
+		{
+			name:     "Valid/JSONNoFindings",
+			format:   lintFormatJSON,
+			findings: nil,
+			want:     "[]\n",
+		},
+		{
+			name:     "Valid/JSON",
+			format:   lintFormatJSON,
+			findings: findings,
+			want: `[
+  {
+    "file": "bundles/cd.json",
+    "pointer": "/component-definition",
+    "check": "framework",
+    "message": "no framework"
+  }
+]
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			require.NoError(t, writeLintFindings(&out, tt.format, tt.findings))
+			require.Equal(t, tt.want, out.String())
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/content"
+	"github.com/complytime/complyctl/internal/terminal"
+)
+
+const (
+	// digestLength is the number of digest characters shown in the bundle list.
+	digestLength = 19
+	// columnGap is the minimum space between columns of the bundle list.
+	columnGap = 2
+)
+
+// contentListOptions defines options for the "content list" subcommand
+type contentListOptions struct {
+	*option.Common
+}
+
+// contentListCmd creates a new cobra.Command for the "content list" subcommand
+func contentListCmd(common *option.Common) *cobra.Command {
+	listOpts := &contentListOptions{
+		Common: common,
+	}
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List the content bundles installed in the user and system content roots.",
+		Example:      "complyctl content list",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runContentList(listOpts)
+		},
+	}
+	return cmd
+}
+
+func runContentList(opts *contentListOptions) error {
+	var rows []table.Row
+	for _, system := range []bool{false, true} {
+		contentRoot, err := complytime.NewContentRoot(system)
+		if err != nil {
+			return err
+		}
+		manifest, err := content.ReadManifest(contentRoot)
+		if err != nil {
+			return err
+		}
+		logger.Debug(fmt.Sprintf("Found %d bundles in %s", len(manifest.Bundles), contentRoot.AppDir()))
+		rows = append(rows, bundleRows(manifest.Bundles, contentRoot.AppDir())...)
+	}
+	if len(rows) == 0 {
+		_, _ = fmt.Fprintln(opts.Out, "No content bundles installed")
+		return nil
+	}
+	columns := []table.Column{
+		{Title: "Name", Width: 20},
+		{Title: "Version", Width: 10},
+		{Title: "Frameworks", Width: 20},
+		{Title: "Digest", Width: digestLength + columnGap},
+		{Title: "Content Root", Width: 25},
+	}
+	// Keep a gap between columns, the plain table does not separate them
+	for _, row := range rows {
+		for i, cell := range row {
+			if len(cell)+columnGap > columns[i].Width {
+				columns[i].Width = len(cell) + columnGap
+			}
+		}
+	}
+	terminal.ShowPlainTable(opts.Out, columns, rows)
+	return nil
+}
+
+// bundleRows returns a table row for each bundle installed in the content root.
+func bundleRows(bundles []content.Bundle, contentRoot string) []table.Row {
+	var rows []table.Row
+	for _, bundle := range bundles {
+		digest := bundle.Digest
+		if len(digest) > digestLength {
+			digest = digest[:digestLength]
+		}
+		rows = append(rows, table.Row{bundle.Name, bundle.Version, strings.Join(bundle.Frameworks, ", "), digest, contentRoot})
+	}
+	return rows
+}
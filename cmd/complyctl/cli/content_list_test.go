@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/content"
+)
+
+func TestBundleRows(t *testing.T) {
+	bundles := []content.Bundle{
+		{
+			Name:       "anssi-content",
+			Version:    "1.0",
+			Digest:     "sha256:80baca5d7303e9fd85de54d1411e8123744a5cbbbc882f237d5d1f53d72aa52d",
+			Frameworks: []string{"anssi_bp28_minimal", "anssi_bp28_high"},
+		},
+		{
+			Name:   "fedora-content",
+			Digest: "sha256:80ba",
+		},
+	}
+	require.Equal(t, []table.Row{
+		{"anssi-content", "1.0", "anssi_bp28_minimal, anssi_bp28_high", "sha256:80baca5d7303", "/usr/share/complytime"},
+		{"fedora-content", "", "", "sha256:80ba", "/usr/share/complytime"},
+	}, bundleRows(bundles, "/usr/share/complytime"))
+	require.Equal(t, "anssi-content 1.0", bundleLabel(bundles[0]))
+	require.Equal(t, "fedora-content", bundleLabel(bundles[1]))
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// contentReindexOptions defines options for the "content reindex" subcommand
+type contentReindexOptions struct {
+	*option.Common
+}
+
+// contentReindexCmd creates a new cobra.Command for the "content reindex" subcommand
+func contentReindexCmd(common *option.Common) *cobra.Command {
+	reindexOpts := &contentReindexOptions{
+		Common: common,
+	}
+	cmd := &cobra.Command{
+		Use:          "reindex",
+		Short:        "Rebuild the content index used by list, info, and plan.",
+		Example:      "complyctl content reindex",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runContentReindex(reindexOpts)
+		},
+	}
+	return cmd
+}
+
+func runContentReindex(opts *contentReindexOptions) error {
+	contentPath := opts.ContentPath()
+	logger.Debug(fmt.Sprintf("Using content path: %s", contentPath))
+	indexPath, err := complytime.ContentIndexPath(contentPath)
+	if err != nil {
+		return fmt.Errorf("error locating content index: %w", err)
+	}
+
+	index, err := complytime.BuildContentIndex(contentPath, validation.NewSchemaValidator())
+	if err != nil {
+		return err
+	}
+	if err := index.Write(indexPath); err != nil {
+		return fmt.Errorf("error writing content index %s: %w", indexPath, err)
+	}
+	_, _ = fmt.Fprintf(opts.Out, "Indexed %d frameworks from %d files in %s\n", len(index.Frameworks), len(index.Files), indexPath)
+	return nil
+}
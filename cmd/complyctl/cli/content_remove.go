@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/content"
+)
+
+// contentRemoveOptions defines options for the "content remove" subcommand
+type contentRemoveOptions struct {
+	*option.Common
+
+	// name is the name of the bundle to remove
+	name string
+
+	// system removes from the system content root instead of the user content root
+	system bool
+}
+
+// contentRemoveCmd creates a new cobra.Command for the "content remove" subcommand
+func contentRemoveCmd(common *option.Common) *cobra.Command {
+	removeOpts := &contentRemoveOptions{
+		Common: common,
+	}
+	cmd := &cobra.Command{
+		Use:          "remove [flags] name",
+		Short:        "Remove an installed content bundle and its files.",
+		Example:      "complyctl content remove anssi-content",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		PreRun: func(_ *cobra.Command, args []string) {
+			removeOpts.name = args[0]
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runContentRemove(removeOpts)
+		},
+	}
+	cmd.Flags().BoolVar(&removeOpts.system, "system", false, "remove from the system content root")
+	return cmd
+}
+
+func runContentRemove(opts *contentRemoveOptions) error {
+	contentRoot, err := complytime.NewContentRoot(opts.system)
+	if err != nil {
+		return err
+	}
+	bundle, err := content.Remove(contentRoot, opts.name)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(opts.Out, "Removed bundle %s with %d files from %s\n", bundleLabel(*bundle), len(bundle.Files), contentRoot.AppDir())
+	return nil
+}
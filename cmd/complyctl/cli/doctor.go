@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+// doctorOptions defines options for the "doctor" subcommand
+type doctorOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+
+	// withPluginConfig is the directory of user customized plugin manifests
+	withPluginConfig string
+}
+
+var doctorExample = `
+# Check the installation, content, plugins, and workspace before running a scan.
+complyctl doctor
+
+# Check plugins with user customized plugin manifests.
+complyctl doctor --plugin-config /tmp/plugins-conf
+`
+
+// doctorCmd creates a new cobra.Command for the "doctor" subcommand
+func doctorCmd(common *option.Common) *cobra.Command {
+	doctorOpts := &doctorOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:          "doctor [flags]",
+		Short:        "Diagnose the environment, content, and plugins used by complyctl.",
+		Example:      doctorExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runDoctor(cmd.Context(), doctorOpts)
+		},
+	}
+	cmd.Flags().StringVarP(&doctorOpts.withPluginConfig, "plugin-config", "c", "", "Directory where user customized plugin manifests located.")
+	doctorOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runDoctor(ctx context.Context, opts *doctorOptions) error {
+	// Do not create missing directories, they are reported instead.
+	appDir, err := complytime.NewApplicationDirectory(false)
+	if err != nil {
+		return err
+	}
+	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
+
+	contentPath := opts.ContentPath()
+	logger.Debug(fmt.Sprintf("Using content path: %s", contentPath))
+
+	sections := doctorSections(ctx, appDir, contentPath, opts)
+	doctor.WriteChecklist(opts.Out, sections)
+
+	if failed := doctor.Count(sections, doctor.Fail); failed > 0 {
+		return fmt.Errorf("%d checks failed", failed)
+	}
+	return nil
+}
+
+// doctorSections runs all checks grouped by the area they diagnose.
+func doctorSections(ctx context.Context, appDir complytime.ApplicationDirectory, contentPath complytime.ContentPath, opts *doctorOptions) []doctor.Section {
+	contentChecks, validationComponents := complytime.CheckContent(contentPath, validation.NewSchemaValidator())
+
+	pluginOptions := complytime.NewPluginOptions()
+	pluginOptions.Workspace = opts.complyTimeOpts.UserWorkspace
+	pluginOptions.UserConfigRoot = opts.withPluginConfig
+	pluginOptions.Overrides = opts.PluginOverrides
+	pluginChecks := complytime.CheckPlugins(ctx, contentPath, validationComponents, pluginOptions, logger)
+
+	return []doctor.Section{
+		{Title: "Application directory", Checks: complytime.CheckApplicationDirectory(appDir)},
+		{Title: "Content path", Checks: complytime.CheckContentPath(contentPath)},
+		{Title: "Content", Checks: contentChecks},
+		{Title: "Plugins", Checks: pluginChecks},
+		{Title: "Workspace", Checks: complytime.CheckWorkspace(opts.complyTimeOpts.UserWorkspace)},
+	}
+}
@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/evidence"
+)
+
+const (
+	// evidenceBundleLocation is the name of an evidence bundle in the workspace, with the time of the scan.
+	evidenceBundleLocation = "evidence-bundle-%s.tar"
+	// evidenceBundleTimeFormat is the format of the time in the name of evidence bundles.
+	evidenceBundleTimeFormat = "20060102T150405Z"
+	// evidenceDir is the directory of the evidence files in an evidence bundle.
+	evidenceDir = "evidence"
+	// scanAttestationLocation is the DSSE envelope of the in-toto statement of a scan in the workspace.
+	scanAttestationLocation = "assessment-results.intoto.jsonl"
+)
+
+// evidenceCmd creates a new cobra.Command for the "evidence" subcommand
+func evidenceCmd(common *option.Common) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "evidence [command]",
+		Short: "Verify the signed evidence bundles and attestations created by \"complyctl scan\".",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(
+		evidenceVerifyCmd(common),
+	)
+	return cmd
+}
+
+// writeEvidenceBundle packages the workspace files and the evidence files referenced by the assessment
+// results into an evidence bundle in the workspace, signed with the signing key. The default signing key
+// is generated when it does not exist. The path of the bundle is returned.
+func writeEvidenceBundle(workspace, signingKeyPath string, workspaceFiles []string, assessmentResults *oscalTypes.AssessmentResults,
+	plugins []evidence.Plugin, created time.Time) (string, error) {
+	key, err := loadSigningKey(signingKeyPath)
+	if err != nil {
+		return "", err
+	}
+
+	bundle := &evidence.Bundle{Plugins: plugins}
+	for _, file := range workspaceFiles {
+		if err := bundle.Add(filepath.Base(file), file); err != nil {
+			return "", err
+		}
+	}
+	for _, file := range evidence.ReferencedFiles(assessmentResults) {
+		if err := bundle.Add(evidenceName(workspace, file), file); err != nil {
+			return "", err
+		}
+	}
+
+	bundlePath := filepath.Join(workspace, fmt.Sprintf(evidenceBundleLocation, created.UTC().Format(evidenceBundleTimeFormat)))
+	if err := bundle.Write(bundlePath, key, created); err != nil {
+		return "", fmt.Errorf("failed to write evidence bundle: %w", err)
+	}
+	return bundlePath, nil
+}
+
+// writeScanAttestation writes the DSSE envelope of the in-toto statement of a scan in the workspace,
+// signed with the signing key. The subjects are the scanned hosts of the assessment results, unless
+// subjects are given. The path of the envelope is returned.
+func writeScanAttestation(workspace, signingKeyPath string, subjects []evidence.ResourceDescriptor, assessmentResults *oscalTypes.AssessmentResults,
+	predicate evidence.ScanPredicate) (string, error) {
+	key, err := loadSigningKey(signingKeyPath)
+	if err != nil {
+		return "", err
+	}
+	if len(subjects) == 0 {
+		for _, host := range scannedHosts(assessmentResults) {
+			subjects = append(subjects, evidence.HostSubject(host))
+		}
+	}
+	statement, err := evidence.NewScanStatement(subjects, predicate)
+	if err != nil {
+		return "", err
+	}
+	envelope, err := statement.Sign(key)
+	if err != nil {
+		return "", err
+	}
+	attestationPath := filepath.Join(workspace, scanAttestationLocation)
+	if err := envelope.Write(attestationPath); err != nil {
+		return "", fmt.Errorf("failed to write attestation: %w", err)
+	}
+	return attestationPath, nil
+}
+
+// scannedHosts returns the hosts observed in the assessment results, ordered by name, or the
+// local host when no host was observed.
+func scannedHosts(assessmentResults *oscalTypes.AssessmentResults) []string {
+	hostResults := complytime.HostRuleResults(assessmentResults)
+	hosts := make([]string, 0, len(hostResults))
+	for host := range hostResults {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	if len(hosts) == 0 {
+		if hostname, err := os.Hostname(); err == nil {
+			hosts = append(hosts, hostname)
+		}
+	}
+	return hosts
+}
+
+// pluginVersions returns the versions of the plugins of the manifests, ordered by plugin ID.
+func pluginVersions(manifests plugin.Manifests) []evidence.Plugin {
+	plugins := make([]evidence.Plugin, 0, len(manifests))
+	for _, manifest := range manifests {
+		plugins = append(plugins, evidence.Plugin{ID: manifest.ID.String(), Version: manifest.Version, Checksum: manifest.Checksum})
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].ID < plugins[j].ID })
+	return plugins
+}
+
+// evidenceName returns the name of an evidence file in the bundle, relative to the workspace for
+// files of the workspace, or following their absolute path otherwise.
+func evidenceName(workspace, file string) string {
+	absWorkspace, errWorkspace := filepath.Abs(workspace)
+	absFile, errFile := filepath.Abs(file)
+	if errWorkspace == nil && errFile == nil {
+		if rel, err := filepath.Rel(absWorkspace, absFile); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+			return path.Join(evidenceDir, filepath.ToSlash(rel))
+		}
+		file = absFile
+	}
+	return path.Join(evidenceDir, strings.TrimPrefix(filepath.ToSlash(filepath.Clean(file)), "/"))
+}
+
+// loadSigningKey reads the signing key at the given path, or the default signing key of the user when
+// the path is empty. The default signing key is generated when it does not exist.
+func loadSigningKey(signingKeyPath string) (ed25519.PrivateKey, error) {
+	if signingKeyPath != "" {
+		return evidence.ReadSigningKey(signingKeyPath)
+	}
+	signingKeyPath = evidence.DefaultSigningKeyPath()
+	key, err := evidence.ReadSigningKey(signingKeyPath)
+	if !errors.Is(err, os.ErrNotExist) {
+		return key, err
+	}
+	key, err = evidence.GenerateSigningKey(signingKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	logger.Info(fmt.Sprintf("Generated the evidence signing key %s. Share its public key %s%s to verify evidence bundles.",
+		signingKeyPath, signingKeyPath, evidence.PublicKeySuffix))
+	return key, nil
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/evidence"
+)
+
+func TestEvidenceName(t *testing.T) {
+	require.Equal(t, "evidence/openscap/results/arf.xml", evidenceName("/workspace", "/workspace/openscap/results/arf.xml"))
+	require.Equal(t, "evidence/var/lib/arf.xml", evidenceName("/workspace", "/var/lib/arf.xml"))
+	require.Equal(t, "evidence/workspace-other/arf.xml", evidenceName("/workspace", "/workspace-other/arf.xml"))
+}
+
+func TestEvidenceBundleVerify(t *testing.T) {
+	workspace := t.TempDir()
+	keyPath := filepath.Join(t.TempDir(), "signing.key")
+	_, err := evidence.GenerateSigningKey(keyPath)
+	require.NoError(t, err)
+
+	planPath := filepath.Join(workspace, "assessment-plan.json")
+	resultsPath := filepath.Join(workspace, assessmentResultsLocationJson)
+	arfPath := filepath.Join(workspace, "openscap", "results", "arf.xml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(arfPath), 0700))
+	for _, file := range []string{planPath, resultsPath, arfPath} {
+		require.NoError(t, os.WriteFile(file, []byte(filepath.Base(file)), 0600))
+	}
+	assessmentResults := &oscalTypes.AssessmentResults{
+		Results: []oscalTypes.Result{{
+			Observations: &[]oscalTypes.Observation{{
+				RelevantEvidence: &[]oscalTypes.RelevantEvidence{{Href: "file://" + arfPath}},
+			}},
+		}},
+	}
+	manifests := plugin.Manifests{"openscap": plugin.Manifest{Metadata: plugin.Metadata{ID: "openscap", Version: "0.0.1"}}}
+	created := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	bundlePath, err := writeEvidenceBundle(workspace, keyPath, []string{planPath, resultsPath}, assessmentResults, pluginVersions(manifests), created)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(workspace, "evidence-bundle-20260615T120000Z.tar"), bundlePath)
+
+	var out bytes.Buffer
+	opts := &evidenceVerifyOptions{Common: &option.Common{Output: option.Output{Out: &out}}, bundle: bundlePath, key: keyPath + evidence.PublicKeySuffix}
+	require.NoError(t, runEvidenceVerify(opts))
+	publicKey, err := evidence.ReadPublicKey(keyPath)
+	require.NoError(t, err)
+	require.Equal(t, `Created: 2026-06-15T12:00:00Z
+Plugin: openscap version 0.0.1
+Signature: valid, key `+evidence.KeyID(publicKey)+`
+Files: 4 verified
+Evidence bundle is intact
+`, out.String())
+
+	// A bundle signed with another key fails verification
+	otherKeyPath := filepath.Join(t.TempDir(), "other.key")
+	_, err = evidence.GenerateSigningKey(otherKeyPath)
+	require.NoError(t, err)
+	out.Reset()
+	opts.key = otherKeyPath + evidence.PublicKeySuffix
+	require.EqualError(t, runEvidenceVerify(opts), "evidence bundle "+bundlePath+" failed verification with 1 problems")
+	require.Contains(t, out.String(), "Signature: invalid")
+}
+
+func TestValidateScan(t *testing.T) {
+	digest := strings.Repeat("ab", 32)
+	subjects, err := validateScan(&scanOptions{attest: true, attestSubjects: []string{"registry.example.com/app@sha256:" + digest}})
+	require.NoError(t, err)
+	require.Equal(t, []evidence.ResourceDescriptor{{Name: "registry.example.com/app", Digest: map[string]string{"sha256": digest}}}, subjects)
+
+	_, err = validateScan(&scanOptions{attestSubjects: []string{"registry.example.com/app@sha256:" + digest}})
+	require.EqualError(t, err, "invalid command flags: \"--attest-subject\" requires \"--attest\"")
+	_, err = validateScan(&scanOptions{signingKey: "signing.key"})
+	require.EqualError(t, err, "invalid command flags: \"--signing-key\" requires \"--attest\" or \"--bundle-evidence\"")
+	_, err = validateScan(&scanOptions{attest: true, attestSubjects: []string{"registry.example.com/app:latest"}})
+	require.EqualError(t, err, "invalid command flags: invalid subject \"registry.example.com/app:latest\", expected name@sha256:digest")
+}
+
+func TestScanAttestationVerify(t *testing.T) {
+	workspace := t.TempDir()
+	keyPath := filepath.Join(t.TempDir(), "signing.key")
+	_, err := evidence.GenerateSigningKey(keyPath)
+	require.NoError(t, err)
+	resultsPath := filepath.Join(workspace, assessmentResultsLocationJson)
+	require.NoError(t, os.WriteFile(resultsPath, []byte("results"), 0600))
+	results, err := evidence.FileDescriptor(resultsPath)
+	require.NoError(t, err)
+
+	predicate := evidence.ScanPredicate{
+		ScannedAt: time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC),
+		Frameworks: []evidence.FrameworkSummary{evidence.NewFrameworkSummary("anssi_bp28_minimal", map[string]int{"pass": 1, "fail": 1},
+			map[string]map[string]int{"r1": {"pass": 1}, "r2": {"fail": 1}})},
+		Plan:    evidence.ResourceDescriptor{Name: "assessment-plan.json", Digest: map[string]string{"sha256": "abc"}},
+		Results: results,
+	}
+	subjects := []evidence.ResourceDescriptor{{Name: "registry.example.com/app", Digest: map[string]string{"sha256": "def"}}}
+	attestationPath, err := writeScanAttestation(workspace, keyPath, subjects, &oscalTypes.AssessmentResults{}, predicate)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(workspace, scanAttestationLocation), attestationPath)
+
+	isEnvelope, err := isAttestation(attestationPath)
+	require.NoError(t, err)
+	require.True(t, isEnvelope)
+
+	var out bytes.Buffer
+	opts := &evidenceVerifyOptions{Common: &option.Common{Output: option.Output{Out: &out}}, bundle: attestationPath, key: keyPath + evidence.PublicKeySuffix}
+	require.NoError(t, runEvidenceVerify(opts))
+	publicKey, err := evidence.ReadPublicKey(keyPath)
+	require.NoError(t, err)
+	require.Equal(t, `Signature: valid, key `+evidence.KeyID(publicKey)+`
+Predicate: `+evidence.ScanPredicateType+`
+Subject: registry.example.com/app sha256:def
+Scanned: 2026-06-15T12:00:00Z
+Framework: anssi_bp28_minimal, 1 of 2 controls satisfied
+Plan: assessment-plan.json sha256:abc
+Results: assessment-results.json sha256:`+results.Digest["sha256"]+`
+Attestation is intact
+`, out.String())
+
+	// Without subjects, the scanned hosts are attested
+	_, err = writeScanAttestation(workspace, keyPath, nil, &oscalTypes.AssessmentResults{}, predicate)
+	require.NoError(t, err)
+	envelope, err := evidence.ReadEnvelope(attestationPath)
+	require.NoError(t, err)
+	payload, err := envelope.Verify(publicKey)
+	require.NoError(t, err)
+	statement, err := evidence.ReadStatement(envelope.PayloadType, payload)
+	require.NoError(t, err)
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+	require.Equal(t, []evidence.ResourceDescriptor{evidence.HostSubject(hostname)}, statement.Subject)
+
+	otherKeyPath := filepath.Join(t.TempDir(), "other.key")
+	_, err = evidence.GenerateSigningKey(otherKeyPath)
+	require.NoError(t, err)
+	opts.key = otherKeyPath
+	err = runEvidenceVerify(opts)
+	require.ErrorContains(t, err, "attestation "+attestationPath+" failed verification: no signature of the envelope was made with key")
+}
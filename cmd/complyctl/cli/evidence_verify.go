@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/evidence"
+)
+
+// evidenceVerifyOptions defines options for the "evidence verify" subcommand
+type evidenceVerifyOptions struct {
+	*option.Common
+
+	// bundle is the evidence bundle or attestation to verify
+	bundle string
+
+	// key is the public key verifying the signature, defaulting to the public key of the default signing key
+	key string
+}
+
+var evidenceVerifyExample = `
+# Verify an evidence bundle signed with the default signing key of the user.
+complyctl evidence verify complytime/evidence-bundle-20260615T120000Z.tar
+
+# Verify an evidence bundle with the public key of the signer.
+complyctl evidence verify evidence-bundle-20260615T120000Z.tar --key evidence-signing.key.pub
+
+# Verify the in-toto attestation of a scan.
+complyctl evidence verify complytime/assessment-results.intoto.jsonl --key evidence-signing.key.pub
+`
+
+// evidenceVerifyCmd creates a new cobra.Command for the "evidence verify" subcommand
+func evidenceVerifyCmd(common *option.Common) *cobra.Command {
+	verifyOpts := &evidenceVerifyOptions{
+		Common: common,
+	}
+	cmd := &cobra.Command{
+		Use:          "verify [flags] bundle.tar|attestation.intoto.jsonl",
+		Short:        "Verify the integrity and signature of an evidence bundle or attestation offline.",
+		Example:      evidenceVerifyExample,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			verifyOpts.bundle = args[0]
+			return runEvidenceVerify(verifyOpts)
+		},
+	}
+	cmd.Flags().StringVarP(&verifyOpts.key, "key", "k", "", fmt.Sprintf("public key verifying the signature, defaults to %s%s", evidence.DefaultSigningKeyPath(), evidence.PublicKeySuffix))
+	return cmd
+}
+
+func runEvidenceVerify(opts *evidenceVerifyOptions) error {
+	keyPath := opts.key
+	if keyPath == "" {
+		keyPath = evidence.DefaultSigningKeyPath() + evidence.PublicKeySuffix
+	}
+	publicKey, err := evidence.ReadPublicKey(filepath.Clean(keyPath))
+	if err != nil {
+		return fmt.Errorf("failed to read public key: %w", err)
+	}
+	bundlePath := filepath.Clean(opts.bundle)
+	isEnvelope, err := isAttestation(bundlePath)
+	if err != nil {
+		return err
+	}
+	if isEnvelope {
+		return verifyAttestation(opts.Out, bundlePath, publicKey)
+	}
+	verification, err := evidence.Verify(bundlePath, publicKey)
+	if err != nil {
+		return err
+	}
+	if err := writeVerification(opts.Out, verification); err != nil {
+		return err
+	}
+	if !verification.Valid() {
+		return fmt.Errorf("evidence bundle %s failed verification with %d problems", bundlePath, len(verification.Problems))
+	}
+	return nil
+}
+
+// writeVerification writes the outcome of the verification of an evidence bundle.
+func writeVerification(out io.Writer, verification *evidence.Verification) error {
+	var b strings.Builder
+	if metadata := verification.Metadata; metadata != nil {
+		fmt.Fprintf(&b, "Created: %s\n", metadata.Created.Format(time.RFC3339))
+		for _, bundledPlugin := range metadata.Plugins {
+			fmt.Fprintf(&b, "Plugin: %s version %s\n", bundledPlugin.ID, bundledPlugin.Version)
+		}
+	}
+	signature := "invalid"
+	if verification.SignatureValid {
+		signature = "valid"
+	}
+	fmt.Fprintf(&b, "Signature: %s, key %s\n", signature, verification.KeyID)
+	fmt.Fprintf(&b, "Files: %d verified\n", len(verification.Verified))
+	for _, problem := range verification.Problems {
+		fmt.Fprintf(&b, "Problem: %s\n", problem)
+	}
+	if verification.Valid() {
+		b.WriteString("Evidence bundle is intact\n")
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// isAttestation returns whether the file is a DSSE envelope, a JSON object, rather than a tar archive.
+func isAttestation(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			// Empty files are reported as invalid bundles
+			return false, nil
+		}
+		if !unicode.IsSpace(rune(b)) {
+			return b == '{', nil
+		}
+	}
+}
+
+// verifyAttestation verifies the signature of the DSSE envelope of a scan attestation and writes
+// its in-toto statement.
+func verifyAttestation(out io.Writer, attestationPath string, publicKey ed25519.PublicKey) error {
+	envelope, err := evidence.ReadEnvelope(attestationPath)
+	if err != nil {
+		return err
+	}
+	payload, err := envelope.Verify(publicKey)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "Signature: invalid, key %s\n", evidence.KeyID(publicKey))
+		return fmt.Errorf("attestation %s failed verification: %w", attestationPath, err)
+	}
+	statement, err := evidence.ReadStatement(envelope.PayloadType, payload)
+	if err != nil {
+		return fmt.Errorf("attestation %s failed verification: %w", attestationPath, err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Signature: valid, key %s\n", evidence.KeyID(publicKey))
+	fmt.Fprintf(&b, "Predicate: %s\n", statement.PredicateType)
+	for _, subject := range statement.Subject {
+		fmt.Fprintf(&b, "Subject: %s %s\n", subject.Name, formatDigest(subject.Digest))
+	}
+	if statement.PredicateType == evidence.ScanPredicateType {
+		var predicate evidence.ScanPredicate
+		if err := json.Unmarshal(statement.Predicate, &predicate); err != nil {
+			return fmt.Errorf("attestation %s has an invalid predicate: %w", attestationPath, err)
+		}
+		fmt.Fprintf(&b, "Scanned: %s\n", predicate.ScannedAt.Format(time.RFC3339))
+		for _, framework := range predicate.Frameworks {
+			satisfied := 0
+			for _, control := range framework.Controls {
+				if control.Status == evidence.ControlSatisfied {
+					satisfied++
+				}
+			}
+			fmt.Fprintf(&b, "Framework: %s, %d of %d controls satisfied\n", framework.ID, satisfied, len(framework.Controls))
+		}
+		fmt.Fprintf(&b, "Plan: %s %s\n", predicate.Plan.Name, formatDigest(predicate.Plan.Digest))
+		fmt.Fprintf(&b, "Results: %s %s\n", predicate.Results.Name, formatDigest(predicate.Results.Digest))
+	}
+	b.WriteString("Attestation is intact\n")
+	_, err = io.WriteString(out, b.String())
+	return err
+}
+
+// formatDigest formats the digests of a resource as algorithm:hex, ordered by algorithm.
+func formatDigest(digest map[string]string) string {
+	algorithms := make([]string, 0, len(digest))
+	for algorithm := range digest {
+		algorithms = append(algorithms, algorithm)
+	}
+	sort.Strings(algorithms)
+	formatted := make([]string, 0, len(algorithms))
+	for _, algorithm := range algorithms {
+		formatted = append(formatted, algorithm+":"+digest[algorithm])
+	}
+	return strings.Join(formatted, " ")
+}
@@ -7,12 +7,12 @@ import (
 
 	"github.com/oscal-compass/compliance-to-policy-go/v2/framework"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/framework/actions"
-	"github.com/oscal-compass/oscal-sdk-go/extensions"
 	"github.com/oscal-compass/oscal-sdk-go/validation"
 	"github.com/spf13/cobra"
 
 	"github.com/complytime/complyctl/cmd/complyctl/option"
 	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/pkg/trace"
 )
 
 // generateOptions defines options for the "generate" subcommand
@@ -44,7 +44,10 @@ func generateCmd(common *option.Common) *cobra.Command {
 
 func runGenerate(cmd *cobra.Command, opts *generateOptions) error {
 	validator := validation.NewSchemaValidator()
+	_, loadSpan := trace.Start(cmd.Context(), "load plan")
 	ap, _, err := loadPlan(opts.complyTimeOpts, validator)
+	loadSpan.RecordError(err)
+	loadSpan.End()
 	if err != nil {
 		return err
 	}
@@ -60,6 +63,9 @@ func runGenerate(cmd *cobra.Command, opts *generateOptions) error {
 		return err
 	}
 	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
+	contentPath := opts.ContentPath()
+	logger.Debug(fmt.Sprintf("Using content path: %s", contentPath))
+	checkPlanDrift(cmd.Context(), contentPath, opts.complyTimeOpts.UserWorkspace, ap, validator)
 	cfg, err := complytime.Config(appDir)
 	if err != nil {
 		return err
@@ -75,16 +81,14 @@ func runGenerate(cmd *cobra.Command, opts *generateOptions) error {
 	}
 
 	// Set the framework ID from state (assessment plan). This is required to populate complyTime required plugin options.
-	frameworkProp, valid := extensions.GetTrestleProp(extensions.FrameworkProp, *ap.Metadata.Props)
-	if !valid {
-		return fmt.Errorf("error reading framework property from assessment plan")
+	if _, err := planFrameworks(opts.complyTimeOpts, ap); err != nil {
+		return err
 	}
-	opts.complyTimeOpts.FrameworkID = frameworkProp.Value
-	logger.Debug(fmt.Sprintf("Framework property was successfully read from the assessment plan: %v.", frameworkProp))
 
 	pluginOptions := opts.complyTimeOpts.ToPluginOptions()
 	pluginOptions.UserConfigRoot = opts.withPluginConfig
-	plugins, cleanup, err := complytime.Plugins(manager, inputContext, pluginOptions, logger)
+	pluginOptions.Overrides = opts.PluginOverrides
+	plugins, cleanup, err := complytime.Plugins(cmd.Context(), manager, contentPath, inputContext, pluginOptions, logger)
 	if cleanup != nil {
 		defer cleanup()
 	}
@@ -92,7 +96,10 @@ func runGenerate(cmd *cobra.Command, opts *generateOptions) error {
 		return fmt.Errorf("errors launching plugins: %w", err)
 	}
 
-	err = actions.GeneratePolicy(cmd.Context(), inputContext, plugins)
+	ctx, span := trace.Start(cmd.Context(), "generate policy")
+	err = actions.GeneratePolicy(ctx, inputContext, plugins)
+	span.RecordError(err)
+	span.End()
 	if err != nil {
 		return err
 	}
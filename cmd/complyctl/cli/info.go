@@ -136,53 +136,77 @@ func runInfo(opts *infoOptions) error {
 		return fmt.Errorf("failed to initialize application directory: %w", err)
 	}
 	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
+	contentPath := opts.ContentPath()
+	logger.Debug(fmt.Sprintf("Using content path: %s", contentPath))
 
-	validator := validation.NewSchemaValidator()
-
-	compDefs, err := complytime.FindComponentDefinitions(appDir.BundleDir(), validator)
+	index, err := complytime.LoadContentIndex(contentPath, validation.NewSchemaValidator())
 	if err != nil {
 		return fmt.Errorf("failed to find component definitions: %w", err)
 	}
 
-	frameworkComponents, validationComponents := loadComponents(compDefs, opts.complyTimeOpts.FrameworkID)
+	frameworkComponents := loadComponents(index.ComponentDefinitions, opts.complyTimeOpts.FrameworkID)
 	if len(frameworkComponents) == 0 {
 		return fmt.Errorf("no components found for framework ID '%s'", opts.complyTimeOpts.FrameworkID)
 	}
 
-	rulePlugins := extractRulePluginMapping(validationComponents)
+	rulePlugins := rulePluginMap(index.RulePlugins)
 
 	ruleRemarks, remarksProps := processComponentProperties(frameworkComponents)
 
-	indexedControls, indexedSetParameters := processControlImplementations(frameworkComponents, rulePlugins, appDir, validator)
+	indexedControls := processControlImplementations(frameworkComponents, rulePlugins, index.Controls[opts.complyTimeOpts.FrameworkID])
+	indexedSetParameters := indexedSetParameters(index.Parameters[opts.complyTimeOpts.FrameworkID])
+
+	// Fall back to plain output when not writing to a terminal
+	if !opts.plain && !terminal.IsTerminal(opts.Out) {
+		opts.plain = true
+	}
+
+	rules := ruleLookup{
+		ruleRemarks:  ruleRemarks,
+		remarksProps: remarksProps,
+		rulePlugins:  rulePlugins,
+	}
 
 	// Display info based on controlID or ruleID flag being passed at CLI
 	if opts.controlID != "" {
-		return displayControlInfo(opts, indexedControls)
+		return displayControlInfo(opts, indexedControls, rules, indexedSetParameters)
 	} else if opts.ruleID != "" {
-		return displayRuleInfo(opts, opts.ruleID, ruleRemarks, remarksProps, indexedSetParameters)
+		return displayRuleInfo(opts, opts.ruleID, rules, indexedSetParameters)
 	} else {
-		return displayAllControls(opts, indexedControls)
+		return displayAllControls(opts, indexedControls, rules, indexedSetParameters)
 	}
 }
 
-// extractRulePluginMapping builds map of rule -> plugins from a list of validation components.
-func extractRulePluginMapping(validationComponents []oscalTypes.DefinedComponent) rulePluginMap {
-	var pluginsForRule = make(rulePluginMap)
+// ruleLookup holds the indexed component information needed to
+// resolve details for a rule ID.
+type ruleLookup struct {
+	ruleRemarks  ruleRemarksMap
+	remarksProps remarksPropertiesMap
+	rulePlugins  rulePluginMap
+}
 
-	for _, comp := range validationComponents {
-		for _, prop := range *comp.Props {
-			if prop.Name == extensions.RuleIdProp {
-				pluginsForRule[prop.Value] = comp.Title
-			}
-		}
+// find returns the rule details for the given rule ID.
+func (r ruleLookup) find(ruleID string) (rule, error) {
+	remarksForRule, ok := r.ruleRemarks[ruleID]
+	if !ok || remarksForRule == "" {
+		return rule{}, fmt.Errorf("rule '%s' remarks not found", ruleID)
+	}
+
+	propsForRule, ok := r.remarksProps[remarksForRule]
+	if !ok || len(propsForRule) == 0 {
+		return rule{}, fmt.Errorf("properties for rule '%s' (remarks '%s') not found", ruleID, remarksForRule)
 	}
-	return pluginsForRule
+
+	ruleDetails := extractRuleDetails(propsForRule)
+	ruleDetails.ID = ruleID // Ensure ID is set for consistency
+	ruleDetails.Plugin = r.rulePlugins[ruleID]
+	return ruleDetails, nil
 }
 
-// processControlImplementations extracts control details and set parameters from component definitions.
-func processControlImplementations(components []oscalTypes.DefinedComponent, rulePluginsMap rulePluginMap, appDir complytime.ApplicationDirectory, validator *validation.SchemaValidator) (indexedControls, indexedSetParameters) {
+// processControlImplementations extracts control details from component definitions. Control titles
+// are looked up in the indexed titles of the framework controls.
+func processControlImplementations(components []oscalTypes.DefinedComponent, rulePluginsMap rulePluginMap, controlTitles map[string]string) indexedControls {
 	controlMap := make(indexedControls)
-	setParameters := make(indexedSetParameters)
 
 	for _, comp := range components {
 
@@ -196,9 +220,9 @@ func processControlImplementations(components []oscalTypes.DefinedComponent, rul
 					controlDetails, ok := controlMap[ir.ControlId]
 					if !ok {
 						// Initialize controlDetails if not already present
-						controlTitle, err := getControlTitle(ir.ControlId, controlImp, appDir, validator)
-						if err != nil {
-							logger.Warn("could not get title for control %s: %v", ir.ControlId, err)
+						controlTitle, found := controlTitles[ir.ControlId]
+						if !found {
+							logger.Warn(fmt.Sprintf("could not get title for control %s", ir.ControlId))
 							controlTitle = "N/A"
 						}
 
@@ -226,18 +250,9 @@ func processControlImplementations(components []oscalTypes.DefinedComponent, rul
 					controlMap[ir.ControlId] = controlDetails // Update map with potentially modified control
 				}
 			}
-
-			// Process set parameters
-			if controlImp.SetParameters != nil {
-				for _, sp := range *controlImp.SetParameters {
-					if sp.ParamId != "" && len(sp.Values) > 0 {
-						setParameters[sp.ParamId] = sp.Values
-					}
-				}
-			}
 		}
 	}
-	return controlMap, setParameters
+	return controlMap
 }
 
 // processComponentProperties extracts rule and property information from
@@ -260,45 +275,10 @@ func processComponentProperties(compDefs []oscalTypes.DefinedComponent) (ruleRem
 	return ruleRemarksMap, remarksPropsMap
 }
 
-// getControlTitle retrieves the title for a given control ID from the associated catalog.
-func getControlTitle(controlID string, controlImplementation oscalTypes.ControlImplementationSet, appDir complytime.ApplicationDirectory, validator *validation.SchemaValidator) (string, error) {
-	profile, err := complytime.LoadProfile(appDir, controlImplementation.Source, validator)
-	if err != nil {
-		return "", fmt.Errorf("failed to load profile from source '%s': %w", controlImplementation.Source, err)
-	}
-
-	if profile.Imports == nil {
-		return "", fmt.Errorf("profile '%s' has no imports", controlImplementation.Source)
-	}
-
-	for _, imp := range profile.Imports {
-		catalog, err := complytime.LoadCatalogSource(appDir, imp.Href, validator)
-		if err != nil {
-			logger.Warn("failed to load catalog from href '%s': %v", imp.Href, err)
-			continue
-		}
-		if catalog.Groups == nil {
-			continue
-		}
-		for _, group := range *catalog.Groups {
-			if group.Controls == nil {
-				continue
-			}
-			for _, control := range *group.Controls {
-				if control.ID == controlID && control.Title != "" {
-					return control.Title, nil
-				}
-			}
-		}
-	}
-	return "", fmt.Errorf("title for control '%s' not found in catalog", controlID)
-}
-
-// loadComponents retrieves components from component definitions by framework ID.
-func loadComponents(componentDefinitions []oscalTypes.ComponentDefinition, frameworkID string) ([]oscalTypes.DefinedComponent, []oscalTypes.DefinedComponent) {
+// loadComponents retrieves the target components implementing the framework ID from component definitions.
+func loadComponents(componentDefinitions []oscalTypes.ComponentDefinition, frameworkID string) []oscalTypes.DefinedComponent {
 
 	var frameworkComponents []oscalTypes.DefinedComponent
-	var validationComponents []oscalTypes.DefinedComponent
 
 	for _, compDef := range componentDefinitions {
 		if compDef.Components == nil {
@@ -306,23 +286,19 @@ func loadComponents(componentDefinitions []oscalTypes.ComponentDefinition, frame
 		}
 
 		for _, component := range *compDef.Components {
-			if component.Type == string(components.Validation) {
-				validationComponents = append(validationComponents, component)
-			} else {
-				if component.ControlImplementations == nil {
-					continue
-				}
-				for _, controlImp := range *component.ControlImplementations {
-					framework, ok := settings.GetFrameworkShortName(controlImp)
-					if ok && framework == frameworkID {
-						frameworkComponents = append(frameworkComponents, component)
-						break // Component belongs to this framework, move to next component
-					}
+			if component.Type == string(components.Validation) || component.ControlImplementations == nil {
+				continue
+			}
+			for _, controlImp := range *component.ControlImplementations {
+				framework, ok := settings.GetFrameworkShortName(controlImp)
+				if ok && framework == frameworkID {
+					frameworkComponents = append(frameworkComponents, component)
+					break // Component belongs to this framework, move to next component
 				}
 			}
 		}
 	}
-	return frameworkComponents, validationComponents
+	return frameworkComponents
 }
 
 // extractRuleDetails parses properties to fill a RuleInfo struct.
@@ -340,9 +316,10 @@ func extractRuleDetails(props []oscalTypes.Property) rule {
 }
 
 // runBubbleTeaProgram executes the model
-func runBubbleTeaProgram(model terminal.Model, output io.Writer) error {
+func runBubbleTeaProgram(model tea.Model, output io.Writer, opts ...tea.ProgramOption) error {
 
-	if _, err := tea.NewProgram(model, tea.WithOutput(output)).Run(); err != nil {
+	opts = append(opts, tea.WithOutput(output))
+	if _, err := tea.NewProgram(model, opts...).Run(); err != nil {
 		return fmt.Errorf("failed to display information: %w", err)
 	}
 	return nil
@@ -528,6 +505,7 @@ func newRuleInfoModel(ruleDetails rule, setParameters indexedSetParameters, rowL
 	headerFields := strings.Join([]string{
 		renderKeyValuePair("Rule ID", ruleDetails.ID),
 		renderKeyValuePair("Rule Description", ruleDetails.Description),
+		renderKeyValuePair("Plugin", ruleDetails.Plugin),
 	}, "\n")
 
 	finalHeaderOutput := infoContainerStyle.Render(headerFields)
@@ -586,7 +564,7 @@ func newControlListModel(controls []control, rowLimit int) terminal.Model {
 }
 
 // displayControlInfo handles displaying information for a specific control.
-func displayControlInfo(opts *infoOptions, controlMap indexedControls) error {
+func displayControlInfo(opts *infoOptions, controlMap indexedControls, rules ruleLookup, setParameters indexedSetParameters) error {
 	control, ok := controlMap[opts.controlID]
 	if !ok {
 		return fmt.Errorf("control '%s' does not exist in workspace", opts.controlID)
@@ -603,43 +581,38 @@ func displayControlInfo(opts *infoOptions, controlMap indexedControls) error {
 		terminal.ShowPlainTable(opts.Out, cols, rows)
 		return nil
 	} else {
-		model := newControlInfoModel(control, opts.limit)
-		return runBubbleTeaProgram(model, opts.Out)
+		browser := newInfoBrowser(nil, rules, setParameters, opts.limit)
+		browser.push(browser.newControlPage(control))
+		return runBubbleTeaProgram(browser, opts.Out, tea.WithAltScreen())
 	}
 
 }
 
 // displayRuleInfo handles displaying information for a specific rule.
-func displayRuleInfo(opts *infoOptions, ruleID string, ruleRemarksMap ruleRemarksMap, remarksPropsMap remarksPropertiesMap, setParameters indexedSetParameters) error {
-	remarksForRule, ok := ruleRemarksMap[ruleID]
-	if !ok || remarksForRule == "" {
-		return fmt.Errorf("rule '%s' remarks not found", ruleID)
-	}
-
-	propsForRule, ok := remarksPropsMap[remarksForRule]
-	if !ok || len(propsForRule) == 0 {
-		return fmt.Errorf("properties for rule '%s' (remarks '%s') not found", ruleID, remarksForRule)
+func displayRuleInfo(opts *infoOptions, ruleID string, rules ruleLookup, setParameters indexedSetParameters) error {
+	ruleDetails, err := rules.find(ruleID)
+	if err != nil {
+		return err
 	}
 
-	ruleDetails := extractRuleDetails(propsForRule)
-	ruleDetails.ID = ruleID // Ensure ID is set for consistency
-
 	if opts.plain {
 		_, _ = fmt.Fprintf(opts.Out, "Rule ID: %s \n", ruleDetails.ID)
 		_, _ = fmt.Fprintf(opts.Out, "Rule Description: %s \n", ruleDetails.Description)
+		_, _ = fmt.Fprintf(opts.Out, "Plugin: %s \n", ruleDetails.Plugin)
 		_, _ = fmt.Fprintln(opts.Out)
 		cols, rows := getRuleParametersColumnsAndRows(ruleDetails, setParameters)
 		terminal.ShowPlainTable(opts.Out, cols, rows)
 		return nil
 	} else {
-		model := newRuleInfoModel(ruleDetails, setParameters, opts.limit)
-		return runBubbleTeaProgram(model, opts.Out)
+		browser := newInfoBrowser(nil, rules, setParameters, opts.limit)
+		browser.push(browser.newRulePage(ruleDetails))
+		return runBubbleTeaProgram(browser, opts.Out, tea.WithAltScreen())
 	}
 
 }
 
 // displayAllControls handles displaying a list controls in the framework.
-func displayAllControls(opts *infoOptions, indexedControls indexedControls) error {
+func displayAllControls(opts *infoOptions, indexedControls indexedControls, rules ruleLookup, setParameters indexedSetParameters) error {
 	var controls []control
 	for _, control := range indexedControls {
 		controls = append(controls, control)
@@ -650,8 +623,9 @@ func displayAllControls(opts *infoOptions, indexedControls indexedControls) erro
 		terminal.ShowPlainTable(opts.Out, cols, rows)
 		return nil
 	} else {
-		model := newControlListModel(controls, opts.limit)
-		return runBubbleTeaProgram(model, opts.Out)
+		browser := newInfoBrowser(controls, rules, setParameters, opts.limit)
+		browser.push(browser.newControlListPage())
+		return runBubbleTeaProgram(browser, opts.Out, tea.WithAltScreen())
 	}
 }
 
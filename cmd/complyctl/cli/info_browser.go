@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/complytime/complyctl/internal/terminal"
+)
+
+// infoPageKind identifies the information shown on an info browser page.
+type infoPageKind int
+
+const (
+	controlListPage infoPageKind = iota
+	controlPage
+	rulePage
+)
+
+// minBrowserTableHeight is the smallest table height, including the header
+// row, used when fitting pages to the terminal.
+const minBrowserTableHeight = 3
+
+var _ tea.Model = (*infoBrowser)(nil)
+
+// infoPage is a single view in the info browser navigation stack.
+type infoPage struct {
+	kind    infoPageKind
+	model   terminal.Model
+	control control
+}
+
+// infoBrowser is an interactive Bubble Tea model to navigate from the
+// controls of a framework to their rules and rule parameters.
+type infoBrowser struct {
+	controls      []control
+	rules         ruleLookup
+	setParameters indexedSetParameters
+	rowLimit      int
+
+	// pages is the navigation stack, the last page is displayed.
+	pages []infoPage
+	// searching is set while the user types a search query.
+	searching bool
+	// query filters the control list by control ID or title.
+	query string
+	// height is the terminal height, zero until known.
+	height int
+}
+
+// newInfoBrowser returns an infoBrowser without pages.
+func newInfoBrowser(controls []control, rules ruleLookup, setParameters indexedSetParameters, rowLimit int) *infoBrowser {
+	return &infoBrowser{
+		controls:      controls,
+		rules:         rules,
+		setParameters: setParameters,
+		rowLimit:      rowLimit,
+	}
+}
+
+func (b *infoBrowser) Init() tea.Cmd { return nil }
+
+func (b *infoBrowser) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		b.height = msg.Height
+		for i := range b.pages {
+			b.fit(&b.pages[i])
+		}
+		return b, nil
+	case tea.KeyMsg:
+		if b.searching {
+			b.updateSearch(msg)
+			return b, nil
+		}
+		if terminal.IsQuitKey(msg) {
+			return b, tea.Quit
+		}
+		switch msg.String() {
+		case "/":
+			if b.current().kind == controlListPage {
+				b.searching = true
+			}
+			return b, nil
+		case "enter":
+			b.selectRow()
+			return b, nil
+		case "esc", "backspace":
+			if len(b.pages) > 1 {
+				b.pages = b.pages[:len(b.pages)-1]
+			} else if b.query != "" {
+				b.query = ""
+				b.refreshControlList()
+			}
+			return b, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	page := b.current()
+	page.model.Table, cmd = page.model.Table.Update(msg)
+	return b, cmd
+}
+
+func (b *infoBrowser) View() string {
+	view := b.current().model.View()
+	if b.searching || b.query != "" {
+		cursor := ""
+		if b.searching {
+			cursor = "_"
+		}
+		view += fmt.Sprintf("Search: %s%s\n", b.query, cursor)
+	}
+	return view
+}
+
+// current returns the displayed page.
+func (b *infoBrowser) current() *infoPage {
+	return &b.pages[len(b.pages)-1]
+}
+
+// push displays a new page on top of the navigation stack.
+func (b *infoBrowser) push(page infoPage) {
+	b.fit(&page)
+	b.pages = append(b.pages, page)
+}
+
+// updateSearch edits the search query and refreshes the control list.
+func (b *infoBrowser) updateSearch(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		b.searching = false
+		return
+	case tea.KeyEsc:
+		b.searching = false
+		b.query = ""
+	case tea.KeyBackspace:
+		if query := []rune(b.query); len(query) > 0 {
+			b.query = string(query[:len(query)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		b.query += string(msg.Runes)
+	default:
+		return
+	}
+	b.refreshControlList()
+}
+
+// refreshControlList replaces the displayed control list with one
+// matching the current search query.
+func (b *infoBrowser) refreshControlList() {
+	page := b.newControlListPage()
+	b.fit(&page)
+	*b.current() = page
+}
+
+// selectRow opens the details of the selected table row.
+func (b *infoBrowser) selectRow() {
+	page := b.current()
+	row := page.model.Table.SelectedRow()
+	if len(row) == 0 {
+		return
+	}
+	switch page.kind {
+	case controlListPage:
+		for _, control := range b.controls {
+			if control.ID == row[0] {
+				b.push(b.newControlPage(control))
+				return
+			}
+		}
+	case controlPage:
+		for _, controlRule := range page.control.Rules {
+			if controlRule.ID != row[0] {
+				continue
+			}
+			ruleDetails, err := b.rules.find(controlRule.ID)
+			if err != nil {
+				// Show what is known about the rule from the control
+				ruleDetails = controlRule
+			}
+			b.push(b.newRulePage(ruleDetails))
+			return
+		}
+	}
+}
+
+// filteredControls returns the controls matching the search query.
+func (b *infoBrowser) filteredControls() []control {
+	if b.query == "" {
+		return b.controls
+	}
+	query := strings.ToLower(b.query)
+	var filtered []control
+	for _, control := range b.controls {
+		if strings.Contains(strings.ToLower(control.ID), query) || strings.Contains(strings.ToLower(control.Title), query) {
+			filtered = append(filtered, control)
+		}
+	}
+	return filtered
+}
+
+func (b *infoBrowser) newControlListPage() infoPage {
+	controls := b.filteredControls()
+	model := newControlListModel(controls, b.rowLimit)
+	model.HelpMsg = fmt.Sprintf("%d of %d controls • ↑/↓ scroll • / search • enter view rules • q quit", len(controls), len(b.controls))
+	return infoPage{kind: controlListPage, model: model}
+}
+
+func (b *infoBrowser) newControlPage(control control) infoPage {
+	model := newControlInfoModel(control, b.rowLimit)
+	model.HelpMsg = fmt.Sprintf("%d rules • ↑/↓ scroll • enter view rule • esc back • q quit", len(control.Rules))
+	return infoPage{kind: controlPage, model: model, control: control}
+}
+
+func (b *infoBrowser) newRulePage(ruleDetails rule) infoPage {
+	model := newRuleInfoModel(ruleDetails, b.setParameters, b.rowLimit)
+	helpMsg := fmt.Sprintf("%d parameters", len(ruleDetails.Parameters))
+	if len(ruleDetails.Parameters) == 0 {
+		helpMsg = model.HelpMsg
+	}
+	model.HelpMsg = helpMsg + " • ↑/↓ scroll • esc back • q quit"
+	return infoPage{kind: rulePage, model: model}
+}
+
+// fit limits the table height of the page to the terminal height.
+func (b *infoBrowser) fit(page *infoPage) {
+	if b.height == 0 {
+		return
+	}
+	// Reserve lines for the table border, help message, and search query.
+	maxHeight := b.height - lipgloss.Height(page.model.HeaderMsg) - 4
+	if maxHeight < minBrowserTableHeight {
+		maxHeight = minBrowserTableHeight
+	}
+	if lipgloss.Height(page.model.Table.View()) > maxHeight {
+		page.model.Table.SetHeight(maxHeight)
+	}
+}
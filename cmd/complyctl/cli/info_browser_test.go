@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfoBrowser(t *testing.T) {
+	controls := []control{
+		{ID: "ac-1", Title: "Access Control Policy", Rules: []rule{{ID: "rule-1", Plugin: "openscap"}}},
+		{ID: "au-1", Title: "Audit Policy", Rules: []rule{{ID: "rule-2", Plugin: "openscap"}}},
+	}
+	rules := ruleLookup{
+		ruleRemarks: ruleRemarksMap{"rule-1": "rule_set_1"},
+		remarksProps: remarksPropertiesMap{
+			"rule_set_1": []oscalTypes.Property{
+				{Name: "Rule_Id", Value: "rule-1", Remarks: "rule_set_1"},
+				{Name: "Rule_Description", Value: "Rule 1", Remarks: "rule_set_1"},
+				{Name: "Parameter_Id", Value: "param-1", Remarks: "rule_set_1"},
+			},
+		},
+		rulePlugins: rulePluginMap{"rule-1": "openscap"},
+	}
+	setParameters := indexedSetParameters{"param-1": []string{"value-1"}}
+
+	browser := newInfoBrowser(controls, rules, setParameters, 0)
+	browser.push(browser.newControlListPage())
+
+	keyPress := func(msg tea.KeyMsg) {
+		_, _ = browser.Update(msg)
+	}
+	runes := func(text string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)}
+	}
+
+	// Search for the audit control
+	keyPress(runes("/"))
+	require.True(t, browser.searching)
+	keyPress(runes("audit"))
+	keyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	require.False(t, browser.searching)
+	require.Equal(t, "audit", browser.query)
+	require.Len(t, browser.current().model.Table.Rows(), 1)
+	require.Contains(t, browser.View(), "Search: audit")
+
+	// Clear the search
+	keyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	require.Equal(t, "", browser.query)
+	require.Len(t, browser.current().model.Table.Rows(), 2)
+
+	// Select the first control, then its rule
+	keyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, controlPage, browser.current().kind)
+	require.Equal(t, "ac-1", browser.current().control.ID)
+
+	keyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, rulePage, browser.current().kind)
+	require.Contains(t, browser.View(), "param-1")
+	require.Contains(t, browser.View(), "value-1")
+	require.Contains(t, browser.View(), "openscap")
+
+	// Navigate back to the control list
+	keyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	require.Equal(t, controlPage, browser.current().kind)
+	keyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	require.Equal(t, controlListPage, browser.current().kind)
+
+	// Fit tables to a small terminal
+	keyPress(tea.KeyMsg{Type: tea.KeyDown})
+	_, _ = browser.Update(tea.WindowSizeMsg{Width: 80, Height: 5})
+	require.Equal(t, minBrowserTableHeight, lipgloss.Height(browser.current().model.Table.View()))
+
+	_, cmd := browser.Update(runes("q"))
+	require.NotNil(t, cmd)
+	require.Equal(t, tea.Quit(), cmd())
+}
@@ -49,17 +49,24 @@ func runList(opts *listOptions) error {
 		return err
 	}
 	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
+	contentPath := opts.ContentPath()
+	logger.Debug(fmt.Sprintf("Using content path: %s", contentPath))
 
-	validator := validation.NewSchemaValidator()
-	frameworks, err := complytime.LoadFrameworks(appDir, validator)
+	index, err := complytime.LoadContentIndex(contentPath, validation.NewSchemaValidator())
 	if err != nil {
 		return err
 	}
+	frameworks := index.Frameworks
 
 	if opts.plain {
 		showDefinitionTable(opts.Out, frameworks)
 	} else {
 		model := showPrettyDefinitionTable(frameworks)
+		// Scroll the table until the user quits when writing to a terminal
+		if terminal.IsTerminal(opts.Out) {
+			model.Interactive = true
+			model.HelpMsg += "\nUse the arrow keys to scroll, q to quit."
+		}
 		if _, err := tea.NewProgram(model, tea.WithOutput(opts.Out)).Run(); err != nil {
 			return fmt.Errorf("failed to display component list: %w", err)
 		}
@@ -105,7 +112,7 @@ func showPrettyDefinitionTable(frameworks []complytime.Framework) terminal.Model
 func getDefinitionColumnsAndRows(frameworks []complytime.Framework) ([]table.Column, []table.Row) {
 	var rows []table.Row
 	for _, framework := range frameworks {
-		row := table.Row{framework.Title, framework.ID, strings.Join(framework.SupportedComponents, ", ")}
+		row := table.Row{framework.Title, framework.ID, strings.Join(framework.SupportedComponents, ", "), strings.Join(framework.Roots, ", ")}
 		rows = append(rows, row)
 	}
 	// Sort the rows slice by the framework short name
@@ -116,6 +123,7 @@ func getDefinitionColumnsAndRows(frameworks []complytime.Framework) ([]table.Col
 		{Title: "Title", Width: 30},
 		{Title: "Framework ID", Width: 20},
 		{Title: "Supported Components", Width: 30},
+		{Title: "Content Root", Width: 30},
 	}
 
 	// Calculate column width based on rows
@@ -25,11 +25,13 @@ func TestShowDefinitionTable(t *testing.T) {
 					ID:                  "anotherexample",
 					Title:               "Example Profile (moderate)",
 					SupportedComponents: []string{"My Software"},
+					Roots:               []string{"/usr/share/complytime"},
 				},
 				{
 					ID:                  "example",
 					Title:               "Example Profile (low)",
 					SupportedComponents: []string{"My Software"},
+					Roots:               []string{"/usr/share/complytime", "/etc/complytime"},
 				},
 			},
 			wantView: plainTable,
@@ -41,11 +43,13 @@ func TestShowDefinitionTable(t *testing.T) {
 					ID:                  "anotherexample",
 					Title:               "Example Profile (moderate)",
 					SupportedComponents: []string{"My Software"},
+					Roots:               []string{"/usr/share/complytime"},
 				},
 				{
 					ID:                  "example",
 					Title:               "Example Profile (low)",
 					SupportedComponents: []string{"My Software"},
+					Roots:               []string{"/usr/share/complytime", "/etc/complytime"},
 				},
 			},
 			pretty:   true,
@@ -88,45 +92,45 @@ func TestShowDefinitionTable(t *testing.T) {
 }
 
 var (
-	emptyTable = `┌──────────────────────────────────────────────────────────────────────────────────────┐
-│ Title                           Framework ID          Supported Components           │
-│──────────────────────────────────────────────────────────────────────────────────────│
-│                                                                                      │
-│                                                                                      │
-│                                                                                      │
-│                                                                                      │
-│                                                                                      │
-│                                                                                      │
-└──────────────────────────────────────────────────────────────────────────────────────┘
+	emptyTable = `┌──────────────────────────────────────────────────────────────────────────────────────────────────────────────────────┐
+│ Title                           Framework ID          Supported Components            Content Root                   │
+│──────────────────────────────────────────────────────────────────────────────────────────────────────────────────────│
+│                                                                                                                      │
+│                                                                                                                      │
+│                                                                                                                      │
+│                                                                                                                      │
+│                                                                                                                      │
+│                                                                                                                      │
+└──────────────────────────────────────────────────────────────────────────────────────────────────────────────────────┘
 Choose an option from the Framework ID column to use with complyctl plan.
 `
-	populatedTable = `┌──────────────────────────────────────────────────────────────────────────────────────┐
-│ Title                           Framework ID          Supported Components           │
-│──────────────────────────────────────────────────────────────────────────────────────│
-│ Example Profile (moderate)      anotherexample        My Software                    │
-│ Example Profile (low)           example               My Software                    │
-│                                                                                      │
-│                                                                                      │
-│                                                                                      │
-│                                                                                      │
-└──────────────────────────────────────────────────────────────────────────────────────┘
+	populatedTable = `┌──────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────┐
+│ Title                           Framework ID          Supported Components            Content Root                           │
+│──────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────│
+│ Example Profile (moderate)      anotherexample        My Software                     /usr/share/complytime                  │
+│ Example Profile (low)           example               My Software                     /usr/share/complytime, /etc/complytime │
+│                                                                                                                              │
+│                                                                                                                              │
+│                                                                                                                              │
+│                                                                                                                              │
+└──────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────┘
 Choose an option from the Framework ID column to use with complyctl plan.
 `
 
-	longTitle = `┌──────────────────────────────────────────────────────────────────────────────────────────────────────┐
-│ Title                                           Framework ID          Supported Components           │
-│──────────────────────────────────────────────────────────────────────────────────────────────────────│
-│ This is a very very very long title (moderate)  anotherexample        My Software                    │
-│                                                                                                      │
-│                                                                                                      │
-│                                                                                                      │
-│                                                                                                      │
-│                                                                                                      │
-└──────────────────────────────────────────────────────────────────────────────────────────────────────┘
+	longTitle = `┌──────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────┐
+│ Title                                           Framework ID          Supported Components            Content Root                   │
+│──────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────│
+│ This is a very very very long title (moderate)  anotherexample        My Software                                                    │
+│                                                                                                                                      │
+│                                                                                                                                      │
+│                                                                                                                                      │
+│                                                                                                                                      │
+│                                                                                                                                      │
+└──────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────┘
 Choose an option from the Framework ID column to use with complyctl plan.
 `
 
-	plainTable = "Title                         Framework ID        Supported Components          \n" +
-		"Example Profile (moderate)    anotherexample      My Software                   \n" +
-		"Example Profile (low)         example             My Software                   \n"
+	plainTable = "Title                         Framework ID        Supported Components          Content Root                          \n" +
+		"Example Profile (moderate)    anotherexample      My Software                   /usr/share/complytime                 \n" +
+		"Example Profile (low)         example             My Software                   /usr/share/complytime, /etc/complytime\n"
 )
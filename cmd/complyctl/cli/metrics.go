@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/expfmt"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/metrics"
+)
+
+// metricsOptions defines options for the "metrics" subcommand
+type metricsOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+
+	// textfile is the path of a file to write metrics to for the
+	// node_exporter textfile collector
+	textfile string
+
+	// listen is the address to serve the /metrics endpoint on
+	listen string
+}
+
+var metricsExample = `
+# Print compliance posture metrics for the latest scan in the workspace.
+complyctl metrics
+
+# Write metrics for the node_exporter textfile collector.
+complyctl metrics --textfile /var/lib/node_exporter/textfile_collector/complyctl.prom
+
+# Serve metrics on http://localhost:9469/metrics.
+complyctl metrics --listen localhost:9469
+`
+
+// metricsCmd creates a new cobra.Command for the "metrics" subcommand
+func metricsCmd(common *option.Common) *cobra.Command {
+	metricsOpts := &metricsOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:          "metrics [flags]",
+		Short:        "Expose compliance posture from the latest scan as Prometheus metrics",
+		Example:      metricsExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := validateMetrics(metricsOpts); err != nil {
+				return err
+			}
+			return runMetrics(cmd.Context(), metricsOpts)
+		},
+	}
+	cmd.Flags().StringVar(&metricsOpts.textfile, "textfile", "", "write metrics to a file for the node_exporter textfile collector")
+	cmd.Flags().StringVar(&metricsOpts.listen, "listen", "", "serve metrics on the /metrics endpoint at the given address")
+	metricsOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func validateMetrics(opts *metricsOptions) error {
+	if opts.textfile != "" && opts.listen != "" {
+		return errors.New("invalid command flags: \"--textfile\" and \"--listen\" cannot be used together")
+	}
+	return nil
+}
+
+func runMetrics(ctx context.Context, opts *metricsOptions) error {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.NewCollector(workspaceMetricsLoader(opts.complyTimeOpts)))
+
+	switch {
+	case opts.textfile != "":
+		if err := prometheus.WriteToTextfile(filepath.Clean(opts.textfile), registry); err != nil {
+			return fmt.Errorf("error writing metrics to %s: %w", opts.textfile, err)
+		}
+		logger.Info(fmt.Sprintf("Metrics written to %s", opts.textfile))
+		return nil
+	case opts.listen != "":
+		return serveMetrics(ctx, opts.listen, registry)
+	default:
+		return writeMetrics(opts.Out, registry)
+	}
+}
+
+// workspaceMetricsLoader returns a metrics.Loader reading the assessment plan, results,
+// and scan status from the workspace.
+func workspaceMetricsLoader(opts *option.ComplyTime) metrics.Loader {
+	return func() (metrics.Source, error) {
+		validator := validation.NoopValidator{}
+		ap, _, err := loadPlan(opts, validator)
+		if err != nil {
+			return metrics.Source{}, err
+		}
+		source := metrics.Source{Plan: ap}
+
+		arPath := filepath.Join(opts.UserWorkspace, assessmentResultsLocationJson)
+		source.Results, err = complytime.ReadAssessmentResults(arPath, validator)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return metrics.Source{}, err
+		}
+
+		statusPath := filepath.Join(opts.UserWorkspace, scanStatusLocation)
+		source.Status, err = complytime.ReadScanStatus(statusPath)
+		if err != nil {
+			return metrics.Source{}, err
+		}
+		return source, nil
+	}
+}
+
+// writeMetrics writes all gathered metrics in the Prometheus text format.
+func writeMetrics(out io.Writer, gatherer prometheus.Gatherer) error {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("error gathering metrics: %w", err)
+	}
+	encoder := expfmt.NewEncoder(out, expfmt.NewFormat(expfmt.TypeTextPlain))
+	for _, family := range families {
+		if err := encoder.Encode(family); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// serveMetrics serves the /metrics endpoint until the context is cancelled.
+func serveMetrics(ctx context.Context, address string, gatherer prometheus.Gatherer) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+	server := &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info(fmt.Sprintf("Serving metrics on http://%s/metrics", address))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
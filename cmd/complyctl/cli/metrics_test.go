@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/metrics"
+)
+
+func TestValidateMetrics(t *testing.T) {
+	require.NoError(t, validateMetrics(&metricsOptions{textfile: "complyctl.prom"}))
+	require.NoError(t, validateMetrics(&metricsOptions{listen: "localhost:9469"}))
+	err := validateMetrics(&metricsOptions{textfile: "complyctl.prom", listen: "localhost:9469"})
+	require.EqualError(t, err, "invalid command flags: \"--textfile\" and \"--listen\" cannot be used together")
+}
+
+func TestWorkspaceMetricsLoader(t *testing.T) {
+	// The test workspace has a plan, but no results or scan status.
+	source, err := workspaceMetricsLoader(&option.ComplyTime{UserWorkspace: "testdata"})()
+	require.NoError(t, err)
+	require.NotNil(t, source.Plan)
+	require.Nil(t, source.Results)
+	require.Nil(t, source.Status)
+
+	_, err = workspaceMetricsLoader(&option.ComplyTime{UserWorkspace: "doesnotexist"})()
+	require.ErrorContains(t, err, "Did you run the plan command?")
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.NewCollector(workspaceMetricsLoader(&option.ComplyTime{UserWorkspace: "testdata"})))
+	out := bytes.NewBuffer(nil)
+	require.NoError(t, writeMetrics(out, registry))
+	// The test plan records no framework, so it has no posture metrics.
+	require.NotContains(t, out.String(), "complyctl_framework_rules")
+	require.NotContains(t, out.String(), "complyctl_control_rules")
+}
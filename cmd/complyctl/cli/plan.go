@@ -7,31 +7,47 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/goccy/go-yaml"
-	"github.com/oscal-compass/oscal-sdk-go/transformers"
 	"github.com/oscal-compass/oscal-sdk-go/validation"
 	"github.com/spf13/cobra"
 
 	"github.com/complytime/complyctl/cmd/complyctl/option"
 	"github.com/complytime/complyctl/internal/complytime"
 	"github.com/complytime/complyctl/internal/complytime/plan"
+	"github.com/complytime/complyctl/internal/terminal"
 )
 
-const assessmentPlanLocation = "assessment-plan.json"
+const (
+	assessmentPlanLocation = "assessment-plan.json"
+	scopeConfigLocation    = "config.yml"
+	planLockLocation       = "plan-lock.json"
+)
 
 // PlanOptions defines options for the "plan" subcommand
 type planOptions struct {
 	*option.Common
 	complyTimeOpts *option.ComplyTime
 
+	// frameworkIDs are the frameworks covered by the assessment plan
+	frameworkIDs []string
+
 	// dryRun loads the defaults and prints the config to stdout
 	dryRun bool
 
 	// WithScopeConfig "config.yml" to customize the generated assessment plan
 	withScopeConfig string
 
+	// interactive opens the scope editor before generating the assessment plan
+	interactive bool
+
+	// locked regenerates the assessment plan from the plan lock in the workspace
+	locked bool
+
 	// Out
 	output string
 }
@@ -48,6 +64,16 @@ complytime plan myframework --dry-run --out config.yml
 
 # Alter the configuration and use it as input for plan customization.
 complytime plan myframework --scope-config config.yml
+
+# Select controls, rules, and parameter values in the interactive scope editor.
+# The scope is saved to config.yml in the workspace, or the file given with --scope-config.
+complytime plan myframework --interactive
+
+# Prepare a single assessment plan covering several frameworks. Each rule is assessed once.
+complytime plan myframework otherframework
+
+# Regenerate the assessment plan recorded in plan-lock.json, failing if the content changed.
+complytime plan myframework --locked
 `
 
 // planCmd creates a new cobra.Command for the "plan" subcommand
@@ -57,10 +83,10 @@ func planCmd(common *option.Common) *cobra.Command {
 		complyTimeOpts: &option.ComplyTime{},
 	}
 	cmd := &cobra.Command{
-		Use:     "plan [flags] id",
-		Short:   "Generate a new assessment plan for a given compliance framework id.",
+		Use:     "plan [flags] id [id...]",
+		Short:   "Generate a new assessment plan for one or more compliance framework ids.",
 		Example: planExample,
-		Args:    cobra.ExactArgs(1),
+		Args:    cobra.MinimumNArgs(1),
 		PreRun: func(cmd *cobra.Command, args []string) {
 			completePlan(planOpts, args)
 		},
@@ -71,20 +97,40 @@ func planCmd(common *option.Common) *cobra.Command {
 			return runPlan(cmd, planOpts)
 		},
 	}
+	cmd.AddCommand(
+		planRefreshCmd(common),
+		planValidateCmd(common),
+	)
 	cmd.Flags().BoolVar(&planOpts.dryRun, "dry-run", false, "load the defaults and print the config to stdout")
 	cmd.Flags().StringVarP(&planOpts.withScopeConfig, "scope-config", "s", "", "load config.yml to customize the generated assessment plan")
+	cmd.Flags().BoolVarP(&planOpts.interactive, "interactive", "i", false, "edit the assessment scope interactively before generating the assessment plan")
+	cmd.Flags().BoolVar(&planOpts.locked, "locked", false, "regenerate the assessment plan from the plan lock in the workspace, failing if the content differs from the lock")
 	cmd.Flags().StringVarP(&planOpts.output, "out", "o", "-", "path to output file. Use '-' for stdout. Default '-'.")
 	planOpts.complyTimeOpts.BindFlags(cmd.Flags())
 	return cmd
 }
 
 func completePlan(opts *planOptions, args []string) {
-	if len(args) == 1 {
-		opts.complyTimeOpts.FrameworkID = filepath.Clean(args[0])
+	for _, arg := range args {
+		opts.frameworkIDs = append(opts.frameworkIDs, filepath.Clean(arg))
+	}
+	if len(opts.frameworkIDs) > 0 {
+		opts.complyTimeOpts.FrameworkID = opts.frameworkIDs[0]
 	}
 }
 
 func validatePlan(opts *planOptions) error {
+	for i, frameworkID := range opts.frameworkIDs {
+		if slices.Contains(opts.frameworkIDs[:i], frameworkID) {
+			return fmt.Errorf("framework %s is given more than once", frameworkID)
+		}
+	}
+	if opts.interactive && opts.dryRun {
+		return errors.New("invalid command flags: \"--interactive\" and \"--dry-run\" cannot be used together")
+	}
+	if opts.locked && (opts.dryRun || opts.interactive || opts.withScopeConfig != "") {
+		return errors.New("invalid command flags: \"--locked\" cannot be used with \"--dry-run\", \"--interactive\", or \"--scope-config\"")
+	}
 	if opts.output != "-" && !opts.dryRun {
 		return errors.New("invalid command flags: \"--dry-run\" must be used with \"--out\"")
 	}
@@ -98,44 +144,117 @@ func runPlan(cmd *cobra.Command, opts *planOptions) error {
 		return err
 	}
 	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
+	contentPath := opts.ContentPath()
 
-	validator := validation.NewSchemaValidator()
-	componentDefs, err := complytime.FindComponentDefinitions(appDir.BundleDir(), validator)
+	index, err := complytime.LoadContentIndex(contentPath, validation.NewSchemaValidator())
 	if err != nil {
 		return err
 	}
+	componentDefs := index.ComponentDefinitions
 
 	if opts.dryRun {
 		// Write the plan configuration to stdout
-		return planDryRun(opts.complyTimeOpts.FrameworkID, componentDefs, opts.output)
+		return planDryRun(opts.frameworkIDs, componentDefs, opts.output)
 	}
 
-	logger.Debug(fmt.Sprintf("Using bundle directory: %s for component definitions.", appDir.BundleDir()))
-	assessmentPlan, err := transformers.ComponentDefinitionsToAssessmentPlan(cmd.Context(), componentDefs, opts.complyTimeOpts.FrameworkID)
+	logger.Debug(fmt.Sprintf("Using content path: %s for component definitions.", contentPath))
+	assessmentPlan, err := plan.NewAssessmentPlan(cmd.Context(), componentDefs, opts.frameworkIDs...)
 	if err != nil {
 		return err
 	}
 
+	if opts.locked {
+		lock, err := checkPlanLock(opts.complyTimeOpts, contentPath, index, opts.frameworkIDs)
+		if err != nil {
+			return err
+		}
+		return writePlanAndLock(opts.complyTimeOpts, assessmentPlan, lock)
+	}
+
+	var assessmentScopes []plan.AssessmentScope
 	if opts.withScopeConfig != "" {
-		configBytes, err := os.ReadFile(filepath.Clean(opts.withScopeConfig))
+		assessmentScopes, err = readScopeConfig(opts.withScopeConfig)
 		if err != nil {
-			return fmt.Errorf("error reading assessment plan: %w", err)
+			return err
 		}
-		assessmentScope := plan.AssessmentScope{}
-		if err := yaml.Unmarshal(configBytes, &assessmentScope); err != nil {
-			return fmt.Errorf("error unmarshaling assessment plan: %w", err)
+		if err := validateScopes(assessmentScopes, opts.frameworkIDs); err != nil {
+			return err
 		}
+	}
+
+	if opts.interactive {
+		editedScopes, err := planInteractive(opts, componentDefs, assessmentPlan, assessmentScopes)
+		if err != nil {
+			return err
+		}
+		if editedScopes == nil {
+			logger.Info("Assessment scope not saved, no assessment plan written")
+			return nil
+		}
+		assessmentScopes = editedScopes
+	}
+
+	lock, err := complytime.NewPlanLock(contentPath, index, opts.frameworkIDs, assessmentScopes)
+	if err != nil {
+		return fmt.Errorf("error locking content for the assessment plan: %w", err)
+	}
+	return writePlanAndLock(opts.complyTimeOpts, assessmentPlan, lock)
+}
+
+// writePlanAndLock writes the assessment plan and the plan lock to the workspace.
+func writePlanAndLock(opts *option.ComplyTime, assessmentPlan *oscalTypes.AssessmentPlan, lock *complytime.PlanLock) error {
+	planPath, err := writeLockedPlan(opts, assessmentPlan, lock)
+	if err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("Assessment plan written to %s\n", planPath))
+	return nil
+}
+
+// checkPlanLock reads the plan lock from the workspace and ensures it locks the given frameworks
+// and the current content of the content path.
+func checkPlanLock(opts *option.ComplyTime, contentPath complytime.ContentPath, index *complytime.ContentIndex, frameworkIDs []string) (*complytime.PlanLock, error) {
+	lockPath := filepath.Join(opts.UserWorkspace, planLockLocation)
+	lock, err := complytime.ReadPlanLock(lockPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("error: plan lock does not exist in workspace %s: %w", opts.UserWorkspace, err)
+		}
+		return nil, err
+	}
+	if !slices.Equal(lock.Frameworks, frameworkIDs) {
+		return nil, fmt.Errorf("plan lock %s is for frameworks %s", lockPath, strings.Join(lock.Frameworks, ", "))
+	}
+	current, err := complytime.NewPlanLock(contentPath, index, lock.Frameworks, lock.Scopes)
+	if err != nil {
+		return nil, fmt.Errorf("error locking content for the assessment plan: %w", err)
+	}
+	if diffs := lock.Diff(current); len(diffs) > 0 {
+		return nil, fmt.Errorf("content differs from plan lock %s:\n  %s", lockPath, strings.Join(diffs, "\n  "))
+	}
+	logger.Debug(fmt.Sprintf("Content matches plan lock %s", lockPath))
+	return lock, nil
+}
+
+// writeLockedPlan applies the scopes of the plan lock to the assessment plan, normalizes the plan with
+// the lock, and writes the plan and the lock to the workspace. The location of the plan is returned.
+func writeLockedPlan(opts *option.ComplyTime, assessmentPlan *oscalTypes.AssessmentPlan, lock *complytime.PlanLock) (string, error) {
+	for _, assessmentScope := range lock.Scopes {
 		assessmentScope.ApplyScope(assessmentPlan, logger)
 	}
+	plan.Normalize(assessmentPlan, lock.Seed(), lock.Generated)
 
-	filePath := filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentPlanLocation)
+	filePath := filepath.Join(opts.UserWorkspace, assessmentPlanLocation)
 	cleanedPath := filepath.Clean(filePath)
 
-	if err := plan.WritePlan(assessmentPlan, opts.complyTimeOpts.FrameworkID, cleanedPath); err != nil {
-		return fmt.Errorf("error writing assessment plan to %s: %w", cleanedPath, err)
+	if err := plan.WritePlan(assessmentPlan, lock.Frameworks, cleanedPath); err != nil {
+		return "", fmt.Errorf("error writing assessment plan to %s: %w", cleanedPath, err)
 	}
-	logger.Info(fmt.Sprintf("Assessment plan written to %s\n", cleanedPath))
-	return nil
+	lockPath := filepath.Join(opts.UserWorkspace, planLockLocation)
+	if err := lock.Write(lockPath); err != nil {
+		return "", fmt.Errorf("error writing plan lock to %s: %w", lockPath, err)
+	}
+	return cleanedPath, nil
 }
 
 // loadPlan returns the loaded assessment plan and path from the workspace.
@@ -154,16 +273,134 @@ func loadPlan(opts *option.ComplyTime, validator validation.Validator) (*oscalTy
 	return assessmentPlan, apCleanedPath, nil
 }
 
-// planDryRun leverages the AssessmentScope structure to populate tailoring config.
-// The config is written to stdout.
-func planDryRun(frameworkId string, cds []oscalTypes.ComponentDefinition, output string) error {
-	scope, err := plan.NewAssessmentScopeFromCDs(frameworkId, cds...)
+// planFrameworks returns the frameworks of the assessment plan and sets the first framework as the
+// workspace framework. Plugins use it as the base profile for the rules of all planned frameworks.
+func planFrameworks(opts *option.ComplyTime, assessmentPlan *oscalTypes.AssessmentPlan) ([]string, error) {
+	frameworkIDs := plan.Frameworks(assessmentPlan)
+	if len(frameworkIDs) == 0 {
+		return nil, errors.New("error reading framework property from assessment plan")
+	}
+	opts.FrameworkID = frameworkIDs[0]
+	logger.Debug(fmt.Sprintf("Framework property was successfully read from the assessment plan: %s.", strings.Join(frameworkIDs, ", ")))
+	return frameworkIDs, nil
+}
+
+// readScopeConfig reads the AssessmentScopes from a scope config file. The file contains a single
+// scope, or a list of scopes when the assessment plan covers several frameworks.
+func readScopeConfig(path string) ([]plan.AssessmentScope, error) {
+	configBytes, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("error reading assessment plan: %w", err)
+	}
+	var assessmentScopes []plan.AssessmentScope
+	if err := yaml.Unmarshal(configBytes, &assessmentScopes); err == nil {
+		return assessmentScopes, nil
+	}
+	assessmentScope := plan.AssessmentScope{}
+	if err := yaml.Unmarshal(configBytes, &assessmentScope); err != nil {
+		return nil, fmt.Errorf("error unmarshaling assessment plan: %w", err)
+	}
+	return []plan.AssessmentScope{assessmentScope}, nil
+}
+
+// marshalScopeConfig returns the scope config for the given AssessmentScopes. A single scope
+// is written as is, so the scope config of single framework plans keeps its format.
+func marshalScopeConfig(assessmentScopes []plan.AssessmentScope) ([]byte, error) {
+	var data []byte
+	var err error
+	if len(assessmentScopes) == 1 {
+		data, err = yaml.Marshal(&assessmentScopes[0])
+	} else {
+		data, err = yaml.Marshal(assessmentScopes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling yaml content: %v", err)
+	}
+	return data, nil
+}
+
+// validateScopes ensures each scope selects a planned framework. A single scope for a single
+// framework plan applies to the plan framework.
+func validateScopes(assessmentScopes []plan.AssessmentScope, frameworkIDs []string) error {
+	if len(frameworkIDs) == 1 && len(assessmentScopes) == 1 {
+		return nil
+	}
+	for _, assessmentScope := range assessmentScopes {
+		if !slices.Contains(frameworkIDs, assessmentScope.FrameworkID) {
+			return fmt.Errorf("scope config framework %q is not one of the planned frameworks", assessmentScope.FrameworkID)
+		}
+	}
+	return nil
+}
+
+// planInteractive opens the scope editor for each planned framework, starting from the given scopes
+// or all controls in scope for frameworks without a scope. The saved scopes are written to the scope
+// config file and returned, or nil is returned when the user quits without saving.
+func planInteractive(opts *planOptions, cds []oscalTypes.ComponentDefinition, assessmentPlan *oscalTypes.AssessmentPlan, scopes []plan.AssessmentScope) ([]plan.AssessmentScope, error) {
+	if !terminal.IsTerminal(opts.Out) {
+		return nil, errors.New("the interactive scope editor requires a terminal")
+	}
+	var editedScopes []plan.AssessmentScope
+	for _, frameworkId := range opts.frameworkIDs {
+		defaultScope, err := plan.NewAssessmentScopeFromCDs(frameworkId, cds...)
+		if err != nil {
+			return nil, fmt.Errorf("error creating assessment scope for %s: %w", frameworkId, err)
+		}
+		scope := defaultScope
+		for _, configScope := range scopes {
+			if configScope.FrameworkID == frameworkId || len(opts.frameworkIDs) == 1 {
+				scope = configScope
+			}
+		}
+		frameworkPlan, err := plan.ForFramework(assessmentPlan, frameworkId)
+		if err != nil {
+			return nil, err
+		}
+
+		editor := newScopeEditor(defaultScope, scope, rulesByControl(complytime.ControlsByRule(frameworkPlan)), plan.ParametersFromPlan(frameworkPlan))
+		if err := runBubbleTeaProgram(editor, opts.Out, tea.WithAltScreen()); err != nil {
+			return nil, err
+		}
+		if !editor.saved {
+			return nil, nil
+		}
+		editedScopes = append(editedScopes, editor.Scope())
+	}
+
+	data, err := marshalScopeConfig(editedScopes)
 	if err != nil {
-		return fmt.Errorf("error creating assessment scope for %s", frameworkId)
+		return nil, err
 	}
-	data, err := yaml.Marshal(&scope)
+	scopePath := filepath.Clean(scopeConfigPath(opts))
+	if err := os.WriteFile(scopePath, data, 0600); err != nil {
+		return nil, fmt.Errorf("error writing assessment scope to %s: %w", scopePath, err)
+	}
+	logger.Info(fmt.Sprintf("Assessment scope written to %s", scopePath))
+	return editedScopes, nil
+}
+
+// scopeConfigPath returns the path where the interactive scope editor saves the scope config.
+func scopeConfigPath(opts *planOptions) string {
+	if opts.withScopeConfig != "" {
+		return opts.withScopeConfig
+	}
+	return filepath.Join(opts.complyTimeOpts.UserWorkspace, scopeConfigLocation)
+}
+
+// planDryRun leverages the AssessmentScope structure to populate tailoring config
+// for each framework. The config is written to stdout.
+func planDryRun(frameworkIDs []string, cds []oscalTypes.ComponentDefinition, output string) error {
+	scopes := make([]plan.AssessmentScope, 0, len(frameworkIDs))
+	for _, frameworkId := range frameworkIDs {
+		scope, err := plan.NewAssessmentScopeFromCDs(frameworkId, cds...)
+		if err != nil {
+			return fmt.Errorf("error creating assessment scope for %s", frameworkId)
+		}
+		scopes = append(scopes, scope)
+	}
+	data, err := marshalScopeConfig(scopes)
 	if err != nil {
-		return fmt.Errorf("error marshalling yaml content: %v", err)
+		return err
 	}
 
 	if output == "-" {
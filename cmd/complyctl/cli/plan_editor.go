@@ -0,0 +1,392 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/complytime/complyctl/internal/complytime/plan"
+	"github.com/complytime/complyctl/internal/terminal"
+)
+
+// scopeEditorPage identifies the items edited on a scope editor page.
+type scopeEditorPage int
+
+const (
+	scopeControlsPage scopeEditorPage = iota
+	scopeRulesPage
+	scopeParametersPage
+)
+
+var _ tea.Model = (*scopeEditor)(nil)
+
+// scopeEditor is an interactive Bubble Tea model to select the controls, rules,
+// and parameter values of an assessment scope.
+type scopeEditor struct {
+	frameworkID    string
+	controlIDs     []string
+	rulesByControl map[string][]string
+	// defaults are the parameter values of the unscoped assessment plan.
+	defaults []plan.ParameterEntry
+
+	includedControls map[string]bool
+	includedRules    map[string]map[string]bool
+	parameterValues  map[string]string
+
+	page scopeEditorPage
+	// control is the control whose rules are displayed on the rules page.
+	control string
+	model   terminal.Model
+	// editing is set while the user types a parameter value.
+	editing bool
+	input   string
+	// saved is set when the user quits by saving the scope.
+	saved  bool
+	height int
+}
+
+// newScopeEditor returns a scopeEditor for all controls of the defaultScope
+// with the selections of the given scope. Rules by control and parameter defaults
+// are taken from the unscoped assessment plan.
+func newScopeEditor(defaultScope, scope plan.AssessmentScope, rulesByControl map[string][]string, defaults []plan.ParameterEntry) *scopeEditor {
+	editor := &scopeEditor{
+		frameworkID:      defaultScope.FrameworkID,
+		rulesByControl:   rulesByControl,
+		defaults:         defaults,
+		includedControls: make(map[string]bool),
+		includedRules:    make(map[string]map[string]bool),
+		parameterValues:  make(map[string]string),
+	}
+	for _, entry := range defaultScope.IncludeControls {
+		editor.controlIDs = append(editor.controlIDs, entry.ControlID)
+		editor.includedRules[entry.ControlID] = make(map[string]bool)
+	}
+	for _, entry := range scope.IncludeControls {
+		if _, found := editor.includedRules[entry.ControlID]; !found {
+			continue
+		}
+		editor.includedControls[entry.ControlID] = true
+		for _, ruleID := range rulesByControl[entry.ControlID] {
+			editor.includedRules[entry.ControlID][ruleID] = entry.IncludesRule(ruleID)
+		}
+	}
+	for _, parameter := range defaults {
+		editor.parameterValues[parameter.Name] = parameter.Value
+	}
+	for _, parameter := range scope.SelectParameters {
+		editor.parameterValues[parameter.Name] = parameter.Value
+	}
+	editor.showPage(scopeControlsPage)
+	return editor
+}
+
+func (e *scopeEditor) Init() tea.Cmd { return nil }
+
+func (e *scopeEditor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		e.height = msg.Height
+		e.fit()
+		return e, nil
+	case tea.KeyMsg:
+		if e.editing {
+			e.updateInput(msg)
+			return e, nil
+		}
+		if terminal.IsQuitKey(msg) {
+			return e, tea.Quit
+		}
+		switch msg.String() {
+		case "s":
+			e.saved = true
+			return e, tea.Quit
+		case " ":
+			e.toggle()
+			return e, nil
+		case "a":
+			e.toggleAll()
+			return e, nil
+		case "tab":
+			if e.page == scopeParametersPage {
+				e.showPage(scopeControlsPage)
+			} else {
+				e.showPage(scopeParametersPage)
+			}
+			return e, nil
+		case "enter":
+			e.selectRow()
+			return e, nil
+		case "esc", "backspace":
+			if e.page == scopeRulesPage {
+				e.showPage(scopeControlsPage)
+			}
+			return e, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	e.model.Table, cmd = e.model.Table.Update(msg)
+	return e, cmd
+}
+
+func (e *scopeEditor) View() string {
+	view := e.model.View()
+	if e.editing {
+		view += fmt.Sprintf("Value: %s_\n", e.input)
+	}
+	return view
+}
+
+// Scope returns the AssessmentScope with the current selections. Parameters
+// are only included when the value differs from the assessment plan default.
+func (e *scopeEditor) Scope() plan.AssessmentScope {
+	scope := plan.NewAssessmentScope(e.frameworkID)
+	for _, controlID := range e.controlIDs {
+		if !e.includedControls[controlID] {
+			continue
+		}
+		rules := e.rulesByControl[controlID]
+		selected := e.selectedRules(controlID)
+		entry := plan.ControlEntry{ControlID: controlID, Rules: selected}
+		if len(selected) == len(rules) {
+			entry.Rules = []string{plan.IncludeAllRules}
+		}
+		scope.IncludeControls = append(scope.IncludeControls, entry)
+	}
+	for _, parameter := range e.defaults {
+		if value := e.parameterValues[parameter.Name]; value != parameter.Value {
+			scope.SelectParameters = append(scope.SelectParameters, plan.ParameterEntry{Name: parameter.Name, Value: value})
+		}
+	}
+	return scope
+}
+
+// selectedRules returns the in-scope rules of a control.
+func (e *scopeEditor) selectedRules(controlID string) []string {
+	selected := []string{}
+	for _, ruleID := range e.rulesByControl[controlID] {
+		if e.includedRules[controlID][ruleID] {
+			selected = append(selected, ruleID)
+		}
+	}
+	return selected
+}
+
+// toggle switches the scope of the selected control or rule.
+func (e *scopeEditor) toggle() {
+	row := e.model.Table.SelectedRow()
+	if len(row) < 2 {
+		return
+	}
+	switch e.page {
+	case scopeControlsPage:
+		e.setControl(row[1], !e.includedControls[row[1]])
+	case scopeRulesPage:
+		e.includedRules[e.control][row[1]] = !e.includedRules[e.control][row[1]]
+		e.includedControls[e.control] = len(e.selectedRules(e.control)) > 0
+	default:
+		return
+	}
+	e.refresh()
+}
+
+// toggleAll includes every item on the page, or excludes every item when
+// all are already included.
+func (e *scopeEditor) toggleAll() {
+	switch e.page {
+	case scopeControlsPage:
+		include := len(e.Scope().IncludeControls) != len(e.controlIDs)
+		for _, controlID := range e.controlIDs {
+			e.setControl(controlID, include)
+		}
+	case scopeRulesPage:
+		e.setControl(e.control, len(e.selectedRules(e.control)) != len(e.rulesByControl[e.control]))
+	default:
+		return
+	}
+	e.refresh()
+}
+
+// setControl includes or excludes a control with all of its rules.
+func (e *scopeEditor) setControl(controlID string, include bool) {
+	e.includedControls[controlID] = include
+	for _, ruleID := range e.rulesByControl[controlID] {
+		e.includedRules[controlID][ruleID] = include
+	}
+}
+
+// selectRow opens the rules of the selected control or starts editing the
+// selected parameter value.
+func (e *scopeEditor) selectRow() {
+	row := e.model.Table.SelectedRow()
+	if len(row) == 0 {
+		return
+	}
+	switch e.page {
+	case scopeControlsPage:
+		e.control = row[1]
+		e.showPage(scopeRulesPage)
+	case scopeParametersPage:
+		e.editing = true
+		e.input = e.parameterValues[row[0]]
+	}
+}
+
+// updateInput edits the value of the selected parameter.
+func (e *scopeEditor) updateInput(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		e.editing = false
+		if row := e.model.Table.SelectedRow(); len(row) > 0 {
+			e.parameterValues[row[0]] = e.input
+		}
+		e.refresh()
+	case tea.KeyEsc:
+		e.editing = false
+	case tea.KeyBackspace:
+		if input := []rune(e.input); len(input) > 0 {
+			e.input = string(input[:len(input)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		e.input += string(msg.Runes)
+	}
+}
+
+// showPage displays the given page with the cursor on the first row.
+func (e *scopeEditor) showPage(page scopeEditorPage) {
+	e.page = page
+	e.model = e.newPageModel()
+	e.fit()
+}
+
+// refresh rebuilds the displayed page, keeping the cursor position.
+func (e *scopeEditor) refresh() {
+	cursor := e.model.Table.Cursor()
+	height := e.model.Table.Height()
+	e.model = e.newPageModel()
+	e.model.Table.SetHeight(height)
+	e.model.Table.SetCursor(cursor)
+}
+
+func (e *scopeEditor) newPageModel() terminal.Model {
+	var (
+		columns []table.Column
+		rows    []table.Row
+		helpMsg string
+	)
+	switch e.page {
+	case scopeControlsPage:
+		columns = []table.Column{
+			{Title: "In Scope", Width: 10},
+			{Title: "Control ID", Width: 20},
+			{Title: "Rules", Width: 10},
+		}
+		for _, controlID := range e.controlIDs {
+			rules := fmt.Sprintf("%d/%d", len(e.selectedRules(controlID)), len(e.rulesByControl[controlID]))
+			rows = append(rows, table.Row{scopeMark(e.includedControls[controlID]), controlID, rules})
+		}
+		helpMsg = "space toggle • a toggle all • enter edit rules • tab parameters • s save • q quit"
+	case scopeRulesPage:
+		columns = []table.Column{
+			{Title: "In Scope", Width: 10},
+			{Title: "Rule ID", Width: 50},
+		}
+		for _, ruleID := range e.rulesByControl[e.control] {
+			rows = append(rows, table.Row{scopeMark(e.includedRules[e.control][ruleID]), ruleID})
+		}
+		helpMsg = fmt.Sprintf("Rules for %s • space toggle • a toggle all • esc back • s save • q quit", e.control)
+	case scopeParametersPage:
+		columns = []table.Column{
+			{Title: "Parameter", Width: 40},
+			{Title: "Value", Width: 30},
+			{Title: "Default", Width: 30},
+		}
+		for _, parameter := range e.defaults {
+			rows = append(rows, table.Row{parameter.Name, e.parameterValues[parameter.Name], parameter.Value})
+		}
+		helpMsg = "enter edit value • tab controls • s save • q quit"
+	}
+
+	tbl := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(true),
+		table.WithHeight(len(rows)+1),
+	)
+	tbl.SetStyles(table.Styles{
+		Header:   tableHeaderStyle,
+		Cell:     tableCellStyle,
+		Selected: table.DefaultStyles().Selected,
+	})
+
+	return terminal.Model{
+		Table:     tbl,
+		HeaderMsg: e.summary(),
+		HelpMsg:   helpMsg,
+	}
+}
+
+// summary returns the counts of in-scope items.
+func (e *scopeEditor) summary() string {
+	scope := e.Scope()
+	allRules := make(map[string]bool)
+	for _, controlID := range e.controlIDs {
+		for _, ruleID := range e.rulesByControl[controlID] {
+			allRules[ruleID] = allRules[ruleID] || e.includedRules[controlID][ruleID]
+		}
+	}
+	includedRules := 0
+	for _, included := range allRules {
+		if included {
+			includedRules++
+		}
+	}
+	return fmt.Sprintf("Framework: %s\nControls in scope: %d/%d • Rules in scope: %d/%d • Parameters changed: %d/%d",
+		e.frameworkID,
+		len(scope.IncludeControls), len(e.controlIDs),
+		includedRules, len(allRules),
+		len(scope.SelectParameters), len(e.defaults))
+}
+
+// fit limits the table height to the terminal height.
+func (e *scopeEditor) fit() {
+	if e.height == 0 {
+		return
+	}
+	// Reserve lines for the table border, help message, and value input.
+	maxHeight := e.height - lipgloss.Height(e.model.HeaderMsg) - 4
+	if maxHeight < minBrowserTableHeight {
+		maxHeight = minBrowserTableHeight
+	}
+	if lipgloss.Height(e.model.Table.View()) > maxHeight {
+		e.model.Table.SetHeight(maxHeight)
+	}
+}
+
+// scopeMark returns the In Scope column value.
+func scopeMark(included bool) string {
+	if included {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+// rulesByControl returns the sorted rule IDs related to each control, indexed
+// by control ID, from the rule to controls mapping of an assessment plan.
+func rulesByControl(controlsByRule map[string][]string) map[string][]string {
+	rules := make(map[string][]string)
+	for ruleID, controlIDs := range controlsByRule {
+		for _, controlID := range controlIDs {
+			rules[controlID] = append(rules[controlID], ruleID)
+		}
+	}
+	for controlID := range rules {
+		sort.Strings(rules[controlID])
+	}
+	return rules
+}
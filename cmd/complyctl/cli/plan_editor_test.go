@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime/plan"
+)
+
+func TestScopeEditor(t *testing.T) {
+	defaultScope := plan.AssessmentScope{
+		FrameworkID: "example",
+		IncludeControls: []plan.ControlEntry{
+			{ControlID: "ac-1", Rules: []string{plan.IncludeAllRules}},
+			{ControlID: "au-1", Rules: []string{plan.IncludeAllRules}},
+		},
+	}
+	controlsByRule := map[string][]string{
+		"rule-1": {"ac-1"},
+		"rule-2": {"ac-1", "au-1"},
+	}
+	defaults := []plan.ParameterEntry{{Name: "param-1", Value: "default"}}
+
+	editor := newScopeEditor(defaultScope, defaultScope, rulesByControl(controlsByRule), defaults)
+	require.Equal(t, defaultScope, editor.Scope())
+	require.Contains(t, editor.View(), "Controls in scope: 2/2 • Rules in scope: 2/2 • Parameters changed: 0/1")
+
+	keyPress := func(msg tea.KeyMsg) {
+		_, _ = editor.Update(msg)
+	}
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+
+	// Exclude rule-1 from ac-1
+	keyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, scopeRulesPage, editor.page)
+	require.Equal(t, "ac-1", editor.control)
+	keyPress(space)
+	keyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	require.Equal(t, scopeControlsPage, editor.page)
+
+	// Exclude au-1
+	keyPress(tea.KeyMsg{Type: tea.KeyDown})
+	keyPress(space)
+	require.Contains(t, editor.View(), "Controls in scope: 1/2 • Rules in scope: 1/2")
+
+	// Change the parameter value
+	keyPress(tea.KeyMsg{Type: tea.KeyTab})
+	require.Equal(t, scopeParametersPage, editor.page)
+	keyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	require.True(t, editor.editing)
+	for range "default" {
+		keyPress(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	keyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("custom")})
+	require.Contains(t, editor.View(), "Value: custom_")
+	keyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	require.False(t, editor.editing)
+
+	wantScope := plan.AssessmentScope{
+		FrameworkID: "example",
+		IncludeControls: []plan.ControlEntry{
+			{ControlID: "ac-1", Rules: []string{"rule-2"}},
+		},
+		SelectParameters: []plan.ParameterEntry{{Name: "param-1", Value: "custom"}},
+	}
+	require.Equal(t, wantScope, editor.Scope())
+
+	// Reopening the editor with the edited scope keeps the selections
+	reopened := newScopeEditor(defaultScope, wantScope, rulesByControl(controlsByRule), defaults)
+	require.Equal(t, wantScope, reopened.Scope())
+
+	_, cmd := editor.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	require.True(t, editor.saved)
+	require.Equal(t, tea.Quit(), cmd())
+}
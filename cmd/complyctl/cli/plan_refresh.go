@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/complytime/plan"
+)
+
+// planRefreshOptions defines options for the "plan refresh" subcommand
+type planRefreshOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+
+	// withScopeConfig is the scope config re-applied to the refreshed plan, defaulting to
+	// the scope config in the workspace
+	withScopeConfig string
+}
+
+var planRefreshExample = `
+# Re-generate the assessment plan from the current content with the scope config in the workspace.
+complyctl plan refresh
+
+# Re-generate the assessment plan with the given scope config.
+complyctl plan refresh --scope-config config.yml
+`
+
+// planRefreshCmd creates a new cobra.Command for the "plan refresh" subcommand
+func planRefreshCmd(common *option.Common) *cobra.Command {
+	refreshOpts := &planRefreshOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:          "refresh [flags]",
+		Short:        "Re-generate the assessment plan from the current content and re-apply its scope.",
+		Example:      planRefreshExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runPlanRefresh(cmd, refreshOpts)
+		},
+	}
+	cmd.Flags().StringVarP(&refreshOpts.withScopeConfig, "scope-config", "s", "", "load config.yml to customize the refreshed assessment plan")
+	refreshOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runPlanRefresh(cmd *cobra.Command, opts *planRefreshOptions) error {
+	validator := validation.NewSchemaValidator()
+	ap, apCleanedPath, err := loadPlan(opts.complyTimeOpts, validator)
+	if err != nil {
+		return err
+	}
+	frameworkIDs, err := planFrameworks(opts.complyTimeOpts, ap)
+	if err != nil {
+		return err
+	}
+
+	index, err := complytime.LoadContentIndex(opts.ContentPath(), validator)
+	if err != nil {
+		return err
+	}
+	// The scopes of the plan are re-applied, unless another scope config is given
+	assessmentScopes, err := plannedScopes(opts.complyTimeOpts.UserWorkspace, frameworkIDs)
+	if err != nil {
+		return err
+	}
+	changes, err := plan.Validate(cmd.Context(), ap, index.ComponentDefinitions, assessmentScopes)
+	if err != nil {
+		return err
+	}
+	if err := writePlanChanges(opts.Out, changes); err != nil {
+		return err
+	}
+
+	refreshedPlan, err := plan.NewAssessmentPlan(cmd.Context(), index.ComponentDefinitions, frameworkIDs...)
+	if err != nil {
+		return err
+	}
+	if opts.withScopeConfig != "" {
+		assessmentScopes, err = loadScopeConfig(opts.withScopeConfig, frameworkIDs)
+		if err != nil {
+			return err
+		}
+	}
+	lock, err := complytime.NewPlanLock(opts.ContentPath(), index, frameworkIDs, assessmentScopes)
+	if err != nil {
+		return fmt.Errorf("error locking content for the assessment plan: %w", err)
+	}
+	if _, err := writeLockedPlan(opts.complyTimeOpts, refreshedPlan, lock); err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("Assessment plan refreshed in %s\n", apCleanedPath))
+	return nil
+}
+
+// plannedScopes returns the scopes applied to the plan in the workspace: the scopes recorded in
+// the plan lock, or the scopes of the scope config in the workspace.
+func plannedScopes(workspace string, frameworkIDs []string) ([]plan.AssessmentScope, error) {
+	lock, err := complytime.ReadPlanLock(filepath.Join(workspace, planLockLocation))
+	if err == nil {
+		logger.Debug("Applying the scope of the plan lock")
+		return lock.Scopes, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	scopePath := filepath.Join(workspace, scopeConfigLocation)
+	if _, err := os.Stat(scopePath); errors.Is(err, os.ErrNotExist) {
+		logger.Debug("No scope config found in the workspace, all controls are in scope")
+		return nil, nil
+	}
+	return loadScopeConfig(scopePath, frameworkIDs)
+}
+
+// loadScopeConfig reads the scopes of the scope config and ensures they select planned frameworks.
+func loadScopeConfig(scopePath string, frameworkIDs []string) ([]plan.AssessmentScope, error) {
+	assessmentScopes, err := readScopeConfig(scopePath)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateScopes(assessmentScopes, frameworkIDs); err != nil {
+		return nil, err
+	}
+	logger.Debug(fmt.Sprintf("Applying scope config %s", scopePath))
+	return assessmentScopes, nil
+}
@@ -4,12 +4,15 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/oscal-compass/oscal-sdk-go/validation"
 	"github.com/stretchr/testify/require"
 
 	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime/plan"
 )
 
 func TestPlansInWorkspace(t *testing.T) {
@@ -59,6 +62,54 @@ func TestValidatePlan(t *testing.T) {
 			wantErr: "" +
 				"invalid command flags: \"--dry-run\" must be used with \"--out\"",
 		},
+		{
+			name: "Invalid/InteractiveOut",
+			opts: planOptions{
+				interactive: true,
+				output:      "myconfig.yml",
+			},
+			wantErr: "invalid command flags: \"--dry-run\" must be used with \"--out\"",
+		},
+		{
+			name: "Invalid/InteractiveDryRun",
+			opts: planOptions{
+				interactive: true,
+				dryRun:      true,
+				output:      "-",
+			},
+			wantErr: "invalid command flags: \"--interactive\" and \"--dry-run\" cannot be used together",
+		},
+		{
+			name: "Valid/MultipleFrameworks",
+			opts: planOptions{
+				frameworkIDs: []string{"cis", "baseline"},
+				output:       "-",
+			},
+		},
+		{
+			name: "Invalid/DuplicateFrameworks",
+			opts: planOptions{
+				frameworkIDs: []string{"cis", "baseline", "cis"},
+				output:       "-",
+			},
+			wantErr: "framework cis is given more than once",
+		},
+		{
+			name: "Valid/Locked",
+			opts: planOptions{
+				locked: true,
+				output: "-",
+			},
+		},
+		{
+			name: "Invalid/LockedScopeConfig",
+			opts: planOptions{
+				locked:          true,
+				withScopeConfig: "config.yml",
+				output:          "-",
+			},
+			wantErr: "invalid command flags: \"--locked\" cannot be used with \"--dry-run\", \"--interactive\", or \"--scope-config\"",
+		},
 	}
 
 	for _, tt := range tests {
@@ -73,3 +124,42 @@ func TestValidatePlan(t *testing.T) {
 		})
 	}
 }
+
+func TestScopeConfig(t *testing.T) {
+	scopes := []plan.AssessmentScope{
+		{
+			FrameworkID: "cis",
+			IncludeControls: []plan.ControlEntry{
+				{ControlID: "cis-1", Rules: []string{plan.IncludeAllRules}},
+			},
+		},
+		{
+			FrameworkID: "baseline",
+			IncludeControls: []plan.ControlEntry{
+				{ControlID: "base-1", Rules: []string{"rule-1"}},
+			},
+		},
+	}
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+
+	// Scopes of a single framework keep the single scope format
+	data, err := marshalScopeConfig(scopes[:1])
+	require.NoError(t, err)
+	require.Contains(t, string(data), "frameworkId: cis")
+	require.NoError(t, os.WriteFile(configPath, data, 0600))
+	gotScopes, err := readScopeConfig(configPath)
+	require.NoError(t, err)
+	require.Equal(t, scopes[:1], gotScopes)
+
+	data, err = marshalScopeConfig(scopes)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(configPath, data, 0600))
+	gotScopes, err = readScopeConfig(configPath)
+	require.NoError(t, err)
+	require.Equal(t, scopes, gotScopes)
+
+	require.NoError(t, validateScopes(scopes, []string{"cis", "baseline"}))
+	require.NoError(t, validateScopes(scopes[1:], []string{"cis"}))
+	require.EqualError(t, validateScopes(scopes, []string{"cis"}), `scope config framework "baseline" is not one of the planned frameworks`)
+	require.EqualError(t, validateScopes(scopes, []string{"cis", "other"}), `scope config framework "baseline" is not one of the planned frameworks`)
+}
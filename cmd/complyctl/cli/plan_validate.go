@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/complytime/plan"
+)
+
+// planValidateOptions defines options for the "plan validate" subcommand
+type planValidateOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+}
+
+var planValidateExample = `
+# Compare the assessment plan in the workspace with the current content.
+complyctl plan validate
+
+# Re-generate the assessment plan from the current content when it is out of date.
+complyctl plan refresh
+`
+
+// planValidateCmd creates a new cobra.Command for the "plan validate" subcommand
+func planValidateCmd(common *option.Common) *cobra.Command {
+	validateOpts := &planValidateOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:          "validate [flags]",
+		Short:        "Check the assessment plan for rules and parameters that changed in the current content.",
+		Example:      planValidateExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runPlanValidate(cmd, validateOpts)
+		},
+	}
+	validateOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runPlanValidate(cmd *cobra.Command, opts *planValidateOptions) error {
+	validator := validation.NewSchemaValidator()
+	ap, apCleanedPath, err := loadPlan(opts.complyTimeOpts, validator)
+	if err != nil {
+		return err
+	}
+	changes, err := planChanges(cmd.Context(), opts.ContentPath(), opts.complyTimeOpts.UserWorkspace, ap, validator)
+	if err != nil {
+		return err
+	}
+	if err := writePlanChanges(opts.Out, changes); err != nil {
+		return err
+	}
+	if len(changes) > 0 {
+		return fmt.Errorf("assessment plan %s is out of date with %d changes, run \"complyctl plan refresh\" to update it", apCleanedPath, len(changes))
+	}
+	return nil
+}
+
+// planChanges compares the assessment plan with the component definitions of the content path.
+// Parameter values selected by the scopes of the plan in the workspace are not reported.
+func planChanges(ctx context.Context, contentPath complytime.ContentPath, workspace string, ap *oscalTypes.AssessmentPlan, validator validation.Validator) ([]plan.Change, error) {
+	index, err := complytime.LoadContentIndex(contentPath, validator)
+	if err != nil {
+		return nil, err
+	}
+	assessmentScopes, err := plannedScopes(workspace, plan.Frameworks(ap))
+	if err != nil {
+		return nil, err
+	}
+	return plan.Validate(ctx, ap, index.ComponentDefinitions, assessmentScopes)
+}
+
+// writePlanChanges writes the changes, one per line.
+func writePlanChanges(out io.Writer, changes []plan.Change) error {
+	for _, change := range changes {
+		if _, err := fmt.Fprintln(out, change.String()); err != nil {
+			return err
+		}
+	}
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(out, "Assessment plan is up to date")
+		return err
+	}
+	return nil
+}
+
+// checkPlanDrift warns when the assessment plan differs from the current content. Problems
+// loading the content are logged, as they do not prevent using the assessment plan.
+func checkPlanDrift(ctx context.Context, contentPath complytime.ContentPath, workspace string, ap *oscalTypes.AssessmentPlan, validator validation.Validator) {
+	changes, err := planChanges(ctx, contentPath, workspace, ap, validator)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Unable to check the assessment plan against the current content: %v", err))
+		return
+	}
+	if len(changes) == 0 {
+		logger.Debug("The assessment plan is up to date with the current content.")
+		return
+	}
+	logger.Warn(fmt.Sprintf("The assessment plan is out of date with the current content, %d changes found:", len(changes)))
+	for _, change := range changes {
+		logger.Warn(fmt.Sprintf("  %s", change))
+	}
+	logger.Warn("Run \"complyctl plan refresh\" to update the assessment plan.")
+}
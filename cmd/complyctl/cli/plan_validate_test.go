@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime/plan"
+)
+
+func TestWritePlanChanges(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	require.NoError(t, writePlanChanges(out, nil))
+	require.Equal(t, "Assessment plan is up to date\n", out.String())
+
+	out.Reset()
+	changes := []plan.Change{
+		{Type: plan.ChangeRemoved, Kind: plan.KindRule, ID: "rule-2"},
+		{Type: plan.ChangeChanged, Kind: plan.KindRule, ID: "rule-1", Detail: "description changed"},
+	}
+	require.NoError(t, writePlanChanges(out, changes))
+	require.Equal(t, "rule rule-2 removed\nrule rule-1 changed: description changed\n", out.String())
+}
@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+)
+
+// pluginCmd creates a new cobra.Command for the "plugin" subcommand
+func pluginCmd(common *option.Common) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin [command]",
+		Short: "Develop and check complyctl plugins.",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(
+		pluginTestCmd(common),
+	)
+	return cmd
+}
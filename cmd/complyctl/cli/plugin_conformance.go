@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/pkg/doctor"
+	"github.com/complytime/complyctl/pkg/plugintest"
+)
+
+// pluginTestOptions defines options for the "plugin test" subcommand
+type pluginTestOptions struct {
+	*option.Common
+
+	// manifest is the manifest of the plugin to test
+	manifest string
+	// profile is the profile given to the plugin
+	profile string
+	// options are name=value option values of the plugin
+	options []string
+	// timeout is the time a plugin call can take
+	timeout time.Duration
+	// keepWorkspace keeps the workspace of the plugin after the checks
+	keepWorkspace bool
+}
+
+var pluginTestExample = `
+# Check a plugin before shipping it.
+complyctl plugin test ./plugins/c2p-myplugin-manifest.json
+
+# Set options of the plugin and keep its workspace to inspect the generated files.
+complyctl plugin test c2p-openscap-manifest.json --option datastream=/usr/share/xml/scap/ssg/content/ssg-rhel9-ds.xml --keep-workspace
+`
+
+// pluginTestCmd creates a new cobra.Command for the "plugin test" subcommand
+func pluginTestCmd(common *option.Common) *cobra.Command {
+	testOpts := &pluginTestOptions{
+		Common: common,
+	}
+	cmd := &cobra.Command{
+		Use:          "test [flags] manifest",
+		Short:        "Check that a plugin conforms to the conventions of complyctl plugins.",
+		Example:      pluginTestExample,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			testOpts.manifest = args[0]
+			if testOpts.timeout <= 0 {
+				return errors.New("invalid command flags: \"--timeout\" must be positive")
+			}
+			_, err := pluginTestOptionValues(testOpts.options)
+			return err
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runPluginTest(testOpts)
+		},
+	}
+	cmd.Flags().StringVar(&testOpts.profile, "profile", plugintest.DefaultProfile, "profile given to the plugin")
+	cmd.Flags().StringArrayVar(&testOpts.options, "option", nil, "name=value option of the plugin, taking precedence over the manifest defaults, can be repeated")
+	cmd.Flags().DurationVar(&testOpts.timeout, "timeout", plugintest.DefaultTimeout, "time a plugin call can take")
+	cmd.Flags().BoolVar(&testOpts.keepWorkspace, "keep-workspace", false, "keep the temporary workspace of the plugin after the checks")
+	return cmd
+}
+
+func runPluginTest(opts *pluginTestOptions) error {
+	options, err := pluginTestOptionValues(opts.options)
+	if err != nil {
+		return err
+	}
+	// Plugin overrides of the context apply as for the other commands
+	pluginID := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(opts.manifest), complytime.PluginManifestPrefix), complytime.PluginManifestSuffix)
+	for name, value := range opts.PluginOverrides[pluginID] {
+		if _, found := options[name]; !found {
+			options[name] = value
+		}
+	}
+
+	workspace, err := os.MkdirTemp("", "complyctl-plugintest-")
+	if err != nil {
+		return fmt.Errorf("error creating workspace: %w", err)
+	}
+	if opts.keepWorkspace {
+		logger.Info(fmt.Sprintf("Plugin workspace: %s", workspace))
+	} else {
+		defer os.RemoveAll(workspace)
+	}
+
+	results, err := plugintest.Run(plugintest.Config{
+		Manifest:  opts.manifest,
+		Workspace: workspace,
+		Profile:   opts.profile,
+		Options:   options,
+		Timeout:   opts.timeout,
+		Logger:    logger,
+	})
+	if err != nil {
+		return err
+	}
+	checks := make([]doctor.Check, 0, len(results))
+	for _, result := range results {
+		switch result.Status {
+		case plugintest.Pass:
+			checks = append(checks, doctor.Passed(result.Name, result.Message))
+		case plugintest.Skip:
+			checks = append(checks, doctor.Warned(result.Name, result.Message, ""))
+		default:
+			checks = append(checks, doctor.Failed(result.Name, result.Message, ""))
+		}
+	}
+	doctor.WriteChecklist(opts.Out, []doctor.Section{{Title: "Plugin " + opts.manifest, Checks: checks}})
+
+	if failed := plugintest.Count(results, plugintest.Fail); failed > 0 {
+		return fmt.Errorf("%d checks failed", failed)
+	}
+	return nil
+}
+
+// pluginTestOptionValues returns the option values of name=value options.
+func pluginTestOptionValues(options []string) (map[string]string, error) {
+	values := make(map[string]string, len(options))
+	for _, option := range options {
+		name, value, found := strings.Cut(option, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid command flags: option %q, expected name=value", option)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+)
+
+func TestPluginTestOptionValues(t *testing.T) {
+	values, err := pluginTestOptionValues([]string{"datastream=/srv/ds.xml", "inventory=", "policy=a=b"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"datastream": "/srv/ds.xml", "inventory": "", "policy": "a=b"}, values)
+
+	_, err = pluginTestOptionValues([]string{"datastream"})
+	require.EqualError(t, err, `invalid command flags: option "datastream", expected name=value`)
+	_, err = pluginTestOptionValues([]string{"=value"})
+	require.EqualError(t, err, `invalid command flags: option "=value", expected name=value`)
+}
+
+func TestRunPluginTest(t *testing.T) {
+	var out bytes.Buffer
+	opts := &pluginTestOptions{
+		Common:   &option.Common{Output: option.Output{Out: &out}},
+		manifest: filepath.Join(t.TempDir(), "c2p-missing-manifest.json"),
+		profile:  "test",
+		timeout:  time.Minute,
+	}
+	require.EqualError(t, runPluginTest(opts), "1 checks failed")
+	require.Contains(t, out.String(), "Plugin "+opts.manifest)
+	require.Contains(t, out.String(), "[FAIL] manifest: open "+opts.manifest+": no such file or directory")
+	require.Contains(t, out.String(), "[WARN] launch and configure: skipped after the manifest check failed")
+}
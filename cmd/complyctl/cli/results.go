@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+)
+
+// resultsCmd creates a new cobra.Command for the "results" subcommand
+func resultsCmd(common *option.Common) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "results [command]",
+		Short: "Work with the assessment results of scans.",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(
+		resultsMergeCmd(common),
+	)
+	return cmd
+}
@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/terminal"
+)
+
+const fleetResultsLocation = "fleet-assessment-results.json"
+
+// resultsMergeOptions defines options for the "results merge" subcommand
+type resultsMergeOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+
+	// inputs are the assessment results files to merge
+	inputs []string
+
+	// output is the merged assessment results file, defaulting to fleet-assessment-results.json in the workspace
+	output string
+
+	// groupBy is the group of the summary, one of host, control, or rule
+	groupBy string
+
+	// withMd writes the summary as markdown next to the merged assessment results
+	withMd bool
+}
+
+var resultsMergeExample = `
+# Merge the assessment results of several hosts and summarize the hosts passing and failing each control.
+complyctl results merge web1.json web2.json db1.json -o fleet.json
+
+# Summarize the rules passing and failing on each host, also as markdown in fleet.md.
+complyctl results merge web1.json web2.json db1.json -o fleet.json --group-by host --with-md
+`
+
+// resultsMergeCmd creates a new cobra.Command for the "results merge" subcommand
+func resultsMergeCmd(common *option.Common) *cobra.Command {
+	mergeOpts := &resultsMergeOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:          "merge [flags] results.json...",
+		Short:        "Merge the assessment results of several hosts into a fleet report.",
+		Example:      resultsMergeExample,
+		SilenceUsage: true,
+		Args:         cobra.MinimumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			mergeOpts.inputs = args
+			if err := validateResultsMerge(mergeOpts); err != nil {
+				return err
+			}
+			return runResultsMerge(mergeOpts)
+		},
+	}
+	cmd.Flags().StringVarP(&mergeOpts.output, "output", "o", "", "file to write the merged assessment results to, defaults to fleet-assessment-results.json in the workspace")
+	cmd.Flags().StringVar(&mergeOpts.groupBy, "group-by", complytime.GroupByControl, fmt.Sprintf("group of the summary, one of %s", strings.Join(complytime.GroupByValues, ", ")))
+	cmd.Flags().BoolVarP(&mergeOpts.withMd, "with-md", "m", false, "write the summary as markdown next to the merged assessment results")
+	mergeOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func validateResultsMerge(opts *resultsMergeOptions) error {
+	if !slices.Contains(complytime.GroupByValues, opts.groupBy) {
+		return fmt.Errorf("invalid command flags: \"--group-by\" must be one of %s", strings.Join(complytime.GroupByValues, ", "))
+	}
+	return nil
+}
+
+func runResultsMerge(opts *resultsMergeOptions) error {
+	validator := validation.NewSchemaValidator()
+	// Rules are related to controls by the assessment plan of the workspace
+	ap, _, err := loadPlan(opts.complyTimeOpts, validator)
+	if err != nil {
+		return err
+	}
+
+	inputs := make([]*oscalTypes.AssessmentResults, 0, len(opts.inputs))
+	for _, input := range opts.inputs {
+		assessmentResults, err := complytime.ReadAssessmentResults(filepath.Clean(input), validator)
+		if err != nil {
+			return err
+		}
+		inputs = append(inputs, assessmentResults)
+	}
+	merged, err := complytime.MergeAssessmentResults(ap, inputs)
+	if err != nil {
+		return err
+	}
+
+	output := opts.output
+	if output == "" {
+		output = filepath.Join(opts.complyTimeOpts.UserWorkspace, fleetResultsLocation)
+	}
+	output = filepath.Clean(output)
+	if err := complytime.WriteAssessmentResults(merged, output); err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("The merged assessment results of %d files were successfully written to %v.", len(inputs), output))
+
+	summary, err := complytime.FleetSummary(ap, merged, opts.groupBy)
+	if err != nil {
+		return err
+	}
+	rows := summaryRows(summary)
+	showSummaryTable(opts.Out, opts.groupBy, rows)
+
+	if opts.withMd {
+		mdPath := strings.TrimSuffix(output, filepath.Ext(output)) + ".md"
+		var md strings.Builder
+		writeSummaryMarkdown(&md, opts.groupBy, len(complytime.HostRuleResults(merged)), rows)
+		if err := os.WriteFile(mdPath, []byte(md.String()), 0600); err != nil {
+			return err
+		}
+		logger.Info(fmt.Sprintf("The fleet summary in markdown was successfully written to %v.", mdPath))
+	}
+	return nil
+}
+
+// summaryColumns returns the columns of a fleet summary grouped by the given group.
+func summaryColumns(groupBy string) []string {
+	columns := []string{strings.ToUpper(groupBy[:1]) + groupBy[1:]}
+	for _, status := range complytime.ResultStatuses {
+		columns = append(columns, strings.ToUpper(status[:1])+status[1:])
+	}
+	return columns
+}
+
+// summaryRows returns a row for each group of the summary, ordered by group, with the counts of each result.
+func summaryRows(summary map[string]map[string]int) []table.Row {
+	keys := make([]string, 0, len(summary))
+	for key := range summary {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	rows := make([]table.Row, 0, len(keys))
+	for _, key := range keys {
+		row := table.Row{key}
+		for _, status := range complytime.ResultStatuses {
+			row = append(row, strconv.Itoa(summary[key][status]))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func showSummaryTable(out io.Writer, groupBy string, rows []table.Row) {
+	if len(rows) == 0 {
+		_, _ = fmt.Fprintln(out, "No results found")
+		return
+	}
+	var columns []table.Column
+	for _, title := range summaryColumns(groupBy) {
+		columns = append(columns, table.Column{Title: title, Width: len(title) + columnGap})
+	}
+	// Keep a gap between columns, the plain table does not separate them
+	for _, row := range rows {
+		for i, cell := range row {
+			if len(cell)+columnGap > columns[i].Width {
+				columns[i].Width = len(cell) + columnGap
+			}
+		}
+	}
+	terminal.ShowPlainTable(out, columns, rows)
+}
+
+// writeSummaryMarkdown writes the fleet summary as a markdown report.
+func writeSummaryMarkdown(out io.Writer, groupBy string, hosts int, rows []table.Row) {
+	counted := "hosts"
+	if groupBy == complytime.GroupByHost {
+		counted = "rules"
+	}
+	_, _ = fmt.Fprintf(out, "# Fleet Assessment Results\n\n")
+	_, _ = fmt.Fprintf(out, "Results of %d hosts, with the number of %s per result for each %s.\n\n", hosts, counted, groupBy)
+	_, _ = fmt.Fprintf(out, "## Summary by %s\n\n", groupBy)
+	columns := summaryColumns(groupBy)
+	_, _ = fmt.Fprintf(out, "| %s |\n", strings.Join(columns, " | "))
+	_, _ = fmt.Fprintf(out, "|%s\n", strings.Repeat(" --- |", len(columns)))
+	for _, row := range rows {
+		_, _ = fmt.Fprintf(out, "| %s |\n", strings.Join(row, " | "))
+	}
+}
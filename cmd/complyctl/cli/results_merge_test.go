@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestValidateResultsMerge(t *testing.T) {
+	require.NoError(t, validateResultsMerge(&resultsMergeOptions{groupBy: complytime.GroupByHost}))
+	require.EqualError(t, validateResultsMerge(&resultsMergeOptions{groupBy: "site"}),
+		"invalid command flags: \"--group-by\" must be one of host, control, rule")
+}
+
+func TestSummaryMarkdown(t *testing.T) {
+	rows := summaryRows(map[string]map[string]int{
+		"control-2": {complytime.ResultPass: 2},
+		"control-1": {complytime.ResultPass: 1, complytime.ResultFail: 1},
+	})
+	var out bytes.Buffer
+	writeSummaryMarkdown(&out, complytime.GroupByControl, 2, rows)
+	require.Equal(t, `# Fleet Assessment Results
+
+Results of 2 hosts, with the number of hosts per result for each control.
+
+## Summary by control
+
+| Control | Pass | Fail | Error | Skipped | Waived |
+| --- | --- | --- | --- | --- | --- |
+| control-1 | 1 | 1 | 0 | 0 | 0 |
+| control-2 | 2 | 0 | 0 | 0 | 0 |
+`, out.String())
+}
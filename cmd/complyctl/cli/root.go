@@ -3,19 +3,24 @@
 package cli
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/spf13/cobra"
 
 	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/config"
 	"github.com/complytime/complyctl/pkg/log"
+	"github.com/complytime/complyctl/pkg/trace"
 )
 
 var logger hclog.Logger
 
 func init() {
-	logger = log.NewLogger(os.Stdout)
+	logger = log.NewLogger(os.Stderr)
 }
 
 func Error(msg string) {
@@ -28,6 +33,135 @@ func enableDebug(opts *option.Common) {
 	}
 }
 
+// applyConfig sets the flags of the command that were not given on the command line from
+// their environment variables, and then from the settings of the selected context of the
+// configuration file. It returns the name of the applied context.
+func applyConfig(cmd *cobra.Command, opts *option.Common) (string, error) {
+	if err := option.ApplyEnvironment(cmd.Flags(), os.LookupEnv); err != nil {
+		return "", err
+	}
+	configPath := config.DefaultPath()
+	cfg, err := config.Read(configPath)
+	if err != nil {
+		return "", err
+	}
+	name, context, err := cfg.Context(opts.Context)
+	if err != nil {
+		return "", fmt.Errorf("%w in %s", err, configPath)
+	}
+	if context == nil {
+		return "", nil
+	}
+	if err := option.ApplyContext(cmd.Flags(), name, context); err != nil {
+		return "", err
+	}
+	opts.PluginOverrides = context.Plugins()
+	return name, nil
+}
+
+// setupLogging replaces the logger with a logger writing logs in the log format to the log file,
+// or else to stderr. The run ID is exported to plugin subprocesses, and an existing run ID is
+// reused so the run can be correlated with the automation running complyctl. JSON logs carry the
+// run ID, command, and workspace on every line.
+func setupLogging(cmd *cobra.Command, opts *option.Common) error {
+	newLogger, err := log.NewLoggerWithFormat(os.Stderr, opts.LogFormat)
+	if err != nil {
+		return fmt.Errorf("invalid command flags: %w", err)
+	}
+	if opts.LogFile != "" {
+		file, err := os.OpenFile(filepath.Clean(opts.LogFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("error opening log file: %w", err)
+		}
+		if newLogger, err = log.NewLoggerWithFormat(file, opts.LogFormat); err != nil {
+			return err
+		}
+	}
+
+	runID := os.Getenv(log.RunIDEnv)
+	if runID == "" {
+		runID = log.NewRunID()
+		if err := os.Setenv(log.RunIDEnv, runID); err != nil {
+			return err
+		}
+	}
+	if opts.LogFormat == log.FormatJSON {
+		fields := []interface{}{log.RunIDKey, runID, log.CommandKey, cmd.CommandPath()}
+		if workspace := cmd.Flags().Lookup("workspace"); workspace != nil {
+			fields = append(fields, log.WorkspaceKey, workspace.Value.String())
+		}
+		newLogger = newLogger.With(fields...)
+	}
+	logger = newLogger
+	return nil
+}
+
+// setupTracing sets the tracer exporting the spans of complyctl to the trace endpoint and file,
+// and exports them to plugin subprocesses, which export their spans to the same destinations.
+func setupTracing(opts *option.Common) error {
+	tracer, err := trace.NewTracer(trace.Config{
+		ServiceName: "complyctl",
+		Endpoint:    opts.TraceEndpoint,
+		File:        opts.TraceFile,
+		OnError:     func(err error) { logger.Warn(err.Error()) },
+	})
+	if err != nil {
+		return fmt.Errorf("invalid command flags: %w", err)
+	}
+	trace.SetDefault(tracer)
+	for env, value := range map[string]string{trace.EndpointEnv: opts.TraceEndpoint, trace.FileEnv: opts.TraceFile} {
+		if err := os.Setenv(env, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// traceCommands records a span for each run of the commands. The span is the parent of the
+// spans of plugin subprocesses, which receive it in the TRACEPARENT environment variable.
+// The queued spans are exported when the command returns.
+func traceCommands(cmd *cobra.Command) {
+	for _, child := range cmd.Commands() {
+		traceCommands(child)
+	}
+	run := cmd.RunE
+	if run == nil {
+		return
+	}
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if !trace.Enabled() {
+			return run(cmd, args)
+		}
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx, span := trace.Start(trace.FromEnvironment(ctx), cmd.CommandPath(),
+			trace.String("complyctl.run_id", os.Getenv(log.RunIDEnv)))
+		defer flushSpans()
+		defer span.End()
+		if workspace := cmd.Flags().Lookup("workspace"); workspace != nil {
+			span.SetAttributes(trace.String("complyctl.workspace", workspace.Value.String()))
+		}
+		if err := os.Setenv(trace.TraceParentEnv, trace.TraceParent(ctx)); err != nil {
+			return err
+		}
+		cmd.SetContext(ctx)
+		err := run(cmd, args)
+		span.RecordError(err)
+		return err
+	}
+}
+
+// flushSpans exports the spans queued by the command before complyctl exits.
+func flushSpans() {
+	ctx, cancel := context.WithTimeout(context.Background(), trace.FlushTimeout)
+	defer cancel()
+	if err := trace.Flush(ctx); err != nil {
+		logger.Warn(err.Error())
+	}
+}
+
 // New creates a new cobra.Command root for complyctl
 func New() *cobra.Command {
 
@@ -48,13 +182,40 @@ func New() *cobra.Command {
 
 	cmd.AddCommand(
 		versionCmd(&opts),
+		resultsCmd(&opts),
 		scanCmd(&opts),
 		generateCmd(&opts),
 		planCmd(&opts),
 		listCmd(&opts),
 		infoCmd(&opts),
+		metricsCmd(&opts),
+		doctorCmd(&opts),
+		contentCmd(&opts),
+		sspCmd(&opts),
+		evidenceCmd(&opts),
+		waiverCmd(&opts),
+		attestCmd(&opts),
+		configCmd(&opts),
+		pluginCmd(&opts),
 	)
-	cmd.PersistentPreRun = func(_ *cobra.Command, _ []string) { enableDebug(&opts) }
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+		contextName, err := applyConfig(cmd, &opts)
+		if err != nil {
+			return err
+		}
+		if err := setupLogging(cmd, &opts); err != nil {
+			return err
+		}
+		if err := setupTracing(&opts); err != nil {
+			return err
+		}
+		enableDebug(&opts)
+		if contextName != "" {
+			logger.Debug(fmt.Sprintf("Using context %s from %s", contextName, config.DefaultPath()))
+		}
+		return nil
+	}
+	traceCommands(cmd)
 
 	return cmd
 }
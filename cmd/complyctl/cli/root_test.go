@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/pkg/log"
+	"github.com/complytime/complyctl/pkg/trace"
+)
+
+func TestSetupLogging(t *testing.T) {
+	previous := logger
+	t.Cleanup(func() { logger = previous })
+	t.Setenv(log.RunIDEnv, "0123456789abcdef")
+
+	common := &option.Common{}
+	root := &cobra.Command{Use: "complyctl"}
+	common.BindFlags(root.PersistentFlags())
+	cmd := scanCmd(common)
+	root.AddCommand(cmd)
+	logPath := filepath.Join(t.TempDir(), "complyctl.log")
+	require.NoError(t, cmd.ParseFlags([]string{"--log-format", "json", "--log-file", logPath, "--workspace", "/srv/complytime"}))
+	require.NoError(t, setupLogging(cmd, common))
+	logger.Info("Successfully loaded 1 plugin(s).")
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	require.Equal(t, "0123456789abcdef", fields[log.RunIDKey])
+	require.Equal(t, "complyctl scan", fields[log.CommandKey])
+	require.Equal(t, "/srv/complytime", fields[log.WorkspaceKey])
+	require.Equal(t, "Successfully loaded 1 plugin(s).", fields["msg"])
+
+	common.LogFormat = "xml"
+	require.EqualError(t, setupLogging(cmd, common), `invalid command flags: unsupported log format "xml", expected text or json`)
+}
+
+func TestTraceCommands(t *testing.T) {
+	t.Setenv(trace.TraceParentEnv, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	t.Setenv(trace.EndpointEnv, "")
+	t.Setenv(trace.FileEnv, "")
+	t.Cleanup(func() { trace.SetDefault(nil) })
+
+	tracePath := filepath.Join(t.TempDir(), "traces.jsonl")
+	common := &option.Common{TraceFile: tracePath}
+	require.NoError(t, setupTracing(common))
+	require.Equal(t, tracePath, os.Getenv(trace.FileEnv))
+
+	var traceParent string
+	root := &cobra.Command{Use: "complyctl"}
+	root.AddCommand(&cobra.Command{
+		Use: "scan",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			traceParent = os.Getenv(trace.TraceParentEnv)
+			require.Equal(t, traceParent, trace.TraceParent(cmd.Context()))
+			return errors.New("scan failed")
+		},
+	})
+	traceCommands(root)
+	root.SetArgs([]string{"scan"})
+	require.EqualError(t, root.Execute(), "scan failed")
+
+	spanContext, err := trace.ParseTraceParent(traceParent)
+	require.NoError(t, err)
+	data, err := os.ReadFile(tracePath)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"name":"complyctl scan"`)
+	require.Contains(t, string(data), `"traceId":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+	require.Contains(t, string(data), `"parentSpanId":"00f067aa0ba902b7"`)
+	require.Contains(t, string(data), fmt.Sprintf(`"spanId":"%x"`, spanContext.SpanID))
+	require.Contains(t, string(data), `"message":"scan failed"`)
+
+	common.TraceEndpoint = "localhost:4318"
+	require.EqualError(t, setupTracing(common), `invalid command flags: invalid trace endpoint "localhost:4318", expected an http or https URL`)
+}
@@ -7,26 +7,46 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/framework"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/framework/actions"
-	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
 	"github.com/oscal-compass/oscal-sdk-go/settings"
 	"github.com/oscal-compass/oscal-sdk-go/validation"
 	"github.com/spf13/cobra"
 
 	"github.com/complytime/complyctl/cmd/complyctl/option"
 	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/complytime/plan"
+	"github.com/complytime/complyctl/internal/evidence"
+	"github.com/complytime/complyctl/pkg/trace"
 )
 
 const assessmentResultsLocationJson = "assessment-results.json"
 const assessmentResultsLocationMd = "assessment-results.md"
+const scanStatusLocation = "scan-status.json"
 
 // scanOptions defined options for the scan subcommand.
 type scanOptions struct {
 	*option.Common
 	complyTimeOpts   *option.ComplyTime
 	withPluginConfig string
+	// bundleEvidence packages the plan, the results, and the evidence files into a signed evidence bundle
+	bundleEvidence bool
+	// attest writes a signed in-toto attestation of the scan
+	attest bool
+	// attestSubjects are the subjects of the attestation as name@sha256:digest, defaulting to the scanned hosts
+	attestSubjects []string
+	// signingKey is the key signing the evidence bundle and attestation, defaulting to the signing key of the user
+	signingKey string
+	// maxFailures is the number of failed rules above which the scan fails, disabled when negative
+	maxFailures int
+	// minPassRate is the percentage of passed rules below which the scan fails
+	minPassRate float64
 }
 
 // scanCmd creates a new cobra.Command for the version subcommand.
@@ -42,19 +62,54 @@ func scanCmd(common *option.Common) *cobra.Command {
 		SilenceUsage: true,
 		Args:         cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			return runScan(cmd, scanOpts)
+			subjects, err := validateScan(scanOpts)
+			if err != nil {
+				return err
+			}
+			return runScan(cmd, scanOpts, subjects)
 		},
 	}
 	cmd.Flags().StringVarP(&scanOpts.withPluginConfig, "plugin-config", "c", "", "Directory where user customized plugin manifests located.")
 	cmd.Flags().BoolP("with-md", "m", false, "If true, assessement-result markdown will be generated")
+	cmd.Flags().BoolVar(&scanOpts.bundleEvidence, "bundle-evidence", false, "package the plan, the results, and the evidence files into a signed evidence bundle in the workspace")
+	cmd.Flags().BoolVar(&scanOpts.attest, "attest", false, fmt.Sprintf("write a DSSE signed in-toto attestation of the scan to %s in the workspace", scanAttestationLocation))
+	cmd.Flags().StringArrayVar(&scanOpts.attestSubjects, "attest-subject", nil, "subject of the attestation as name@sha256:digest, such as an image digest reference, defaults to the scanned hosts, can be repeated")
+	cmd.Flags().StringVar(&scanOpts.signingKey, "signing-key", "", fmt.Sprintf("Ed25519 private key signing the evidence bundle and attestation, defaults to %s which is generated if missing", evidence.DefaultSigningKeyPath()))
+	cmd.Flags().IntVar(&scanOpts.maxFailures, "max-failures", -1, "fail the scan when more rules failed or errored, disabled when negative")
+	cmd.Flags().Float64Var(&scanOpts.minPassRate, "min-pass-rate", 0, "fail the scan when the percentage of passed rules among the assessed rules is lower, not counting waived rules")
 	scanOpts.complyTimeOpts.BindFlags(cmd.Flags())
 	return cmd
 }
 
-func runScan(cmd *cobra.Command, opts *scanOptions) error {
+// validateScan checks the flags of the scan and returns the parsed attestation subjects.
+func validateScan(opts *scanOptions) ([]evidence.ResourceDescriptor, error) {
+	if len(opts.attestSubjects) > 0 && !opts.attest {
+		return nil, errors.New("invalid command flags: \"--attest-subject\" requires \"--attest\"")
+	}
+	if opts.signingKey != "" && !opts.attest && !opts.bundleEvidence {
+		return nil, errors.New("invalid command flags: \"--signing-key\" requires \"--attest\" or \"--bundle-evidence\"")
+	}
+	if opts.minPassRate < 0 || opts.minPassRate > 100 {
+		return nil, fmt.Errorf("invalid command flags: \"--min-pass-rate\" must be between 0 and 100, got %v", opts.minPassRate)
+	}
+	subjects := make([]evidence.ResourceDescriptor, 0, len(opts.attestSubjects))
+	for _, value := range opts.attestSubjects {
+		subject, err := evidence.ParseSubject(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid command flags: %w", err)
+		}
+		subjects = append(subjects, subject)
+	}
+	return subjects, nil
+}
+
+func runScan(cmd *cobra.Command, opts *scanOptions, attestSubjects []evidence.ResourceDescriptor) error {
 	validator := validation.NewSchemaValidator()
 	// Load settings from assessment plan
+	_, loadSpan := trace.Start(cmd.Context(), "load plan")
 	ap, apCleanedPath, err := loadPlan(opts.complyTimeOpts, validator)
+	loadSpan.RecordError(err)
+	loadSpan.End()
 	if err != nil {
 		return err
 	}
@@ -70,6 +125,9 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 		return err
 	}
 	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
+	contentPath := opts.ContentPath()
+	logger.Debug(fmt.Sprintf("Using content path: %s", contentPath))
+	checkPlanDrift(cmd.Context(), contentPath, opts.complyTimeOpts.UserWorkspace, ap, validator)
 
 	cfg, err := complytime.Config(appDir)
 	if err != nil {
@@ -86,16 +144,15 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 
 	// Determine what profile to load from framework information captured
 	// from state (assessment plan). This is required to populate complyTime required plugin options.
-	frameworkProp, valid := extensions.GetTrestleProp(extensions.FrameworkProp, *ap.Metadata.Props)
-	if !valid {
-		return fmt.Errorf("error reading framework property from assessment plan")
+	frameworkIDs, err := planFrameworks(opts.complyTimeOpts, ap)
+	if err != nil {
+		return err
 	}
-	opts.complyTimeOpts.FrameworkID = frameworkProp.Value
-	logger.Debug(fmt.Sprintf("Framework property was successfully read from the assessment plan: %v.", frameworkProp))
 
 	pluginOptions := opts.complyTimeOpts.ToPluginOptions()
 	pluginOptions.UserConfigRoot = opts.withPluginConfig
-	plugins, cleanup, err := complytime.Plugins(manager, inputContext, pluginOptions, logger)
+	pluginOptions.Overrides = opts.PluginOverrides
+	plugins, cleanup, err := complytime.Plugins(cmd.Context(), manager, contentPath, inputContext, pluginOptions, logger)
 	if cleanup != nil {
 		defer cleanup()
 	}
@@ -104,17 +161,26 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 	}
 	logger.Info(fmt.Sprintf("Successfully loaded %v plugin(s).", len(plugins)))
 
-	allResults, err := actions.AggregateResults(cmd.Context(), inputContext, plugins)
+	allResults, err := aggregateResults(cmd, opts.complyTimeOpts.UserWorkspace, inputContext, plugins)
 	if err != nil {
 		return err
 	}
 
 	// Collect results in a single report
 	planHref := fmt.Sprintf("file://%s", apCleanedPath)
-	assessmentResults, err := actions.Report(cmd.Context(), inputContext, planHref, *ap, allResults)
+	reportCtx, reportSpan := trace.Start(cmd.Context(), "report")
+	assessmentResults, err := actions.Report(reportCtx, inputContext, planHref, *ap, allResults)
+	reportSpan.RecordError(err)
+	reportSpan.End()
 	if err != nil {
 		return err
 	}
+	waivers, err := complytime.ReadWaivers(filepath.Join(opts.complyTimeOpts.UserWorkspace, waiversLocation))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	logWaivedRules(complytime.ApplyWaivers(assessmentResults, ap, waivers.Waivers, now))
 	arJsonPath := filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentResultsLocationJson)
 	err = complytime.WriteAssessmentResults(assessmentResults, arJsonPath)
 	if err != nil {
@@ -122,62 +188,228 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 	}
 	logger.Info(fmt.Sprintf("The assessment results in JSON were successfully written to %v.", arJsonPath))
 
+	frameworkPlans := make(map[string]*oscalTypes.AssessmentPlan, len(frameworkIDs))
+	frameworkSummaries := make([]evidence.FrameworkSummary, 0, len(frameworkIDs))
+	for _, frameworkID := range frameworkIDs {
+		frameworkPlan, err := plan.ForFramework(ap, frameworkID)
+		if err != nil {
+			return err
+		}
+		frameworkPlans[frameworkID] = frameworkPlan
+		counts := complytime.RuleCounts(frameworkPlan, assessmentResults)
+		logger.Info(fmt.Sprintf("Framework %s: %d rules passed, %d failed, %d waived, %d errors, %d skipped.", frameworkID,
+			counts[complytime.ResultPass], counts[complytime.ResultFail], counts[complytime.ResultWaived], counts[complytime.ResultError], counts[complytime.ResultSkipped]))
+		frameworkSummaries = append(frameworkSummaries, evidence.NewFrameworkSummary(frameworkID, counts, complytime.ControlRuleCounts(frameworkPlan, assessmentResults)))
+	}
+
 	outputFlag, _ := cmd.Flags().GetBool("with-md")
 	if outputFlag {
-		var profileHref string
-		compDefs, err := complytime.FindComponentDefinitions(appDir.BundleDir(), validator)
+		_, indexSpan := trace.Start(cmd.Context(), "load content index")
+		index, err := complytime.LoadContentIndex(contentPath, validator)
+		indexSpan.RecordError(err)
+		indexSpan.End()
 		if err != nil {
 			return err
 		}
-		for _, compDef := range compDefs {
-			if compDef.Components == nil {
-				continue
+		arMarkdownPath := filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentResultsLocationMd)
+
+		// Posture is reported for each framework from the results of its rules
+		var assessmentResultsMd []byte
+		for _, frameworkID := range frameworkIDs {
+			catalog, err := frameworkCatalog(contentPath, index.ComponentDefinitions, frameworkID, validator)
+			if err != nil {
+				return err
 			}
-			for _, component := range *compDef.Components {
-				if component.ControlImplementations == nil {
-					continue
-				}
-				for _, implementation := range *component.ControlImplementations {
-					frameworkShortName, found := settings.GetFrameworkShortName(implementation)
-					// If the framework property value match the assessment plan framework property values
-					// this is the correct control source.
-					if found && frameworkShortName == frameworkProp.Value {
-						profileHref = implementation.Source
-						break
-					}
-				}
-				if profileHref != "" {
-					break
+			frameworkResults := assessmentResults
+			if len(frameworkIDs) > 1 {
+				frameworkResults, err = actions.Report(cmd.Context(), inputContext, planHref, *frameworkPlans[frameworkID], allResults)
+				if err != nil {
+					return err
 				}
+				complytime.ApplyWaivers(frameworkResults, frameworkPlans[frameworkID], waivers.Waivers, now)
+			}
+			posture := framework.NewPosture(frameworkResults, catalog, frameworkPlans[frameworkID], logger)
+			frameworkMd, err := posture.Generate(arMarkdownPath)
+			if err != nil {
+				return err
 			}
+			if len(assessmentResultsMd) > 0 {
+				assessmentResultsMd = append(assessmentResultsMd, '\n')
+			}
+			assessmentResultsMd = append(assessmentResultsMd, frameworkMd...)
 		}
-
-		profile, err := complytime.LoadProfile(appDir, profileHref, validator)
+		err = os.WriteFile(arMarkdownPath, assessmentResultsMd, 0600)
 		if err != nil {
 			return err
 		}
+		logger.Info(fmt.Sprintf("The assessment results in markdown were successfully written to %v.", arMarkdownPath))
+	} else {
+		logger.Info("No assessment result in markdown will be generated.")
+	}
+
+	if !opts.attest && !opts.bundleEvidence {
+		return checkScanThresholds(opts, complytime.RuleCounts(ap, assessmentResults))
+	}
+	workspace := opts.complyTimeOpts.UserWorkspace
+	manifests, err := contentPath.FindPlugins(inputContext.RequestedProviders())
+	if err != nil {
+		return err
+	}
+	versions := pluginVersions(manifests)
 
-		if len(profile.Imports) != 1 {
-			return errors.New("profile imports must be one")
+	var attestationPath string
+	if opts.attest {
+		plan, err := evidence.FileDescriptor(apCleanedPath)
+		if err != nil {
+			return err
 		}
-		catalog, err := complytime.LoadCatalogSource(appDir, profile.Imports[0].Href, validator)
+		results, err := evidence.FileDescriptor(arJsonPath)
 		if err != nil {
 			return err
 		}
-		arMarkdownPath := filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentResultsLocationMd)
-
-		posture := framework.NewPosture(assessmentResults, catalog, ap, logger)
-		assessmentResultsMd, err := posture.Generate(arMarkdownPath)
+		predicate := evidence.ScanPredicate{
+			ScannedAt:  now.UTC(),
+			Frameworks: frameworkSummaries,
+			Plan:       plan,
+			Results:    results,
+			Plugins:    versions,
+		}
+		attestationPath, err = writeScanAttestation(workspace, opts.signingKey, attestSubjects, assessmentResults, predicate)
 		if err != nil {
 			return err
 		}
-		err = os.WriteFile(arMarkdownPath, assessmentResultsMd, 0600)
+		logger.Info(fmt.Sprintf("The attestation of the scan was successfully written to %v.", attestationPath))
+	}
+
+	if opts.bundleEvidence {
+		workspaceFiles := []string{apCleanedPath}
+		lockPath := filepath.Join(workspace, planLockLocation)
+		if _, err := os.Stat(lockPath); err != nil {
+			logger.Warn(fmt.Sprintf("The evidence bundle does not include the content lock of the plan: %v", err))
+		} else {
+			workspaceFiles = append(workspaceFiles, lockPath)
+		}
+		workspaceFiles = append(workspaceFiles, arJsonPath)
+		if outputFlag {
+			workspaceFiles = append(workspaceFiles, filepath.Join(workspace, assessmentResultsLocationMd))
+		}
+		if attestationPath != "" {
+			workspaceFiles = append(workspaceFiles, attestationPath)
+		}
+		// The scan status is missing when it could not be written
+		statusPath := filepath.Join(workspace, scanStatusLocation)
+		if _, err := os.Stat(statusPath); err == nil {
+			workspaceFiles = append(workspaceFiles, statusPath)
+		}
+		bundlePath, err := writeEvidenceBundle(workspace, opts.signingKey, workspaceFiles, assessmentResults, versions, now)
 		if err != nil {
 			return err
 		}
-		logger.Info(fmt.Sprintf("The assessment results in markdown were successfully written to %v.", arMarkdownPath))
-	} else {
-		logger.Info("No assessment result in markdown will be generated.")
+		logger.Info(fmt.Sprintf("The evidence bundle was successfully written to %v.", bundlePath))
+	}
+	return checkScanThresholds(opts, complytime.RuleCounts(ap, assessmentResults))
+}
+
+// checkScanThresholds fails the scan when the rule counts of the plan exceed the thresholds.
+// Waived rules are accepted risks and skipped rules are not assessed, so neither is counted.
+func checkScanThresholds(opts *scanOptions, counts map[string]int) error {
+	var problems []string
+	failures := counts[complytime.ResultFail] + counts[complytime.ResultError]
+	if opts.maxFailures >= 0 && failures > opts.maxFailures {
+		problems = append(problems, fmt.Sprintf("%d rules failed or errored, more than the maximum of %d", failures, opts.maxFailures))
+	}
+	passed := counts[complytime.ResultPass]
+	if assessed := passed + failures; opts.minPassRate > 0 && assessed > 0 {
+		if passRate := 100 * float64(passed) / float64(assessed); passRate < opts.minPassRate {
+			problems = append(problems, fmt.Sprintf("%.1f%% of rules passed, less than the minimum of %v%%", passRate, opts.minPassRate))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("scan results exceed the thresholds: %s", strings.Join(problems, ", "))
 	}
 	return nil
 }
+
+// logWaivedRules logs the failing rules matched by waivers. Rules of expired waivers are called out
+// as their failures are reported again.
+func logWaivedRules(waivedRules []complytime.WaivedRule) {
+	for _, waivedRule := range waivedRules {
+		waiver := waivedRule.Waiver
+		if waivedRule.Expired {
+			logger.Warn(fmt.Sprintf("The waiver of %s %s approved by %s expired on %s, failures of rule %s are reported.",
+				waiver.Kind(), waiver.ID(), waiver.Approver, waiver.Expires, waivedRule.RuleID))
+			continue
+		}
+		logger.Info(fmt.Sprintf("Failures of rule %s are waived by %s until %s.", waivedRule.RuleID, waiver.Approver, waiver.Expires))
+	}
+}
+
+// frameworkCatalog returns the catalog imported by the profile of the given framework in the component definitions.
+func frameworkCatalog(contentPath complytime.ContentPath, componentDefs []oscalTypes.ComponentDefinition, frameworkID string, validator validation.Validator) (*oscalTypes.Catalog, error) {
+	var profileHref string
+	for _, compDef := range componentDefs {
+		if compDef.Components == nil {
+			continue
+		}
+		for _, component := range *compDef.Components {
+			if component.ControlImplementations == nil {
+				continue
+			}
+			for _, implementation := range *component.ControlImplementations {
+				frameworkShortName, found := settings.GetFrameworkShortName(implementation)
+				// If the framework property value match the assessment plan framework property values
+				// this is the correct control source.
+				if found && frameworkShortName == frameworkID {
+					profileHref = implementation.Source
+					break
+				}
+			}
+			if profileHref != "" {
+				break
+			}
+		}
+	}
+
+	profile, err := complytime.LoadProfile(contentPath, profileHref, validator)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(profile.Imports) != 1 {
+		return nil, errors.New("profile imports must be one")
+	}
+	return complytime.LoadCatalogSource(contentPath, profile.Imports[0].Href, validator)
+}
+
+// aggregateResults collects results from each plugin and records the per-plugin
+// timing and errors of the scan in the workspace.
+func aggregateResults(cmd *cobra.Command, workspace string, inputContext *actions.InputContext, plugins map[plugin.ID]policy.Provider) ([]policy.PVPResult, error) {
+	statusPath := filepath.Join(workspace, scanStatusLocation)
+	previousStatus, err := complytime.ReadScanStatus(statusPath)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Ignoring unreadable scan status: %v", err))
+	}
+	status := complytime.NewScanStatus(time.Now(), previousStatus)
+
+	var allResults []policy.PVPResult
+	var scanErr error
+	for pluginId, pluginProvider := range plugins {
+		start := time.Now()
+		ctx, span := trace.Start(cmd.Context(), "aggregate results", trace.String("complytime.plugin_id", pluginId.String()))
+		pluginResults, err := actions.AggregateResults(ctx, inputContext, map[plugin.ID]policy.Provider{pluginId: pluginProvider})
+		span.RecordError(err)
+		span.End()
+		status.Record(pluginId.String(), time.Since(start), err)
+		if err != nil {
+			scanErr = err
+			break
+		}
+		allResults = append(allResults, pluginResults...)
+	}
+	status.Complete(time.Now())
+
+	if err := complytime.WriteScanStatus(status, statusPath); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to write scan status to %s: %v", statusPath, err))
+	}
+	return allResults, scanErr
+}
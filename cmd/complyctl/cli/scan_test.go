@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestCheckScanThresholds(t *testing.T) {
+	counts := map[string]int{
+		complytime.ResultPass:    6,
+		complytime.ResultWaived:  4,
+		complytime.ResultFail:    1,
+		complytime.ResultError:   1,
+		complytime.ResultSkipped: 10,
+	}
+	require.NoError(t, checkScanThresholds(&scanOptions{maxFailures: -1}, counts))
+	require.NoError(t, checkScanThresholds(&scanOptions{maxFailures: 2, minPassRate: 75}, counts))
+	require.EqualError(t, checkScanThresholds(&scanOptions{maxFailures: 1, minPassRate: 75}, counts),
+		"scan results exceed the thresholds: 2 rules failed or errored, more than the maximum of 1")
+	// Waived rules count neither as passed nor as assessed
+	require.EqualError(t, checkScanThresholds(&scanOptions{maxFailures: 0, minPassRate: 80}, counts),
+		"scan results exceed the thresholds: 2 rules failed or errored, more than the maximum of 0, 75.0% of rules passed, less than the minimum of 80%")
+	require.NoError(t, checkScanThresholds(&scanOptions{maxFailures: 0, minPassRate: 100}, map[string]int{complytime.ResultWaived: 2, complytime.ResultSkipped: 3}))
+}
+
+func TestValidateScanThresholds(t *testing.T) {
+	_, err := validateScan(&scanOptions{maxFailures: -1, minPassRate: 101})
+	require.EqualError(t, err, `invalid command flags: "--min-pass-rate" must be between 0 and 100, got 101`)
+}
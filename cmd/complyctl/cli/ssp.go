@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/complytime/ssp"
+)
+
+const (
+	systemSecurityPlanLocation = "system-security-plan.json"
+	systemConfigLocation       = "system.yml"
+)
+
+// sspOptions defines options for the "ssp" subcommand
+type sspOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+
+	// systemConfig is the YAML file with the system characteristics, defaulting to system.yml in the workspace
+	systemConfig string
+
+	// withResults links the implemented requirements to the assessment results in the workspace
+	withResults bool
+}
+
+var sspExample = `
+# Generate a system security plan for a framework with the system characteristics in system.yml of the workspace.
+complyctl ssp myframework
+
+# Use another system config and link the rules to the latest assessment results in the workspace.
+complyctl ssp myframework --system-config mysystem.yml --with-results
+`
+
+// sspCmd creates a new cobra.Command for the "ssp" subcommand
+func sspCmd(common *option.Common) *cobra.Command {
+	sspOpts := &sspOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:          "ssp [flags] id",
+		Short:        "Generate an OSCAL system security plan skeleton for a compliance framework id.",
+		Example:      sspExample,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		PreRun: func(_ *cobra.Command, args []string) {
+			sspOpts.complyTimeOpts.FrameworkID = filepath.Clean(args[0])
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runSSP(sspOpts)
+		},
+	}
+	cmd.Flags().StringVarP(&sspOpts.systemConfig, "system-config", "s", "", "YAML file with the system characteristics, defaults to system.yml in the workspace")
+	cmd.Flags().BoolVar(&sspOpts.withResults, "with-results", false, "link the implemented requirements to the assessment results in the workspace")
+	sspOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runSSP(opts *sspOptions) error {
+	systemConfigPath := opts.systemConfig
+	if systemConfigPath == "" {
+		systemConfigPath = filepath.Join(opts.complyTimeOpts.UserWorkspace, systemConfigLocation)
+	}
+	systemConfig, err := ssp.ReadSystemConfig(systemConfigPath)
+	if err != nil {
+		return err
+	}
+
+	validator := validation.NewSchemaValidator()
+	contentPath := opts.ContentPath()
+	logger.Debug(fmt.Sprintf("Using content path: %s", contentPath))
+	index, err := complytime.LoadContentIndex(contentPath, validator)
+	if err != nil {
+		return err
+	}
+
+	frameworkID := opts.complyTimeOpts.FrameworkID
+	systemPlan, err := ssp.NewSystemSecurityPlan(frameworkID, *systemConfig, loadComponents(index.ComponentDefinitions, frameworkID))
+	if err != nil {
+		return err
+	}
+
+	if opts.withResults {
+		arPath := filepath.Clean(filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentResultsLocationJson))
+		assessmentResults, err := complytime.ReadAssessmentResults(arPath, validator)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("error: assessment results do not exist in workspace %s: %w\n\nDid you run the scan command?", opts.complyTimeOpts.UserWorkspace, err)
+			}
+			return err
+		}
+		ssp.LinkAssessmentResults(systemPlan, assessmentResults, fmt.Sprintf("file://%s", arPath))
+		logger.Debug(fmt.Sprintf("Linked assessment results %s", arPath))
+	}
+
+	sspPath := filepath.Clean(filepath.Join(opts.complyTimeOpts.UserWorkspace, systemSecurityPlanLocation))
+	if err := ssp.WriteSystemSecurityPlan(systemPlan, sspPath); err != nil {
+		return fmt.Errorf("error writing system security plan to %s: %w", sspPath, err)
+	}
+	logger.Info(fmt.Sprintf("System security plan written to %s", sspPath))
+	return nil
+}
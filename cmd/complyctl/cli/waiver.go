@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+)
+
+const waiversLocation = "waivers.yml"
+
+// waiverCmd creates a new cobra.Command for the "waiver" subcommand
+func waiverCmd(common *option.Common) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "waiver [command]",
+		Short: "Manage the waivers accepting the risk of failing rules in the workspace.",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(
+		waiverAddCmd(common),
+		waiverExpireCmd(common),
+		waiverListCmd(common),
+	)
+	return cmd
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// waiverAddOptions defines options for the "waiver add" subcommand
+type waiverAddOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+
+	// waiver is the waiver to add
+	waiver complytime.Waiver
+}
+
+var waiverAddExample = `
+# Accept the failures of a rule until the end of the year.
+complyctl waiver add --rule my_rule --justification "Compensated by network controls" --approver "Jane Doe" --expires 2026-12-31
+
+# Accept the failures of all rules of a control.
+complyctl waiver add --control ac-2 --justification "Legacy system, replaced in Q3" --approver "Jane Doe" --expires 2026-09-30
+`
+
+// waiverAddCmd creates a new cobra.Command for the "waiver add" subcommand
+func waiverAddCmd(common *option.Common) *cobra.Command {
+	addOpts := &waiverAddOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:          "add [flags]",
+		Short:        "Add a waiver accepting the failures of a rule or of the rules of a control until it expires.",
+		Example:      waiverAddExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runWaiverAdd(addOpts)
+		},
+	}
+	cmd.Flags().StringVar(&addOpts.waiver.Rule, "rule", "", "ID of the waived rule")
+	cmd.Flags().StringVar(&addOpts.waiver.Control, "control", "", "ID of the control whose rules are waived")
+	cmd.Flags().StringVar(&addOpts.waiver.Justification, "justification", "", "why the risk of the failures is accepted")
+	cmd.Flags().StringVar(&addOpts.waiver.Approver, "approver", "", "person accepting the risk")
+	cmd.Flags().StringVar(&addOpts.waiver.Expires, "expires", "", "date the waiver expires on, formatted as YYYY-MM-DD")
+	addOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runWaiverAdd(opts *waiverAddOptions) error {
+	waiversPath := filepath.Join(opts.complyTimeOpts.UserWorkspace, waiversLocation)
+	waivers, err := complytime.ReadWaivers(waiversPath)
+	if err != nil {
+		return err
+	}
+	if err := waivers.Add(opts.waiver, time.Now()); err != nil {
+		return err
+	}
+	if err := waivers.Write(waiversPath); err != nil {
+		return fmt.Errorf("error writing waivers to %s: %w", waiversPath, err)
+	}
+	_, _ = fmt.Fprintf(opts.Out, "Added waiver of %s %s expiring on %s to %s\n", opts.waiver.Kind(), opts.waiver.ID(), opts.waiver.Expires, waiversPath)
+	return nil
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// waiverExpireOptions defines options for the "waiver expire" subcommand
+type waiverExpireOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+
+	// id is the rule or control ID of the waivers to expire
+	id string
+}
+
+// waiverExpireCmd creates a new cobra.Command for the "waiver expire" subcommand
+func waiverExpireCmd(common *option.Common) *cobra.Command {
+	expireOpts := &waiverExpireOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:          "expire [flags] id",
+		Short:        "Expire the active waiver of a rule or control today, so its failures are reported again.",
+		Example:      "complyctl waiver expire my_rule",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		PreRun: func(_ *cobra.Command, args []string) {
+			expireOpts.id = args[0]
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runWaiverExpire(expireOpts)
+		},
+	}
+	expireOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runWaiverExpire(opts *waiverExpireOptions) error {
+	waiversPath := filepath.Join(opts.complyTimeOpts.UserWorkspace, waiversLocation)
+	waivers, err := complytime.ReadWaivers(waiversPath)
+	if err != nil {
+		return err
+	}
+	expired, err := waivers.Expire(opts.id, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := waivers.Write(waiversPath); err != nil {
+		return fmt.Errorf("error writing waivers to %s: %w", waiversPath, err)
+	}
+	_, _ = fmt.Fprintf(opts.Out, "Expired %d waiver(s) of %s in %s\n", expired, opts.id, waiversPath)
+	return nil
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/terminal"
+)
+
+// waiverListOptions defines options for the "waiver list" subcommand
+type waiverListOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+}
+
+// waiverListCmd creates a new cobra.Command for the "waiver list" subcommand
+func waiverListCmd(common *option.Common) *cobra.Command {
+	listOpts := &waiverListOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:          "list [flags]",
+		Short:        "List the waivers of the workspace and whether they expired.",
+		Example:      "complyctl waiver list",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runWaiverList(listOpts)
+		},
+	}
+	listOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runWaiverList(opts *waiverListOptions) error {
+	waiversPath := filepath.Join(opts.complyTimeOpts.UserWorkspace, waiversLocation)
+	waivers, err := complytime.ReadWaivers(waiversPath)
+	if err != nil {
+		return err
+	}
+	logger.Debug(fmt.Sprintf("Found %d waivers in %s", len(waivers.Waivers), waiversPath))
+	if len(waivers.Waivers) == 0 {
+		_, _ = fmt.Fprintln(opts.Out, "No waivers found")
+		return nil
+	}
+	columns := []table.Column{
+		{Title: "Kind", Width: 9},
+		{Title: "ID", Width: 20},
+		{Title: "Approver", Width: 15},
+		{Title: "Expires", Width: 12},
+		{Title: "Status", Width: 9},
+		{Title: "Justification", Width: 30},
+	}
+	rows := waiverRows(waivers.Waivers, time.Now())
+	// Keep a gap between columns, the plain table does not separate them
+	for _, row := range rows {
+		for i, cell := range row {
+			if len(cell)+columnGap > columns[i].Width {
+				columns[i].Width = len(cell) + columnGap
+			}
+		}
+	}
+	terminal.ShowPlainTable(opts.Out, columns, rows)
+	return nil
+}
+
+// waiverRows returns a table row for each waiver with its status at the given time.
+func waiverRows(waivers []complytime.Waiver, now time.Time) []table.Row {
+	var rows []table.Row
+	for _, waiver := range waivers {
+		status := "active"
+		if waiver.Expired(now) {
+			status = "expired"
+		}
+		rows = append(rows, table.Row{waiver.Kind(), waiver.ID(), waiver.Approver, waiver.Expires, status, waiver.Justification})
+	}
+	return rows
+}
@@ -3,17 +3,33 @@
 package option
 
 import (
+	"fmt"
 	"io"
 	"path/filepath"
 
 	"github.com/spf13/pflag"
 
 	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/pkg/log"
 )
 
 // Common options for the complytctl CLI.
 type Common struct {
 	Debug bool
+	// ContentDirs are additional content roots with the highest precedence in the content path.
+	ContentDirs []string
+	// Context is the name of the context of the configuration file, defaulting to the current context.
+	Context string
+	// PluginOverrides are the plugin option values of the context, indexed by plugin ID and option name.
+	PluginOverrides map[string]map[string]string
+	// LogFormat is the format of the logs, text or json.
+	LogFormat string
+	// LogFile is the file the logs are appended to, defaulting to stderr.
+	LogFile string
+	// TraceEndpoint is the OTLP/HTTP endpoint the spans of complyctl and its plugins are exported to.
+	TraceEndpoint string
+	// TraceFile is the file the spans of complyctl and its plugins are appended to.
+	TraceFile string
 	Output
 }
 
@@ -28,6 +44,18 @@ type Output struct {
 // BindFlags populate Common options from user-specified flags.
 func (o *Common) BindFlags(fs *pflag.FlagSet) {
 	fs.BoolVarP(&o.Debug, "debug", "d", false, "output debug logs")
+	fs.StringArrayVar(&o.ContentDirs, "content-dir", nil, "additional content root searched before all other content roots, can be repeated")
+	fs.StringVar(&o.LogFormat, "log-format", log.FormatText, fmt.Sprintf("format of the logs, %s or %s", log.FormatText, log.FormatJSON))
+	fs.StringVar(&o.LogFile, "log-file", "", "file the logs are appended to, defaults to stderr")
+	fs.StringVar(&o.TraceEndpoint, "trace-endpoint", "", "OTLP/HTTP endpoint the OpenTelemetry spans of complyctl and its plugins are exported to, such as http://localhost:4318")
+	fs.StringVar(&o.TraceFile, "trace-file", "", "file the OpenTelemetry spans of complyctl and its plugins are appended to as OTLP JSON lines")
+	fs.StringVar(&o.Context, ContextFlag, "", "context of the configuration file to use, defaults to the current context")
+}
+
+// ContentPath returns the content path searched for component definitions, control sources,
+// and plugin manifests.
+func (o *Common) ContentPath() complytime.ContentPath {
+	return complytime.NewContentPath(o.ContentDirs)
 }
 
 // ComplyTime options are configurations needed for the complyctl CLI to run.
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package option
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/complytime/complyctl/internal/config"
+)
+
+// ContextFlag is the name of the flag selecting the context of the configuration file.
+const ContextFlag = "context"
+
+// isListFlag returns whether the flag is repeated to set several values.
+func isListFlag(flag *pflag.Flag) bool {
+	return strings.HasSuffix(flag.Value.Type(), "Array")
+}
+
+// ApplyEnvironment sets the flags that were not given on the command line from their
+// environment variables, such as COMPLYCTL_WORKSPACE for --workspace. The values of
+// repeatable flags are separated by commas. Empty environment variables are ignored.
+func ApplyEnvironment(fs *pflag.FlagSet, lookupEnv func(string) (string, bool)) error {
+	var err error
+	fs.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed || flag.Name == "help" {
+			return
+		}
+		envName := config.EnvName(flag.Name)
+		value, found := lookupEnv(envName)
+		if !found || value == "" {
+			return
+		}
+		values := []string{value}
+		if isListFlag(flag) {
+			values = strings.Split(value, ",")
+		}
+		if setErr := setFlag(fs, flag, values); setErr != nil {
+			err = fmt.Errorf("invalid environment variable %s: %w", envName, setErr)
+		}
+	})
+	return err
+}
+
+// ApplyContext sets the flags that were not given on the command line or in the environment
+// from the settings of the context.
+func ApplyContext(fs *pflag.FlagSet, name string, context config.Context) error {
+	var err error
+	fs.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed || flag.Name == "help" || flag.Name == ContextFlag {
+			return
+		}
+		setting, found := context[flag.Name]
+		if !found {
+			return
+		}
+		if setErr := setFlag(fs, flag, config.Values(setting)); setErr != nil {
+			err = fmt.Errorf("invalid setting %s of context %s: %w", flag.Name, name, setErr)
+		}
+	})
+	return err
+}
+
+// setFlag sets the values of a flag as if it was given once per value on the command line.
+func setFlag(fs *pflag.FlagSet, flag *pflag.Flag, values []string) error {
+	for _, value := range values {
+		if err := fs.Set(flag.Name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/complytime/complyctl/internal/complytime/manual"
+)
+
+const (
+	PluginDir      string = manual.PluginID
+	ResultsDir     string = "results"
+	RemediationDir string = "remediations"
+)
+
+type Config struct {
+	Files struct {
+		// Workspace is the workspace directory of complyctl.
+		Workspace string
+		// Attestations is the attestations file of the workspace, written by complyctl attest.
+		Attestations string
+		// Results is the results directory of the plugin.
+		Results string
+		// Remediations is the remediations directory of the plugin.
+		Remediations string
+	}
+	Parameters struct {
+		Profile string
+	}
+}
+
+// NewConfig creates a new, empty Config.
+func NewConfig() *Config {
+	return &Config{}
+}
+
+// LoadSettings sets the values in the Config from a given config map,
+// performs validation, and creates the workspace directories of the plugin.
+func (c *Config) LoadSettings(config map[string]string) error {
+	for _, key := range []string{"workspace", "profile"} {
+		if config[key] == "" {
+			return fmt.Errorf("missing configuration value for option %q", key)
+		}
+	}
+	workspace, err := filepath.Abs(filepath.Clean(config["workspace"]))
+	if err != nil {
+		return err
+	}
+	c.Files.Workspace = workspace
+	c.Files.Attestations = filepath.Join(workspace, manual.AttestationsLocation)
+	c.Files.Results = filepath.Join(workspace, PluginDir, ResultsDir)
+	c.Files.Remediations = filepath.Join(workspace, PluginDir, RemediationDir)
+	c.Parameters.Profile = config["profile"]
+	return c.ensureWorkspace()
+}
+
+// ensureWorkspace creates the workspace directories of the plugin.
+func (c *Config) ensureWorkspace() error {
+	for _, dir := range []string{c.Files.Results, c.Files.Remediations} {
+		_, err := os.Stat(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			if err := os.MkdirAll(dir, 0750); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			hclog.Default().Info("Directory created", "path", dir)
+		} else if err != nil {
+			return fmt.Errorf("error checking directory: %w", err)
+		}
+	}
+	return nil
+}
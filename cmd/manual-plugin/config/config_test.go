@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSettings(t *testing.T) {
+	workspace := t.TempDir()
+	cfg := NewConfig()
+	require.EqualError(t, cfg.LoadSettings(map[string]string{"workspace": workspace}),
+		`missing configuration value for option "profile"`)
+
+	require.NoError(t, cfg.LoadSettings(map[string]string{"workspace": workspace, "profile": "baseline"}))
+	require.Equal(t, filepath.Join(workspace, "attestations.yml"), cfg.Files.Attestations)
+	require.Equal(t, "baseline", cfg.Parameters.Profile)
+	require.DirExists(t, filepath.Join(workspace, PluginDir, ResultsDir))
+	require.DirExists(t, filepath.Join(workspace, PluginDir, RemediationDir))
+}
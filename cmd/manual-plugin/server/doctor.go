@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/complytime/complyctl/internal/complytime/manual"
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+// Doctor checks the attestations file of the workspace for the given
+// configuration selections.
+func Doctor(selections map[string]string) []doctor.Check {
+	if selections["workspace"] == "" {
+		return []doctor.Check{doctor.Warned("attestations", "the workspace option is not set",
+			"Set the workspace with the --workspace flag of complyctl")}
+	}
+	attestationsPath := filepath.Join(selections["workspace"], manual.AttestationsLocation)
+	attestations, err := manual.ReadAttestations(attestationsPath)
+	if err != nil {
+		return []doctor.Check{doctor.Failed("attestations", err.Error(),
+			fmt.Sprintf("Fix the attestations in %s, or answer the manual checks again with complyctl attest", attestationsPath))}
+	}
+	expired := 0
+	now := time.Now()
+	for _, attestation := range attestations.Attestations {
+		if attestation.Expired(now) {
+			expired++
+		}
+	}
+	if expired > 0 {
+		return []doctor.Check{doctor.Warned("attestations",
+			fmt.Sprintf("%d of %d attestations in %s expired", expired, len(attestations.Attestations), attestationsPath),
+			"Answer the expired manual checks with complyctl attest")}
+	}
+	return []doctor.Check{doctor.Passed("attestations",
+		fmt.Sprintf("%d attestations in %s", len(attestations.Attestations), attestationsPath))}
+}
@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime/manual"
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+func TestDoctor(t *testing.T) {
+	workspace := t.TempDir()
+	checks := Doctor(map[string]string{"workspace": workspace})
+	require.Len(t, checks, 1)
+	require.Equal(t, doctor.Pass, checks[0].Status)
+
+	attestations := manual.Attestations{Attestations: []manual.Attestation{
+		{Check: "training_completed", Answer: manual.AnswerPass, AttestedBy: "Jane Doe", AttestedAt: time.Now(), Expires: "2020-01-01"},
+	}}
+	attestationsPath := filepath.Join(workspace, manual.AttestationsLocation)
+	require.NoError(t, attestations.Write(attestationsPath))
+	checks = Doctor(map[string]string{"workspace": workspace})
+	require.Equal(t, doctor.Warn, checks[0].Status)
+	require.Contains(t, checks[0].Message, "1 of 1 attestations")
+
+	require.NoError(t, os.WriteFile(attestationsPath, []byte("attestations: invalid"), 0600))
+	checks = Doctor(map[string]string{"workspace": workspace})
+	require.Equal(t, doctor.Fail, checks[0].Status)
+	require.Contains(t, checks[0].Fix, "complyctl attest")
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+
+	"github.com/complytime/complyctl/cmd/manual-plugin/config"
+	"github.com/complytime/complyctl/internal/complytime/manual"
+	"github.com/complytime/complyctl/pkg/pluginutil"
+	"github.com/complytime/complyctl/pkg/trace"
+)
+
+var _ policy.Provider = (*PluginServer)(nil)
+
+type PluginServer struct {
+	Config *config.Config
+}
+
+func New() PluginServer {
+	return PluginServer{
+		Config: config.NewConfig(),
+	}
+}
+
+func (s PluginServer) Configure(configMap map[string]string) error {
+	return s.Config.LoadSettings(configMap)
+}
+
+// Generate has no policy to translate, the manual checks are answered with complyctl attest.
+// It reports the checks of the policy without a current attestation.
+func (s PluginServer) Generate(policy policy.Policy) (err error) {
+	_, span := trace.Start(trace.FromEnvironment(context.Background()), "manual.Generate")
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	if err := pluginutil.ValidatePolicy(policy); err != nil {
+		return err
+	}
+	attestations, err := manual.ReadAttestations(s.Config.Files.Attestations)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, ruleSet := range policy {
+		for _, check := range ruleSet.Checks {
+			if attestation, found := attestations.Find(check.ID); !found || attestation.Expired(now) {
+				hclog.Default().Warn("Manual check needs an attestation, run complyctl attest", "check", check.ID, "rule", ruleSet.Rule.ID)
+			}
+		}
+	}
+	return nil
+}
+
+func (s PluginServer) GetResults(oscalPolicy policy.Policy) (_ policy.PVPResult, err error) {
+	_, span := trace.Start(trace.FromEnvironment(context.Background()), "manual.GetResults")
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	if err := pluginutil.ValidatePolicy(oscalPolicy); err != nil {
+		return policy.PVPResult{}, err
+	}
+	attestations, err := manual.ReadAttestations(s.Config.Files.Attestations)
+	if err != nil {
+		return policy.PVPResult{}, err
+	}
+
+	pvpResults := policy.PVPResult{}
+	observed := make(map[string]bool)
+	now := time.Now()
+	for _, ruleSet := range oscalPolicy {
+		for _, check := range ruleSet.Checks {
+			if observed[check.ID] {
+				continue
+			}
+			observed[check.ID] = true
+			attestation, found := attestations.Find(check.ID)
+			observation := s.observation(ruleSet.Rule.ID, check.ID, check.Description, attestation, found, now)
+			pvpResults.ObservationsByCheck = append(pvpResults.ObservationsByCheck, observation)
+		}
+	}
+	return pvpResults, nil
+}
+
+// observation returns the observation of a manual check from its attestation. Checks without a current
+// attestation have an error result.
+func (s PluginServer) observation(ruleID, checkID, description string, attestation manual.Attestation, found bool, now time.Time) policy.ObservationByCheck {
+	observation := policy.ObservationByCheck{
+		Title:       ruleID,
+		Description: description,
+		CheckID:     checkID,
+		Methods:     []string{"MANUAL"},
+		Collected:   now,
+	}
+	subject := policy.Subject{
+		Title:       fmt.Sprintf("Attestation of %s", checkID),
+		Type:        "resource",
+		ResourceID:  checkID,
+		EvaluatedOn: now,
+	}
+
+	switch {
+	case !found:
+		subject.Result = policy.ResultError
+		subject.Reason = "check has no attestation, run complyctl attest"
+	case attestation.Expired(now):
+		subject.Result = policy.ResultError
+		subject.Reason = fmt.Sprintf("attestation by %s expired on %s, run complyctl attest", attestation.AttestedBy, attestation.Expires)
+	default:
+		subject.Result = policy.ResultFail
+		if attestation.Answer == manual.AnswerPass {
+			subject.Result = policy.ResultPass
+		}
+		subject.Reason = fmt.Sprintf("attested %s by %s on %s", attestation.Answer, attestation.AttestedBy, attestation.AttestedAt.Format(time.DateOnly))
+		if attestation.Comment != "" {
+			subject.Reason += ": " + attestation.Comment
+		}
+	}
+	if found {
+		subject.EvaluatedOn = attestation.AttestedAt
+		subject.Props = []policy.Property{
+			{
+				Name:  "attested-by",
+				Value: attestation.AttestedBy,
+			},
+			{
+				Name:  "attestation-expires",
+				Value: attestation.Expires,
+			},
+		}
+		observation.RelevantEvidences = append(observation.RelevantEvidences, policy.Link{
+			Href:        fmt.Sprintf("file://%s", s.Config.Files.Attestations),
+			Description: "ATTESTATIONS_FILE",
+		})
+		for _, link := range attestation.EvidenceLinks(s.Config.Files.Workspace) {
+			observation.RelevantEvidences = append(observation.RelevantEvidences, policy.Link{
+				Href:        link,
+				Description: "ATTESTATION_EVIDENCE",
+			})
+		}
+	}
+	observation.Subjects = []policy.Subject{subject}
+	return observation
+}
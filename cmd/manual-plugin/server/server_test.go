@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime/manual"
+)
+
+func TestGetResults(t *testing.T) {
+	now := time.Now()
+	expires := now.AddDate(1, 0, 0).Format(time.DateOnly)
+	workspace := t.TempDir()
+	file := manual.Attestations{Attestations: []manual.Attestation{
+		{
+			Check: "training_completed", Answer: manual.AnswerPass, AttestedBy: "Jane Doe", AttestedAt: now,
+			Expires: expires, Comment: "All staff trained", Evidence: []string{"/srv/training.pdf", "https://example.com/report"},
+		},
+		{Check: "visitor_log_reviewed", Answer: manual.AnswerFail, AttestedBy: "John Doe", AttestedAt: now, Expires: expires},
+		{Check: "badge_audit", Answer: manual.AnswerPass, AttestedBy: "Jane Doe", AttestedAt: now.AddDate(-1, 0, 0), Expires: "2020-01-01"},
+	}}
+	require.NoError(t, file.Write(filepath.Join(workspace, manual.AttestationsLocation)))
+	s := New()
+	require.NoError(t, s.Configure(map[string]string{"workspace": workspace, "profile": "baseline"}))
+	testPolicy := policy.Policy{
+		{
+			Rule:   extensions.Rule{ID: "security_awareness_training"},
+			Checks: []extensions.Check{{ID: "training_completed", Description: "Staff completed the training"}},
+		},
+		{
+			Rule:   extensions.Rule{ID: "physical_access"},
+			Checks: []extensions.Check{{ID: "visitor_log_reviewed"}, {ID: "badge_audit"}, {ID: "camera_footage_kept"}},
+		},
+	}
+	require.NoError(t, s.Generate(testPolicy))
+	results, err := s.GetResults(testPolicy)
+	require.NoError(t, err)
+	require.Len(t, results.ObservationsByCheck, 4)
+
+	wantResults := []struct {
+		rule   string
+		check  string
+		result policy.Result
+		reason string
+	}{
+		{"security_awareness_training", "training_completed", policy.ResultPass, "attested pass by Jane Doe on " + now.Format(time.DateOnly) + ": All staff trained"},
+		{"physical_access", "visitor_log_reviewed", policy.ResultFail, "attested fail by John Doe on " + now.Format(time.DateOnly)},
+		{"physical_access", "badge_audit", policy.ResultError, "attestation by Jane Doe expired on 2020-01-01, run complyctl attest"},
+		{"physical_access", "camera_footage_kept", policy.ResultError, "check has no attestation, run complyctl attest"},
+	}
+	for i, want := range wantResults {
+		observation := results.ObservationsByCheck[i]
+		assert.Equal(t, want.rule, observation.Title)
+		assert.Equal(t, want.check, observation.CheckID)
+		assert.Equal(t, []string{"MANUAL"}, observation.Methods)
+		require.Len(t, observation.Subjects, 1)
+		assert.Equal(t, "resource", observation.Subjects[0].Type)
+		assert.Equal(t, want.result, observation.Subjects[0].Result, want.check)
+		assert.Equal(t, want.reason, observation.Subjects[0].Reason)
+	}
+
+	evidences := results.ObservationsByCheck[0].RelevantEvidences
+	require.Len(t, evidences, 3)
+	assert.Equal(t, "file://"+s.Config.Files.Attestations, evidences[0].Href)
+	assert.Equal(t, "file:///srv/training.pdf", evidences[1].Href)
+	assert.Equal(t, "https://example.com/report", evidences[2].Href)
+	assert.Empty(t, results.ObservationsByCheck[3].RelevantEvidences)
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/complytime/complyctl/cmd/opa-plugin/rego"
+)
+
+const (
+	PluginDir      string = "opa"
+	BundleDir      string = "bundle"
+	ResultsDir     string = "results"
+	RemediationDir string = "remediations"
+	// InputFile is the file of the results directory holding the input document of the last scan.
+	InputFile string = "input.json"
+	// DefaultOPA is the OPA executable when the opa option is not set.
+	DefaultOPA string = "opa"
+	// DefaultTimeout is the time the evaluation of a check can take when the timeout option is not set.
+	DefaultTimeout = 30 * time.Second
+)
+
+type Config struct {
+	Files struct {
+		// Workspace is the workspace directory of complyctl.
+		Workspace string
+		// Policies is the directory of the Rego policies.
+		Policies string
+		// Bundle is the directory of the Rego bundle assembled by Generate.
+		Bundle string
+		// Results is the directory of the input document of the policies.
+		Results string
+		// Remediations is the directory of the remediations, which the plugin does not provide.
+		Remediations string
+	}
+	Parameters struct {
+		Profile string
+		// OPA is the path of the OPA executable.
+		OPA string
+		// Inputs are the patterns of the input files evaluated by the policies.
+		Inputs []rego.InputPattern
+		// Timeout is the time the evaluation of a check can take.
+		Timeout time.Duration
+	}
+}
+
+// NewConfig creates a new, empty Config.
+func NewConfig() *Config {
+	return &Config{}
+}
+
+// LoadSettings sets the values in the Config from a given config map,
+// performs validation, and creates the workspace directories of the plugin.
+func (c *Config) LoadSettings(config map[string]string) error {
+	for _, key := range []string{"workspace", "profile", "policies", "inputs"} {
+		if config[key] == "" {
+			return fmt.Errorf("missing configuration value for option %q", key)
+		}
+	}
+
+	policies, err := filepath.Abs(filepath.Clean(config["policies"]))
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(policies); err != nil {
+		return fmt.Errorf("invalid policies directory: %w", err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("invalid policies directory: %s is not a directory", policies)
+	}
+
+	inputs, err := rego.ParseInputs(config["inputs"])
+	if err != nil {
+		return fmt.Errorf("invalid inputs: %w", err)
+	}
+
+	opa := config["opa"]
+	if opa == "" {
+		opa = DefaultOPA
+	}
+	opaPath, err := exec.LookPath(opa)
+	if err != nil {
+		return fmt.Errorf("invalid opa executable: %w", err)
+	}
+
+	timeout := DefaultTimeout
+	if config["timeout"] != "" {
+		timeout, err = time.ParseDuration(config["timeout"])
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid timeout %q, expected a positive duration such as 30s", config["timeout"])
+		}
+	}
+
+	workspace, err := filepath.Abs(filepath.Clean(config["workspace"]))
+	if err != nil {
+		return err
+	}
+	c.Files.Workspace = workspace
+	c.Files.Policies = policies
+	c.Files.Bundle = filepath.Join(workspace, PluginDir, BundleDir)
+	c.Files.Results = filepath.Join(workspace, PluginDir, ResultsDir)
+	c.Files.Remediations = filepath.Join(workspace, PluginDir, RemediationDir)
+	c.Parameters.Profile = config["profile"]
+	c.Parameters.OPA = opaPath
+	c.Parameters.Inputs = inputs
+	c.Parameters.Timeout = timeout
+	return c.ensureWorkspace()
+}
+
+// ensureWorkspace creates the workspace directories of the plugin.
+func (c *Config) ensureWorkspace() error {
+	for _, dir := range []string{c.Files.Bundle, c.Files.Results, c.Files.Remediations} {
+		_, err := os.Stat(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			if err := os.MkdirAll(dir, 0750); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			hclog.Default().Info("Directory created", "path", dir)
+		} else if err != nil {
+			return fmt.Errorf("error checking directory: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/opa-plugin/rego"
+)
+
+func TestLoadSettings(t *testing.T) {
+	workspace, policiesDir := t.TempDir(), t.TempDir()
+	opa, err := filepath.Abs("../rego/testdata/opa")
+	require.NoError(t, err)
+	cfg := NewConfig()
+	require.NoError(t, cfg.LoadSettings(map[string]string{
+		"workspace": workspace,
+		"profile":   "baseline",
+		"policies":  policiesDir,
+		"inputs":    "/etc/docker/daemon.json, /etc/ssh/sshd_config.d/*.conf:text",
+		"opa":       opa,
+	}))
+	require.Equal(t, policiesDir, cfg.Files.Policies)
+	require.Equal(t, filepath.Join(workspace, PluginDir, BundleDir), cfg.Files.Bundle)
+	require.Equal(t, opa, cfg.Parameters.OPA)
+	require.Equal(t, []rego.InputPattern{
+		{Pattern: "/etc/docker/daemon.json"},
+		{Pattern: "/etc/ssh/sshd_config.d/*.conf", Format: rego.FormatText},
+	}, cfg.Parameters.Inputs)
+	require.Equal(t, DefaultTimeout, cfg.Parameters.Timeout)
+	for _, dir := range []string{cfg.Files.Bundle, cfg.Files.Results, cfg.Files.Remediations} {
+		require.DirExists(t, dir)
+	}
+
+	require.NoError(t, cfg.LoadSettings(map[string]string{
+		"workspace": workspace,
+		"profile":   "baseline",
+		"policies":  policiesDir,
+		"inputs":    "/etc/docker/daemon.json",
+		"opa":       opa,
+		"timeout":   "2m",
+	}))
+	require.Equal(t, 2*time.Minute, cfg.Parameters.Timeout)
+}
+
+func TestLoadSettingsInvalid(t *testing.T) {
+	workspace, policiesDir := t.TempDir(), t.TempDir()
+	opa, err := filepath.Abs("../rego/testdata/opa")
+	require.NoError(t, err)
+	tests := []struct {
+		name    string
+		config  map[string]string
+		wantErr string
+	}{
+		{
+			name:    "MissingPolicies",
+			config:  map[string]string{"workspace": workspace, "profile": "baseline", "inputs": "/etc/app.conf"},
+			wantErr: `missing configuration value for option "policies"`,
+		},
+		{
+			name:    "MissingInputs",
+			config:  map[string]string{"workspace": workspace, "profile": "baseline", "policies": policiesDir},
+			wantErr: `missing configuration value for option "inputs"`,
+		},
+		{
+			name:    "PoliciesNotFound",
+			config:  map[string]string{"workspace": workspace, "profile": "baseline", "policies": filepath.Join(policiesDir, "missing"), "inputs": "/etc/app.conf"},
+			wantErr: "invalid policies directory",
+		},
+		{
+			name:    "InvalidInputs",
+			config:  map[string]string{"workspace": workspace, "profile": "baseline", "policies": policiesDir, "inputs": "/etc/["},
+			wantErr: "invalid inputs",
+		},
+		{
+			name:    "OPANotFound",
+			config:  map[string]string{"workspace": workspace, "profile": "baseline", "policies": policiesDir, "inputs": "/etc/app.conf", "opa": "/nonexistent/opa"},
+			wantErr: "invalid opa executable",
+		},
+		{
+			name:    "InvalidTimeout",
+			config:  map[string]string{"workspace": workspace, "profile": "baseline", "policies": policiesDir, "inputs": "/etc/app.conf", "opa": opa, "timeout": "-1s"},
+			wantErr: `invalid timeout "-1s"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			require.ErrorContains(t, cfg.LoadSettings(tt.config), tt.wantErr)
+			require.Empty(t, cfg.Files.Workspace)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rego
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+
+	"github.com/complytime/complyctl/pkg/pluginutil"
+)
+
+const (
+	// BundleFile is the file of the bundle listing the checks and their policies. OPA ignores it, as it
+	// only loads the Rego files and data files of a bundle.
+	BundleFile = "bundle.yaml"
+	// DataFile is the data file of the bundle holding the rule parameters.
+	DataFile = "data.json"
+)
+
+// Bundle is the Rego bundle assembled for a policy. The directory of the bundle holds the Rego files of
+// the policies directory and the parameters of the rules as data.
+type Bundle struct {
+	Checks []BundleCheck `yaml:"checks"`
+}
+
+// BundleCheck is a check of a rule of the policy.
+type BundleCheck struct {
+	// ID is the ID of the check.
+	ID string `yaml:"id"`
+	// Rule is the ID of the rule of the check.
+	Rule string `yaml:"rule"`
+	// Description describes what the check verifies.
+	Description string `yaml:"description,omitempty"`
+	// Package is the package of the policy of the check, empty when the check has no policy.
+	Package string `yaml:"package,omitempty"`
+	// Policies are the files declaring the package, relative to the bundle directory.
+	Policies []string `yaml:"policies,omitempty"`
+}
+
+// Data is the data document of the bundle.
+type Data struct {
+	// Parameters are the values of the rule parameters by parameter ID, available to the policies as data.parameters.
+	Parameters map[string]string `json:"parameters"`
+}
+
+// Render assembles the Rego bundle of the policy into the bundle directory, replacing a previous bundle.
+// The Rego files of the policies directory are copied, and the parameters of the rules are written as data.
+// Checks without policy are recorded without package, so they are reported as errors.
+func Render(bundleDir, policiesDir string, p policy.Policy, policies []Policy) (Bundle, error) {
+	if err := pluginutil.ValidatePolicy(p); err != nil {
+		return Bundle{}, err
+	}
+	if err := os.RemoveAll(bundleDir); err != nil {
+		return Bundle{}, err
+	}
+	if err := os.MkdirAll(bundleDir, 0750); err != nil {
+		return Bundle{}, err
+	}
+
+	policyFiles := make(map[string][]string)
+	for _, regoPolicy := range policies {
+		if err := copyFile(filepath.Join(policiesDir, regoPolicy.Path), filepath.Join(bundleDir, regoPolicy.Path)); err != nil {
+			return Bundle{}, err
+		}
+		policyFiles[regoPolicy.Package] = append(policyFiles[regoPolicy.Package], regoPolicy.Path)
+	}
+
+	var bundle Bundle
+	data := Data{Parameters: make(map[string]string)}
+	parameterRules := make(map[string]string)
+	packageChecks := make(map[string]string)
+	rendered := make(map[string]bool)
+	for _, ruleSet := range p {
+		for _, parameter := range ruleSet.Rule.Parameters {
+			if previous, found := data.Parameters[parameter.ID]; found && previous != parameter.Value {
+				return Bundle{}, fmt.Errorf("parameter %s has the value %q in rule %s and %q in rule %s",
+					parameter.ID, previous, parameterRules[parameter.ID], parameter.Value, ruleSet.Rule.ID)
+			}
+			data.Parameters[parameter.ID] = parameter.Value
+			parameterRules[parameter.ID] = ruleSet.Rule.ID
+		}
+		for _, check := range ruleSet.Checks {
+			if rendered[check.ID] {
+				continue
+			}
+			rendered[check.ID] = true
+			bundleCheck := BundleCheck{ID: check.ID, Rule: ruleSet.Rule.ID, Description: check.Description}
+			checkPackage := CheckPackage(check.ID)
+			if previous, found := packageChecks[checkPackage]; found {
+				return Bundle{}, fmt.Errorf("checks %s and %s have the same policy package %s", previous, check.ID, checkPackage)
+			}
+			packageChecks[checkPackage] = check.ID
+			if files, found := policyFiles[checkPackage]; found {
+				bundleCheck.Package = checkPackage
+				bundleCheck.Policies = files
+			}
+			bundle.Checks = append(bundle.Checks, bundleCheck)
+		}
+	}
+
+	dataJSON, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return Bundle{}, err
+	}
+	if err := os.WriteFile(filepath.Join(bundleDir, DataFile), dataJSON, 0600); err != nil {
+		return Bundle{}, err
+	}
+	bundleYAML, err := yaml.Marshal(bundle)
+	if err != nil {
+		return Bundle{}, err
+	}
+	return bundle, os.WriteFile(filepath.Join(bundleDir, BundleFile), bundleYAML, 0600)
+}
+
+// ReadBundle reads the bundle of the bundle directory.
+func ReadBundle(bundleDir string) (Bundle, error) {
+	data, err := os.ReadFile(filepath.Join(bundleDir, BundleFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return Bundle{}, fmt.Errorf("no Rego bundle in %s, generate the policy first", bundleDir)
+	}
+	if err != nil {
+		return Bundle{}, err
+	}
+	var bundle Bundle
+	if err := yaml.Unmarshal(data, &bundle); err != nil {
+		return Bundle{}, fmt.Errorf("invalid Rego bundle %s: %w", filepath.Join(bundleDir, BundleFile), err)
+	}
+	return bundle, nil
+}
+
+// copyFile copies a policy file, creating the directory of the destination.
+func copyFile(source, destination string) error {
+	data, err := os.ReadFile(filepath.Clean(source))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(destination), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(destination, data, 0600)
+}
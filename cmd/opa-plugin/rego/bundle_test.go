@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rego
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	policies, err := LoadPolicies("testdata/policies")
+	require.NoError(t, err)
+	bundleDir := filepath.Join(t.TempDir(), "bundle")
+	require.NoError(t, os.MkdirAll(bundleDir, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "stale.rego"), []byte("package checks.stale\n"), 0600))
+
+	testPolicy := policy.Policy{
+		{
+			Rule: extensions.Rule{
+				ID:         "docker_hardened",
+				Parameters: []extensions.Parameter{{ID: "var_docker_log_driver", Value: "journald"}},
+			},
+			Checks: []extensions.Check{{ID: "docker_live_restore", Description: "Containers survive daemon restarts"}, {ID: "undefined_check"}},
+		},
+		{
+			Rule: extensions.Rule{
+				ID:         "docker_logging",
+				Parameters: []extensions.Parameter{{ID: "var_docker_log_driver", Value: "journald"}},
+			},
+			Checks: []extensions.Check{{ID: "docker_live_restore"}},
+		},
+	}
+	bundle, err := Render(bundleDir, "testdata/policies", testPolicy, policies)
+	require.NoError(t, err)
+	assert.Equal(t, Bundle{Checks: []BundleCheck{
+		{
+			ID:          "docker_live_restore",
+			Rule:        "docker_hardened",
+			Description: "Containers survive daemon restarts",
+			Package:     "checks.docker_live_restore",
+			Policies:    []string{"docker_live_restore.rego"},
+		},
+		{ID: "undefined_check", Rule: "docker_hardened"},
+	}}, bundle)
+
+	assert.NoFileExists(t, filepath.Join(bundleDir, "stale.rego"))
+	assert.NoFileExists(t, filepath.Join(bundleDir, "docker_live_restore_test.rego"))
+	assert.FileExists(t, filepath.Join(bundleDir, "lib", "files.rego"))
+	data, err := os.ReadFile(filepath.Join(bundleDir, DataFile))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"parameters": {"var_docker_log_driver": "journald"}}`, string(data))
+
+	read, err := ReadBundle(bundleDir)
+	require.NoError(t, err)
+	assert.Equal(t, bundle, read)
+	_, err = ReadBundle(t.TempDir())
+	require.ErrorContains(t, err, "generate the policy first")
+}
+
+func TestRenderErrors(t *testing.T) {
+	bundleDir := t.TempDir()
+	tests := []struct {
+		name    string
+		policy  policy.Policy
+		wantErr string
+	}{
+		{
+			name: "Invalid/ParameterValues",
+			policy: policy.Policy{
+				{Rule: extensions.Rule{ID: "rule_a", Parameters: []extensions.Parameter{{ID: "var", Value: "1"}}}},
+				{Rule: extensions.Rule{ID: "rule_b", Parameters: []extensions.Parameter{{ID: "var", Value: "2"}}}},
+			},
+			wantErr: `parameter var has the value "1" in rule rule_a and "2" in rule rule_b`,
+		},
+		{
+			name:    "Invalid/SamePackage",
+			policy:  policy.Policy{{Rule: extensions.Rule{ID: "rule"}, Checks: []extensions.Check{{ID: "check-a"}, {ID: "check.a"}}}},
+			wantErr: "checks check-a and check.a have the same policy package checks.check_a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Render(bundleDir, "testdata/policies", tt.policy, nil)
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
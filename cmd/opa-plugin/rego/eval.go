@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rego
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// waitDelay is the time given to OPA to release its output after it is killed.
+const waitDelay = time.Second
+
+// evalOutput is the JSON output of opa eval.
+type evalOutput struct {
+	Result []struct {
+		Expressions []struct {
+			Value any `json:"value"`
+		} `json:"expressions"`
+	} `json:"result"`
+	Errors []struct {
+		Message  string `json:"message"`
+		Location *struct {
+			File string `json:"file"`
+			Row  int    `json:"row"`
+		} `json:"location"`
+	} `json:"errors"`
+}
+
+// Eval evaluates the deny rule of the policy package with opa eval, against the bundle and the input
+// document, and returns the deny messages. Messages that are not strings are formatted as JSON. An error
+// is returned when OPA fails, times out, or the package has no deny rule.
+func Eval(ctx context.Context, opa, bundleDir, inputPath, pkg string, timeout time.Duration) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	query := fmt.Sprintf("data.%s.%s", pkg, DenyRule)
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, opa, "eval", "--format", "json", "--bundle", bundleDir, "--input", inputPath, query)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = waitDelay
+	hclog.Default().Debug("Executing command", "command", cmd.Args)
+	runErr := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("opa eval of %s timed out after %s", query, timeout)
+	}
+
+	var output evalOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("opa eval of %s failed: %w: %s", query, runErr, strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("invalid output of opa eval of %s: %w", query, err)
+	}
+	if len(output.Errors) > 0 {
+		messages := make([]string, 0, len(output.Errors))
+		for _, evalErr := range output.Errors {
+			message := evalErr.Message
+			if evalErr.Location != nil {
+				message = fmt.Sprintf("%s:%d: %s", evalErr.Location.File, evalErr.Location.Row, message)
+			}
+			messages = append(messages, message)
+		}
+		return nil, fmt.Errorf("opa eval of %s failed: %s", query, strings.Join(messages, "; "))
+	}
+	if runErr != nil {
+		return nil, fmt.Errorf("opa eval of %s failed: %w: %s", query, runErr, strings.TrimSpace(stderr.String()))
+	}
+	if len(output.Result) == 0 || len(output.Result[0].Expressions) == 0 {
+		return nil, fmt.Errorf("policy package %s has no %s rule", pkg, DenyRule)
+	}
+
+	values, ok := output.Result[0].Expressions[0].Value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s rule of policy package %s is not a set of messages", DenyRule, pkg)
+	}
+	messages := make([]string, 0, len(values))
+	for _, value := range values {
+		if message, ok := value.(string); ok {
+			messages = append(messages, message)
+			continue
+		}
+		message, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, string(message))
+	}
+	return messages, nil
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rego
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEval(t *testing.T) {
+	opa, err := filepath.Abs("testdata/opa")
+	require.NoError(t, err)
+	bundleDir := t.TempDir()
+	inputPath := filepath.Join(t.TempDir(), "input.json")
+	require.NoError(t, Input{Files: map[string]any{"/etc/app.conf": "enabled"}}.Write(inputPath))
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, DataFile), []byte(`{"parameters":{"var":"1"}}`), 0600))
+
+	tests := []struct {
+		name         string
+		pkg          string
+		timeout      time.Duration
+		wantMessages []string
+		wantErr      string
+	}{
+		{
+			name:         "Valid/NoDenyMessages",
+			pkg:          "checks.pass_check",
+			wantMessages: []string{},
+		},
+		{
+			name:         "Valid/DenyMessages",
+			pkg:          "checks.fail_check",
+			wantMessages: []string{"live-restore is not enabled", `{"file":"/etc/docker/daemon.json"}`},
+		},
+		{
+			name:         "Valid/Input",
+			pkg:          "checks.input_check",
+			wantMessages: []string{`{"files":{"/etc/app.conf":"enabled"}}`},
+		},
+		{
+			name:         "Valid/Data",
+			pkg:          "checks.parameters_check",
+			wantMessages: []string{`{"parameters":{"var":"1"}}`},
+		},
+		{
+			name:    "Invalid/NoDenyRule",
+			pkg:     "checks.no_deny",
+			wantErr: "policy package checks.no_deny has no deny rule",
+		},
+		{
+			name:    "Invalid/NotASet",
+			pkg:     "checks.not_a_set",
+			wantErr: "deny rule of policy package checks.not_a_set is not a set of messages",
+		},
+		{
+			name:    "Invalid/EvalError",
+			pkg:     "checks.broken",
+			wantErr: "opa eval of data.checks.broken.deny failed: broken.rego:5: var x is unsafe",
+		},
+		{
+			name:    "Invalid/Crash",
+			pkg:     "checks.crash",
+			wantErr: "opa eval of data.checks.crash.deny failed: exit status 2: unexpected failure",
+		},
+		{
+			name:    "Invalid/Timeout",
+			pkg:     "checks.slow",
+			timeout: 100 * time.Millisecond,
+			wantErr: "opa eval of data.checks.slow.deny timed out after 100ms",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout := tt.timeout
+			if timeout == 0 {
+				timeout = 10 * time.Second
+			}
+			messages, err := Eval(context.Background(), opa, bundleDir, inputPath, tt.pkg, timeout)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantMessages, messages)
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rego
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Formats of the input files.
+const (
+	// FormatJSON parses a file as JSON.
+	FormatJSON = "json"
+	// FormatYAML parses a file as YAML.
+	FormatYAML = "yaml"
+	// FormatINI parses a file as INI, such as a systemd unit.
+	FormatINI = "ini"
+	// FormatText keeps the content of a file as a string.
+	FormatText = "text"
+)
+
+// formatsByExt are the formats of the input files by extension. Other files are read as text.
+var formatsByExt = map[string]string{
+	".json":    FormatJSON,
+	".yaml":    FormatYAML,
+	".yml":     FormatYAML,
+	".ini":     FormatINI,
+	".cfg":     FormatINI,
+	".conf":    FormatINI,
+	".service": FormatINI,
+	".socket":  FormatINI,
+	".timer":   FormatINI,
+	".mount":   FormatINI,
+	".path":    FormatINI,
+	".target":  FormatINI,
+	".network": FormatINI,
+	".netdev":  FormatINI,
+	".link":    FormatINI,
+}
+
+// InputPattern is a path or glob pattern of input files, with the format of the files.
+type InputPattern struct {
+	Pattern string
+	// Format is the format of the files, or empty to use the extension of each file.
+	Format string
+}
+
+// Input is the input document of the policies.
+type Input struct {
+	// Files are the parsed contents of the input files by absolute path.
+	Files map[string]any `json:"files"`
+}
+
+// ParseInputs parses a comma separated list of paths or glob patterns, each optionally followed by
+// :json, :yaml, :ini, or :text to set the format of its files.
+func ParseInputs(value string) ([]InputPattern, error) {
+	var patterns []InputPattern
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		pattern := InputPattern{Pattern: item}
+		if i := strings.LastIndex(item, ":"); i >= 0 {
+			switch format := item[i+1:]; format {
+			case FormatJSON, FormatYAML, FormatINI, FormatText:
+				pattern = InputPattern{Pattern: item[:i], Format: format}
+			}
+		}
+		if _, err := filepath.Match(pattern.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid input pattern %q: %w", pattern.Pattern, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("no input files in %q", value)
+	}
+	return patterns, nil
+}
+
+// LoadInput reads and parses the files matched by the patterns into the input document. The patterns
+// matching no file are returned, as a missing file is an input of the policies too.
+func LoadInput(patterns []InputPattern) (Input, []string, error) {
+	input := Input{Files: make(map[string]any)}
+	var unmatched []string
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(pattern.Pattern)
+		if err != nil {
+			return Input{}, nil, err
+		}
+		sort.Strings(paths)
+		matched := false
+		for _, path := range paths {
+			info, err := os.Stat(path)
+			if err != nil {
+				return Input{}, nil, err
+			}
+			if info.IsDir() {
+				continue
+			}
+			matched = true
+			path, err = filepath.Abs(path)
+			if err != nil {
+				return Input{}, nil, err
+			}
+			content, err := parseFile(path, pattern.Format)
+			if err != nil {
+				return Input{}, nil, err
+			}
+			input.Files[path] = content
+		}
+		if !matched {
+			unmatched = append(unmatched, pattern.Pattern)
+		}
+	}
+	return input, unmatched, nil
+}
+
+// Write stores the input document as JSON at the given path.
+func (i Input) Write(path string) error {
+	data, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// parseFile reads and parses an input file with the format, or the format of its extension when empty.
+func parseFile(path, format string) (any, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = formatsByExt[strings.ToLower(filepath.Ext(path))]
+	}
+	var content any
+	switch format {
+	case FormatJSON:
+		err = json.Unmarshal(data, &content)
+	case FormatYAML:
+		err = yaml.Unmarshal(data, &content)
+	case FormatINI:
+		content, err = parseINI(data)
+	default:
+		content = string(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse input file %s as %s: %w", path, format, err)
+	}
+	return content, nil
+}
+
+// parseINI parses INI data into an object of the keys before the first section and of an object per section.
+// Values are strings, and the values of a repeated key are an array. Lines ending with \ continue on the next
+// line, and lines starting with # or ; are comments, as in systemd units.
+func parseINI(data []byte) (map[string]any, error) {
+	document := make(map[string]any)
+	section := document
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimSpace(lines[i])
+		for strings.HasSuffix(line, `\`) && i+1 < len(lines) {
+			i++
+			line = strings.TrimSpace(strings.TrimSuffix(line, `\`)) + " " + strings.TrimSpace(lines[i])
+		}
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+		case line[0] == '[':
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section %s", lineNumber, line)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			switch existing := document[name].(type) {
+			case nil:
+				section = make(map[string]any)
+				document[name] = section
+			case map[string]any:
+				section = existing
+			default:
+				return nil, fmt.Errorf("line %d: section %s has the name of a key", lineNumber, name)
+			}
+		default:
+			key, value, found := strings.Cut(line, "=")
+			if !found {
+				return nil, fmt.Errorf("line %d: expected key=value", lineNumber)
+			}
+			key = strings.TrimSpace(key)
+			value = strings.TrimSpace(value)
+			switch previous := section[key].(type) {
+			case nil:
+				section[key] = value
+			case string:
+				section[key] = []any{previous, value}
+			case []any:
+				section[key] = append(previous, value)
+			default:
+				return nil, fmt.Errorf("line %d: key %s has the name of a section", lineNumber, key)
+			}
+		}
+	}
+	return document, nil
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rego
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInputs(t *testing.T) {
+	patterns, err := ParseInputs("/etc/docker/daemon.json, /etc/ssh/sshd_config.d/*.conf:text,,/etc/app/config:yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []InputPattern{
+		{Pattern: "/etc/docker/daemon.json"},
+		{Pattern: "/etc/ssh/sshd_config.d/*.conf", Format: FormatText},
+		{Pattern: "/etc/app/config", Format: FormatYAML},
+	}, patterns)
+
+	_, err = ParseInputs(" , ")
+	require.EqualError(t, err, `no input files in " , "`)
+	_, err = ParseInputs("/etc/[")
+	require.ErrorContains(t, err, `invalid input pattern "/etc/["`)
+}
+
+func TestLoadInput(t *testing.T) {
+	inputs, err := filepath.Abs("testdata/inputs")
+	require.NoError(t, err)
+	patterns, err := ParseInputs(inputs + "/*.json," + inputs + "/*.yaml," + inputs + "/chronyd.service," +
+		inputs + "/sshd_config," + inputs + "/missing.conf")
+	require.NoError(t, err)
+	input, unmatched, err := LoadInput(patterns)
+	require.NoError(t, err)
+	assert.Equal(t, []string{inputs + "/missing.conf"}, unmatched)
+
+	inputPath := filepath.Join(t.TempDir(), "input.json")
+	require.NoError(t, input.Write(inputPath))
+	data, err := os.ReadFile(inputPath)
+	require.NoError(t, err)
+	var document map[string]map[string]any
+	require.NoError(t, json.Unmarshal(data, &document))
+	files := document["files"]
+	require.Len(t, files, 4)
+	assert.Equal(t, map[string]any{"live-restore": true, "log-opts": map[string]any{"max-size": "10m"}},
+		files[filepath.Join(inputs, "daemon.json")])
+	assert.Equal(t, map[string]any{"server": map[string]any{"port": float64(8443), "tls": true}, "users": []any{"admin"}},
+		files[filepath.Join(inputs, "app.yaml")])
+	assert.Equal(t, map[string]any{
+		"Unit": map[string]any{"Description": "NTP client/server"},
+		"Service": map[string]any{
+			"ExecStartPre":  []any{"/usr/libexec/chrony-helper check", "/usr/libexec/chrony-helper prepare"},
+			"ExecStart":     "/usr/sbin/chronyd $OPTIONS -F 2",
+			"ProtectSystem": "full",
+			"PrivateTmp":    "yes",
+		},
+	}, files[filepath.Join(inputs, "chronyd.service")])
+	assert.Equal(t, "PermitRootLogin no\n", files[filepath.Join(inputs, "sshd_config")])
+
+	_, _, err = LoadInput([]InputPattern{{Pattern: "testdata/invalid.json"}})
+	require.ErrorContains(t, err, "failed to parse input file")
+	_, _, err = LoadInput([]InputPattern{{Pattern: inputs + "/sshd_config", Format: FormatINI}})
+	require.ErrorContains(t, err, "line 1: expected key=value")
+}
+
+func TestParseINI(t *testing.T) {
+	document, err := parseINI([]byte("top = level\n[section]\nkey=a\nkey=b\nkey=c\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"top": "level", "section": map[string]any{"key": []any{"a", "b", "c"}}}, document)
+
+	_, err = parseINI([]byte("[section\n"))
+	require.EqualError(t, err, "line 1: unterminated section [section")
+	_, err = parseINI([]byte("section=value\n[section]\n"))
+	require.EqualError(t, err, "line 2: section section has the name of a key")
+	_, err = parseINI([]byte("[section]\n[other]\nsection=value\n"))
+	require.NoError(t, err)
+}
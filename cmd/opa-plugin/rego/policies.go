@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rego
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// ChecksPackage is the package under which the policy of each check is declared.
+	ChecksPackage = "checks"
+	// DenyRule is the rule of the policy of a check holding its deny messages.
+	DenyRule   = "deny"
+	regoExt    = ".rego"
+	testSuffix = "_test" + regoExt
+)
+
+var (
+	// packageDeclaration matches the package declaration of a Rego file.
+	packageDeclaration = regexp.MustCompile(`(?m)^[ \t]*package[ \t]+([^\s#]+)`)
+	// unsafePackageChars matches the characters of check IDs replaced in package names.
+	unsafePackageChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+)
+
+// Policy is a Rego file of the policies directory.
+type Policy struct {
+	// Path is the path of the file relative to the policies directory.
+	Path string
+	// Package is the package declared by the file.
+	Package string
+}
+
+// LoadPolicies reads the package declarations of the *.rego files of the directory and its subdirectories.
+// Rego test files are skipped.
+func LoadPolicies(dir string) ([]Policy, error) {
+	var policies []Policy
+	var errs []error
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != regoExt || strings.HasSuffix(path, testSuffix) {
+			return nil
+		}
+		data, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		match := packageDeclaration.FindSubmatch(data)
+		if match == nil {
+			errs = append(errs, fmt.Errorf("invalid policy %s: no package declaration", path))
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		policies = append(policies, Policy{Path: rel, Package: string(match[1])})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Path < policies[j].Path
+	})
+	return policies, nil
+}
+
+// CheckPackage returns the package of the policy of a check: the check ID under the checks package, with
+// the characters other than letters, digits, and _ replaced by _.
+func CheckPackage(checkID string) string {
+	name := unsafePackageChars.ReplaceAllString(checkID, "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return ChecksPackage + "." + name
+}
+
+// CheckPolicies returns the number of checks with a policy, the packages under the checks package.
+func CheckPolicies(policies []Policy) int {
+	packages := make(map[string]bool)
+	for _, policy := range policies {
+		if strings.HasPrefix(policy.Package, ChecksPackage+".") {
+			packages[policy.Package] = true
+		}
+	}
+	return len(packages)
+}
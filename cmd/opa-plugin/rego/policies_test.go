@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package rego
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPolicies(t *testing.T) {
+	policies, err := LoadPolicies("testdata/policies")
+	require.NoError(t, err)
+	assert.Equal(t, []Policy{
+		{Path: "docker_live_restore.rego", Package: "checks.docker_live_restore"},
+		{Path: filepath.Join("lib", "files.rego"), Package: "lib.files"},
+	}, policies)
+	assert.Equal(t, 1, CheckPolicies(policies))
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "empty.rego"), []byte("# no package\n"), 0600))
+	_, err = LoadPolicies(dir)
+	require.ErrorContains(t, err, "empty.rego: no package declaration")
+
+	_, err = LoadPolicies(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
+
+func TestCheckPackage(t *testing.T) {
+	assert.Equal(t, "checks.docker_live_restore", CheckPackage("docker_live_restore"))
+	assert.Equal(t, "checks.package_telnet_server_removed", CheckPackage("package_telnet-server_removed"))
+	assert.Equal(t, "checks._1_2_auditd", CheckPackage("1.2_auditd"))
+}
@@ -0,0 +1,9 @@
+package checks.docker_live_restore
+
+import rego.v1
+
+import data.lib.files
+
+deny contains "live-restore is not enabled" if {
+	not files.content("/etc/docker/daemon.json")["live-restore"]
+}
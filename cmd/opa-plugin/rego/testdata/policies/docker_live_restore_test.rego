@@ -0,0 +1,7 @@
+package checks.docker_live_restore_test
+
+import rego.v1
+
+test_deny if {
+	count(data.checks.docker_live_restore.deny) == 1 with input as {"files": {}}
+}
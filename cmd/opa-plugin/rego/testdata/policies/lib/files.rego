@@ -0,0 +1,6 @@
+# Helpers shared by the policies.
+package lib.files
+
+import rego.v1
+
+content(path) := input.files[path]
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/complytime/complyctl/cmd/opa-plugin/config"
+	"github.com/complytime/complyctl/cmd/opa-plugin/rego"
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+// Doctor checks the external dependencies of the plugin for the given
+// configuration selections.
+func Doctor(selections map[string]string) []doctor.Check {
+	var checks []doctor.Check
+
+	opa := selections["opa"]
+	if opa == "" {
+		opa = config.DefaultOPA
+	}
+	if opaPath, err := exec.LookPath(opa); err != nil {
+		checks = append(checks, doctor.Failed("opa binary", err.Error(),
+			"Install OPA from https://www.openpolicyagent.org/docs/latest/#running-opa, "+
+				"or set the opa option in the plugin drop-in configuration"))
+	} else {
+		checks = append(checks, doctor.Passed("opa binary", opaPath))
+	}
+
+	if selections["policies"] == "" {
+		checks = append(checks, doctor.Failed("policies", "the policies option is not set",
+			"Set the policies option in the plugin drop-in configuration to the directory of the Rego policies"))
+	} else if policies, err := rego.LoadPolicies(selections["policies"]); err != nil {
+		checks = append(checks, doctor.Failed("policies", err.Error(),
+			"Fix the Rego files of the policies directory"))
+	} else {
+		checks = append(checks, doctor.Passed("policies",
+			fmt.Sprintf("%d check policies in %s", rego.CheckPolicies(policies), selections["policies"])))
+	}
+
+	if selections["inputs"] == "" {
+		checks = append(checks, doctor.Failed("input files", "the inputs option is not set",
+			"Set the inputs option in the plugin drop-in configuration to the files evaluated by the policies"))
+		return checks
+	}
+	patterns, err := rego.ParseInputs(selections["inputs"])
+	if err != nil {
+		checks = append(checks, doctor.Failed("input files", err.Error(),
+			"Fix the inputs option in the plugin drop-in configuration"))
+		return checks
+	}
+	input, unmatched, err := rego.LoadInput(patterns)
+	switch {
+	case err != nil:
+		checks = append(checks, doctor.Failed("input files", err.Error(),
+			"Fix the input file, or set its format with a :json, :yaml, :ini, or :text suffix in the inputs option"))
+	case len(unmatched) > 0:
+		checks = append(checks, doctor.Warned("input files",
+			fmt.Sprintf("no file matches %s", strings.Join(unmatched, ", ")),
+			"Check the inputs option in the plugin drop-in configuration, unless the policies expect the files to be missing"))
+	default:
+		checks = append(checks, doctor.Passed("input files", fmt.Sprintf("%d input files", len(input.Files))))
+	}
+	return checks
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+func TestDoctor(t *testing.T) {
+	checks := Doctor(map[string]string{"opa": "/nonexistent/opa"})
+	require.Len(t, checks, 3)
+	require.Equal(t, "opa binary", checks[0].Name)
+	require.Equal(t, doctor.Fail, checks[0].Status)
+	require.Equal(t, "policies", checks[1].Name)
+	require.Equal(t, doctor.Fail, checks[1].Status)
+	require.Contains(t, checks[1].Fix, "policies option")
+	require.Equal(t, "input files", checks[2].Name)
+	require.Equal(t, doctor.Fail, checks[2].Status)
+	require.Contains(t, checks[2].Fix, "inputs option")
+
+	inputsDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(inputsDir, "daemon.json"), []byte(`{}`), 0600))
+	opa, err := filepath.Abs("../rego/testdata/opa")
+	require.NoError(t, err)
+	selections := map[string]string{
+		"opa":      opa,
+		"policies": "../rego/testdata/policies",
+		"inputs":   filepath.Join(inputsDir, "*.json"),
+	}
+	checks = Doctor(selections)
+	require.Equal(t, doctor.Pass, checks[0].Status)
+	require.Equal(t, doctor.Pass, checks[1].Status)
+	require.Contains(t, checks[1].Message, "1 check policies")
+	require.Equal(t, doctor.Pass, checks[2].Status)
+	require.Equal(t, "1 input files", checks[2].Message)
+
+	selections["inputs"] += "," + filepath.Join(inputsDir, "missing.conf")
+	checks = Doctor(selections)
+	require.Equal(t, doctor.Warn, checks[2].Status)
+	require.Contains(t, checks[2].Message, "no file matches")
+
+	require.NoError(t, os.WriteFile(filepath.Join(inputsDir, "invalid.json"), []byte(`{`), 0600))
+	checks = Doctor(selections)
+	require.Equal(t, doctor.Fail, checks[2].Status)
+}
@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+
+	"github.com/complytime/complyctl/cmd/opa-plugin/config"
+	"github.com/complytime/complyctl/cmd/opa-plugin/rego"
+	"github.com/complytime/complyctl/pkg/pluginutil"
+	"github.com/complytime/complyctl/pkg/trace"
+)
+
+var _ policy.Provider = (*PluginServer)(nil)
+
+type PluginServer struct {
+	Config *config.Config
+}
+
+func New() PluginServer {
+	return PluginServer{
+		Config: config.NewConfig(),
+	}
+}
+
+func (s PluginServer) Configure(configMap map[string]string) error {
+	return s.Config.LoadSettings(configMap)
+}
+
+func (s PluginServer) Generate(policy policy.Policy) (err error) {
+	_, span := trace.Start(trace.FromEnvironment(context.Background()), "opa.Generate")
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	if err := pluginutil.ValidatePolicy(policy); err != nil {
+		return err
+	}
+	policies, err := rego.LoadPolicies(s.Config.Files.Policies)
+	if err != nil {
+		return err
+	}
+
+	hclog.Default().Info("Assembling the Rego bundle", "path", s.Config.Files.Bundle)
+	bundle, err := rego.Render(s.Config.Files.Bundle, s.Config.Files.Policies, policy, policies)
+	if err != nil {
+		return err
+	}
+	for _, check := range bundle.Checks {
+		if check.Package == "" {
+			hclog.Default().Warn("Check has no policy", "check", check.ID, "rule", check.Rule,
+				"package", rego.CheckPackage(check.ID), "policies", s.Config.Files.Policies)
+		}
+	}
+	span.SetAttributes(trace.Int("opa.checks", len(bundle.Checks)))
+	return nil
+}
+
+func (s PluginServer) GetResults(oscalPolicy policy.Policy) (_ policy.PVPResult, err error) {
+	ctx, span := trace.Start(trace.FromEnvironment(context.Background()), "opa.GetResults")
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	if err := pluginutil.ValidatePolicy(oscalPolicy); err != nil {
+		return policy.PVPResult{}, err
+	}
+	bundle, err := rego.ReadBundle(s.Config.Files.Bundle)
+	if err != nil {
+		return policy.PVPResult{}, err
+	}
+	bundleChecks := make(map[string]rego.BundleCheck, len(bundle.Checks))
+	for _, check := range bundle.Checks {
+		bundleChecks[check.ID] = check
+	}
+
+	input, unmatched, err := rego.LoadInput(s.Config.Parameters.Inputs)
+	if err != nil {
+		return policy.PVPResult{}, err
+	}
+	for _, pattern := range unmatched {
+		hclog.Default().Warn("No input file matches the pattern", "pattern", pattern)
+	}
+	inputPath := filepath.Join(s.Config.Files.Results, config.InputFile)
+	if err := input.Write(inputPath); err != nil {
+		return policy.PVPResult{}, err
+	}
+	span.SetAttributes(trace.Int("opa.input_files", len(input.Files)))
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return policy.PVPResult{}, err
+	}
+
+	pvpResults := policy.PVPResult{}
+	observed := make(map[string]bool)
+	for _, ruleSet := range oscalPolicy {
+		for _, policyCheck := range ruleSet.Checks {
+			if observed[policyCheck.ID] {
+				continue
+			}
+			observed[policyCheck.ID] = true
+			check, found := bundleChecks[policyCheck.ID]
+			if !found {
+				check = rego.BundleCheck{ID: policyCheck.ID, Rule: ruleSet.Rule.ID, Description: policyCheck.Description}
+			}
+			observation := s.evalCheck(ctx, check, found, inputPath, hostname)
+			pvpResults.ObservationsByCheck = append(pvpResults.ObservationsByCheck, observation)
+		}
+	}
+	return pvpResults, nil
+}
+
+// evalCheck evaluates the policy of a check of the bundle against the input document and returns the
+// observation of the check on the host. The deny messages of the policy are the reason of a failure.
+func (s PluginServer) evalCheck(ctx context.Context, check rego.BundleCheck, generated bool, inputPath, hostname string) policy.ObservationByCheck {
+	ctx, span := trace.Start(ctx, "check", trace.String("opa.check_id", check.ID))
+	defer span.End()
+
+	observation := policy.ObservationByCheck{
+		Title:       check.Rule,
+		Description: check.Description,
+		CheckID:     check.ID,
+		Methods:     []string{"AUTOMATED"},
+		Collected:   time.Now(),
+	}
+	subject := policy.Subject{
+		Title:       fmt.Sprintf("Host %s", hostname),
+		Type:        "inventory-item",
+		ResourceID:  hostname,
+		EvaluatedOn: time.Now(),
+		Props: []policy.Property{
+			{
+				Name:  "hostname",
+				Value: hostname,
+			},
+		},
+	}
+
+	switch {
+	case !generated:
+		subject.Result = policy.ResultError
+		subject.Reason = "check is not in the Rego bundle, generate the policy first"
+	case check.Package == "":
+		subject.Result = policy.ResultError
+		subject.Reason = fmt.Sprintf("check has no policy package %s in %s", rego.CheckPackage(check.ID), s.Config.Files.Policies)
+	default:
+		hclog.Default().Debug("Evaluating check", "check", check.ID, "package", check.Package)
+		messages, err := rego.Eval(ctx, s.Config.Parameters.OPA, s.Config.Files.Bundle, inputPath, check.Package, s.Config.Parameters.Timeout)
+		switch {
+		case err != nil:
+			span.RecordError(err)
+			subject.Result = policy.ResultError
+			subject.Reason = err.Error()
+		case len(messages) > 0:
+			subject.Result = policy.ResultFail
+			subject.Reason = strings.Join(messages, "; ")
+		default:
+			subject.Result = policy.ResultPass
+			subject.Reason = "no deny messages"
+		}
+		span.SetAttributes(trace.Int("opa.deny_messages", len(messages)), trace.String("opa.result", subject.Result.String()))
+
+		observation.RelevantEvidences = []policy.Link{
+			{
+				Href:        fmt.Sprintf("file://%s", inputPath),
+				Description: "INPUT_DOCUMENT",
+			},
+		}
+		for _, file := range check.Policies {
+			observation.RelevantEvidences = append(observation.RelevantEvidences, policy.Link{
+				Href:        fmt.Sprintf("file://%s", filepath.Join(s.Config.Files.Bundle, file)),
+				Description: "REGO_POLICY",
+			})
+		}
+	}
+	observation.Subjects = []policy.Subject{subject}
+	return observation
+}
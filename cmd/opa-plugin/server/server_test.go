@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPolicies are the policies of the checks answered by the fake opa of the rego testdata.
+var testPolicies = map[string]string{
+	"pass_check.rego":  "package checks.pass_check\n",
+	"fail_check.rego":  "package checks.fail_check\n",
+	"input_check.rego": "package checks.input_check\n",
+	"broken.rego":      "package checks.broken\n",
+}
+
+func TestGenerateAndGetResults(t *testing.T) {
+	policiesDir := t.TempDir()
+	for name, content := range testPolicies {
+		require.NoError(t, os.WriteFile(filepath.Join(policiesDir, name), []byte(content), 0600))
+	}
+	daemonPath := filepath.Join(t.TempDir(), "daemon.json")
+	require.NoError(t, os.WriteFile(daemonPath, []byte(`{"live-restore": true}`), 0600))
+	opa, err := filepath.Abs("../rego/testdata/opa")
+	require.NoError(t, err)
+
+	s := New()
+	require.NoError(t, s.Configure(map[string]string{
+		"workspace": t.TempDir(),
+		"profile":   "baseline",
+		"policies":  policiesDir,
+		"inputs":    daemonPath + "," + filepath.Join(filepath.Dir(daemonPath), "*.yaml"),
+		"opa":       opa,
+	}))
+	testPolicy := policy.Policy{
+		{
+			Rule: extensions.Rule{
+				ID:         "docker_hardened",
+				Parameters: []extensions.Parameter{{ID: "var_docker_log_driver", Value: "journald"}},
+			},
+			Checks: []extensions.Check{{ID: "pass_check"}, {ID: "fail_check"}, {ID: "input_check"}},
+		},
+		{
+			Rule:   extensions.Rule{ID: "chronyd_hardened"},
+			Checks: []extensions.Check{{ID: "broken"}, {ID: "undefined_check"}, {ID: "pass_check"}},
+		},
+	}
+
+	require.NoError(t, s.Generate(testPolicy))
+	results, err := s.GetResults(append(testPolicy, extensions.RuleSet{
+		Rule:   extensions.Rule{ID: "ungenerated_rule"},
+		Checks: []extensions.Check{{ID: "ungenerated_check"}},
+	}))
+	require.NoError(t, err)
+	require.Len(t, results.ObservationsByCheck, 6)
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+	inputPath := filepath.Join(s.Config.Files.Results, "input.json")
+	wantResults := []struct {
+		rule   string
+		check  string
+		result policy.Result
+		reason string
+	}{
+		{"docker_hardened", "pass_check", policy.ResultPass, "no deny messages"},
+		{"docker_hardened", "fail_check", policy.ResultFail, `live-restore is not enabled; {"file":"/etc/docker/daemon.json"}`},
+		{"docker_hardened", "input_check", policy.ResultFail, `"live-restore":true`},
+		{"chronyd_hardened", "broken", policy.ResultError, "broken.rego:5: var x is unsafe"},
+		{"chronyd_hardened", "undefined_check", policy.ResultError, "check has no policy package checks.undefined_check"},
+		{"ungenerated_rule", "ungenerated_check", policy.ResultError, "check is not in the Rego bundle"},
+	}
+	for i, want := range wantResults {
+		observation := results.ObservationsByCheck[i]
+		assert.Equal(t, want.rule, observation.Title)
+		assert.Equal(t, want.check, observation.CheckID)
+		require.Len(t, observation.Subjects, 1)
+		assert.Equal(t, hostname, observation.Subjects[0].ResourceID)
+		assert.Equal(t, want.result, observation.Subjects[0].Result, want.check)
+		assert.Contains(t, observation.Subjects[0].Reason, want.reason)
+	}
+
+	assert.Equal(t, []policy.Link{
+		{Href: "file://" + inputPath, Description: "INPUT_DOCUMENT"},
+		{Href: "file://" + filepath.Join(s.Config.Files.Bundle, "fail_check.rego"), Description: "REGO_POLICY"},
+	}, results.ObservationsByCheck[1].RelevantEvidences)
+	assert.FileExists(t, inputPath)
+	assert.Empty(t, results.ObservationsByCheck[4].RelevantEvidences)
+}
@@ -34,6 +34,7 @@ type Config struct {
 		Results    string `config:"results"`
 		ARF        string `config:"arf"`
 		Policy     string `config:"policy"`
+		Inventory  string `config:"inventory"`
 	}
 	Parameters struct {
 		Profile string `config:"profile"`
@@ -76,35 +77,58 @@ func (c *Config) validate() error {
 		*inputValue = sanitized
 	}
 
-	cleanDsPath, err := SanitizePath(c.Files.Datastream)
+	datastream, err := ResolveDatastream(c.Files.Datastream)
 	if err != nil {
 		return err
 	}
+	c.Files.Datastream = datastream
+
+	if c.Files.Inventory != "" {
+		inventory, err := SanitizePath(c.Files.Inventory)
+		if err != nil {
+			return err
+		}
+		if _, err := validatePath(inventory, false); err != nil {
+			return fmt.Errorf("invalid inventory path: %s: %w", inventory, err)
+		}
+		c.Files.Inventory = inventory
+	}
+
+	if err := defineFilesPaths(c); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ResolveDatastream returns the validated datastream path for the given datastream option.
+// If no datastream is set, a datastream matching the system information is searched for in
+// DatastreamsDir.
+func ResolveDatastream(datastream string) (string, error) {
+	cleanDsPath, err := SanitizePath(datastream)
+	if err != nil {
+		return "", err
+	}
 
 	// if a Datastream path is not defined in plugin manifest, it will be set
 	// to the current directory after SanitizePath.
 	if cleanDsPath == "." {
 		matchingDsFile, err := findMatchingDatastream()
 		if err != nil {
-			return err
+			return "", err
 		}
-		c.Files.Datastream = matchingDsFile
+		datastream = matchingDsFile
 	}
 
-	_, err = validatePath(c.Files.Datastream, false)
+	_, err = validatePath(datastream, false)
 	if err != nil {
-		return fmt.Errorf("invalid datastream path: %s: %w", c.Files.Datastream, err)
+		return "", fmt.Errorf("invalid datastream path: %s: %w", datastream, err)
 	}
 
-	isXML, err := IsXMLFile(c.Files.Datastream)
+	isXML, err := IsXMLFile(datastream)
 	if err != nil || !isXML {
-		return fmt.Errorf("invalid datastream file: %s: %w", c.Files.Datastream, err)
-	}
-
-	if err := defineFilesPaths(c); err != nil {
-		return err
+		return "", fmt.Errorf("invalid datastream file: %s: %w", datastream, err)
 	}
-	return nil
+	return datastream, nil
 }
 
 func SanitizeInput(input string) (string, error) {
@@ -229,6 +253,15 @@ func defineFilesPaths(cfg *Config) error {
 	return nil
 }
 
+// optionalKeys are the configuration options that may be missing from the config map.
+var optionalKeys = map[string]bool{
+	// if datastream is not set in manifest file, plugin will try to determine
+	// and validate the datastream path later based on system information.
+	"datastream": true,
+	// if inventory is not set in manifest file, the local system is scanned.
+	"inventory": true,
+}
+
 // setConfigStruct populates struct fields with matching tags to values
 // in a given config map.
 func setConfigStruct(val reflect.Value, config map[string]string) error {
@@ -237,9 +270,7 @@ func setConfigStruct(val reflect.Value, config map[string]string) error {
 		fieldType := t.Field(i)
 		key := fieldType.Tag.Get("config")
 		value, ok := config[key]
-		// if datastream is not set in manifest file, plugin will try to determine
-		// and validate the datastream path later based on system information.
-		if !ok && key != "datastream" {
+		if !ok && !optionalKeys[key] {
 			return fmt.Errorf("missing configuration value for option %q (field: %s)", key, fieldType.Name)
 		}
 
@@ -201,6 +201,7 @@ func TestEnsureWorkspace(t *testing.T) {
 					Results    string "config:\"results\""
 					ARF        string "config:\"arf\""
 					Policy     string "config:\"policy\""
+					Inventory  string "config:\"inventory\""
 				}{
 					Workspace: filepath.Join(tempDir, "workspace"),
 					Policy:    "policy.yaml",
@@ -218,6 +219,7 @@ func TestEnsureWorkspace(t *testing.T) {
 					Results    string "config:\"results\""
 					ARF        string "config:\"arf\""
 					Policy     string "config:\"policy\""
+					Inventory  string "config:\"inventory\""
 				}{
 					Workspace: filepath.Join(tempDir, "invalid\000workspace"),
 					Policy:    "policy.yaml",
@@ -263,6 +265,7 @@ func TestDefineFilesPaths(t *testing.T) {
 					Results    string "config:\"results\""
 					ARF        string "config:\"arf\""
 					Policy     string "config:\"policy\""
+					Inventory  string "config:\"inventory\""
 				}{
 					Workspace:  filepath.Join(tempDir, "workspace"),
 					Datastream: filepath.Join(tempDir, "datastream.xml"),
@@ -331,6 +334,7 @@ func TestConfig_LoadSettings(t *testing.T) {
 					Results    string "config:\"results\""
 					ARF        string "config:\"arf\""
 					Policy     string "config:\"policy\""
+					Inventory  string "config:\"inventory\""
 				}{
 					Workspace:  tempDir,
 					Datastream: tempDataStream,
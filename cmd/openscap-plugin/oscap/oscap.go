@@ -3,22 +3,30 @@
 package oscap
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"path/filepath"
 
 	"github.com/complytime/complyctl/cmd/openscap-plugin/config"
+	"github.com/complytime/complyctl/pkg/trace"
 	"github.com/hashicorp/go-hclog"
 )
 
-func executeCommand(command []string) ([]byte, error) {
+func executeCommand(ctx context.Context, command []string) (_ []byte, err error) {
+	_, span := trace.Start(ctx, "oscap", trace.Strings("openscap.command", command))
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	cmdPath, err := exec.LookPath(command[0])
 	if err != nil {
 		return nil, fmt.Errorf("command not found: %s: %w", command[0], err)
 	}
 
 	hclog.Default().Debug("Executing command", "command", command)
-	cmd := exec.Command(cmdPath, command[1:]...)
+	cmd := exec.CommandContext(ctx, cmdPath, command[1:]...)
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -54,10 +62,15 @@ func constructScanCommand(openscapFiles map[string]string, profile string) []str
 	return cmd
 }
 
-func OscapScan(openscapFiles map[string]string, profile string) ([]byte, error) {
+// ScanCommand returns the oscap command evaluating the profile with the given files.
+func ScanCommand(openscapFiles map[string]string, profile string) []string {
+	return constructScanCommand(openscapFiles, profile)
+}
+
+func OscapScan(ctx context.Context, openscapFiles map[string]string, profile string) ([]byte, error) {
 	command := constructScanCommand(openscapFiles, profile)
 
-	return executeCommand(command)
+	return executeCommand(ctx, command)
 }
 
 func constructGenerateFixCommand(fixType, output, profile, tailoringFile, datastream string) []string {
@@ -76,7 +89,7 @@ func constructGenerateFixCommand(fixType, output, profile, tailoringFile, datast
 	return cmd
 }
 
-func OscapGenerateFix(pluginDir, profile, policyFile, datastream string) error {
+func OscapGenerateFix(ctx context.Context, pluginDir, profile, policyFile, datastream string) error {
 	fixTypes := map[string]string{
 		"bash":      "remediation-script.sh",
 		"ansible":   "remediation-playbook.yml",
@@ -87,7 +100,7 @@ func OscapGenerateFix(pluginDir, profile, policyFile, datastream string) error {
 		outputPath := filepath.Join(pluginDir, config.RemediationDir, outputFile)
 		hclog.Default().Debug("Generating remedation file %s", outputPath)
 		command := constructGenerateFixCommand(fixType, outputPath, profile, policyFile, datastream)
-		_, err := executeCommand(command)
+		_, err := executeCommand(ctx, command)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package remote
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// safeName matches user and host names that are safe to pass to the ssh client
+// and to use as directory names.
+var safeName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9-_.]*$`)
+
+// Target is a host scanned over SSH.
+type Target struct {
+	// User is the user to log in as, defaulting to the user of the ssh client configuration.
+	User string
+	// Host is the name or address of the host.
+	Host string
+	// Port is the SSH port of the host, defaulting to the port of the ssh client configuration.
+	Port int
+}
+
+// ParseTarget parses a target in the [user@]host[:port] format. IPv6 addresses with
+// a port are enclosed in brackets, as in [::1]:2222.
+func ParseTarget(value string) (Target, error) {
+	var target Target
+	hostPort := value
+	if user, rest, found := strings.Cut(value, "@"); found {
+		target.User = user
+		hostPort = rest
+	}
+	target.Host = hostPort
+	if host, port, err := net.SplitHostPort(hostPort); err == nil {
+		portNumber, err := strconv.Atoi(port)
+		if err != nil || portNumber < 1 || portNumber > 65535 {
+			return Target{}, fmt.Errorf("invalid port in target %q", value)
+		}
+		target.Host = host
+		target.Port = portNumber
+	}
+	if net.ParseIP(target.Host) == nil && !safeName.MatchString(target.Host) {
+		return Target{}, fmt.Errorf("invalid host in target %q", value)
+	}
+	if target.User != "" && !safeName.MatchString(target.User) {
+		return Target{}, fmt.Errorf("invalid user in target %q", value)
+	}
+	return target, nil
+}
+
+// String returns the target in the [user@]host[:port] format.
+func (t Target) String() string {
+	host := t.Host
+	if t.Port != 0 {
+		host = net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+	}
+	if t.User != "" {
+		return t.User + "@" + host
+	}
+	return host
+}
+
+// ReadInventory reads the targets of an inventory file. The inventory has one target per
+// line in the [user@]host[:port] format, so several targets can share a host on different
+// ports or with different users. Empty lines and lines starting with # are ignored.
+func ReadInventory(path string) ([]Target, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open inventory: %w", err)
+	}
+	defer file.Close()
+
+	var targets []Target
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		target, err := ParseTarget(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNumber, err)
+		}
+		if seen[target.String()] {
+			return nil, fmt.Errorf("%s:%d: duplicate target %s", path, lineNumber, target)
+		}
+		seen[target.String()] = true
+		targets = append(targets, target)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read inventory %s: %w", path, err)
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("inventory %s has no targets", path)
+	}
+	return targets, nil
+}
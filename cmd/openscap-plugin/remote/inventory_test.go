@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package remote
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		expected      Target
+		expectedError string
+	}{
+		{
+			name:     "Host",
+			value:    "web1.example.com",
+			expected: Target{Host: "web1.example.com"},
+		},
+		{
+			name:     "User, host, and port",
+			value:    "scanner@10.0.0.5:2222",
+			expected: Target{User: "scanner", Host: "10.0.0.5", Port: 2222},
+		},
+		{
+			name:     "IPv6 address",
+			value:    "::1",
+			expected: Target{Host: "::1"},
+		},
+		{
+			name:     "IPv6 address with port",
+			value:    "root@[::1]:2222",
+			expected: Target{User: "root", Host: "::1", Port: 2222},
+		},
+		{
+			name:          "Invalid port",
+			value:         "web1:ssh",
+			expectedError: "invalid port in target \"web1:ssh\"",
+		},
+		{
+			name:          "Option injection",
+			value:         "-oProxyCommand=touch",
+			expectedError: "invalid host in target \"-oProxyCommand=touch\"",
+		},
+		{
+			name:          "Invalid user",
+			value:         "$(id)@web1",
+			expectedError: "invalid user in target \"$(id)@web1\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := ParseTarget(tt.value)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, target)
+			assert.Equal(t, tt.value, target.String())
+		})
+	}
+}
+
+func TestReadInventory(t *testing.T) {
+	inventoryPath := filepath.Join(t.TempDir(), "inventory")
+
+	require.NoError(t, os.WriteFile(inventoryPath, []byte("# web servers\nweb1\n\n  scanner@web2:2222  \n"), 0600))
+	targets, err := ReadInventory(inventoryPath)
+	require.NoError(t, err)
+	assert.Equal(t, []Target{{Host: "web1"}, {User: "scanner", Host: "web2", Port: 2222}}, targets)
+
+	// Targets may share a host on different ports or with different users
+	require.NoError(t, os.WriteFile(inventoryPath, []byte("localhost:2222\nlocalhost:2223\nroot@localhost:2222\n"), 0600))
+	targets, err = ReadInventory(inventoryPath)
+	require.NoError(t, err)
+	assert.Equal(t, []Target{{Host: "localhost", Port: 2222}, {Host: "localhost", Port: 2223}, {User: "root", Host: "localhost", Port: 2222}}, targets)
+
+	require.NoError(t, os.WriteFile(inventoryPath, []byte("web1\nroot@web1:2222\nweb1\n"), 0600))
+	_, err = ReadInventory(inventoryPath)
+	assert.EqualError(t, err, inventoryPath+":3: duplicate target web1")
+
+	require.NoError(t, os.WriteFile(inventoryPath, []byte("# no hosts yet\n"), 0600))
+	_, err = ReadInventory(inventoryPath)
+	assert.EqualError(t, err, "inventory "+inventoryPath+" has no targets")
+
+	_, err = ReadInventory(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "failed to open inventory")
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package remote
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/complytime/complyctl/pkg/pluginutil"
+)
+
+// defaultSSHCommand is the ssh client used to reach targets.
+const defaultSSHCommand = "ssh"
+
+// Transport runs commands and copies files on a target with the ssh client, as oscap-ssh does.
+// The ssh client configuration, keys, and agent of the user running the plugin are used.
+// Files are copied by streaming them through the standard input and output of cat, so
+// only a POSIX shell is needed on the target.
+type Transport struct {
+	// Target is the host commands are run on.
+	Target Target
+	// Command is the ssh client, defaulting to ssh.
+	Command string
+	// Options are additional options passed to the ssh client.
+	Options []string
+}
+
+// NewTransport returns a Transport to the target using the ssh client in PATH.
+func NewTransport(target Target) *Transport {
+	return &Transport{Target: target, Command: defaultSSHCommand}
+}
+
+// args returns the arguments of the ssh client to run the remote command. Password prompts
+// are disabled as the plugin cannot answer them.
+func (t *Transport) args(remoteCommand string) []string {
+	args := []string{"-o", "BatchMode=yes"}
+	if t.Target.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(t.Target.Port))
+	}
+	args = append(args, t.Options...)
+	destination := t.Target.Host
+	if t.Target.User != "" {
+		destination = t.Target.User + "@" + t.Target.Host
+	}
+	return append(args, "--", destination, remoteCommand)
+}
+
+// Run runs the shell command on the target with the given standard input and output.
+// The error of a failed command is a *CommandError with the exit status of the remote
+// command, which is 255 when the target could not be reached.
+func (t *Transport) Run(remoteCommand string, stdin io.Reader, stdout io.Writer) error {
+	command := t.Command
+	if command == "" {
+		command = defaultSSHCommand
+	}
+	cmdPath, err := exec.LookPath(command)
+	if err != nil {
+		return fmt.Errorf("command not found: %s: %w", command, err)
+	}
+
+	hclog.Default().Debug("Executing remote command", "target", t.Target.String(), "command", remoteCommand)
+	var stderr bytes.Buffer
+	cmd := exec.Command(cmdPath, t.args(remoteCommand)...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return &CommandError{Target: t.Target, Stderr: strings.TrimSpace(stderr.String()), Err: err}
+	}
+	return nil
+}
+
+// Output runs the shell command on the target and returns its trimmed standard output.
+func (t *Transport) Output(remoteCommand string) (string, error) {
+	var stdout bytes.Buffer
+	if err := t.Run(remoteCommand, nil, &stdout); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// Upload copies the local file to the path on the target.
+func (t *Transport) Upload(localPath, remotePath string) error {
+	file, err := os.Open(filepath.Clean(localPath))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := t.Run("cat > "+pluginutil.ShellQuote(remotePath), file, nil); err != nil {
+		return fmt.Errorf("failed to copy %s to %s:%s: %w", localPath, t.Target.Host, remotePath, err)
+	}
+	return nil
+}
+
+// Download copies the file at the path on the target to the local path.
+func (t *Transport) Download(remotePath, localPath string) error {
+	file, err := os.OpenFile(filepath.Clean(localPath), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	err = t.Run("cat "+pluginutil.ShellQuote(remotePath), nil, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to copy %s:%s to %s: %w", t.Target.Host, remotePath, localPath, err)
+	}
+	return nil
+}
+
+// CommandError is the error of a command run on a target.
+type CommandError struct {
+	Target Target
+	Stderr string
+	Err    error
+}
+
+func (e *CommandError) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("command failed on %s: %v", e.Target.Host, e.Err)
+	}
+	return fmt.Sprintf("command failed on %s: %v: %s", e.Target.Host, e.Err, e.Stderr)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit status of the remote command, or -1 when it did not run.
+func (e *CommandError) ExitCode() int {
+	if exitErr, ok := e.Err.(*exec.ExitError); ok {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package remote
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/pkg/pluginutil"
+)
+
+// fakeTransport returns a Transport using the fake ssh client of testdata, which runs
+// remote commands locally.
+func fakeTransport(t *testing.T, target Target) *Transport {
+	sshPath, err := filepath.Abs("testdata/ssh")
+	require.NoError(t, err)
+	return &Transport{Target: target, Command: sshPath}
+}
+
+func TestTransportArgs(t *testing.T) {
+	transport := NewTransport(Target{User: "scanner", Host: "web1", Port: 2222})
+	transport.Options = []string{"-i", "/etc/complyctl/id_ed25519"}
+	assert.Equal(t, []string{
+		"-o", "BatchMode=yes",
+		"-p", "2222",
+		"-i", "/etc/complyctl/id_ed25519",
+		"--", "scanner@web1", "uname",
+	}, transport.args("uname"))
+	assert.Equal(t, []string{"-o", "BatchMode=yes", "--", "web1", "uname"}, NewTransport(Target{Host: "web1"}).args("uname"))
+}
+
+func TestTransport(t *testing.T) {
+	transport := fakeTransport(t, Target{Host: "web1"})
+	dir := t.TempDir()
+
+	output, err := transport.Output("echo $FAKE_SSH_DESTINATION")
+	require.NoError(t, err)
+	assert.Equal(t, "web1", output)
+
+	localPath := filepath.Join(dir, "local file.xml")
+	require.NoError(t, os.WriteFile(localPath, []byte("<root/>\n"), 0600))
+	remotePath := filepath.Join(dir, "remote file.xml")
+	require.NoError(t, transport.Upload(localPath, remotePath))
+	fetchedPath := filepath.Join(dir, "fetched.xml")
+	require.NoError(t, transport.Download(remotePath, fetchedPath))
+	fetched, err := os.ReadFile(fetchedPath)
+	require.NoError(t, err)
+	assert.Equal(t, "<root/>\n", string(fetched))
+
+	err = transport.Run("exit 2", nil, nil)
+	var commandErr *CommandError
+	require.True(t, errors.As(err, &commandErr))
+	assert.Equal(t, 2, commandErr.ExitCode())
+
+	err = fakeTransport(t, Target{Host: "unreachable"}).Run("true", nil, nil)
+	require.True(t, errors.As(err, &commandErr))
+	assert.Equal(t, 255, commandErr.ExitCode())
+	assert.EqualError(t, err, "command failed on unreachable: exit status 255: ssh: connect to host unreachable port 22: Connection refused")
+}
+
+// TestTransportLoopback runs the transport against a real sshd, such as a local sshd container or
+// a loopback sshd, given as [user@]host[:port] in OPENSCAP_PLUGIN_SSH_TARGET. The ssh client must be
+// able to log in without a password prompt.
+func TestTransportLoopback(t *testing.T) {
+	value := os.Getenv("OPENSCAP_PLUGIN_SSH_TARGET")
+	if value == "" {
+		t.Skip("OPENSCAP_PLUGIN_SSH_TARGET is not set")
+	}
+	target, err := ParseTarget(value)
+	require.NoError(t, err)
+	transport := NewTransport(target)
+	transport.Options = []string{"-o", "StrictHostKeyChecking=accept-new"}
+
+	remoteDir, err := transport.Output("mktemp -d")
+	require.NoError(t, err)
+	defer func() { _ = transport.Run("rm -rf "+pluginutil.ShellQuote(remoteDir), nil, nil) }()
+
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "local.xml")
+	require.NoError(t, os.WriteFile(localPath, []byte("<root/>\n"), 0600))
+	require.NoError(t, transport.Upload(localPath, remoteDir+"/remote.xml"))
+	fetchedPath := filepath.Join(dir, "fetched.xml")
+	require.NoError(t, transport.Download(remoteDir+"/remote.xml", fetchedPath))
+	fetched, err := os.ReadFile(fetchedPath)
+	require.NoError(t, err)
+	assert.Equal(t, "<root/>\n", string(fetched))
+}
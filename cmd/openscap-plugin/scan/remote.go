@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/complytime/complyctl/cmd/openscap-plugin/config"
+	"github.com/complytime/complyctl/cmd/openscap-plugin/oscap"
+	"github.com/complytime/complyctl/cmd/openscap-plugin/remote"
+	"github.com/complytime/complyctl/cmd/openscap-plugin/xccdf"
+	"github.com/complytime/complyctl/pkg/pluginutil"
+	"github.com/complytime/complyctl/pkg/trace"
+)
+
+// ResultsDir returns the directory the results of the target are fetched into, in the
+// results directory of the workspace. The directory is named after the whole target, as
+// targets of an inventory may share a host.
+func ResultsDir(cfg *config.Config, target remote.Target) string {
+	return filepath.Join(filepath.Dir(cfg.Files.ARF), target.String())
+}
+
+// ScanTarget scans a remote target over SSH. The datastream and the tailoring file are copied
+// to a temporary directory on the target, where oscap evaluates the tailored profile. The ARF
+// and results files are fetched back into the results directory of the target, and the path
+// of the fetched ARF file is returned. The temporary directory is removed after the scan.
+func ScanTarget(ctx context.Context, cfg *config.Config, profile string, transport *remote.Transport) (_ string, err error) {
+	_, span := trace.Start(ctx, "openscap.ScanTarget", trace.String("openscap.target", transport.Target.String()))
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	if _, err := validateOpenSCAPFiles(cfg); err != nil {
+		return "", fmt.Errorf("invalid openscap files: %w", err)
+	}
+	resultsDir := ResultsDir(cfg, transport.Target)
+	if err := os.MkdirAll(resultsDir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create results directory: %w", err)
+	}
+
+	remoteDir, err := transport.Output("mktemp -d")
+	if err != nil {
+		return "", fmt.Errorf("failed to create a temporary directory: %w", err)
+	}
+	if remoteDir == "" || strings.ContainsAny(remoteDir, "\n") {
+		return "", fmt.Errorf("unexpected temporary directory %q on %s", remoteDir, transport.Target.Host)
+	}
+	defer func() {
+		if err := transport.Run("rm -rf "+pluginutil.ShellQuote(remoteDir), nil, nil); err != nil {
+			hclog.Default().Warn("failed to remove temporary directory", "target", transport.Target.String(), "dir", remoteDir, "err", err)
+		}
+	}()
+
+	remoteFiles := map[string]string{
+		"datastream": path.Join(remoteDir, filepath.Base(cfg.Files.Datastream)),
+		"policy":     path.Join(remoteDir, filepath.Base(cfg.Files.Policy)),
+		"results":    path.Join(remoteDir, filepath.Base(cfg.Files.Results)),
+		"arf":        path.Join(remoteDir, filepath.Base(cfg.Files.ARF)),
+	}
+	if err := transport.Upload(cfg.Files.Datastream, remoteFiles["datastream"]); err != nil {
+		return "", err
+	}
+	if err := transport.Upload(cfg.Files.Policy, remoteFiles["policy"]); err != nil {
+		return "", err
+	}
+
+	tailoringProfile := fmt.Sprintf("%s_%s", profile, xccdf.XCCDFTailoringSuffix)
+	command := oscap.ScanCommand(remoteFiles, tailoringProfile)
+	quoted := make([]string, 0, len(command))
+	for _, arg := range command {
+		quoted = append(quoted, pluginutil.ShellQuote(arg))
+	}
+	hclog.Default().Info("Scanning remote target", "target", transport.Target.String())
+	if err := transport.Run(strings.Join(quoted, " "), nil, nil); err != nil {
+		// As for local scans, exit status 2 reports rules that failed or are unknown
+		var commandErr *remote.CommandError
+		if !errors.As(err, &commandErr) || commandErr.ExitCode() != 2 {
+			return "", fmt.Errorf("failed during scan: %w", err)
+		}
+		hclog.Default().Warn("at least one rule resulted in fail or unknown", "target", transport.Target.String())
+	}
+
+	arfPath := filepath.Join(resultsDir, filepath.Base(cfg.Files.ARF))
+	if err := transport.Download(remoteFiles["arf"], arfPath); err != nil {
+		return "", err
+	}
+	if err := transport.Download(remoteFiles["results"], filepath.Join(resultsDir, filepath.Base(cfg.Files.Results))); err != nil {
+		return "", err
+	}
+	return arfPath, nil
+}
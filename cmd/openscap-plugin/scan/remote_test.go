@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/openscap-plugin/config"
+	"github.com/complytime/complyctl/cmd/openscap-plugin/remote"
+)
+
+// TestScanTarget scans targets with the fake ssh client and oscap of the remote package,
+// which run the remote commands locally.
+func TestScanTarget(t *testing.T) {
+	fakes, err := filepath.Abs("../remote/testdata")
+	require.NoError(t, err)
+	t.Setenv("PATH", fakes+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	resultsDir := t.TempDir()
+	cfg := new(config.Config)
+	cfg.Files.Datastream = "testdata/valid.xml"
+	cfg.Files.Policy = "testdata/valid.xml"
+	cfg.Files.ARF = filepath.Join(resultsDir, "arf.xml")
+	cfg.Files.Results = filepath.Join(resultsDir, "results.xml")
+
+	for _, host := range []string{"web1", "web-fail"} {
+		target := remote.Target{User: "scanner", Host: host}
+		arfPath, err := ScanTarget(context.Background(), cfg, "test-profile", remote.NewTransport(target))
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(resultsDir, "scanner@"+host, "arf.xml"), arfPath)
+		arf, err := os.ReadFile(arfPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(arf), "<target>"+host+"</target>")
+		assert.FileExists(t, filepath.Join(resultsDir, "scanner@"+host, "results.xml"))
+	}
+
+	_, err = ScanTarget(context.Background(), cfg, "test-profile", remote.NewTransport(remote.Target{Host: "unreachable"}))
+	assert.ErrorContains(t, err, "failed to create a temporary directory: command failed on unreachable: exit status 255")
+}
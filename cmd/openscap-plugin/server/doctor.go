@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/complytime/complyctl/cmd/openscap-plugin/config"
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+// Doctor checks the external dependencies of the plugin for the given
+// configuration selections.
+func Doctor(selections map[string]string) []doctor.Check {
+	var checks []doctor.Check
+
+	if oscapPath, err := exec.LookPath("oscap"); err != nil {
+		checks = append(checks, doctor.Failed("oscap binary", err.Error(),
+			"Install the openscap-scanner package"))
+	} else {
+		checks = append(checks, doctor.Passed("oscap binary", oscapPath))
+	}
+
+	datastream, err := config.ResolveDatastream(selections["datastream"])
+	switch {
+	case err != nil && selections["datastream"] == "":
+		checks = append(checks, doctor.Failed("datastream", err.Error(),
+			fmt.Sprintf("Install the scap-security-guide package with a datastream for this system in %s, "+
+				"or set the datastream option in the plugin drop-in configuration", config.DatastreamsDir)))
+	case err != nil:
+		checks = append(checks, doctor.Failed("datastream", err.Error(),
+			"Set the datastream option in the plugin drop-in configuration to an existing datastream file"))
+	default:
+		checks = append(checks, doctor.Passed("datastream", datastream))
+	}
+	return checks
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+func TestDoctor(t *testing.T) {
+	checks := Doctor(map[string]string{"datastream": "testdata/missing-ds.xml"})
+	require.Len(t, checks, 2)
+	require.Equal(t, "oscap binary", checks[0].Name)
+	require.Equal(t, "datastream", checks[1].Name)
+	require.Equal(t, doctor.Fail, checks[1].Status)
+	require.Contains(t, checks[1].Fix, "datastream option")
+}
@@ -4,6 +4,7 @@ package server
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -19,8 +20,10 @@ import (
 
 	"github.com/complytime/complyctl/cmd/openscap-plugin/config"
 	"github.com/complytime/complyctl/cmd/openscap-plugin/oscap"
+	"github.com/complytime/complyctl/cmd/openscap-plugin/remote"
 	"github.com/complytime/complyctl/cmd/openscap-plugin/scan"
 	"github.com/complytime/complyctl/cmd/openscap-plugin/xccdf"
+	"github.com/complytime/complyctl/pkg/trace"
 )
 
 var (
@@ -46,9 +49,18 @@ func (s PluginServer) Configure(configMap map[string]string) error {
 	return s.Config.LoadSettings(configMap)
 }
 
-func (s PluginServer) Generate(policy policy.Policy) error {
+func (s PluginServer) Generate(policy policy.Policy) (err error) {
+	ctx, span := trace.Start(trace.FromEnvironment(context.Background()), "openscap.Generate")
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	hclog.Default().Info("Generating a tailoring file")
+	_, tailoringSpan := trace.Start(ctx, "tailoring generation")
 	tailoringXML, err := xccdf.PolicyToXML(policy, s.Config)
+	tailoringSpan.RecordError(err)
+	tailoringSpan.End()
 	if err != nil {
 		return err
 	}
@@ -66,45 +78,161 @@ func (s PluginServer) Generate(policy policy.Policy) error {
 	// Generate remedation files
 	hclog.Default().Info(("Generating remediation files"))
 	pluginDir := filepath.Join(s.Config.Files.Workspace, config.PluginDir)
-	err = oscap.OscapGenerateFix(pluginDir, s.Config.Parameters.Profile, s.Config.Files.Policy, s.Config.Files.Datastream)
+	err = oscap.OscapGenerateFix(ctx, pluginDir, s.Config.Parameters.Profile, s.Config.Files.Policy, s.Config.Files.Datastream)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s PluginServer) GetResults(oscalPolicy policy.Policy) (policy.PVPResult, error) {
-	pvpResults := policy.PVPResult{}
+func (s PluginServer) GetResults(oscalPolicy policy.Policy) (_ policy.PVPResult, err error) {
+	ctx, span := trace.Start(trace.FromEnvironment(context.Background()), "openscap.GetResults")
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	policyChecks := newChecks()
+	policyChecks.LoadPolicy(oscalPolicy)
 
-	_, err := scan.ScanSystem(s.Config, s.Config.Parameters.Profile)
+	scans, failedTargets, err := s.scan(ctx)
 	if err != nil {
 		return policy.PVPResult{}, err
 	}
 
-	policyChecks.LoadPolicy(oscalPolicy)
+	// Observations of each rule have one subject per scanned host
+	pvpResults := policy.PVPResult{}
+	observations := make(map[string]int)
+	for _, targetScan := range scans {
+		_, arfSpan := trace.Start(ctx, "ARF parsing", trace.String("openscap.arf_file", targetScan.arfFile))
+		arfObservations, err := readARF(targetScan.arfFile, targetScan.host, policyChecks)
+		arfSpan.RecordError(err)
+		arfSpan.End()
+		if err != nil {
+			return policy.PVPResult{}, err
+		}
+		for _, observation := range arfObservations {
+			i, found := observations[observation.Title]
+			if !found {
+				observations[observation.Title] = len(pvpResults.ObservationsByCheck)
+				pvpResults.ObservationsByCheck = append(pvpResults.ObservationsByCheck, observation)
+				continue
+			}
+			existing := &pvpResults.ObservationsByCheck[i]
+			existing.Subjects = append(existing.Subjects, observation.Subjects...)
+			existing.RelevantEvidences = append(existing.RelevantEvidences, observation.RelevantEvidences...)
+		}
+	}
+	for i := range pvpResults.ObservationsByCheck {
+		observation := &pvpResults.ObservationsByCheck[i]
+		for _, failed := range failedTargets {
+			observation.Subjects = append(observation.Subjects, failed.subject())
+		}
+	}
+	return pvpResults, nil
+}
+
+// targetError is a target of the inventory that failed to scan.
+type targetError struct {
+	target remote.Target
+	err    error
+}
+
+// subject returns the subject with an error result reported for the target in each observation.
+// It names the host as the subjects of a successful scan of the target.
+func (t targetError) subject() policy.Subject {
+	return hostSubject(t.target.String(), policy.ResultError, fmt.Sprintf("failed to scan target %s: %v", t.target, t.err))
+}
+
+// targetScan is the ARF file of a successful scan.
+type targetScan struct {
+	arfFile string
+	// host names the scanned target in the subjects of the results, or is empty to use the
+	// target of the ARF file.
+	host string
+}
 
-	// get some results here
-	file, err := os.Open(filepath.Clean(s.Config.Files.ARF))
+// hostSubject returns the subject of the result of a rule on the given host.
+func hostSubject(host string, result policy.Result, reason string) policy.Subject {
+	return policy.Subject{
+		Title:       fmt.Sprintf("Host %s", host),
+		Type:        "inventory-item",
+		ResourceID:  host,
+		EvaluatedOn: time.Now(),
+		Result:      result,
+		Reason:      reason,
+		Props: []policy.Property{
+			{
+				Name:  "hostname",
+				Value: host,
+			},
+		},
+	}
+}
+
+// scan scans the local system, or each target of the inventory over SSH, and returns the
+// successful scans and the targets that failed to scan. Targets of the inventory are named
+// by their [user@]host[:port], so targets sharing a host are distinct subjects. The scan
+// fails when no target could be scanned.
+func (s PluginServer) scan(ctx context.Context) ([]targetScan, []targetError, error) {
+	if s.Config.Files.Inventory == "" {
+		if _, err := scan.ScanSystem(ctx, s.Config, s.Config.Parameters.Profile); err != nil {
+			return nil, nil, err
+		}
+		return []targetScan{{arfFile: s.Config.Files.ARF}}, nil, nil
+	}
+
+	targets, err := remote.ReadInventory(s.Config.Files.Inventory)
 	if err != nil {
-		return policy.PVPResult{}, err
+		return nil, nil, err
+	}
+	var scans []targetScan
+	var failedTargets []targetError
+	var scanErrs []error
+	for _, target := range targets {
+		arfFile, err := scan.ScanTarget(ctx, s.Config, s.Config.Parameters.Profile, remote.NewTransport(target))
+		if err != nil {
+			hclog.Default().Error("Failed to scan remote target", "target", target.String(), "err", err)
+			failedTargets = append(failedTargets, targetError{target: target, err: err})
+			scanErrs = append(scanErrs, fmt.Errorf("%s: %w", target, err))
+			continue
+		}
+		scans = append(scans, targetScan{arfFile: arfFile, host: target.String()})
+	}
+	if len(scans) == 0 {
+		return nil, nil, fmt.Errorf("failed to scan the targets of inventory %s: %w", s.Config.Files.Inventory, errors.Join(scanErrs...))
+	}
+	return scans, failedTargets, nil
+}
+
+// readARF returns the observations of the rules of the policy checks in the ARF file.
+// Each observation has the scanned host as subject, named by the given host or else by
+// the target of the ARF file.
+func readARF(arfFile, host string, policyChecks checks) ([]policy.ObservationByCheck, error) {
+	file, err := os.Open(filepath.Clean(arfFile))
+	if err != nil {
+		return nil, err
 	}
 	defer file.Close()
 
 	xmlnode, err := utils.ParseContent(bufio.NewReader(file))
 	if err != nil {
-		return policy.PVPResult{}, err
+		return nil, err
 	}
 
 	// extract hostname from xml to use in subject, this will
 	// map to in inventory item in the OSCAL assessment results
 	targetEl := xmlnode.SelectElement("//target")
 	if targetEl == nil {
-		return policy.PVPResult{}, errors.New("result has no 'target' attribute")
+		return nil, errors.New("result has no 'target' attribute")
 	}
 	target := targetEl.InnerText()
 	hclog.Default().Debug(fmt.Sprintf("hostname from results target is %s", target))
+	if host != "" {
+		target = host
+	}
 
+	var observations []policy.ObservationByCheck
 	ruleTable := xccdf.NewRuleHashTable(xmlnode)
 	results := xmlnode.SelectElements("//rule-result")
 	for i := range results {
@@ -128,12 +256,12 @@ func (s PluginServer) GetResults(oscalPolicy policy.Policy) (policy.PVPResult, e
 		}
 		ovalCheck, err := parseCheck(ovalRefEl)
 		if err != nil {
-			return policy.PVPResult{}, err
+			return nil, err
 		}
 		if policyChecks.Has(ovalCheck) {
 			mappedResult, err := mapResultStatus(result)
 			if err != nil {
-				return policy.PVPResult{}, err
+				return nil, err
 			}
 			observation := policy.ObservationByCheck{
 				Title:     ruleIDRef,
@@ -141,32 +269,19 @@ func (s PluginServer) GetResults(oscalPolicy policy.Policy) (policy.PVPResult, e
 				Collected: time.Now(),
 				CheckID:   ovalCheck,
 				Subjects: []policy.Subject{
-					{
-						Title:       fmt.Sprintf("Host %s", target),
-						Type:        "inventory-item",
-						ResourceID:  target,
-						EvaluatedOn: time.Now(),
-						Result:      mappedResult,
-						Reason:      fmt.Sprintf("openscap rule-result is %s", result.SelectElement("result").InnerText()),
-						Props: []policy.Property{
-							{
-								Name:  "hostname",
-								Value: target,
-							},
-						},
-					},
+					hostSubject(target, mappedResult, fmt.Sprintf("openscap rule-result is %s", result.SelectElement("result").InnerText())),
 				},
 				RelevantEvidences: []policy.Link{
 					{
-						Href:        fmt.Sprintf("file://%s", s.Config.Files.ARF),
+						Href:        fmt.Sprintf("file://%s", arfFile),
 						Description: "ARF_FILE",
 					},
 				},
 			}
-			pvpResults.ObservationsByCheck = append(pvpResults.ObservationsByCheck, observation)
+			observations = append(observations, observation)
 		}
 	}
-	return pvpResults, nil
+	return observations, nil
 }
 
 // checks is a Set implementation for comparing OSCAL
@@ -4,12 +4,16 @@ package server
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/antchfx/xmlquery"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMapResultStatus(t *testing.T) {
@@ -124,3 +128,59 @@ func TestParseCheck(t *testing.T) {
 		})
 	}
 }
+
+// TestGetResultsInventory scans the targets of an inventory with the fake ssh client and oscap
+// of the remote package, which run the remote commands locally.
+func TestGetResultsInventory(t *testing.T) {
+	fakes, err := filepath.Abs("../remote/testdata")
+	require.NoError(t, err)
+	t.Setenv("PATH", fakes+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	dir := t.TempDir()
+	inventoryPath := filepath.Join(dir, "inventory")
+	require.NoError(t, os.WriteFile(inventoryPath, []byte("web1\nscanner@web-fail:2222\nweb1:2222\nunreachable\n"), 0600))
+	policyPath := filepath.Join(dir, "policy.xml")
+	require.NoError(t, os.WriteFile(policyPath, []byte("<root/>"), 0600))
+
+	s := New()
+	s.Config.Files.Datastream = policyPath
+	s.Config.Files.Policy = policyPath
+	s.Config.Files.ARF = filepath.Join(dir, "results", "arf.xml")
+	s.Config.Files.Results = filepath.Join(dir, "results", "results.xml")
+	s.Config.Files.Inventory = inventoryPath
+	s.Config.Parameters.Profile = "test-profile"
+
+	oscalPolicy := policy.Policy{
+		{Checks: []extensions.Check{{ID: "package_telnet-server_removed"}}},
+		{Checks: []extensions.Check{{ID: "set_password_hashing_algorithm_logindefs"}}},
+	}
+	results, err := s.GetResults(oscalPolicy)
+	require.NoError(t, err)
+	require.Len(t, results.ObservationsByCheck, 2)
+
+	// One subject per target, named as in the inventory, with an error result for the unreachable target
+	hashing := results.ObservationsByCheck[1]
+	assert.Equal(t, "set_password_hashing_algorithm_logindefs", hashing.CheckID)
+	require.Len(t, hashing.Subjects, 4)
+	assert.Equal(t, "web1", hashing.Subjects[0].ResourceID)
+	assert.Equal(t, policy.ResultPass, hashing.Subjects[0].Result)
+	assert.Equal(t, "scanner@web-fail:2222", hashing.Subjects[1].ResourceID)
+	assert.Equal(t, policy.ResultFail, hashing.Subjects[1].Result)
+	assert.Equal(t, "web1:2222", hashing.Subjects[2].ResourceID)
+	assert.Equal(t, policy.ResultPass, hashing.Subjects[2].Result)
+	assert.Equal(t, "unreachable", hashing.Subjects[3].ResourceID)
+	assert.Equal(t, policy.ResultError, hashing.Subjects[3].Result)
+	assert.Contains(t, hashing.Subjects[3].Reason, "failed to scan target unreachable")
+	assert.Equal(t, []policy.Link{
+		{Href: "file://" + filepath.Join(dir, "results", "web1", "arf.xml"), Description: "ARF_FILE"},
+		{Href: "file://" + filepath.Join(dir, "results", "scanner@web-fail:2222", "arf.xml"), Description: "ARF_FILE"},
+		{Href: "file://" + filepath.Join(dir, "results", "web1:2222", "arf.xml"), Description: "ARF_FILE"},
+	}, hashing.RelevantEvidences)
+	for _, observation := range results.ObservationsByCheck {
+		assert.Equal(t, policy.ResultError, observation.Subjects[len(observation.Subjects)-1].Result)
+	}
+
+	require.NoError(t, os.WriteFile(inventoryPath, []byte("unreachable\n"), 0600))
+	_, err = s.GetResults(oscalPolicy)
+	assert.ErrorContains(t, err, "failed to scan the targets of inventory "+inventoryPath)
+}
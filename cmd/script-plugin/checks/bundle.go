@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+
+	"github.com/complytime/complyctl/pkg/pluginutil"
+)
+
+const (
+	// BundleFile is the file of the check bundle listing the checks and their expectations.
+	BundleFile = "bundle.yaml"
+	// ParameterEnvPrefix prefixes the environment variables holding the rule parameters in the check scripts.
+	ParameterEnvPrefix = "PARAM_"
+	scriptSuffix       = ".sh"
+)
+
+var (
+	// unsafeFileChars matches the characters replaced in the file names of the scripts.
+	unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+	// unsafeEnvChars matches the characters replaced in the names of the environment variables.
+	unsafeEnvChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+)
+
+// Bundle is the check bundle rendered for a policy. Each check has an executable script in the
+// directory of the bundle.
+type Bundle struct {
+	Checks []BundleCheck `yaml:"checks"`
+}
+
+// BundleCheck is a check of a rule of the policy.
+type BundleCheck struct {
+	// ID is the ID of the check.
+	ID string `yaml:"id"`
+	// Rule is the ID of the rule of the check.
+	Rule string `yaml:"rule"`
+	// Description describes what the check verifies.
+	Description string `yaml:"description,omitempty"`
+	// Script is the file name of the check script in the bundle directory, empty when the check has no definition.
+	Script string `yaml:"script,omitempty"`
+	// Expect defines the expected outcome of the script.
+	Expect Expectation `yaml:"expect,omitempty"`
+	// Timeout is the time the script can take.
+	Timeout string `yaml:"timeout,omitempty"`
+}
+
+// Render renders the check bundle of the policy into the bundle directory, with a script running the
+// command of the definition of each check, and the remediation scripts of the checks into the remediations
+// directory. The scripts of a previous bundle are removed. Checks without definition are recorded without
+// script, so they are reported as errors. Checks whose script names collide are rejected.
+func Render(bundleDir, remediationsDir string, p policy.Policy, definitions map[string]Definition, shell string, timeout time.Duration) (Bundle, error) {
+	if err := pluginutil.ValidatePolicy(p); err != nil {
+		return Bundle{}, err
+	}
+	if err := checkScriptNames(p, definitions); err != nil {
+		return Bundle{}, err
+	}
+	for _, dir := range []string{bundleDir, remediationsDir} {
+		if err := removeScripts(dir); err != nil {
+			return Bundle{}, err
+		}
+	}
+
+	var bundle Bundle
+	rendered := make(map[string]bool)
+	for _, ruleSet := range p {
+		for _, check := range ruleSet.Checks {
+			if rendered[check.ID] {
+				continue
+			}
+			rendered[check.ID] = true
+			bundleCheck := BundleCheck{ID: check.ID, Rule: ruleSet.Rule.ID, Description: check.Description}
+			definition, found := definitions[check.ID]
+			if !found {
+				bundle.Checks = append(bundle.Checks, bundleCheck)
+				continue
+			}
+
+			bundleCheck.Script = ScriptName(check.ID)
+			bundleCheck.Expect = definition.Expect
+			bundleCheck.Timeout = timeout.String()
+			if definition.Timeout != "" {
+				bundleCheck.Timeout = definition.Timeout
+			}
+			if definition.Description != "" {
+				bundleCheck.Description = definition.Description
+			}
+			script := renderScript(shell, bundleCheck, ruleSet.Rule.Parameters, definition.Command)
+			if err := os.WriteFile(filepath.Join(bundleDir, bundleCheck.Script), []byte(script), 0700); err != nil {
+				return Bundle{}, err
+			}
+			if definition.Remediation != "" {
+				remediation := renderScript(shell, bundleCheck, ruleSet.Rule.Parameters, definition.Remediation)
+				if err := os.WriteFile(filepath.Join(remediationsDir, bundleCheck.Script), []byte(remediation), 0700); err != nil {
+					return Bundle{}, err
+				}
+			}
+			bundle.Checks = append(bundle.Checks, bundleCheck)
+		}
+	}
+
+	data, err := yaml.Marshal(bundle)
+	if err != nil {
+		return Bundle{}, err
+	}
+	return bundle, os.WriteFile(filepath.Join(bundleDir, BundleFile), data, 0600)
+}
+
+// ReadBundle reads the check bundle of the bundle directory.
+func ReadBundle(bundleDir string) (Bundle, error) {
+	data, err := os.ReadFile(filepath.Join(bundleDir, BundleFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return Bundle{}, fmt.Errorf("no check bundle in %s, generate the policy first", bundleDir)
+	}
+	if err != nil {
+		return Bundle{}, err
+	}
+	var bundle Bundle
+	if err := yaml.Unmarshal(data, &bundle); err != nil {
+		return Bundle{}, fmt.Errorf("invalid check bundle %s: %w", filepath.Join(bundleDir, BundleFile), err)
+	}
+	return bundle, nil
+}
+
+// checkScriptNames returns an error when the scripts of two checks of the policy with a definition
+// have the same file name, as characters unsafe in file names are replaced.
+func checkScriptNames(p policy.Policy, definitions map[string]Definition) error {
+	checksByScript := make(map[string]string)
+	for _, ruleSet := range p {
+		for _, check := range ruleSet.Checks {
+			if _, found := definitions[check.ID]; !found {
+				continue
+			}
+			name := ScriptName(check.ID)
+			if other, found := checksByScript[name]; found && other != check.ID {
+				return fmt.Errorf("checks %s and %s have the same script name %s", other, check.ID, name)
+			}
+			checksByScript[name] = check.ID
+		}
+	}
+	return nil
+}
+
+// ScriptName returns the file name of the script of a check.
+func ScriptName(checkID string) string {
+	return unsafeFileChars.ReplaceAllString(checkID, "_") + scriptSuffix
+}
+
+// ParameterEnv returns the name of the environment variable holding a rule parameter.
+func ParameterEnv(parameterID string) string {
+	return ParameterEnvPrefix + strings.ToUpper(unsafeEnvChars.ReplaceAllString(parameterID, "_"))
+}
+
+// renderScript returns a script exporting the rule parameters and running the command with the shell.
+func renderScript(shell string, check BundleCheck, parameters []extensions.Parameter, command string) string {
+	var script strings.Builder
+	fmt.Fprintf(&script, "#!%s\n", shell)
+	fmt.Fprintf(&script, "# Check %s of rule %s, rendered by the complyctl script plugin.\n", check.ID, check.Rule)
+	if check.Description != "" {
+		fmt.Fprintf(&script, "# %s\n", strings.ReplaceAll(check.Description, "\n", "\n# "))
+	}
+	for _, parameter := range parameters {
+		name := ParameterEnv(parameter.ID)
+		fmt.Fprintf(&script, "%s=%s\nexport %s\n", name, pluginutil.ShellQuote(parameter.Value), name)
+	}
+	script.WriteString(strings.TrimRight(command, "\n"))
+	script.WriteString("\n")
+	return script.String()
+}
+
+// removeScripts removes the scripts and bundle file of the directory.
+func removeScripts(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && (strings.HasSuffix(entry.Name(), scriptSuffix) || entry.Name() == BundleFile) {
+			if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
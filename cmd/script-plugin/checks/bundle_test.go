@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPolicy() policy.Policy {
+	return policy.Policy{
+		{
+			Rule: extensions.Rule{
+				ID:         "ssh_root_login",
+				Parameters: []extensions.Parameter{{ID: "root-login", Value: "it's no"}},
+			},
+			Checks: []extensions.Check{{ID: "ssh_root_login_disabled", Description: "root login"}},
+		},
+		{
+			Rule:   extensions.Rule{ID: "ssh_service"},
+			Checks: []extensions.Check{{ID: "ssh_service_enabled"}, {ID: "undefined_check"}},
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	definitions, err := LoadDefinitions("testdata/definitions")
+	require.NoError(t, err)
+	bundleDir, remediationsDir := t.TempDir(), t.TempDir()
+	staleScript := filepath.Join(bundleDir, "stale.sh")
+	require.NoError(t, os.WriteFile(staleScript, nil, 0600))
+
+	bundle, err := Render(bundleDir, remediationsDir, testPolicy(), definitions, "/bin/sh", time.Minute)
+	require.NoError(t, err)
+	assert.NoFileExists(t, staleScript)
+	require.Len(t, bundle.Checks, 3)
+
+	rootLogin := bundle.Checks[0]
+	assert.Equal(t, "ssh_root_login", rootLogin.Rule)
+	assert.Equal(t, "SSH root login is disabled", rootLogin.Description)
+	assert.Equal(t, "ssh_root_login_disabled.sh", rootLogin.Script)
+	assert.Equal(t, "1m0s", rootLogin.Timeout)
+	assert.Equal(t, "5s", bundle.Checks[1].Timeout)
+	assert.Equal(t, BundleCheck{ID: "undefined_check", Rule: "ssh_service"}, bundle.Checks[2])
+
+	script, err := os.ReadFile(filepath.Join(bundleDir, rootLogin.Script))
+	require.NoError(t, err)
+	assert.Equal(t, `#!/bin/sh
+# Check ssh_root_login_disabled of rule ssh_root_login, rendered by the complyctl script plugin.
+# SSH root login is disabled
+PARAM_ROOT_LOGIN='it'\''s no'
+export PARAM_ROOT_LOGIN
+echo "permitrootlogin $PARAM_ROOT_LOGIN"
+`, string(script))
+	info, err := os.Stat(filepath.Join(bundleDir, rootLogin.Script))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	assert.FileExists(t, filepath.Join(remediationsDir, rootLogin.Script))
+	assert.NoFileExists(t, filepath.Join(remediationsDir, bundle.Checks[1].Script))
+
+	read, err := ReadBundle(bundleDir)
+	require.NoError(t, err)
+	assert.Equal(t, bundle, read)
+}
+
+func TestRenderInvalidPolicy(t *testing.T) {
+	invalid := policy.Policy{{Rule: extensions.Rule{}, Checks: []extensions.Check{{ID: "check"}}}}
+	bundleDir := t.TempDir()
+	_, err := Render(bundleDir, t.TempDir(), testPolicy(), nil, "/bin/sh", time.Minute)
+	require.NoError(t, err)
+	_, err = Render(bundleDir, t.TempDir(), invalid, nil, "/bin/sh", time.Minute)
+	assert.EqualError(t, err, "policy has a rule without identifier")
+	// The bundle of the previous policy is kept
+	assert.FileExists(t, filepath.Join(bundleDir, BundleFile))
+
+	// Checks whose IDs differ only in characters unsafe in file names have the same script
+	colliding := policy.Policy{{Rule: extensions.Rule{ID: "rule"}, Checks: []extensions.Check{{ID: "a/b"}, {ID: "a_b"}}}}
+	definitions := map[string]Definition{"a/b": {Command: "true"}, "a_b": {Command: "true"}}
+	_, err = Render(bundleDir, t.TempDir(), colliding, definitions, "/bin/sh", time.Minute)
+	assert.EqualError(t, err, "checks a/b and a_b have the same script name a_b.sh")
+	assert.FileExists(t, filepath.Join(bundleDir, BundleFile))
+
+	_, err = ReadBundle(t.TempDir())
+	assert.ErrorContains(t, err, "generate the policy first")
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, "xccdf_org.check_a_b.sh", ScriptName("xccdf_org.check/a b"))
+	assert.Equal(t, "PARAM_VAR_SSHD_SET_KEEPALIVE_1", ParameterEnv("var_sshd-set.keepalive 1"))
+}
@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package checks
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// confineProcess runs the command as the user of the confinement, in its own process group killed as a
+// whole when the command is canceled, and kills the command when the plugin exits.
+func confineProcess(cmd *exec.Cmd, confinement Confinement) error {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid:   true,
+		Pdeathsig: syscall.SIGKILL,
+		// Only root can drop the supplementary groups, other users can only run the scripts as themselves
+		Credential: &syscall.Credential{Uid: confinement.UID, Gid: confinement.GID, NoSetGroups: os.Getuid() != 0},
+	}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package checks
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// confineProcess returns an error, check scripts can only be confined on Linux.
+func confineProcess(cmd *exec.Cmd, confinement Confinement) error {
+	return errors.New("check scripts can only be confined on Linux")
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Definition is a declarative check run as a shell command. The check passes when the command exits
+// with the expected exit code and its output matches the expected output. Without expectations, the
+// check passes when the command exits with 0.
+type Definition struct {
+	// ID is the ID of the check in the OSCAL component definition.
+	ID string `yaml:"id"`
+	// Description describes what the check verifies.
+	Description string `yaml:"description,omitempty"`
+	// Command is the shell command of the check.
+	Command string `yaml:"command"`
+	// Expect defines the expected outcome of the command.
+	Expect Expectation `yaml:"expect,omitempty"`
+	// Timeout is the time the command can take, such as 10s. The timeout option of the plugin applies when empty.
+	Timeout string `yaml:"timeout,omitempty"`
+	// Remediation is an optional shell command fixing a failing check.
+	Remediation string `yaml:"remediation,omitempty"`
+}
+
+// Expectation is the expected outcome of a check command.
+type Expectation struct {
+	// ExitCode is the expected exit code of the command, 0 when neither the exit code nor the output are set.
+	ExitCode *int `yaml:"exit-code,omitempty"`
+	// Output is a regular expression matching the standard output of the command, or a line of it.
+	Output string `yaml:"output,omitempty"`
+}
+
+// outputExpression compiles the expected output. The expression is matched in multi-line mode,
+// so ^ and $ match at the start and end of each line of the output.
+func (e Expectation) outputExpression() (*regexp.Regexp, error) {
+	return regexp.Compile("(?m)" + e.Output)
+}
+
+// File is the content of a check definition file.
+type File struct {
+	Checks []Definition `yaml:"checks"`
+}
+
+// Validate returns an error when the definition cannot be run.
+func (d Definition) Validate() error {
+	if d.ID == "" {
+		return errors.New("check has no id")
+	}
+	if strings.TrimSpace(d.Command) == "" {
+		return fmt.Errorf("check %s has no command", d.ID)
+	}
+	if d.Expect.Output != "" {
+		if _, err := d.Expect.outputExpression(); err != nil {
+			return fmt.Errorf("check %s has an invalid output expression: %w", d.ID, err)
+		}
+	}
+	if d.Timeout != "" {
+		if timeout, err := time.ParseDuration(d.Timeout); err != nil || timeout <= 0 {
+			return fmt.Errorf("check %s has an invalid timeout %q, expected a positive duration such as 10s", d.ID, d.Timeout)
+		}
+	}
+	return nil
+}
+
+// LoadDefinitions reads the check definitions of the *.yaml and *.yml files of the directory, indexed by check ID.
+// Check IDs must be unique across the files.
+func LoadDefinitions(dir string) (map[string]Definition, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+
+	definitions := make(map[string]Definition)
+	definedIn := make(map[string]string)
+	var errs []error
+	for _, path := range files {
+		data, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var file File
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			errs = append(errs, fmt.Errorf("invalid check definitions %s: %w", path, err))
+			continue
+		}
+		for _, definition := range file.Checks {
+			if err := definition.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("invalid check definitions %s: %w", path, err))
+				continue
+			}
+			if previous, found := definedIn[definition.ID]; found {
+				errs = append(errs, fmt.Errorf("check %s of %s is already defined in %s", definition.ID, path, previous))
+				continue
+			}
+			definitions[definition.ID] = definition
+			definedIn[definition.ID] = path
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return definitions, nil
+}
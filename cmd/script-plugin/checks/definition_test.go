@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDefinitions(t *testing.T) {
+	definitions, err := LoadDefinitions("testdata/definitions")
+	require.NoError(t, err)
+	require.Len(t, definitions, 3)
+
+	rootLogin := definitions["ssh_root_login_disabled"]
+	assert.Equal(t, "SSH root login is disabled", rootLogin.Description)
+	assert.Nil(t, rootLogin.Expect.ExitCode)
+	assert.Equal(t, "^permitrootlogin no$", rootLogin.Expect.Output)
+	assert.NotEmpty(t, rootLogin.Remediation)
+
+	service := definitions["ssh_service_enabled"]
+	require.NotNil(t, service.Expect.ExitCode)
+	assert.Equal(t, 3, *service.Expect.ExitCode)
+	assert.Equal(t, "5s", service.Timeout)
+
+	assert.Equal(t, "sleep 10\n", definitions["tmp_mounted"].Command)
+}
+
+func TestLoadDefinitionsInvalid(t *testing.T) {
+	_, err := LoadDefinitions("testdata/invalid")
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "check no_command has no command")
+	assert.ErrorContains(t, err, "check invalid_output has an invalid output expression")
+	assert.ErrorContains(t, err, `check invalid_timeout has an invalid timeout "soon"`)
+	assert.ErrorContains(t, err, "check tmp_mounted of testdata/invalid/duplicate.yaml is already defined in testdata/invalid/checks.yaml")
+
+	_, err = LoadDefinitions("testdata/missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package checks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+)
+
+const (
+	// MaxOutput is the size of the standard output and error kept for a check.
+	MaxOutput = 1 << 20
+	// scriptPath is the PATH of the check scripts.
+	scriptPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+	// waitDelay is the time given to the processes started by a check to release its output after it exits.
+	waitDelay = time.Second
+
+	// WrapperBwrap runs the check scripts with bubblewrap in a read-only view of the file system, with
+	// their own process and IPC namespaces.
+	WrapperBwrap = "bwrap"
+	// MaxFileSize is the size of the largest file a check script can write.
+	MaxFileSize = 64 << 20
+	// MaxProcesses is the number of processes the user running the check scripts can have.
+	MaxProcesses = 512
+	// confinedShell starts the shell running a script once the confinement commands succeeded. It
+	// reports on file descriptor 3 that the script is confined.
+	confinedShell = `printf confined >&3 && exec 3>&- && exec "$@"`
+)
+
+// Confinement is the unprivileged user running the check scripts, and the optional wrapper command
+// confining them further.
+type Confinement struct {
+	// UID and GID are the user and group IDs of the user.
+	UID uint32
+	GID uint32
+	// Wrapper is the wrapper command, WrapperBwrap, or empty to run the scripts without wrapper.
+	Wrapper string
+}
+
+// Output is the outcome of a check script.
+type Output struct {
+	ExitCode int
+	Stdout   []byte
+	Stderr   []byte
+	// TimedOut is true when the script was killed after its timeout.
+	TimedOut bool
+	Duration time.Duration
+}
+
+// Run runs a check script with the shell in a confined subprocess. The script runs as the user of the
+// confinement with the no_new_privs flag, so it cannot gain privileges, and with limits on its CPU time,
+// the size of the files it writes, and the number of processes of the user. It runs in an empty
+// temporary working directory of the user, removed afterwards, with a minimal environment and no
+// standard input, and optionally in the wrapper of the confinement.
+// The script and the processes it starts are killed after the timeout. An error is returned when the
+// script cannot be started or confined, so it never runs unconfined.
+func Run(ctx context.Context, shell, script string, timeout time.Duration, confinement Confinement) (Output, error) {
+	if _, err := exec.LookPath(shell); err != nil {
+		return Output{}, fmt.Errorf("failed to run %s: %w", filepath.Base(script), err)
+	}
+	runDir, err := os.MkdirTemp("", "complyctl-script-check-")
+	if err != nil {
+		return Output{}, err
+	}
+	defer os.RemoveAll(runDir)
+	// The script is copied next to the working directory, as the user may not read the check bundle
+	workDir := filepath.Join(runDir, "work")
+	scriptCopy := filepath.Join(runDir, filepath.Base(script))
+	if err := prepareRunDir(runDir, workDir, script, scriptCopy, confinement); err != nil {
+		return Output{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// The confinement commands replace themselves with the next one, up to the shell running the script
+	var args []string
+	if confinement.Wrapper == WrapperBwrap {
+		args = append(args, WrapperBwrap, "--ro-bind", "/", "/", "--dev", "/dev", "--proc", "/proc",
+			"--tmpfs", "/tmp", "--bind", runDir, runDir, "--unshare-pid", "--unshare-ipc", "--die-with-parent", "--")
+	}
+	cpuTime := int(math.Ceil(timeout.Seconds()))
+	args = append(args, "setpriv", "--no-new-privs", "--",
+		"prlimit", fmt.Sprintf("--cpu=%d", cpuTime), fmt.Sprintf("--fsize=%d", MaxFileSize), fmt.Sprintf("--nproc=%d", MaxProcesses), "--",
+		"/bin/sh", "-c", confinedShell, "confined-check", shell, scriptCopy)
+
+	confined, confinedWriter, err := os.Pipe()
+	if err != nil {
+		return Output{}, err
+	}
+	defer confined.Close()
+
+	stdout := &limitedBuffer{limit: MaxOutput}
+	stderr := &limitedBuffer{limit: MaxOutput}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = workDir
+	cmd.Env = []string{
+		"PATH=" + scriptPath,
+		"HOME=" + workDir,
+		"TMPDIR=" + workDir,
+		"LANG=C",
+		"LC_ALL=C",
+	}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.ExtraFiles = []*os.File{confinedWriter}
+	cmd.WaitDelay = waitDelay
+	if err := confineProcess(cmd, confinement); err != nil {
+		confinedWriter.Close()
+		return Output{}, err
+	}
+
+	start := time.Now()
+	err = cmd.Start()
+	confinedWriter.Close()
+	if err == nil {
+		err = cmd.Wait()
+	}
+	output := Output{Stdout: stdout.Bytes(), Stderr: stderr.Bytes(), Duration: time.Since(start)}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		output.TimedOut = true
+		output.ExitCode = -1
+		return output, nil
+	}
+	// The report is written before the script starts, so it is complete once the script exited
+	_ = confined.SetReadDeadline(time.Now().Add(waitDelay))
+	if report, _ := io.ReadAll(confined); string(report) != "confined" {
+		if err == nil {
+			err = errors.New(strings.TrimSpace(string(output.Stderr)))
+		}
+		return output, fmt.Errorf("failed to confine %s: %w", filepath.Base(script), err)
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		output.ExitCode = exitErr.ExitCode()
+	case errors.Is(err, exec.ErrWaitDelay):
+		// The script exited but left processes holding its output
+	default:
+		return output, fmt.Errorf("failed to run %s: %w", filepath.Base(script), err)
+	}
+	return output, nil
+}
+
+// prepareRunDir creates the working directory of a script and copies the script, owned by the user
+// running the script.
+func prepareRunDir(runDir, workDir, script, scriptCopy string, confinement Confinement) error {
+	content, err := os.ReadFile(filepath.Clean(script))
+	if err != nil {
+		return err
+	}
+	if err := os.Mkdir(workDir, 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(scriptCopy, content, 0500); err != nil {
+		return err
+	}
+	if os.Getuid() != 0 {
+		return nil
+	}
+	for _, path := range []string{runDir, workDir, scriptCopy} {
+		if err := os.Chown(path, int(confinement.UID), int(confinement.GID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the result of a check from the output of its script, and the reason of the result.
+func Evaluate(check BundleCheck, output Output) (policy.Result, string) {
+	if output.TimedOut {
+		return policy.ResultError, fmt.Sprintf("check timed out after %s", check.Timeout)
+	}
+
+	expectedExitCode := 0
+	if check.Expect.ExitCode != nil {
+		expectedExitCode = *check.Expect.ExitCode
+	}
+	checkExitCode := check.Expect.ExitCode != nil || check.Expect.Output == ""
+	if checkExitCode && output.ExitCode != expectedExitCode {
+		return policy.ResultFail, fmt.Sprintf("exit code %d, expected %d", output.ExitCode, expectedExitCode)
+	}
+	if check.Expect.Output != "" {
+		expression, err := check.Expect.outputExpression()
+		if err != nil {
+			return policy.ResultError, fmt.Sprintf("invalid output expression: %v", err)
+		}
+		if !expression.Match(output.Stdout) {
+			return policy.ResultFail, fmt.Sprintf("output does not match %q", check.Expect.Output)
+		}
+		if checkExitCode {
+			return policy.ResultPass, fmt.Sprintf("exit code %d and output matches %q", output.ExitCode, check.Expect.Output)
+		}
+		return policy.ResultPass, fmt.Sprintf("output matches %q", check.Expect.Output)
+	}
+	return policy.ResultPass, fmt.Sprintf("exit code %d", output.ExitCode)
+}
+
+// limitedBuffer is a writer keeping the writes up to its limit and discarding the rest.
+type limitedBuffer struct {
+	buffer bytes.Buffer
+	limit  int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.buffer.Len(); remaining > 0 {
+		if len(p) > remaining {
+			b.buffer.Write(p[:remaining])
+		} else {
+			b.buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buffer.Bytes()
+}
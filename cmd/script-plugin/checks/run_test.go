@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package checks
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfinement returns the confinement of the scripts of the tests: root runs them as nobody, and
+// other users as themselves.
+func testConfinement(t *testing.T) Confinement {
+	t.Helper()
+	if os.Getuid() != 0 {
+		return Confinement{UID: uint32(os.Getuid()), GID: uint32(os.Getgid())}
+	}
+	nobody, err := user.Lookup("nobody")
+	require.NoError(t, err)
+	uid, err := strconv.ParseUint(nobody.Uid, 10, 32)
+	require.NoError(t, err)
+	gid, err := strconv.ParseUint(nobody.Gid, 10, 32)
+	require.NoError(t, err)
+	return Confinement{UID: uint32(uid), GID: uint32(gid)}
+}
+
+func writeScript(t *testing.T, content string) string {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), "check.sh")
+	require.NoError(t, os.WriteFile(script, []byte(content), 0700))
+	return script
+}
+
+func TestRun(t *testing.T) {
+	t.Setenv("PLUGIN_SECRET", "secret")
+	script := writeScript(t, `echo "pwd=$(pwd) path=$PATH secret=$PLUGIN_SECRET"; echo error >&2; exit 4`)
+	output, err := Run(context.Background(), "/bin/sh", script, time.Minute, testConfinement(t))
+	require.NoError(t, err)
+	assert.Equal(t, 4, output.ExitCode)
+	assert.False(t, output.TimedOut)
+	assert.Contains(t, string(output.Stdout), "path="+scriptPath+" secret=\n")
+	assert.Contains(t, string(output.Stdout), "pwd="+os.TempDir())
+	assert.Equal(t, "error\n", string(output.Stderr))
+
+	workDir := strings.TrimPrefix(strings.Fields(string(output.Stdout))[0], "pwd=")
+	assert.NoDirExists(t, workDir)
+}
+
+func TestRunConfined(t *testing.T) {
+	confinement := testConfinement(t)
+	script := writeScript(t, "id -u; grep NoNewPrivs /proc/self/status; grep -e 'Max cpu time' -e 'Max file size' -e 'Max processes' /proc/self/limits")
+	output, err := Run(context.Background(), "/bin/sh", script, 90*time.Second, confinement)
+	require.NoError(t, err)
+	require.Equal(t, 0, output.ExitCode, string(output.Stderr))
+	lines := strings.Split(strings.TrimSpace(string(output.Stdout)), "\n")
+	require.Len(t, lines, 5)
+	assert.Equal(t, strconv.Itoa(int(confinement.UID)), lines[0])
+	assert.Equal(t, []string{"NoNewPrivs:", "1"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"Max", "cpu", "time", "90", "90", "seconds"}, strings.Fields(lines[2]))
+	assert.Equal(t, []string{"Max", "file", "size", strconv.Itoa(MaxFileSize), strconv.Itoa(MaxFileSize), "bytes"}, strings.Fields(lines[3]))
+	assert.Equal(t, []string{"Max", "processes", strconv.Itoa(MaxProcesses), strconv.Itoa(MaxProcesses), "processes"}, strings.Fields(lines[4]))
+
+	// A script that cannot be confined does not run
+	marker := filepath.Join(t.TempDir(), "ran")
+	script = writeScript(t, "touch "+marker)
+	t.Setenv("PATH", t.TempDir())
+	_, err = Run(context.Background(), "/bin/sh", script, time.Minute, confinement)
+	assert.ErrorContains(t, err, "failed to confine check.sh")
+	assert.NoFileExists(t, marker)
+}
+
+func TestRunTimeout(t *testing.T) {
+	// The background sleep keeps the output open, it must be killed with the script
+	script := writeScript(t, "sleep 30 &\nsleep 30\n")
+	start := time.Now()
+	output, err := Run(context.Background(), "/bin/sh", script, 100*time.Millisecond, testConfinement(t))
+	require.NoError(t, err)
+	assert.True(t, output.TimedOut)
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestRunOutputLimit(t *testing.T) {
+	script := writeScript(t, "head -c 2000000 /dev/zero")
+	output, err := Run(context.Background(), "/bin/sh", script, time.Minute, testConfinement(t))
+	require.NoError(t, err)
+	assert.Len(t, output.Stdout, MaxOutput)
+
+	_, err = Run(context.Background(), "/nonexistent/shell", script, time.Minute, testConfinement(t))
+	assert.Error(t, err)
+}
+
+func TestEvaluate(t *testing.T) {
+	three := 3
+	tests := []struct {
+		name       string
+		expect     Expectation
+		output     Output
+		wantResult policy.Result
+		wantReason string
+	}{
+		{
+			name:       "Valid/DefaultExitCode",
+			output:     Output{ExitCode: 0},
+			wantResult: policy.ResultPass,
+			wantReason: "exit code 0",
+		},
+		{
+			name:       "Valid/ExitCodeAndOutput",
+			expect:     Expectation{ExitCode: &three, Output: "^enabled$"},
+			output:     Output{ExitCode: 3, Stdout: []byte("service\nenabled\n")},
+			wantResult: policy.ResultPass,
+			wantReason: `exit code 3 and output matches "^enabled$"`,
+		},
+		{
+			name:       "Valid/OutputAnyExitCode",
+			expect:     Expectation{Output: "enabled"},
+			output:     Output{ExitCode: 1, Stdout: []byte("enabled\n")},
+			wantResult: policy.ResultPass,
+			wantReason: `output matches "enabled"`,
+		},
+		{
+			name:       "Invalid/ExitCode",
+			output:     Output{ExitCode: 1},
+			wantResult: policy.ResultFail,
+			wantReason: "exit code 1, expected 0",
+		},
+		{
+			name:       "Invalid/Output",
+			expect:     Expectation{Output: "^enabled"},
+			output:     Output{Stdout: []byte("disabled\n")},
+			wantResult: policy.ResultFail,
+			wantReason: `output does not match "^enabled"`,
+		},
+		{
+			name:       "Invalid/TimedOut",
+			output:     Output{TimedOut: true, ExitCode: -1},
+			wantResult: policy.ResultError,
+			wantReason: "check timed out after 5s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, reason := Evaluate(BundleCheck{ID: "check", Expect: tt.expect, Timeout: "5s"}, tt.output)
+			assert.Equal(t, tt.wantResult, result)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/complytime/complyctl/cmd/script-plugin/checks"
+)
+
+const (
+	PluginDir      string = "script"
+	ChecksDir      string = "checks"
+	ResultsDir     string = "results"
+	RemediationDir string = "remediations"
+	// DefaultShell is the shell running the checks when the shell option is not set.
+	DefaultShell string = "/bin/sh"
+	// DefaultTimeout is the timeout of checks without a timeout when the timeout option is not set.
+	DefaultTimeout = 30 * time.Second
+	// DefaultUser is the user running the checks when the user option is not set.
+	DefaultUser string = "nobody"
+)
+
+type Config struct {
+	Files struct {
+		// Workspace is the workspace directory of complyctl.
+		Workspace string
+		// Definitions is the directory of the YAML check definitions.
+		Definitions string
+		// Bundle is the directory of the check bundle rendered by Generate.
+		Bundle string
+		// Results is the directory of the outputs of the checks.
+		Results string
+		// Remediations is the directory of the remediation scripts rendered by Generate.
+		Remediations string
+	}
+	Parameters struct {
+		Profile string
+		// Shell is the shell running the check scripts.
+		Shell string
+		// Timeout is the timeout of checks without a timeout.
+		Timeout time.Duration
+		// Confinement is the user running the check scripts and their wrapper command.
+		Confinement checks.Confinement
+	}
+}
+
+// NewConfig creates a new, empty Config.
+func NewConfig() *Config {
+	return &Config{}
+}
+
+// LoadSettings sets the values in the Config from a given config map,
+// performs validation, and creates the workspace directories of the plugin.
+func (c *Config) LoadSettings(config map[string]string) error {
+	for _, key := range []string{"workspace", "profile", "checks"} {
+		if config[key] == "" {
+			return fmt.Errorf("missing configuration value for option %q", key)
+		}
+	}
+
+	definitions, err := filepath.Abs(filepath.Clean(config["checks"]))
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(definitions); err != nil {
+		return fmt.Errorf("invalid checks directory: %w", err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("invalid checks directory: %s is not a directory", definitions)
+	}
+
+	shell := config["shell"]
+	if shell == "" {
+		shell = DefaultShell
+	}
+	if _, err := exec.LookPath(shell); err != nil {
+		return fmt.Errorf("invalid shell: %w", err)
+	}
+
+	timeout := DefaultTimeout
+	if config["timeout"] != "" {
+		timeout, err = time.ParseDuration(config["timeout"])
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid timeout %q, expected a positive duration such as 30s", config["timeout"])
+		}
+	}
+
+	confinement, err := LoadConfinement(config["user"], config["wrapper"])
+	if err != nil {
+		return err
+	}
+
+	workspace, err := filepath.Abs(filepath.Clean(config["workspace"]))
+	if err != nil {
+		return err
+	}
+	c.Files.Workspace = workspace
+	c.Files.Definitions = definitions
+	c.Files.Bundle = filepath.Join(workspace, PluginDir, ChecksDir)
+	c.Files.Results = filepath.Join(workspace, PluginDir, ResultsDir)
+	c.Files.Remediations = filepath.Join(workspace, PluginDir, RemediationDir)
+	c.Parameters.Profile = config["profile"]
+	c.Parameters.Shell = shell
+	c.Parameters.Timeout = timeout
+	c.Parameters.Confinement = confinement
+	return c.ensureWorkspace()
+}
+
+// LoadConfinement returns the confinement of the check scripts run as the named user, DefaultUser
+// when empty, with the wrapper command.
+func LoadConfinement(userName, wrapper string) (checks.Confinement, error) {
+	if userName == "" {
+		userName = DefaultUser
+	}
+	checkUser, err := user.Lookup(userName)
+	if err != nil {
+		return checks.Confinement{}, fmt.Errorf("invalid user: %w", err)
+	}
+	uid, err := strconv.ParseUint(checkUser.Uid, 10, 32)
+	if err != nil {
+		return checks.Confinement{}, fmt.Errorf("invalid user %q: %w", userName, err)
+	}
+	gid, err := strconv.ParseUint(checkUser.Gid, 10, 32)
+	if err != nil {
+		return checks.Confinement{}, fmt.Errorf("invalid user %q: %w", userName, err)
+	}
+	if uid == 0 {
+		return checks.Confinement{}, fmt.Errorf("invalid user %q, expected an unprivileged user", userName)
+	}
+	switch wrapper {
+	case "":
+	case checks.WrapperBwrap:
+		if _, err := exec.LookPath(wrapper); err != nil {
+			return checks.Confinement{}, fmt.Errorf("invalid wrapper: %w", err)
+		}
+	default:
+		return checks.Confinement{}, fmt.Errorf("invalid wrapper %q, expected %s", wrapper, checks.WrapperBwrap)
+	}
+	return checks.Confinement{UID: uint32(uid), GID: uint32(gid), Wrapper: wrapper}, nil
+}
+
+// ensureWorkspace creates the workspace directories of the plugin.
+func (c *Config) ensureWorkspace() error {
+	for _, dir := range []string{c.Files.Bundle, c.Files.Results, c.Files.Remediations} {
+		_, err := os.Stat(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			if err := os.MkdirAll(dir, 0750); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			hclog.Default().Info("Directory created", "path", dir)
+		} else if err != nil {
+			return fmt.Errorf("error checking directory: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSettings(t *testing.T) {
+	workspace, checksDir := t.TempDir(), t.TempDir()
+	cfg := NewConfig()
+	require.NoError(t, cfg.LoadSettings(map[string]string{
+		"workspace": workspace,
+		"profile":   "baseline",
+		"checks":    checksDir,
+	}))
+	require.Equal(t, checksDir, cfg.Files.Definitions)
+	require.Equal(t, filepath.Join(workspace, PluginDir, ChecksDir), cfg.Files.Bundle)
+	require.Equal(t, DefaultShell, cfg.Parameters.Shell)
+	require.Equal(t, DefaultTimeout, cfg.Parameters.Timeout)
+	require.NotZero(t, cfg.Parameters.Confinement.UID)
+	require.Empty(t, cfg.Parameters.Confinement.Wrapper)
+	for _, dir := range []string{cfg.Files.Bundle, cfg.Files.Results, cfg.Files.Remediations} {
+		require.DirExists(t, dir)
+	}
+
+	require.NoError(t, cfg.LoadSettings(map[string]string{
+		"workspace": workspace,
+		"profile":   "baseline",
+		"checks":    checksDir,
+		"shell":     "sh",
+		"timeout":   "2m",
+	}))
+	require.Equal(t, "sh", cfg.Parameters.Shell)
+	require.Equal(t, 2*time.Minute, cfg.Parameters.Timeout)
+}
+
+func TestLoadSettingsInvalid(t *testing.T) {
+	workspace, checksDir := t.TempDir(), t.TempDir()
+	tests := []struct {
+		name    string
+		config  map[string]string
+		wantErr string
+	}{
+		{
+			name:    "MissingChecks",
+			config:  map[string]string{"workspace": workspace, "profile": "baseline"},
+			wantErr: `missing configuration value for option "checks"`,
+		},
+		{
+			name:    "ChecksNotFound",
+			config:  map[string]string{"workspace": workspace, "profile": "baseline", "checks": filepath.Join(checksDir, "missing")},
+			wantErr: "invalid checks directory",
+		},
+		{
+			name:    "ShellNotFound",
+			config:  map[string]string{"workspace": workspace, "profile": "baseline", "checks": checksDir, "shell": "/nonexistent/sh"},
+			wantErr: "invalid shell",
+		},
+		{
+			name:    "InvalidTimeout",
+			config:  map[string]string{"workspace": workspace, "profile": "baseline", "checks": checksDir, "timeout": "-1s"},
+			wantErr: `invalid timeout "-1s"`,
+		},
+		{
+			name:    "UserNotFound",
+			config:  map[string]string{"workspace": workspace, "profile": "baseline", "checks": checksDir, "user": "nonexistent-user"},
+			wantErr: "invalid user",
+		},
+		{
+			name:    "PrivilegedUser",
+			config:  map[string]string{"workspace": workspace, "profile": "baseline", "checks": checksDir, "user": "root"},
+			wantErr: `invalid user "root", expected an unprivileged user`,
+		},
+		{
+			name:    "InvalidWrapper",
+			config:  map[string]string{"workspace": workspace, "profile": "baseline", "checks": checksDir, "wrapper": "firejail"},
+			wantErr: `invalid wrapper "firejail", expected bwrap`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			require.ErrorContains(t, cfg.LoadSettings(tt.config), tt.wantErr)
+			require.Empty(t, cfg.Files.Workspace)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/complytime/complyctl/cmd/script-plugin/server"
+	"github.com/complytime/complyctl/pkg/doctor"
+	"github.com/complytime/complyctl/pkg/trace"
+
+	hplugin "github.com/hashicorp/go-plugin"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
+)
+
+var logger hclog.Logger
+
+func init() {
+	logger = hclog.New(&hclog.LoggerOptions{
+		Name:       "script-plugin",
+		Level:      hclog.Debug,
+		Output:     os.Stderr,
+		JSONFormat: true,
+	})
+	hclog.SetDefault(logger)
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == doctor.PluginCheckArg {
+		if err := doctor.ServePluginChecks(os.Stdin, os.Stdout, server.Doctor); err != nil {
+			hclog.Default().Error(err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	hclog.Default().Info("Starting script plugin")
+	// complyctl sets the trace destination and context in the environment of the plugin
+	traceConfig := trace.ConfigFromEnvironment("script-plugin")
+	traceConfig.OnError = func(err error) { hclog.Default().Warn(err.Error()) }
+	tracer, err := trace.NewTracer(traceConfig)
+	if err != nil {
+		hclog.Default().Warn("Tracing disabled", "err", err)
+	}
+	trace.SetDefault(tracer)
+
+	scriptPlugin := server.New()
+	pluginByType := map[string]hplugin.Plugin{
+		plugin.PVPPluginName: &plugin.PVPPlugin{Impl: scriptPlugin},
+	}
+	config := plugin.ServeConfig{
+		PluginSet: pluginByType,
+		Logger:    logger,
+	}
+	plugin.Register(config)
+
+	// complyctl waits shortly for the plugin to exit, so the queued spans are exported within a deadline
+	ctx, cancel := context.WithTimeout(context.Background(), trace.FlushTimeout)
+	defer cancel()
+	if err := trace.Flush(ctx); err != nil {
+		hclog.Default().Warn(err.Error())
+	}
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/complytime/complyctl/cmd/script-plugin/checks"
+	"github.com/complytime/complyctl/cmd/script-plugin/config"
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+// Doctor checks the external dependencies of the plugin for the given
+// configuration selections.
+func Doctor(selections map[string]string) []doctor.Check {
+	var checkResults []doctor.Check
+
+	shell := selections["shell"]
+	if shell == "" {
+		shell = config.DefaultShell
+	}
+	if shellPath, err := exec.LookPath(shell); err != nil {
+		checkResults = append(checkResults, doctor.Failed("shell", err.Error(),
+			"Install the shell, or set the shell option in the plugin drop-in configuration"))
+	} else {
+		checkResults = append(checkResults, doctor.Passed("shell", shellPath))
+	}
+	checkResults = append(checkResults, doctorConfinement(selections))
+
+	if selections["checks"] == "" {
+		checkResults = append(checkResults, doctor.Failed("check definitions", "the checks option is not set",
+			"Set the checks option in the plugin drop-in configuration to the directory of the check definitions"))
+		return checkResults
+	}
+	definitions, err := checks.LoadDefinitions(selections["checks"])
+	if err != nil {
+		checkResults = append(checkResults, doctor.Failed("check definitions", err.Error(),
+			"Fix the check definition files of the checks directory"))
+	} else {
+		checkResults = append(checkResults, doctor.Passed("check definitions",
+			fmt.Sprintf("%d checks in %s", len(definitions), selections["checks"])))
+	}
+	return checkResults
+}
+
+// doctorConfinement checks that the check scripts can be confined as the user of the configuration.
+func doctorConfinement(selections map[string]string) doctor.Check {
+	confinement, err := config.LoadConfinement(selections["user"], selections["wrapper"])
+	if err != nil {
+		return doctor.Failed("confinement", err.Error(),
+			"Set the user option to an unprivileged user, and the wrapper option to bwrap or nothing, in the plugin drop-in configuration")
+	}
+	for _, command := range []string{"setpriv", "prlimit"} {
+		if _, err := exec.LookPath(command); err != nil {
+			return doctor.Failed("confinement", err.Error(), "Install util-linux")
+		}
+	}
+	if os.Getuid() != 0 && uint32(os.Getuid()) != confinement.UID {
+		return doctor.Failed("confinement", fmt.Sprintf("only root can run the checks as user %d", confinement.UID),
+			"Run complyctl as root, or set the user option to the user running complyctl")
+	}
+	message := fmt.Sprintf("checks run as user %d", confinement.UID)
+	if confinement.Wrapper != "" {
+		message += " with " + confinement.Wrapper
+	}
+	return doctor.Passed("confinement", message)
+}
@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+func TestDoctor(t *testing.T) {
+	checks := Doctor(map[string]string{"shell": "/nonexistent/sh", "user": "root"})
+	require.Len(t, checks, 3)
+	require.Equal(t, "shell", checks[0].Name)
+	require.Equal(t, doctor.Fail, checks[0].Status)
+	require.Equal(t, "confinement", checks[1].Name)
+	require.Equal(t, doctor.Fail, checks[1].Status)
+	require.Contains(t, checks[1].Message, "expected an unprivileged user")
+	require.Equal(t, "check definitions", checks[2].Name)
+	require.Equal(t, doctor.Fail, checks[2].Status)
+	require.Contains(t, checks[2].Fix, "checks option")
+
+	checksDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(checksDir, "checks.yaml"), []byte(testChecks), 0600))
+	selections := map[string]string{"checks": checksDir}
+	// Only root can run the checks as another user
+	if os.Getuid() != 0 {
+		currentUser, err := user.Current()
+		require.NoError(t, err)
+		selections["user"] = currentUser.Username
+	}
+	checks = Doctor(selections)
+	require.Equal(t, doctor.Pass, checks[0].Status)
+	require.Equal(t, doctor.Pass, checks[1].Status, checks[1].Message)
+	require.Contains(t, checks[1].Message, "checks run as user")
+	require.Equal(t, doctor.Pass, checks[2].Status)
+	require.Contains(t, checks[2].Message, "2 checks")
+}
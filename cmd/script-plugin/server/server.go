@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+
+	"github.com/complytime/complyctl/cmd/script-plugin/checks"
+	"github.com/complytime/complyctl/cmd/script-plugin/config"
+	"github.com/complytime/complyctl/pkg/pluginutil"
+	"github.com/complytime/complyctl/pkg/trace"
+)
+
+var _ policy.Provider = (*PluginServer)(nil)
+
+type PluginServer struct {
+	Config *config.Config
+}
+
+func New() PluginServer {
+	return PluginServer{
+		Config: config.NewConfig(),
+	}
+}
+
+func (s PluginServer) Configure(configMap map[string]string) error {
+	return s.Config.LoadSettings(configMap)
+}
+
+func (s PluginServer) Generate(policy policy.Policy) (err error) {
+	_, span := trace.Start(trace.FromEnvironment(context.Background()), "script.Generate")
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	definitions, err := checks.LoadDefinitions(s.Config.Files.Definitions)
+	if err != nil {
+		return err
+	}
+
+	hclog.Default().Info("Rendering the check bundle", "path", s.Config.Files.Bundle)
+	bundle, err := checks.Render(s.Config.Files.Bundle, s.Config.Files.Remediations, policy, definitions,
+		s.Config.Parameters.Shell, s.Config.Parameters.Timeout)
+	if err != nil {
+		return err
+	}
+	for _, check := range bundle.Checks {
+		if check.Script == "" {
+			hclog.Default().Warn("Check has no definition", "check", check.ID, "rule", check.Rule,
+				"checks", s.Config.Files.Definitions)
+		}
+	}
+	span.SetAttributes(trace.Int("script.checks", len(bundle.Checks)))
+	return nil
+}
+
+func (s PluginServer) GetResults(oscalPolicy policy.Policy) (_ policy.PVPResult, err error) {
+	ctx, span := trace.Start(trace.FromEnvironment(context.Background()), "script.GetResults")
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	if err := pluginutil.ValidatePolicy(oscalPolicy); err != nil {
+		return policy.PVPResult{}, err
+	}
+	bundle, err := checks.ReadBundle(s.Config.Files.Bundle)
+	if err != nil {
+		return policy.PVPResult{}, err
+	}
+	bundleChecks := make(map[string]checks.BundleCheck, len(bundle.Checks))
+	for _, check := range bundle.Checks {
+		bundleChecks[check.ID] = check
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return policy.PVPResult{}, err
+	}
+
+	pvpResults := policy.PVPResult{}
+	observed := make(map[string]bool)
+	for _, ruleSet := range oscalPolicy {
+		for _, policyCheck := range ruleSet.Checks {
+			if observed[policyCheck.ID] {
+				continue
+			}
+			observed[policyCheck.ID] = true
+			check, found := bundleChecks[policyCheck.ID]
+			if !found {
+				check = checks.BundleCheck{ID: policyCheck.ID, Rule: ruleSet.Rule.ID, Description: policyCheck.Description}
+			}
+			observation, err := s.runCheck(ctx, check, found, hostname)
+			if err != nil {
+				return policy.PVPResult{}, err
+			}
+			pvpResults.ObservationsByCheck = append(pvpResults.ObservationsByCheck, observation)
+		}
+	}
+	return pvpResults, nil
+}
+
+// runCheck runs the script of a check of the bundle and returns the observation of the check on the host.
+// The output of the script is kept in the results directory as evidence.
+func (s PluginServer) runCheck(ctx context.Context, check checks.BundleCheck, generated bool, hostname string) (_ policy.ObservationByCheck, err error) {
+	ctx, span := trace.Start(ctx, "check", trace.String("script.check_id", check.ID))
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	observation := policy.ObservationByCheck{
+		Title:       check.Rule,
+		Description: check.Description,
+		CheckID:     check.ID,
+		Methods:     []string{"AUTOMATED"},
+		Collected:   time.Now(),
+	}
+	subject := policy.Subject{
+		Title:       fmt.Sprintf("Host %s", hostname),
+		Type:        "inventory-item",
+		ResourceID:  hostname,
+		EvaluatedOn: time.Now(),
+		Props: []policy.Property{
+			{
+				Name:  "hostname",
+				Value: hostname,
+			},
+		},
+	}
+
+	switch {
+	case !generated:
+		subject.Result = policy.ResultError
+		subject.Reason = "check is not in the check bundle, generate the policy first"
+	case check.Script == "":
+		subject.Result = policy.ResultError
+		subject.Reason = fmt.Sprintf("check has no definition in %s", s.Config.Files.Definitions)
+	default:
+		timeout, err := time.ParseDuration(check.Timeout)
+		if err != nil {
+			return observation, fmt.Errorf("invalid timeout of check %s in the check bundle: %w", check.ID, err)
+		}
+		hclog.Default().Debug("Running check", "check", check.ID, "script", check.Script)
+		output, err := checks.Run(ctx, s.Config.Parameters.Shell, filepath.Join(s.Config.Files.Bundle, check.Script), timeout,
+			s.Config.Parameters.Confinement)
+		if err != nil {
+			subject.Result = policy.ResultError
+			subject.Reason = err.Error()
+		} else {
+			subject.Result, subject.Reason = checks.Evaluate(check, output)
+		}
+		span.SetAttributes(trace.Int("script.exit_code", output.ExitCode), trace.String("script.result", subject.Result.String()))
+
+		logPath := filepath.Join(s.Config.Files.Results, strings.TrimSuffix(check.Script, filepath.Ext(check.Script))+".log")
+		if err := writeLog(logPath, check, output, subject.Reason); err != nil {
+			return observation, err
+		}
+		observation.RelevantEvidences = []policy.Link{
+			{
+				Href:        fmt.Sprintf("file://%s", logPath),
+				Description: "CHECK_OUTPUT",
+			},
+		}
+	}
+	observation.Subjects = []policy.Subject{subject}
+	return observation, nil
+}
+
+// writeLog writes the output of the script of a check to the log file.
+func writeLog(logPath string, check checks.BundleCheck, output checks.Output, reason string) error {
+	var log strings.Builder
+	fmt.Fprintf(&log, "check: %s\nrule: %s\n", check.ID, check.Rule)
+	if output.TimedOut {
+		fmt.Fprintf(&log, "timed out after: %s\n", check.Timeout)
+	} else {
+		fmt.Fprintf(&log, "exit code: %d\n", output.ExitCode)
+	}
+	fmt.Fprintf(&log, "duration: %s\nresult: %s\n", output.Duration.Round(time.Millisecond), reason)
+	fmt.Fprintf(&log, "--- stdout\n%s", output.Stdout)
+	fmt.Fprintf(&log, "--- stderr\n%s", output.Stderr)
+	return os.WriteFile(logPath, []byte(log.String()), 0600)
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChecks = `checks:
+  - id: root_login_disabled
+    command: echo "PermitRootLogin $PARAM_ROOT_LOGIN"
+    expect:
+      output: "PermitRootLogin no"
+  - id: firewall_enabled
+    command: exit 1
+`
+
+func TestGenerateAndGetResults(t *testing.T) {
+	checksDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(checksDir, "checks.yaml"), []byte(testChecks), 0600))
+	options := map[string]string{
+		"workspace": t.TempDir(),
+		"profile":   "baseline",
+		"checks":    checksDir,
+	}
+	// Only root can run the checks as another user
+	if os.Getuid() != 0 {
+		currentUser, err := user.Current()
+		require.NoError(t, err)
+		options["user"] = currentUser.Username
+	}
+	s := New()
+	require.NoError(t, s.Configure(options))
+	testPolicy := policy.Policy{
+		{
+			Rule: extensions.Rule{
+				ID:         "sshd_disable_root_login",
+				Parameters: []extensions.Parameter{{ID: "root_login", Value: "no"}},
+			},
+			Checks: []extensions.Check{{ID: "root_login_disabled"}},
+		},
+		{
+			Rule:   extensions.Rule{ID: "firewalld_enabled"},
+			Checks: []extensions.Check{{ID: "firewall_enabled"}, {ID: "undefined_check"}},
+		},
+	}
+
+	require.NoError(t, s.Generate(testPolicy))
+	results, err := s.GetResults(append(testPolicy, extensions.RuleSet{
+		Rule:   extensions.Rule{ID: "ungenerated_rule"},
+		Checks: []extensions.Check{{ID: "ungenerated_check"}},
+	}))
+	require.NoError(t, err)
+	require.Len(t, results.ObservationsByCheck, 4)
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+	wantResults := []struct {
+		rule   string
+		check  string
+		result policy.Result
+		reason string
+	}{
+		{"sshd_disable_root_login", "root_login_disabled", policy.ResultPass, `output matches "PermitRootLogin no"`},
+		{"firewalld_enabled", "firewall_enabled", policy.ResultFail, "exit code 1, expected 0"},
+		{"firewalld_enabled", "undefined_check", policy.ResultError, "check has no definition"},
+		{"ungenerated_rule", "ungenerated_check", policy.ResultError, "check is not in the check bundle"},
+	}
+	for i, want := range wantResults {
+		observation := results.ObservationsByCheck[i]
+		assert.Equal(t, want.rule, observation.Title)
+		assert.Equal(t, want.check, observation.CheckID)
+		require.Len(t, observation.Subjects, 1)
+		assert.Equal(t, hostname, observation.Subjects[0].ResourceID)
+		assert.Equal(t, want.result, observation.Subjects[0].Result, want.check)
+		assert.Contains(t, observation.Subjects[0].Reason, want.reason)
+	}
+
+	evidences := results.ObservationsByCheck[0].RelevantEvidences
+	require.Len(t, evidences, 1)
+	log, err := os.ReadFile(strings.TrimPrefix(evidences[0].Href, "file://"))
+	require.NoError(t, err)
+	assert.Contains(t, string(log), "--- stdout\nPermitRootLogin no\n")
+	assert.Empty(t, results.ObservationsByCheck[2].RelevantEvidences)
+}
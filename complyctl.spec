@@ -33,6 +33,30 @@ OpenSCAP. The plugin communicates with complyctl via gRPC, providing a
 standard and consistent communication mechanism that gives independence for
 plugin developers to choose their preferred languages.
 
+%package        script-plugin
+Summary:        A plugin which extends complyctl capabilities to run checks defined as shell commands
+Requires:       %{name}%{?_isa} = %{version}-%{release}
+%description    script-plugin
+script-plugin is a reference plugin which extends the complyctl capabilities
+to run checks defined declaratively in YAML files as shell commands, with an
+expected exit code or output and a timeout.
+
+%package        manual-plugin
+Summary:        A plugin which extends complyctl capabilities to report attested manual checks
+Requires:       %{name}%{?_isa} = %{version}-%{release}
+%description    manual-plugin
+manual-plugin is a plugin which extends the complyctl capabilities to assess
+controls that cannot be checked automatically. The manual checks are answered
+with complyctl attest, and the plugin reports the recorded attestations.
+
+%package        opa-plugin
+Summary:        A plugin which extends complyctl capabilities to check configuration files with Rego policies
+Requires:       %{name}%{?_isa} = %{version}-%{release}
+%description    opa-plugin
+opa-plugin is a plugin which extends the complyctl capabilities to check
+structured configuration files, such as JSON, YAML, INI files and systemd
+units, with Rego policies evaluated by the Open Policy Agent.
+
 %prep
 %autosetup -n %{name}-%{version}
 
@@ -77,6 +101,18 @@ install -p -m 0644 docs/man/complyctl.1 %{buildroot}%{_mandir}/man1/complyctl.1
 install -p -m 0755 bin/openscap-plugin %{buildroot}%{_libexecdir}/%{name}/plugins/openscap-plugin
 install -p -m 0644 docs/man/c2p-openscap-manifest.5 %{buildroot}%{_mandir}/man5/c2p-openscap-manifest.5
 
+# Install files for script-plugin package
+install -p -m 0755 bin/script-plugin %{buildroot}%{_libexecdir}/%{name}/plugins/script-plugin
+install -p -m 0644 docs/man/c2p-script-manifest.5 %{buildroot}%{_mandir}/man5/c2p-script-manifest.5
+
+# Install files for manual-plugin package
+install -p -m 0755 bin/manual-plugin %{buildroot}%{_libexecdir}/%{name}/plugins/manual-plugin
+install -p -m 0644 docs/man/c2p-manual-manifest.5 %{buildroot}%{_mandir}/man5/c2p-manual-manifest.5
+
+# Install files for opa-plugin package
+install -p -m 0755 bin/opa-plugin %{buildroot}%{_libexecdir}/%{name}/plugins/opa-plugin
+install -p -m 0644 docs/man/c2p-opa-manifest.5 %{buildroot}%{_mandir}/man5/c2p-opa-manifest.5
+
 %check
 # Run unit tests
 go test -mod=vendor -race -v ./...
@@ -94,6 +130,20 @@ go test -mod=vendor -race -v ./...
 %attr(0755, root, root) %{_libexecdir}/%{name}/plugins/openscap-plugin
 %{_mandir}/man5/c2p-openscap-manifest.5*
 
+%files          script-plugin
+%attr(0755, root, root) %{_libexecdir}/%{name}/plugins/script-plugin
+%{_mandir}/man5/c2p-script-manifest.5*
+%{_datadir}/%{name}/samples/sample-script-checks.yaml
+
+%files          manual-plugin
+%attr(0755, root, root) %{_libexecdir}/%{name}/plugins/manual-plugin
+%{_mandir}/man5/c2p-manual-manifest.5*
+
+%files          opa-plugin
+%attr(0755, root, root) %{_libexecdir}/%{name}/plugins/opa-plugin
+%{_mandir}/man5/c2p-opa-manifest.5*
+%{_datadir}/%{name}/samples/sample-opa-policy.rego
+
 %changelog
 * Mon Jun 16 2025 George Vauter <gvauter@redhat.com>
 - Update package name to complyctl
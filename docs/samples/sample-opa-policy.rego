@@ -0,0 +1,20 @@
+# Policy of the docker_daemon_hardened check, evaluated by the complyctl OPA plugin with
+# inputs=/etc/docker/daemon.json. Each deny message fails the check.
+package checks.docker_daemon_hardened
+
+import rego.v1
+
+daemon := input.files["/etc/docker/daemon.json"]
+
+deny contains "/etc/docker/daemon.json is missing" if not daemon
+
+deny contains "live-restore is not enabled in /etc/docker/daemon.json" if {
+	daemon
+	not daemon["live-restore"]
+}
+
+deny contains msg if {
+	driver := object.get(daemon, "log-driver", "json-file")
+	driver != data.parameters.var_docker_log_driver
+	msg := sprintf("log-driver is %s, expected %s", [driver, data.parameters.var_docker_log_driver])
+}
@@ -9,12 +9,11 @@ import (
 )
 
 // The assessment results md file needs the catalog title information
-// LoadCatalogSource returns an OSCAL catalogs from a given application directory and a found catalog source.
-func LoadCatalogSource(appDir ApplicationDirectory, catalogSource string, validator validation.Validator) (*oscalTypes.Catalog, error) {
-	sourceFile, err := findControlSource(appDir, catalogSource)
+// LoadCatalogSource returns an OSCAL catalogs from a given content path and a found catalog source.
+func LoadCatalogSource(contentPath ContentPath, catalogSource string, validator validation.Validator) (*oscalTypes.Catalog, error) {
+	sourceFile, err := findControlSource(contentPath, catalogSource)
 	if err != nil {
 		return nil, err
 	}
-	defer sourceFile.Close()
 	return models.NewCatalog(sourceFile, validator)
 }
@@ -11,27 +11,23 @@ import (
 
 func TestLoadCatalogSource(t *testing.T) {
 	tests := []struct {
-		name    string
-		appDir  func() ApplicationDirectory
-		source  string
-		wantErr string
+		name        string
+		contentPath func() ContentPath
+		source      string
+		wantErr     string
 	}{
 		{
 			name: "Valid Catalog Load",
-			appDir: func() ApplicationDirectory {
-				appDir, err := newApplicationDirectory("testdata", false)
-				require.NoError(t, err)
-				return appDir
+			contentPath: func() ContentPath {
+				return testContentPath(t, "testdata")
 			},
 			source:  "file://controls/sample-catalog.json",
 			wantErr: "",
 		},
 		{
 			name: "File Does Not Exist",
-			appDir: func() ApplicationDirectory {
-				appDir, err := newApplicationDirectory("testdata", false)
-				require.NoError(t, err)
-				return appDir
+			contentPath: func() ContentPath {
+				return testContentPath(t, "testdata")
 			},
 			source:  "file://nonexistent/path/catalog.json",
 			wantErr: "got path nonexistent/path/catalog.json, control source is expected to be under path testdata/complytime/controls",
@@ -40,7 +36,7 @@ func TestLoadCatalogSource(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := LoadProfile(tt.appDir(), tt.source, validation.NoopValidator{})
+			_, err := LoadProfile(tt.contentPath(), tt.source, validation.NoopValidator{})
 			if tt.wantErr != "" {
 				require.Contains(t, err.Error(), tt.wantErr)
 			} else {
@@ -3,6 +3,7 @@
 package complytime
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
@@ -17,11 +18,14 @@ import (
 	"github.com/oscal-compass/oscal-sdk-go/models/components"
 	"github.com/oscal-compass/oscal-sdk-go/validation"
 
+	"github.com/complytime/complyctl/internal/complytime/format"
 	"github.com/complytime/complyctl/internal/complytime/plan"
 )
 
 const (
-	compDefSuffix          = "component-definition.json"
+	CompDefSuffix          = "component-definition"
+	PluginManifestPrefix   = "c2p-"
+	PluginManifestSuffix   = "-manifest.json"
 	ApplicationDir         = "complytime"
 	PluginDir              = "plugins"
 	BundlesDir             = "bundles"
@@ -31,6 +35,12 @@ const (
 	DefaultPluginConfigDir = "/etc/complytime/config.d/"
 )
 
+// IsComponentDefinitionFile returns whether the file name follows the naming scheme of
+// component definitions, $COMPONENT-NAME-component-definition with an OSCAL content extension.
+func IsComponentDefinitionFile(name string) bool {
+	return format.HasExtension(name) && strings.HasSuffix(format.TrimExtension(name), CompDefSuffix)
+}
+
 // ErrNoComponentDefinitionsFound returns an error indicated the supplied directory
 // does not contain component definitions that are detectable by complytime.
 var ErrNoComponentDefinitionsFound = errors.New("no component definitions found")
@@ -64,29 +74,45 @@ func NewApplicationDirectory(create bool) (ApplicationDirectory, error) {
 	}
 }
 
+// NewContentRoot returns the ApplicationDirectory of the user content root below the
+// XDG data directory, or of the system content root when system is true. Content bundles
+// are installed into and removed from these roots.
+func NewContentRoot(system bool) (ApplicationDirectory, error) {
+	if system {
+		return newApplicationDirectory(DataRootDir, false)
+	}
+	return newApplicationDirectory(xdg.DataHome, false)
+}
+
 // newApplicationDirectory returns a new ApplicationDirectory with the
 // given root directory. Creation of the directories is optional using the
 // `create` input. If the application directories exist, this will not overwrite what is
 // existing.
 func newApplicationDirectory(rootDir string, create bool) (ApplicationDirectory, error) {
-	applicationDir := ApplicationDirectory{
-		appDir: filepath.Join(rootDir, ApplicationDir),
-	}
+	applicationDir := contentDirectory(filepath.Join(rootDir, ApplicationDir))
 	// Drop-in configuration to be supported in CPLYTM-716
-	applicationDir.pluginManifestDir = filepath.Join(applicationDir.appDir, PluginDir)
-	if rootDir == DataRootDir {
+	if rootDir == DataRootDir || rootDir == SystemConfigRootDir {
 		applicationDir.pluginDir = filepath.Join(PluginBinaryRootDir, ApplicationDir, PluginDir)
-	} else {
-		applicationDir.pluginDir = applicationDir.pluginManifestDir
 	}
-	applicationDir.bundleDir = filepath.Join(applicationDir.appDir, BundlesDir)
-	applicationDir.controlDir = filepath.Join(applicationDir.appDir, ControlsDir)
 	if create {
 		return applicationDir, applicationDir.create()
 	}
 	return applicationDir, nil
 }
 
+// contentDirectory returns an ApplicationDirectory with the given top-level directory and plugin
+// executables next to the plugin manifests.
+func contentDirectory(appDir string) ApplicationDirectory {
+	applicationDir := ApplicationDirectory{
+		appDir: filepath.Clean(appDir),
+	}
+	applicationDir.pluginManifestDir = filepath.Join(applicationDir.appDir, PluginDir)
+	applicationDir.pluginDir = applicationDir.pluginManifestDir
+	applicationDir.bundleDir = filepath.Join(applicationDir.appDir, BundlesDir)
+	applicationDir.controlDir = filepath.Join(applicationDir.appDir, ControlsDir)
+	return applicationDir
+}
+
 // create creates the application directories if they do not exist.
 func (a ApplicationDirectory) create() error {
 	for _, dir := range a.Dirs() {
@@ -134,41 +160,48 @@ func (a ApplicationDirectory) Dirs() []string {
 }
 
 // FindComponentDefinitions locates all the OSCAL Component Definitions in the
-// given `bundles` directory that meet the defined naming scheme.
+// bundle directories of the content path, and their subdirectories, that meet the
+// defined naming scheme.
 //
-// The defined scheme is $COMPONENT-NAME-component-definition.json.
-func FindComponentDefinitions(bundleDir string, validator validation.Validator) ([]oscalTypes.ComponentDefinition, error) {
-	items, err := os.ReadDir(bundleDir)
+// The defined scheme is $COMPONENT-NAME-component-definition with a .json, .yaml, .yml,
+// or .xml extension. The format is detected from the content of the file.
+func FindComponentDefinitions(contentPath ContentPath, validator validation.Validator) ([]oscalTypes.ComponentDefinition, error) {
+	files, err := contentPath.ComponentDefinitionFiles()
 	if err != nil {
-		return nil, fmt.Errorf("unable to read bundle directory %s: %w", bundleDir, err)
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("directories %s: %w", strings.Join(contentPath.BundleDirs(), ", "), ErrNoComponentDefinitionsFound)
 	}
 
 	var compDefBundles []oscalTypes.ComponentDefinition
-	for _, item := range items {
-		if !strings.HasSuffix(item.Name(), compDefSuffix) {
-			continue
-		}
-		compDefPath := filepath.Join(bundleDir, item.Name())
-		compDefPath = filepath.Clean(compDefPath)
-		file, err := os.Open(compDefPath)
+	for _, file := range files {
+		definition, err := loadComponentDefinition(file.Path, validator)
 		if err != nil {
 			return nil, err
 		}
-		definition, err := models.NewComponentDefinition(file, validator)
-		if err != nil {
-			return nil, err
-		}
-		if definition == nil {
-			return nil, fmt.Errorf("could not load component definition from %s", compDefPath)
-		}
 		compDefBundles = append(compDefBundles, *definition)
 	}
-	if len(compDefBundles) == 0 {
-		return nil, fmt.Errorf("directory %s: %w", bundleDir, ErrNoComponentDefinitionsFound)
-	}
 	return compDefBundles, nil
 }
 
+// loadComponentDefinition reads an OSCAL Component Definition from the given path.
+func loadComponentDefinition(compDefPath string, validator validation.Validator) (*oscalTypes.ComponentDefinition, error) {
+	compDefPath = filepath.Clean(compDefPath)
+	data, err := format.ReadFile(compDefPath)
+	if err != nil {
+		return nil, err
+	}
+	definition, err := models.NewComponentDefinition(bytes.NewReader(data), validator)
+	if err != nil {
+		return nil, err
+	}
+	if definition == nil {
+		return nil, fmt.Errorf("could not load component definition from %s", compDefPath)
+	}
+	return definition, nil
+}
+
 // Config creates a new C2P config for the ComplyTime CLI to use to configure
 // the plugin manager.
 func Config(a ApplicationDirectory) (*framework.C2PConfig, error) {
@@ -42,11 +42,11 @@ func TestApplicationDirectory(t *testing.T) {
 }
 
 func TestFindComponentDefinitions(t *testing.T) {
-	compDefs, err := FindComponentDefinitions("testdata/complytime/bundles", validation.NoopValidator{})
+	compDefs, err := FindComponentDefinitions(testContentPath(t, "testdata"), validation.NoopValidator{})
 	require.NoError(t, err)
 	require.Len(t, compDefs, 1)
 
-	_, err = FindComponentDefinitions("testdata/", validation.NoopValidator{})
+	_, err = FindComponentDefinitions(testContentPath(t, t.TempDir()), validation.NoopValidator{})
 	require.ErrorIs(t, err, ErrNoComponentDefinitionsFound)
 
 }
@@ -0,0 +1,219 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/adrg/xdg"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
+)
+
+const (
+	// SystemConfigRootDir is the root directory of the content root for local administrator content.
+	SystemConfigRootDir = "/etc"
+	// ContentPathEnv is the environment variable listing additional content roots, separated
+	// by the OS path list separator.
+	ContentPathEnv = "COMPLYCTL_CONTENT_PATH"
+)
+
+// Origins of the content roots in the content path.
+const (
+	OriginSystem       = "system"
+	OriginSystemConfig = "system config"
+	OriginUser         = "user"
+	OriginEnv          = ContentPathEnv
+	OriginFlag         = "--content-dir"
+)
+
+// ContentRoot is an application directory searched for content.
+type ContentRoot struct {
+	ApplicationDirectory
+	// Origin describes where the content root is configured.
+	Origin string
+}
+
+// ContentPath is the list of content roots searched for component definitions, control sources,
+// and plugin manifests, in increasing order of precedence. A file replaces the files with the same
+// name in the same directory of the content roots before it. Files with different names are merged.
+type ContentPath []ContentRoot
+
+// ContentFile is a file found in the content path.
+type ContentFile struct {
+	// Name is the slash separated path of the file relative to the searched directory.
+	Name string
+	// Path is the location of the file.
+	Path string
+	// Root is the content root providing the file.
+	Root ContentRoot
+}
+
+// NewContentPath returns the content path with the system content root, the system config content
+// root, the user content root, the content roots listed in the COMPLYCTL_CONTENT_PATH environment
+// variable, and the given content directories, in that order.
+//
+// The system and system config content roots are the complytime directories below /usr/share and
+// /etc and use the plugin executables installed in /usr/libexec. The user content root is the
+// complytime directory below the XDG data directory. Additional content directories are content
+// roots themselves, containing the bundles, controls, and plugins directories.
+func NewContentPath(contentDirs []string) ContentPath {
+	system, _ := newApplicationDirectory(DataRootDir, false)
+	systemConfig, _ := newApplicationDirectory(SystemConfigRootDir, false)
+	user, _ := newApplicationDirectory(xdg.DataHome, false)
+	contentPath := ContentPath{
+		{ApplicationDirectory: system, Origin: OriginSystem},
+		{ApplicationDirectory: systemConfig, Origin: OriginSystemConfig},
+		{ApplicationDirectory: user, Origin: OriginUser},
+	}
+	for _, dir := range filepath.SplitList(os.Getenv(ContentPathEnv)) {
+		if dir != "" {
+			contentPath = append(contentPath, ContentRoot{ApplicationDirectory: contentDirectory(dir), Origin: OriginEnv})
+		}
+	}
+	for _, dir := range contentDirs {
+		contentPath = append(contentPath, ContentRoot{ApplicationDirectory: contentDirectory(dir), Origin: OriginFlag})
+	}
+	return contentPath
+}
+
+// String returns the top-level directories of the content roots separated by the OS path list separator.
+func (p ContentPath) String() string {
+	dirs := make([]string, 0, len(p))
+	for _, root := range p {
+		dirs = append(dirs, root.AppDir())
+	}
+	return strings.Join(dirs, string(os.PathListSeparator))
+}
+
+// BundleDirs returns the bundle directories of the content roots.
+func (p ContentPath) BundleDirs() []string {
+	dirs := make([]string, 0, len(p))
+	for _, root := range p {
+		dirs = append(dirs, root.BundleDir())
+	}
+	return dirs
+}
+
+// ControlDirs returns the control directories of the content roots.
+func (p ContentPath) ControlDirs() []string {
+	dirs := make([]string, 0, len(p))
+	for _, root := range p {
+		dirs = append(dirs, root.ControlDir())
+	}
+	return dirs
+}
+
+// ComponentDefinitionFiles returns the component definition files found in the bundle directories
+// of the content path and their subdirectories, ordered by name.
+func (p ContentPath) ComponentDefinitionFiles() ([]ContentFile, error) {
+	return p.findFiles(ApplicationDirectory.BundleDir, true, IsComponentDefinitionFile)
+}
+
+// PluginManifestFiles returns the plugin manifests found in the plugin manifest directories of
+// the content path, ordered by name.
+func (p ContentPath) PluginManifestFiles() ([]ContentFile, error) {
+	return p.findFiles(ApplicationDirectory.PluginManifestDir, false, func(name string) bool {
+		return strings.HasPrefix(name, PluginManifestPrefix) && strings.HasSuffix(name, PluginManifestSuffix)
+	})
+}
+
+// findFiles returns the matching files in the given directory of each content root, and in its
+// subdirectories if recursive is set. Files are named by their slash separated path relative to
+// the directory. Missing directories are skipped.
+func (p ContentPath) findFiles(dir func(ApplicationDirectory) string, recursive bool, match func(name string) bool) ([]ContentFile, error) {
+	byName := make(map[string]ContentFile)
+	for _, root := range p {
+		searchDir := dir(root.ApplicationDirectory)
+		if _, err := os.Stat(searchDir); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		err := filepath.WalkDir(searchDir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				if path != searchDir && !recursive {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !match(entry.Name()) {
+				return nil
+			}
+			name, err := filepath.Rel(searchDir, path)
+			if err != nil {
+				return err
+			}
+			name = filepath.ToSlash(name)
+			byName[name] = ContentFile{Name: name, Path: path, Root: root}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to read directory %s: %w", searchDir, err)
+		}
+	}
+	files := make([]ContentFile, 0, len(byName))
+	for _, file := range byName {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
+
+// resolveControlSource returns the location of a control source path relative to the content
+// roots, searching the content roots in decreasing order of precedence.
+func (p ContentPath) resolveControlSource(path string) (string, error) {
+	for i := len(p) - 1; i >= 0; i-- {
+		candidate := filepath.Join(p[i].AppDir(), path)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("control source %s not found in %s: %w", path, strings.Join(p.ControlDirs(), ", "), os.ErrNotExist)
+}
+
+// FindPlugins returns the manifests of the requested policy validation plugins from the content
+// path, with the executable path resolved from the plugin directory of the content root providing
+// the manifest.
+func (p ContentPath) FindPlugins(requestedPlugins []plugin.ID) (plugin.Manifests, error) {
+	manifestFiles, err := p.PluginManifestFiles()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[plugin.ID]ContentFile)
+	for _, file := range manifestFiles {
+		pluginID := strings.TrimSuffix(strings.TrimPrefix(file.Name, PluginManifestPrefix), PluginManifestSuffix)
+		byID[plugin.ID(pluginID)] = file
+	}
+
+	manifests := make(plugin.Manifests)
+	var errs []error
+	for _, pluginID := range requestedPlugins {
+		file, found := byID[pluginID]
+		if !found {
+			errs = append(errs, &plugin.NotFoundError{PluginID: pluginID.String()})
+			continue
+		}
+		rootManifests, err := plugin.FindPlugins(file.Root.PluginDir(), file.Root.PluginManifestDir(),
+			plugin.WithProviderIds([]plugin.ID{pluginID}),
+			plugin.WithPluginType(plugin.PVPPluginName),
+		)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for id, manifest := range rootManifests {
+			manifests[id] = manifest
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return manifests, nil
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrg/xdg"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
+	"github.com/stretchr/testify/require"
+)
+
+// testContentPath returns a content path with the complytime directory below each root directory.
+func testContentPath(t *testing.T, rootDirs ...string) ContentPath {
+	var contentPath ContentPath
+	for _, rootDir := range rootDirs {
+		appDir, err := newApplicationDirectory(rootDir, false)
+		require.NoError(t, err)
+		contentPath = append(contentPath, ContentRoot{ApplicationDirectory: appDir, Origin: OriginFlag})
+	}
+	return contentPath
+}
+
+func TestNewContentPath(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+	t.Setenv(ContentPathEnv, "/opt/content"+string(os.PathListSeparator)+string(os.PathListSeparator)+"/srv/content")
+
+	contentPath := NewContentPath([]string{"./my-content"})
+	var appDirs, origins []string
+	for _, root := range contentPath {
+		appDirs = append(appDirs, root.AppDir())
+		origins = append(origins, root.Origin)
+	}
+	require.Equal(t, []string{
+		"/usr/share/complytime",
+		"/etc/complytime",
+		filepath.Join(dataHome, "complytime"),
+		"/opt/content",
+		"/srv/content",
+		"my-content",
+	}, appDirs)
+	require.Equal(t, []string{OriginSystem, OriginSystemConfig, OriginUser, OriginEnv, OriginEnv, OriginFlag}, origins)
+	require.Equal(t, "/usr/libexec/complytime/plugins", contentPath[1].PluginDir())
+	require.Equal(t, "/opt/content/plugins", contentPath[3].PluginDir())
+	require.Equal(t, "my-content/bundles", contentPath[5].BundleDir())
+}
+
+func TestContentPathOverrides(t *testing.T) {
+	systemDir, userDir := t.TempDir(), t.TempDir()
+	contentPath := testContentPath(t, systemDir, userDir)
+	writeFile := func(path string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte("{}"), 0600))
+	}
+	writeFile(filepath.Join(contentPath[0].BundleDir(), "anssi-component-definition.json"))
+	writeFile(filepath.Join(contentPath[0].BundleDir(), "cis-component-definition.json"))
+	writeFile(filepath.Join(contentPath[0].BundleDir(), "README.md"))
+	writeFile(filepath.Join(contentPath[1].BundleDir(), "cis-component-definition.json"))
+	writeFile(filepath.Join(contentPath[0].ControlDir(), "profile.json"))
+	writeFile(filepath.Join(contentPath[0].ControlDir(), "catalog.json"))
+	writeFile(filepath.Join(contentPath[1].ControlDir(), "catalog.json"))
+
+	files, err := contentPath.ComponentDefinitionFiles()
+	require.NoError(t, err)
+	require.Equal(t, []ContentFile{
+		{
+			Name: "anssi-component-definition.json",
+			Path: filepath.Join(contentPath[0].BundleDir(), "anssi-component-definition.json"),
+			Root: contentPath[0],
+		},
+		{
+			Name: "cis-component-definition.json",
+			Path: filepath.Join(contentPath[1].BundleDir(), "cis-component-definition.json"),
+			Root: contentPath[1],
+		},
+	}, files)
+
+	path, err := contentPath.resolveControlSource("controls/catalog.json")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(contentPath[1].ControlDir(), "catalog.json"), path)
+	path, err = contentPath.resolveControlSource("controls/profile.json")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(contentPath[0].ControlDir(), "profile.json"), path)
+	_, err = contentPath.resolveControlSource("controls/missing.json")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	// Missing content roots are skipped
+	files, err = testContentPath(t, filepath.Join(systemDir, "missing")).ComponentDefinitionFiles()
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+func TestContentPathFindPlugins(t *testing.T) {
+	systemDir, userDir := t.TempDir(), t.TempDir()
+	contentPath := testContentPath(t, systemDir, userDir)
+	writePlugin := func(root ContentRoot, id, executable string) {
+		require.NoError(t, os.MkdirAll(root.PluginDir(), 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(root.PluginDir(), executable), []byte("#!/bin/sh\n"), 0700))
+		manifest := `{"metadata":{"id":"` + id + `","types":["pvp"]},"executablePath":"` + executable + `"}`
+		require.NoError(t, os.WriteFile(filepath.Join(root.PluginManifestDir(), "c2p-"+id+"-manifest.json"), []byte(manifest), 0600))
+	}
+	writePlugin(contentPath[0], "openscap", "openscap-plugin")
+	writePlugin(contentPath[0], "ampel", "ampel-plugin")
+	writePlugin(contentPath[1], "openscap", "my-openscap-plugin")
+
+	manifests, err := contentPath.FindPlugins([]plugin.ID{"openscap", "ampel"})
+	require.NoError(t, err)
+	require.Len(t, manifests, 2)
+	require.Equal(t, filepath.Join(contentPath[1].PluginDir(), "my-openscap-plugin"), manifests["openscap"].ExecutablePath)
+	require.Equal(t, filepath.Join(contentPath[0].PluginDir(), "ampel-plugin"), manifests["ampel"].ExecutablePath)
+
+	_, err = contentPath.FindPlugins([]plugin.ID{"missing"})
+	var notFound *plugin.NotFoundError
+	require.ErrorAs(t, err, &notFound)
+}
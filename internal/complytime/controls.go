@@ -3,17 +3,23 @@
 package complytime
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/oscal-compass/oscal-sdk-go/models"
 	"github.com/oscal-compass/oscal-sdk-go/settings"
 	"github.com/oscal-compass/oscal-sdk-go/validation"
+
+	"github.com/complytime/complyctl/internal/complytime/format"
 )
 
 // Framework represents an implemented compliance framework across
@@ -21,43 +27,67 @@ import (
 type Framework struct {
 	// ID is the short-name identifier that is used to consistently
 	// represent a framework.
-	ID string
+	ID string `json:"id"`
 	// Title is the human-readable name for a framework
-	Title string
+	Title string `json:"title"`
 	// SupportedComponents define the component titles that implement the
 	// framework.
-	SupportedComponents []string
+	SupportedComponents []string `json:"supported-components"`
+	// Roots are the content roots of the component definitions that implement
+	// the framework.
+	Roots []string `json:"roots"`
 }
 
-// LoadFrameworks returns all loaded framework information from a given application directory.
-func LoadFrameworks(appDir ApplicationDirectory, validator validation.Validator) ([]Framework, error) {
-	definitions, err := FindComponentDefinitions(appDir.BundleDir(), validator)
+// LoadFrameworks returns all loaded framework information from a given content path.
+func LoadFrameworks(contentPath ContentPath, validator validation.Validator) ([]Framework, error) {
+	files, err := contentPath.ComponentDefinitionFiles()
+	if err == nil && len(files) == 0 {
+		err = ErrNoComponentDefinitionsFound
+	}
 	if err != nil {
-		return nil, fmt.Errorf("error finding component defintions in %s: %w", appDir.BundleDir(), err)
+		return nil, fmt.Errorf("error finding component defintions in %s: %w", strings.Join(contentPath.BundleDirs(), ", "), err)
 	}
 
+	definitions := make([]oscalTypes.ComponentDefinition, 0, len(files))
+	for _, file := range files {
+		definition, err := loadComponentDefinition(file.Path, validator)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, *definition)
+	}
+	return newContentLoader(contentPath, validator).frameworks(files, definitions)
+}
+
+// frameworks returns the frameworks implemented by the component definitions loaded from the
+// given files, ordered by ID.
+func (l *contentLoader) frameworks(files []ContentFile, definitions []oscalTypes.ComponentDefinition) ([]Framework, error) {
 	byFramework := make(map[string]Framework)
-	for _, definition := range definitions {
-		if definition.Components != nil {
-			for _, comp := range *definition.Components {
-				// The goal is to only display the target component and abstract away the
-				// validation implementation details, so skipping.
-				if comp.Type == "validation" {
-					continue
-				}
-				frameworks, err := processComponent(appDir, comp, validator)
-				if err != nil {
-					return nil, err
+	for i, definition := range definitions {
+		if definition.Components == nil {
+			continue
+		}
+		for _, comp := range *definition.Components {
+			// The goal is to only display the target component and abstract away the
+			// validation implementation details, so skipping.
+			if comp.Type == "validation" {
+				continue
+			}
+			frameworks, err := l.processComponent(comp)
+			if err != nil {
+				return nil, err
+			}
+			for _, inputFramework := range frameworks {
+				framework, ok := byFramework[inputFramework.ID]
+				if !ok {
+					framework = inputFramework
+					framework.SupportedComponents = []string{}
 				}
-				for _, inputFramework := range frameworks {
-					framework, ok := byFramework[inputFramework.ID]
-					if !ok {
-						framework = inputFramework
-						framework.SupportedComponents = []string{}
-					}
-					framework.SupportedComponents = append(framework.SupportedComponents, comp.Title)
-					byFramework[framework.ID] = framework
+				framework.SupportedComponents = append(framework.SupportedComponents, comp.Title)
+				if !slices.Contains(framework.Roots, files[i].Root.AppDir()) {
+					framework.Roots = append(framework.Roots, files[i].Root.AppDir())
 				}
+				byFramework[framework.ID] = framework
 			}
 		}
 	}
@@ -66,10 +96,11 @@ func LoadFrameworks(appDir ApplicationDirectory, validator validation.Validator)
 	for _, framework := range byFramework {
 		frameworks = append(frameworks, framework)
 	}
+	sort.Slice(frameworks, func(i, j int) bool { return frameworks[i].ID < frameworks[j].ID })
 	return frameworks, nil
 }
 
-func processComponent(appDir ApplicationDirectory, component oscalTypes.DefinedComponent, validator validation.Validator) ([]Framework, error) {
+func (l *contentLoader) processComponent(component oscalTypes.DefinedComponent) ([]Framework, error) {
 	if component.ControlImplementations == nil {
 		return nil, nil
 	}
@@ -81,7 +112,7 @@ func processComponent(appDir ApplicationDirectory, component oscalTypes.DefinedC
 		}
 
 		// Load profile of local and get more information for the description
-		profile, err := LoadProfile(appDir, implementation.Source, validator)
+		profile, err := l.profile(implementation.Source)
 		if err != nil {
 			return nil, fmt.Errorf("error loading control source %s for component %s: %w", frameworkShortName, component.Title, err)
 		}
@@ -96,39 +127,54 @@ func processComponent(appDir ApplicationDirectory, component oscalTypes.DefinedC
 	return frameworks, nil
 }
 
-// LoadProfile returns an OSCAL profiles from a given application directory and a found profile source.
-func LoadProfile(appDir ApplicationDirectory, controlSource string, validator validation.Validator) (*oscalTypes.Profile, error) {
-	sourceFile, err := findControlSource(appDir, controlSource)
+// errControlSourceLocation is returned when a relative control source is not below the controls directory.
+var errControlSourceLocation = errors.New("control source is expected to be under path")
+
+// LoadProfile returns an OSCAL profiles from a given content path and a found profile source.
+func LoadProfile(contentPath ContentPath, controlSource string, validator validation.Validator) (*oscalTypes.Profile, error) {
+	sourceFile, err := findControlSource(contentPath, controlSource)
 	if err != nil {
 		return nil, err
 	}
-	defer sourceFile.Close()
 	return models.NewProfile(sourceFile, validator)
 }
 
-// findControlSource returns the correct control source file from the given control source or imported source.
-func findControlSource(appDir ApplicationDirectory, controlSource string) (io.ReadCloser, error) {
-	uri, err := url.ParseRequestURI(controlSource)
+// findControlSource returns the content of the correct control source file from the given control source or
+// imported source, converted to the OSCAL JSON format.
+func findControlSource(contentPath ContentPath, controlSource string) (io.Reader, error) {
+	path, err := controlSourcePath(contentPath, controlSource)
 	if err != nil {
 		return nil, err
 	}
+	data, err := format.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+// controlSourcePath returns the location of the correct control source file from the given control source or
+// imported source.
+func controlSourcePath(contentPath ContentPath, controlSource string) (string, error) {
+	uri, err := url.ParseRequestURI(controlSource)
+	if err != nil {
+		return "", err
+	}
 
 	path := uri.Host + uri.Path
-	appDirPath := appDir.AppDir()
 
 	// Handle the two supported cases:
 	// An absolute path or
-	// A path relative to the root of the complytime application directory
+	// A path relative to the root of a complytime content root, found in the
+	// content root with the highest precedence
 	if !filepath.IsAbs(path) {
 		if !strings.HasPrefix(path, ControlsDir+string(os.PathSeparator)) {
-			return nil, fmt.Errorf("got path %s, control source is expected to be under path %s", path, appDir.ControlDir())
+			return "", fmt.Errorf("got path %s, %w %s", path, errControlSourceLocation, strings.Join(contentPath.ControlDirs(), ", "))
+		}
+		path, err = contentPath.resolveControlSource(path)
+		if err != nil {
+			return "", err
 		}
-		path = filepath.Join(appDirPath, path)
-	}
-	cleanedPath := filepath.Clean(path)
-	sourceFile, err := os.Open(cleanedPath)
-	if err != nil {
-		return nil, err
 	}
-	return sourceFile, nil
+	return filepath.Clean(path), nil
 }
@@ -12,32 +12,42 @@ import (
 func TestLoadFrameworks(t *testing.T) {
 	tests := []struct {
 		name           string
-		appDir         func() ApplicationDirectory
+		contentPath    func() ContentPath
 		wantFrameworks []Framework
 		wantErr        error
 	}{
 		{
 			name: "Valid/HappyPath",
-			appDir: func() ApplicationDirectory {
-				appDir, err := newApplicationDirectory("testdata", false)
-				require.NoError(t, err)
-				return appDir
+			contentPath: func() ContentPath {
+				return testContentPath(t, "testdata")
+			},
+			wantFrameworks: []Framework{
+				{
+					Title:               "Example Profile (low)",
+					ID:                  "example",
+					SupportedComponents: []string{"My Software"},
+					Roots:               []string{"testdata/complytime"},
+				},
+			},
+		},
+		{
+			name: "Valid/YAMLAndXML",
+			contentPath: func() ContentPath {
+				return testContentPath(t, "testdata/formats")
 			},
 			wantFrameworks: []Framework{
 				{
 					Title:               "Example Profile (low)",
 					ID:                  "example",
 					SupportedComponents: []string{"My Software"},
+					Roots:               []string{"testdata/formats/complytime"},
 				},
 			},
 		},
 		{
 			name: "Invalid/NoComponentDefinitions",
-			appDir: func() ApplicationDirectory {
-				tmpDir := t.TempDir()
-				appDir, err := newApplicationDirectory(tmpDir, true)
-				require.NoError(t, err)
-				return appDir
+			contentPath: func() ContentPath {
+				return testContentPath(t, t.TempDir())
 			},
 			wantErr: ErrNoComponentDefinitionsFound,
 		},
@@ -45,8 +55,7 @@ func TestLoadFrameworks(t *testing.T) {
 
 	for _, c := range tests {
 		t.Run(c.name, func(t *testing.T) {
-			appDir := c.appDir()
-			gotFrameworks, err := LoadFrameworks(appDir, validation.NoopValidator{})
+			gotFrameworks, err := LoadFrameworks(c.contentPath(), validation.NoopValidator{})
 			if c.wantErr != nil {
 				require.ErrorIs(t, err, c.wantErr)
 			} else {
@@ -59,27 +68,23 @@ func TestLoadFrameworks(t *testing.T) {
 
 func TestLoadProfile(t *testing.T) {
 	tests := []struct {
-		name    string
-		appDir  func() ApplicationDirectory
-		source  string
-		wantErr string
+		name        string
+		contentPath func() ContentPath
+		source      string
+		wantErr     string
 	}{
 		{
 			name: "Valid Profile Load",
-			appDir: func() ApplicationDirectory {
-				appDir, err := newApplicationDirectory("testdata", false)
-				require.NoError(t, err)
-				return appDir
+			contentPath: func() ContentPath {
+				return testContentPath(t, "testdata")
 			},
 			source:  "file://controls/sample-profile.json",
 			wantErr: "",
 		},
 		{
 			name: "File Does Not Exist",
-			appDir: func() ApplicationDirectory {
-				appDir, err := newApplicationDirectory("testdata", false)
-				require.NoError(t, err)
-				return appDir
+			contentPath: func() ContentPath {
+				return testContentPath(t, "testdata")
 			},
 			source:  "file://nonexistent/path/profile.json",
 			wantErr: "got path nonexistent/path/profile.json, control source is expected to be under path testdata/complytime/controls",
@@ -88,7 +93,7 @@ func TestLoadProfile(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := LoadProfile(tt.appDir(), tt.source, validation.NoopValidator{})
+			_, err := LoadProfile(tt.contentPath(), tt.source, validation.NoopValidator{})
 			if tt.wantErr != "" {
 				require.Contains(t, err.Error(), tt.wantErr)
 			} else {
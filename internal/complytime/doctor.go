@@ -0,0 +1,332 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
+	"github.com/oscal-compass/oscal-sdk-go/models/components"
+	"github.com/oscal-compass/oscal-sdk-go/settings"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+
+	"github.com/complytime/complyctl/internal/complytime/format"
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+const (
+	// pluginCheckTimeout limits the time a plugin can take to report its dependency checks.
+	pluginCheckTimeout = 30 * time.Second
+)
+
+// CheckApplicationDirectory checks that all directories of the ApplicationDirectory exist.
+func CheckApplicationDirectory(appDir ApplicationDirectory) []doctor.Check {
+	var checks []doctor.Check
+	checked := make(map[string]bool)
+	for _, dir := range appDir.Dirs() {
+		// The plugin and plugin manifest directories are the same in development mode.
+		if checked[dir] {
+			continue
+		}
+		checked[dir] = true
+		info, err := os.Stat(dir)
+		switch {
+		case err != nil:
+			checks = append(checks, doctor.Failed(dir, err.Error(),
+				"Reinstall complyctl, or create the directory when using COMPLYTIME_DEV_MODE"))
+		case !info.IsDir():
+			checks = append(checks, doctor.Failed(dir, "not a directory",
+				"Remove the file and reinstall complyctl"))
+		default:
+			checks = append(checks, doctor.Passed(dir, "exists"))
+		}
+	}
+	return checks
+}
+
+// CheckContentPath checks that the content roots configured with COMPLYCTL_CONTENT_PATH or
+// --content-dir exist. The system and user content roots are optional.
+func CheckContentPath(contentPath ContentPath) []doctor.Check {
+	var checks []doctor.Check
+	for _, root := range contentPath {
+		info, err := os.Stat(root.AppDir())
+		switch {
+		case err == nil && info.IsDir():
+			checks = append(checks, doctor.Passed(root.AppDir(), root.Origin+" content root"))
+		case err == nil:
+			checks = append(checks, doctor.Failed(root.AppDir(), "not a directory",
+				fmt.Sprintf("Remove the %s content root or replace the file with a directory", root.Origin)))
+		case root.Origin == OriginEnv || root.Origin == OriginFlag:
+			checks = append(checks, doctor.Warned(root.AppDir(), err.Error(),
+				fmt.Sprintf("Create the directory or remove it from %s", root.Origin)))
+		default:
+			checks = append(checks, doctor.Passed(root.AppDir(), root.Origin+" content root, not present"))
+		}
+	}
+	return checks
+}
+
+// CheckContent checks every component definition in the content path and the profiles and
+// catalogs referenced by their control implementations. The titles of all validation components
+// are returned to be matched against plugin manifests.
+func CheckContent(contentPath ContentPath, validator validation.Validator) ([]doctor.Check, []string) {
+	bundleFix := fmt.Sprintf("Install component definitions named *-%s with a %s extension in %s",
+		CompDefSuffix, strings.Join(format.Extensions, ", "), contentPath[len(contentPath)-1].BundleDir())
+	files, err := contentPath.ComponentDefinitionFiles()
+	if err != nil {
+		return []doctor.Check{doctor.Failed("component definitions", err.Error(), bundleFix)}, nil
+	}
+
+	var (
+		checks               []doctor.Check
+		validationComponents []string
+	)
+	checkedSources := make(map[string]bool)
+	for _, file := range files {
+		definition, err := loadComponentDefinition(file.Path, validator)
+		if err != nil {
+			checks = append(checks, doctor.Failed(file.Path, err.Error(),
+				"Replace the file with a valid OSCAL component definition"))
+			continue
+		}
+		if definition.Components == nil || len(*definition.Components) == 0 {
+			checks = append(checks, doctor.Warned(file.Path, "no components defined",
+				"Remove the file or add components to the component definition"))
+			continue
+		}
+		checks = append(checks, doctor.Passed(file.Path, fmt.Sprintf("%d components", len(*definition.Components))))
+
+		for _, component := range *definition.Components {
+			if components.ComponentType(component.Type) == components.Validation {
+				validationComponents = append(validationComponents, component.Title)
+			}
+			if component.ControlImplementations == nil {
+				continue
+			}
+			for _, implementation := range *component.ControlImplementations {
+				if _, found := settings.GetFrameworkShortName(implementation); !found {
+					checks = append(checks, doctor.Failed(
+						fmt.Sprintf("%s control implementation %q", component.Title, implementation.Description),
+						"no framework short name found",
+						"Add the framework short name property to the control implementation"))
+				}
+				if checkedSources[implementation.Source] {
+					continue
+				}
+				checkedSources[implementation.Source] = true
+				checks = append(checks, checkControlSource(contentPath, implementation.Source, validator, checkedSources)...)
+			}
+		}
+	}
+	if len(files) == 0 {
+		checks = append(checks, doctor.Failed("component definitions",
+			fmt.Sprintf("directories %s: %s", strings.Join(contentPath.BundleDirs(), ", "), ErrNoComponentDefinitionsFound), bundleFix))
+	}
+	return checks, validationComponents
+}
+
+// checkControlSource checks that a profile and the catalogs it imports can be loaded.
+func checkControlSource(contentPath ContentPath, profileSource string, validator validation.Validator, checkedSources map[string]bool) []doctor.Check {
+	profile, err := LoadProfile(contentPath, profileSource, validator)
+	if err != nil {
+		return []doctor.Check{doctor.Failed("profile "+profileSource, err.Error(), controlSourceFix(contentPath, err))}
+	}
+	checks := []doctor.Check{doctor.Passed("profile "+profileSource, profile.Metadata.Title)}
+	for _, imp := range profile.Imports {
+		if checkedSources[imp.Href] {
+			continue
+		}
+		checkedSources[imp.Href] = true
+		catalog, err := LoadCatalogSource(contentPath, imp.Href, validator)
+		if err != nil {
+			checks = append(checks, doctor.Failed("catalog "+imp.Href, err.Error(), controlSourceFix(contentPath, err)))
+			continue
+		}
+		checks = append(checks, doctor.Passed("catalog "+imp.Href, catalog.Metadata.Title))
+	}
+	return checks
+}
+
+// controlSourceFix returns the suggested fix for an error loading a control source.
+func controlSourceFix(contentPath ContentPath, err error) string {
+	switch {
+	case errors.Is(err, errControlSourceLocation), errors.Is(err, os.ErrNotExist):
+		return fmt.Sprintf("Place the file in %s and reference it as file://%s/<file name>", contentPath[len(contentPath)-1].ControlDir(), ControlsDir)
+	default:
+		return "Replace the file with valid OSCAL content"
+	}
+}
+
+// CheckPlugins checks every plugin manifest in the content path against its executable and checksum,
+// runs the dependency checks reported by each plugin, and checks that a plugin exists for each
+// validation component.
+func CheckPlugins(ctx context.Context, contentPath ContentPath, validationComponents []string, selections PluginOptions, logger hclog.Logger) []doctor.Check {
+	manifestFix := fmt.Sprintf("Install plugins with their manifests in %s", contentPath[len(contentPath)-1].PluginManifestDir())
+	files, err := contentPath.PluginManifestFiles()
+	if err != nil {
+		return []doctor.Check{doctor.Failed("plugin manifests", err.Error(), manifestFix)}
+	}
+
+	if selections.UserConfigRoot == "" {
+		if _, err := os.Stat(DefaultPluginConfigDir); err == nil {
+			selections.UserConfigRoot = DefaultPluginConfigDir
+		}
+	}
+
+	var checks []doctor.Check
+	pluginIDs := make(map[string]bool)
+	for _, file := range files {
+		pluginID := strings.TrimSuffix(strings.TrimPrefix(file.Name, PluginManifestPrefix), PluginManifestSuffix)
+		pluginIDs[pluginID] = true
+		manifestChecks, manifest, ok := checkPluginManifest(file.Root.ApplicationDirectory, file.Path, pluginID)
+		checks = append(checks, manifestChecks...)
+		if !ok {
+			continue
+		}
+		checks = append(checks, checkPluginDependencies(ctx, manifest, selections, logger)...)
+	}
+	if len(pluginIDs) == 0 {
+		checks = append(checks, doctor.Failed("plugin manifests", "no plugin manifests found",
+			fmt.Sprintf("Install plugins with manifests named %s<plugin id>%s in %s",
+				PluginManifestPrefix, PluginManifestSuffix, contentPath[len(contentPath)-1].PluginManifestDir())))
+	}
+
+	installed := make([]string, 0, len(pluginIDs))
+	for pluginID := range pluginIDs {
+		installed = append(installed, pluginID)
+	}
+	sort.Strings(installed)
+	for _, title := range validationComponents {
+		name := fmt.Sprintf("validation component %q", title)
+		if pluginIDs[title] {
+			checks = append(checks, doctor.Passed(name, "matches plugin "+title))
+			continue
+		}
+		checks = append(checks, doctor.Failed(name, "no plugin manifest with a matching id",
+			fmt.Sprintf("Install the %q plugin or set the component title to an installed plugin id (%s)",
+				title, strings.Join(installed, ", "))))
+	}
+	return checks
+}
+
+// checkPluginManifest checks that a plugin manifest can be read, has the expected plugin id, and that its
+// executable exists with the expected checksum. The manifest is returned with a resolved executable path.
+func checkPluginManifest(appDir ApplicationDirectory, manifestPath string, pluginID string) ([]doctor.Check, plugin.Manifest, bool) {
+	name := "plugin " + pluginID
+	manifestFile, err := os.Open(filepath.Clean(manifestPath))
+	if err != nil {
+		return []doctor.Check{doctor.Failed(name, err.Error(), "Reinstall the plugin")}, plugin.Manifest{}, false
+	}
+	defer manifestFile.Close()
+	var manifest plugin.Manifest
+	if err := json.NewDecoder(manifestFile).Decode(&manifest); err != nil {
+		return []doctor.Check{doctor.Failed(name, fmt.Sprintf("invalid manifest %s: %v", manifestPath, err),
+			"Reinstall the plugin")}, plugin.Manifest{}, false
+	}
+	if manifest.ID.String() != pluginID {
+		fix := fmt.Sprintf("Set metadata.id to %q in %s", pluginID, manifestPath)
+		if manifest.ID != "" {
+			fix += fmt.Sprintf(" or rename the manifest to %s%s%s", PluginManifestPrefix, manifest.ID, PluginManifestSuffix)
+		}
+		return []doctor.Check{doctor.Failed(name, fmt.Sprintf("manifest id %q does not match manifest file name", manifest.ID), fix)},
+			plugin.Manifest{}, false
+	}
+	if !manifest.ID.Validate() {
+		return []doctor.Check{doctor.Failed(name, fmt.Sprintf("invalid plugin id %q", manifest.ID),
+			"Use a plugin id of lowercase letters, digits, and dashes")}, plugin.Manifest{}, false
+	}
+	if err := manifest.ResolvePath(appDir.PluginDir()); err != nil {
+		return []doctor.Check{doctor.Failed(name, err.Error(),
+				fmt.Sprintf("Install the %q executable in %s and make it executable", manifest.ExecutablePath, appDir.PluginDir()))},
+			plugin.Manifest{}, false
+	}
+	checksum, err := fileChecksum(manifest.ExecutablePath)
+	if err != nil {
+		return []doctor.Check{doctor.Failed(name, err.Error(), "Reinstall the plugin")}, plugin.Manifest{}, false
+	}
+	if !strings.EqualFold(checksum, manifest.Checksum) {
+		return []doctor.Check{doctor.Failed(name, fmt.Sprintf("checksum of %s does not match the manifest", manifest.ExecutablePath),
+				fmt.Sprintf("Reinstall the plugin, or set sha256 to %s in %s if the executable is trusted", checksum, manifestPath))},
+			plugin.Manifest{}, false
+	}
+	return []doctor.Check{doctor.Passed(name, fmt.Sprintf("version %s at %s", manifest.Version, manifest.ExecutablePath))}, manifest, true
+}
+
+// checkPluginDependencies runs the plugin in diagnostic mode with its configuration selections.
+func checkPluginDependencies(ctx context.Context, manifest plugin.Manifest, selections PluginOptions, logger hclog.Logger) []doctor.Check {
+	pluginID := manifest.ID.String()
+	name := fmt.Sprintf("plugin %s configuration", pluginID)
+	selectionsMap, err := selections.ToMap(pluginID, logger)
+	if err != nil {
+		return []doctor.Check{doctor.Failed(name, err.Error(),
+			fmt.Sprintf("Fix the plugin drop-in configuration in %s", selections.UserConfigRoot))}
+	}
+	resolved, err := manifest.ResolveOptions(selectionsMap)
+	if err != nil {
+		return []doctor.Check{doctor.Failed(name, err.Error(),
+			"Set a default value for the option in the plugin drop-in configuration")}
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, pluginCheckTimeout)
+	defer cancel()
+	pluginChecks, err := doctor.RunPluginChecks(checkCtx, manifest.ExecutablePath, resolved)
+	if err != nil {
+		logger.Debug(fmt.Sprintf("Plugin %s diagnostics: %v", pluginID, err))
+		return []doctor.Check{doctor.Warned(fmt.Sprintf("plugin %s dependencies", pluginID),
+			"the plugin does not report dependency checks",
+			"Run a scan with --debug to see errors reported by the plugin")}
+	}
+	for i := range pluginChecks {
+		pluginChecks[i].Name = fmt.Sprintf("plugin %s: %s", pluginID, pluginChecks[i].Name)
+	}
+	return pluginChecks
+}
+
+// CheckWorkspace checks that the workspace directory can be written to.
+func CheckWorkspace(workspace string) []doctor.Check {
+	fix := "Fix the directory permissions or use another directory with --workspace"
+	info, err := os.Stat(workspace)
+	if errors.Is(err, os.ErrNotExist) {
+		return []doctor.Check{doctor.Warned(workspace, "does not exist",
+			fmt.Sprintf("Create the workspace with \"mkdir -p %s\"", workspace))}
+	}
+	if err != nil {
+		return []doctor.Check{doctor.Failed(workspace, err.Error(), fix)}
+	}
+	if !info.IsDir() {
+		return []doctor.Check{doctor.Failed(workspace, "not a directory", fix)}
+	}
+	probe, err := os.CreateTemp(workspace, ".complyctl-doctor-*")
+	if err != nil {
+		return []doctor.Check{doctor.Failed(workspace, "not writable: "+err.Error(), fix)}
+	}
+	_ = probe.Close()
+	_ = os.Remove(probe.Name())
+	return []doctor.Check{doctor.Passed(workspace, "writable")}
+}
+
+// fileChecksum returns the hex encoded SHA256 checksum of a file.
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+func TestCheckApplicationDirectory(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir, err := newApplicationDirectory(tmpDir, true)
+	require.NoError(t, err)
+	checks := CheckApplicationDirectory(appDir)
+	require.Len(t, checks, 4)
+	require.Equal(t, doctor.Pass, checks[0].Status)
+
+	require.NoError(t, os.RemoveAll(appDir.ControlDir()))
+	checks = CheckApplicationDirectory(appDir)
+	require.Equal(t, doctor.Fail, checks[3].Status)
+}
+
+func TestCheckContentPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentPath := testContentPath(t, "testdata", filepath.Join(tmpDir, "missing"), filepath.Join(tmpDir, "file"))
+	contentPath[0].Origin = OriginSystem
+	require.NoError(t, os.MkdirAll(filepath.Dir(contentPath[2].AppDir()), 0700))
+	require.NoError(t, os.WriteFile(contentPath[2].AppDir(), []byte{}, 0600))
+	contentPath = append(contentPath, ContentRoot{ApplicationDirectory: contentPath[1].ApplicationDirectory, Origin: OriginUser})
+
+	checks := CheckContentPath(contentPath)
+	require.Len(t, checks, 4)
+	require.Equal(t, doctor.Passed("testdata/complytime", "system content root"), checks[0])
+	require.Equal(t, doctor.Warn, checks[1].Status)
+	require.Equal(t, doctor.Fail, checks[2].Status)
+	require.Equal(t, doctor.Passed(contentPath[3].AppDir(), "user content root, not present"), checks[3])
+}
+
+func TestCheckContent(t *testing.T) {
+	checks, validationComponents := CheckContent(testContentPath(t, "testdata"), validation.NoopValidator{})
+	require.Equal(t, []string{"myplugin"}, validationComponents)
+	require.Equal(t, []doctor.Check{
+		doctor.Passed("testdata/complytime/bundles/example-component-definition.json", "2 components"),
+		doctor.Passed("profile file://controls/sample-profile.json", "Example Profile (low)"),
+		doctor.Passed("catalog file://controls/sample-catalog.json", "Catalog for anssi"),
+	}, checks)
+
+	checks, _ = CheckContent(testContentPath(t, t.TempDir()), validation.NoopValidator{})
+	require.Len(t, checks, 1)
+	require.Equal(t, doctor.Fail, checks[0].Status)
+	require.Contains(t, checks[0].Message, ErrNoComponentDefinitionsFound.Error())
+}
+
+func TestCheckPlugins(t *testing.T) {
+	appDir, err := newApplicationDirectory(t.TempDir(), true)
+	require.NoError(t, err)
+
+	script := []byte("#!/bin/sh\ncat > /dev/null\necho '[{\"name\":\"dependency\",\"status\":\"pass\"}]'\n")
+	executable := filepath.Join(appDir.PluginDir(), "myplugin")
+	require.NoError(t, os.WriteFile(executable, script, 0700))
+	checksum := sha256.Sum256(script)
+	writeManifest := func(fileID, id, sum string) {
+		manifest := fmt.Sprintf(`{"metadata":{"id":%q,"version":"0.0.1","types":["pvp"]},"executablePath":"myplugin","sha256":%q}`, id, sum)
+		path := filepath.Join(appDir.PluginManifestDir(), "c2p-"+fileID+"-manifest.json")
+		require.NoError(t, os.WriteFile(path, []byte(manifest), 0600))
+	}
+	writeManifest("myplugin", "myplugin", hex.EncodeToString(checksum[:]))
+	writeManifest("renamed", "other", hex.EncodeToString(checksum[:]))
+	writeManifest("tampered", "tampered", "0000")
+
+	selections := PluginOptions{Workspace: "workspace", Profile: "profile", UserConfigRoot: t.TempDir()}
+	contentPath := ContentPath{{ApplicationDirectory: appDir, Origin: OriginFlag}}
+	checks := CheckPlugins(context.Background(), contentPath, []string{"myplugin", "missing"}, selections, hclog.NewNullLogger())
+
+	byName := make(map[string]doctor.Check)
+	for _, check := range checks {
+		byName[check.Name] = check
+	}
+	require.Len(t, byName, 6)
+	require.Equal(t, doctor.Pass, byName["plugin myplugin"].Status)
+	require.Equal(t, doctor.Pass, byName["plugin myplugin: dependency"].Status)
+	require.Equal(t, doctor.Fail, byName["plugin renamed"].Status)
+	require.Contains(t, byName["plugin renamed"].Fix, "c2p-other-manifest.json")
+	require.Equal(t, doctor.Fail, byName["plugin tampered"].Status)
+	require.Contains(t, byName["plugin tampered"].Message, "checksum")
+	require.Equal(t, doctor.Pass, byName[`validation component "myplugin"`].Status)
+	require.Equal(t, doctor.Fail, byName[`validation component "missing"`].Status)
+}
+
+func TestCheckWorkspace(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.Equal(t, []doctor.Check{doctor.Passed(tmpDir, "writable")}, CheckWorkspace(tmpDir))
+
+	missing := filepath.Join(tmpDir, "missing")
+	require.Equal(t, doctor.Warn, CheckWorkspace(missing)[0].Status)
+
+	file := filepath.Join(tmpDir, "file")
+	require.NoError(t, os.WriteFile(file, nil, 0600))
+	require.Equal(t, doctor.Fail, CheckWorkspace(file)[0].Status)
+}
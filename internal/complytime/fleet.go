@@ -0,0 +1,391 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/defenseunicorns/go-oscal/src/pkg/uuid"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/models"
+)
+
+// Groups of the fleet summaries of merged assessment results.
+const (
+	// GroupByHost counts the rules of each host per result.
+	GroupByHost = "host"
+	// GroupByControl counts the hosts per result of each control.
+	GroupByControl = "control"
+	// GroupByRule counts the hosts per result of each rule.
+	GroupByRule = "rule"
+)
+
+// GroupByValues lists all groups of fleet summaries.
+var GroupByValues = []string{GroupByHost, GroupByControl, GroupByRule}
+
+// hostProps are the subject properties naming the host of a subject, in order of preference.
+var hostProps = []string{"hostname", "fqdn", "resource-id"}
+
+// inventoryItemType is the subject type of the hosts in assessment results.
+const inventoryItemType = "inventory-item"
+
+// SubjectHost returns the host of an observation subject, named by its properties or else its title.
+func SubjectHost(subject oscalTypes.SubjectReference) string {
+	if subject.Props != nil {
+		for _, name := range hostProps {
+			if prop, found := extensions.GetTrestleProp(name, *subject.Props); found && prop.Value != "" {
+				return prop.Value
+			}
+		}
+	}
+	return subject.Title
+}
+
+// HostRuleResults returns the worst normalized result of each rule on each host observed in the
+// assessment results, indexed by host and rule ID. Failures of observations related to an accepted
+// risk are waived.
+func HostRuleResults(assessmentResults *oscalTypes.AssessmentResults) map[string]map[string]string {
+	hostResults := make(map[string]map[string]string)
+	forEachSubjectResult(assessmentResults, func(ruleID string, subject oscalTypes.SubjectReference, subjectResult string) {
+		host := SubjectHost(subject)
+		if hostResults[host] == nil {
+			hostResults[host] = make(map[string]string)
+		}
+		current, seen := hostResults[host][ruleID]
+		if !seen || resultSeverity[subjectResult] > resultSeverity[current] {
+			hostResults[host][ruleID] = subjectResult
+		}
+	})
+	return hostResults
+}
+
+// FleetSummary returns the counts per normalized result of each host, control, or rule of the
+// assessment plan in the assessment results of a fleet. Hosts are summarized by the number of
+// rules per result, while controls and rules are summarized by the number of hosts per result.
+// A host has the worst result of the rules of a control, and in-scope rules without observations
+// on a host are counted as skipped.
+func FleetSummary(assessmentPlan *oscalTypes.AssessmentPlan, assessmentResults *oscalTypes.AssessmentResults, groupBy string) (map[string]map[string]int, error) {
+	hostResults := HostRuleResults(assessmentResults)
+	controlsByRule := ControlsByRule(assessmentPlan)
+	resultOn := func(host, ruleID string) string {
+		if result, found := hostResults[host][ruleID]; found {
+			return result
+		}
+		return ResultSkipped
+	}
+
+	counts := make(map[string]map[string]int)
+	count := func(key, result string) {
+		if counts[key] == nil {
+			counts[key] = make(map[string]int)
+		}
+		counts[key][result]++
+	}
+	switch groupBy {
+	case GroupByHost:
+		for host := range hostResults {
+			for ruleID := range controlsByRule {
+				count(host, resultOn(host, ruleID))
+			}
+		}
+	case GroupByRule:
+		for ruleID := range controlsByRule {
+			for host := range hostResults {
+				count(ruleID, resultOn(host, ruleID))
+			}
+		}
+	case GroupByControl:
+		for host := range hostResults {
+			controlResults := make(map[string]string)
+			for ruleID, controls := range controlsByRule {
+				result := resultOn(host, ruleID)
+				for _, controlID := range controls {
+					current, seen := controlResults[controlID]
+					if !seen || resultSeverity[result] > resultSeverity[current] {
+						controlResults[controlID] = result
+					}
+				}
+			}
+			for controlID, result := range controlResults {
+				count(controlID, result)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("invalid group %q, expected one of %v", groupBy, GroupByValues)
+	}
+	return counts, nil
+}
+
+// MergeAssessmentResults merges the assessment results of the hosts of a fleet into assessment
+// results with a single result. Observations, risks, and findings of all results are combined, and
+// the subjects of each host refer to a single inventory item. The findings roll up the number of
+// hosts per result of each control of the assessment plan.
+func MergeAssessmentResults(assessmentPlan *oscalTypes.AssessmentPlan, inputs []*oscalTypes.AssessmentResults) (*oscalTypes.AssessmentResults, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("no assessment results to merge")
+	}
+	metadata := models.NewSampleMetadata()
+	metadata.Title = "Fleet Assessment Results"
+
+	merger := newResultsMerger()
+	for _, input := range inputs {
+		for _, result := range input.Results {
+			merger.add(result)
+		}
+		merger.addBackMatter(input.BackMatter)
+	}
+	merged := &oscalTypes.AssessmentResults{
+		UUID:       uuid.NewUUID(),
+		ImportAp:   inputs[0].ImportAp,
+		Metadata:   metadata,
+		Results:    []oscalTypes.Result{merger.result(len(inputs))},
+		BackMatter: merger.backMatter(),
+	}
+
+	controlCounts, err := FleetSummary(assessmentPlan, merged, GroupByControl)
+	if err != nil {
+		return nil, err
+	}
+	merger.rollUp(&merged.Results[0], controlCounts)
+	return merged, nil
+}
+
+// resultsMerger combines the results of several assessment results.
+type resultsMerger struct {
+	start            time.Time
+	end              *time.Time
+	reviewedControls oscalTypes.ReviewedControls
+	observations     []oscalTypes.Observation
+	risks            []oscalTypes.Risk
+	findings         map[string]*oscalTypes.Finding
+	inventoryItems   []oscalTypes.InventoryItem
+	resources        []oscalTypes.Resource
+	// hostItems are the UUIDs of the inventory items of each host
+	hostItems map[string]string
+	seen      map[string]bool
+}
+
+func newResultsMerger() *resultsMerger {
+	return &resultsMerger{
+		findings:  make(map[string]*oscalTypes.Finding),
+		hostItems: make(map[string]string),
+		seen:      make(map[string]bool),
+	}
+}
+
+// add merges a result. Observations and risks are added once, so the same results can be merged again.
+func (m *resultsMerger) add(result oscalTypes.Result) {
+	if m.start.IsZero() || result.Start.Before(m.start) {
+		m.start = result.Start
+	}
+	if result.End != nil && (m.end == nil || result.End.After(*m.end)) {
+		m.end = result.End
+	}
+	for _, selection := range result.ReviewedControls.ControlSelections {
+		if !containsEqual(m.reviewedControls.ControlSelections, selection) {
+			m.reviewedControls.ControlSelections = append(m.reviewedControls.ControlSelections, selection)
+		}
+	}
+
+	// Subjects refer to the inventory item of their host in the merged result
+	items := make(map[string]oscalTypes.InventoryItem)
+	if result.LocalDefinitions != nil && result.LocalDefinitions.InventoryItems != nil {
+		for _, item := range *result.LocalDefinitions.InventoryItems {
+			items[item.UUID] = item
+		}
+	}
+	if result.Observations != nil {
+		for _, observation := range *result.Observations {
+			if m.seen[observation.UUID] {
+				continue
+			}
+			m.seen[observation.UUID] = true
+			if observation.Subjects != nil {
+				subjects := make([]oscalTypes.SubjectReference, len(*observation.Subjects))
+				copy(subjects, *observation.Subjects)
+				for i := range subjects {
+					if subjects[i].Type != inventoryItemType {
+						continue
+					}
+					subjects[i].SubjectUuid = m.hostItem(SubjectHost(subjects[i]), subjects[i], items)
+				}
+				observation.Subjects = &subjects
+			}
+			m.observations = append(m.observations, observation)
+		}
+	}
+	if result.Risks != nil {
+		for _, risk := range *result.Risks {
+			if !m.seen[risk.UUID] {
+				m.seen[risk.UUID] = true
+				m.risks = append(m.risks, risk)
+			}
+		}
+	}
+	if result.Findings != nil {
+		for _, finding := range *result.Findings {
+			m.addFinding(finding)
+		}
+	}
+}
+
+// hostItem returns the UUID of the inventory item of the host, adding the inventory item of the
+// subject when the host has none yet.
+func (m *resultsMerger) hostItem(host string, subject oscalTypes.SubjectReference, items map[string]oscalTypes.InventoryItem) string {
+	if itemUUID, found := m.hostItems[host]; found {
+		return itemUUID
+	}
+	item, found := items[subject.SubjectUuid]
+	if !found {
+		item = oscalTypes.InventoryItem{UUID: subject.SubjectUuid, Description: subject.Title}
+	}
+	m.hostItems[host] = item.UUID
+	m.inventoryItems = append(m.inventoryItems, item)
+	return item.UUID
+}
+
+// addFinding combines the finding with the finding of the same target.
+func (m *resultsMerger) addFinding(finding oscalTypes.Finding) {
+	existing, found := m.findings[finding.Target.TargetId]
+	if !found {
+		m.findings[finding.Target.TargetId] = &finding
+		return
+	}
+	if finding.RelatedObservations != nil {
+		if existing.RelatedObservations == nil {
+			existing.RelatedObservations = &[]oscalTypes.RelatedObservation{}
+		}
+		for _, relatedObservation := range *finding.RelatedObservations {
+			if !containsEqual(*existing.RelatedObservations, relatedObservation) {
+				*existing.RelatedObservations = append(*existing.RelatedObservations, relatedObservation)
+			}
+		}
+	}
+	if finding.RelatedRisks != nil {
+		if existing.RelatedRisks == nil {
+			existing.RelatedRisks = &[]oscalTypes.AssociatedRisk{}
+		}
+		for _, relatedRisk := range *finding.RelatedRisks {
+			if !containsEqual(*existing.RelatedRisks, relatedRisk) {
+				*existing.RelatedRisks = append(*existing.RelatedRisks, relatedRisk)
+			}
+		}
+	}
+}
+
+func (m *resultsMerger) addBackMatter(backMatter *oscalTypes.BackMatter) {
+	if backMatter == nil || backMatter.Resources == nil {
+		return
+	}
+	for _, resource := range *backMatter.Resources {
+		if !m.seen[resource.UUID] {
+			m.seen[resource.UUID] = true
+			m.resources = append(m.resources, resource)
+		}
+	}
+}
+
+// result returns the merged result of the given number of assessment results.
+func (m *resultsMerger) result(inputs int) oscalTypes.Result {
+	result := oscalTypes.Result{
+		UUID:             uuid.NewUUID(),
+		Title:            "Fleet Assessment Result",
+		Description:      fmt.Sprintf("Assessment result merged from %d assessment results of %d hosts.", inputs, len(m.hostItems)),
+		Start:            m.start,
+		End:              m.end,
+		ReviewedControls: m.reviewedControls,
+	}
+	if result.ReviewedControls.ControlSelections == nil {
+		result.ReviewedControls.ControlSelections = []oscalTypes.AssessedControls{}
+	}
+	if len(m.inventoryItems) > 0 {
+		sort.Slice(m.inventoryItems, func(i, j int) bool { return m.inventoryItems[i].Description < m.inventoryItems[j].Description })
+		result.LocalDefinitions = &oscalTypes.LocalDefinitions{InventoryItems: &m.inventoryItems}
+	}
+	if len(m.observations) > 0 {
+		result.Observations = &m.observations
+	}
+	if len(m.risks) > 0 {
+		result.Risks = &m.risks
+	}
+	return result
+}
+
+func (m *resultsMerger) backMatter() *oscalTypes.BackMatter {
+	if len(m.resources) == 0 {
+		return nil
+	}
+	return &oscalTypes.BackMatter{Resources: &m.resources}
+}
+
+// rollUp sets the findings of the merged result, one for each control of the counts with the
+// number of hosts per result. A control is satisfied when it passed on a host and did not fail or
+// error on any host. A control skipped on every host was not assessed, so it gets no finding, and
+// the finding of a host for it keeps its status.
+func (m *resultsMerger) rollUp(result *oscalTypes.Result, controlCounts map[string]map[string]int) {
+	for controlID, counts := range controlCounts {
+		// Findings target the statements of controls, as in the assessment results of a scan
+		targetID := fmt.Sprintf("%s_smt", controlID)
+		hosts := 0
+		for _, status := range ResultStatuses {
+			hosts += counts[status]
+		}
+		skipped := counts[ResultSkipped] == hosts
+		finding, found := m.findings[targetID]
+		if !found && skipped {
+			continue
+		}
+		if !found {
+			finding = &oscalTypes.Finding{
+				UUID:   uuid.NewUUID(),
+				Target: oscalTypes.FindingTarget{TargetId: targetID, Type: "statement-id"},
+			}
+			m.findings[targetID] = finding
+		}
+		props := make([]oscalTypes.Property, 0, len(ResultStatuses))
+		for _, status := range ResultStatuses {
+			props = append(props, oscalTypes.Property{
+				Name:  fmt.Sprintf("hosts-%s", status),
+				Value: strconv.Itoa(counts[status]),
+				Ns:    extensions.TrestleNameSpace,
+			})
+		}
+		finding.Title = fmt.Sprintf("Control %s", controlID)
+		finding.Description = fmt.Sprintf("Control %s passed on %d of %d hosts, failed on %d, and errored on %d.",
+			controlID, counts[ResultPass], hosts, counts[ResultFail], counts[ResultError])
+		finding.Props = &props
+		switch {
+		case skipped:
+		case counts[ResultPass] > 0 && counts[ResultFail] == 0 && counts[ResultError] == 0:
+			finding.Target.Status.State = "satisfied"
+		default:
+			finding.Target.Status.State = "not-satisfied"
+		}
+	}
+
+	if len(m.findings) == 0 {
+		return
+	}
+	findings := make([]oscalTypes.Finding, 0, len(m.findings))
+	for _, finding := range m.findings {
+		findings = append(findings, *finding)
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Target.TargetId < findings[j].Target.TargetId })
+	result.Findings = &findings
+}
+
+// containsEqual returns whether the values contain a value deeply equal to the given value.
+func containsEqual[T any](values []T, value T) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
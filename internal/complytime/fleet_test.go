@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/go-oscal/src/pkg/uuid"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+)
+
+// testUUID returns a UUID derived from the name.
+func testUUID(name string) string {
+	return uuid.NewUUIDWithSource(name)
+}
+
+var fleetPlan = &oscalTypes.AssessmentPlan{
+	LocalDefinitions: &oscalTypes.LocalDefinitions{
+		Activities: &[]oscalTypes.Activity{
+			testActivity("rule-1", "control-1"),
+			testActivity("rule-2", "control-1", "control-2"),
+			testActivity("rule-3", "control-2"),
+		},
+	},
+}
+
+// testHostResults returns assessment results of a scan of the host with the given result of each rule.
+func testHostResults(host string, start time.Time, ruleResults map[string]string) *oscalTypes.AssessmentResults {
+	itemUUID := testUUID("item-" + host)
+	var observations []oscalTypes.Observation
+	var findings []oscalTypes.Finding
+	for _, ruleID := range []string{"rule-1", "rule-2", "rule-3"} {
+		result, found := ruleResults[ruleID]
+		if !found {
+			continue
+		}
+		observation := testObservation(ruleID, result)
+		observation.UUID = testUUID(host + "/" + ruleID)
+		observation.Methods = []string{"TEST"}
+		observation.Collected = start
+		observation.Description = ruleID
+		subject := &(*observation.Subjects)[0]
+		subject.SubjectUuid = itemUUID
+		subject.Title = fmt.Sprintf("Host %s", host)
+		subject.Type = "inventory-item"
+		*subject.Props = append(*subject.Props, oscalTypes.Property{Name: "hostname", Value: host, Ns: extensions.TrestleNameSpace})
+		observations = append(observations, observation)
+		if result == ResultFail {
+			findings = append(findings, oscalTypes.Finding{
+				UUID:                testUUID(host + "/finding/" + ruleID),
+				Title:               ruleID,
+				Description:         ruleID,
+				RelatedObservations: &[]oscalTypes.RelatedObservation{{ObservationUuid: observation.UUID}},
+				Target:              oscalTypes.FindingTarget{TargetId: "control-1_smt", Type: "statement-id", Status: oscalTypes.ObjectiveStatus{State: "not-satisfied"}},
+			})
+		}
+	}
+	result := oscalTypes.Result{
+		UUID:        testUUID("result-" + host),
+		Title:       "Result",
+		Description: "Result",
+		Start:       start,
+		ReviewedControls: oscalTypes.ReviewedControls{
+			ControlSelections: []oscalTypes.AssessedControls{{Description: "controls"}},
+		},
+		LocalDefinitions: &oscalTypes.LocalDefinitions{
+			InventoryItems: &[]oscalTypes.InventoryItem{{UUID: itemUUID, Description: fmt.Sprintf("Host %s", host)}},
+		},
+		Observations: &observations,
+	}
+	if len(findings) > 0 {
+		result.Findings = &findings
+	}
+	return &oscalTypes.AssessmentResults{
+		UUID:     testUUID("ar-" + host),
+		ImportAp: oscalTypes.ImportAp{Href: "file://assessment-plan.json"},
+		Results:  []oscalTypes.Result{result},
+	}
+}
+
+func TestFleetSummary(t *testing.T) {
+	start := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	web1 := testHostResults("web1", start, map[string]string{"rule-1": "pass", "rule-2": "pass", "rule-3": "pass"})
+	web2 := testHostResults("web2", start, map[string]string{"rule-1": "fail", "rule-2": "pass"})
+	web2.Results = append(web2.Results, web1.Results...)
+
+	hostCounts, err := FleetSummary(fleetPlan, web2, GroupByHost)
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string]int{
+		"web1": {ResultPass: 3},
+		"web2": {ResultPass: 1, ResultFail: 1, ResultSkipped: 1},
+	}, hostCounts)
+
+	controlCounts, err := FleetSummary(fleetPlan, web2, GroupByControl)
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string]int{
+		"control-1": {ResultPass: 1, ResultFail: 1},
+		"control-2": {ResultPass: 2},
+	}, controlCounts)
+
+	ruleCounts, err := FleetSummary(fleetPlan, web2, GroupByRule)
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string]int{
+		"rule-1": {ResultPass: 1, ResultFail: 1},
+		"rule-2": {ResultPass: 2},
+		"rule-3": {ResultPass: 1, ResultSkipped: 1},
+	}, ruleCounts)
+
+	_, err = FleetSummary(fleetPlan, web2, "site")
+	require.EqualError(t, err, "invalid group \"site\", expected one of [host control rule]")
+}
+
+func TestMergeAssessmentResults(t *testing.T) {
+	_, err := MergeAssessmentResults(fleetPlan, nil)
+	require.EqualError(t, err, "no assessment results to merge")
+
+	start := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	web1 := testHostResults("web1", start, map[string]string{"rule-1": "fail", "rule-2": "pass", "rule-3": "pass"})
+	web2 := testHostResults("web2", start.Add(-time.Hour), map[string]string{"rule-1": "fail", "rule-2": "pass", "rule-3": "pass"})
+	// A later scan of web1 with new subject and observation UUIDs
+	web1Again := testHostResults("web1", start.Add(time.Hour), map[string]string{"rule-1": "pass"})
+	(*web1Again.Results[0].LocalDefinitions.InventoryItems)[0].UUID = testUUID("item-web1-again")
+	for i := range *web1Again.Results[0].Observations {
+		observation := &(*web1Again.Results[0].Observations)[i]
+		observation.UUID = testUUID(observation.UUID + "-again")
+		(*observation.Subjects)[0].SubjectUuid = testUUID("item-web1-again")
+	}
+
+	merged, err := MergeAssessmentResults(fleetPlan, []*oscalTypes.AssessmentResults{web1, web2, web1Again, web2})
+	require.NoError(t, err)
+	require.NoError(t, validation.NewSchemaValidator().Validate(oscalTypes.OscalModels{AssessmentResults: merged}))
+
+	require.Len(t, merged.Results, 1)
+	result := merged.Results[0]
+	require.Equal(t, start.Add(-time.Hour), result.Start)
+	require.Len(t, result.ReviewedControls.ControlSelections, 1)
+	require.Len(t, *result.Observations, 7)
+	items := *result.LocalDefinitions.InventoryItems
+	require.Len(t, items, 2)
+	require.Equal(t, testUUID("item-web1"), items[0].UUID)
+	require.Equal(t, testUUID("item-web2"), items[1].UUID)
+	for _, observation := range *result.Observations {
+		require.Contains(t, []string{testUUID("item-web1"), testUUID("item-web2")}, (*observation.Subjects)[0].SubjectUuid)
+	}
+
+	findings := *result.Findings
+	require.Len(t, findings, 2)
+	require.Equal(t, "control-1_smt", findings[0].Target.TargetId)
+	require.Equal(t, "not-satisfied", findings[0].Target.Status.State)
+	require.Len(t, *findings[0].RelatedObservations, 2)
+	hostsFail, found := extensions.GetTrestleProp("hosts-fail", *findings[0].Props)
+	require.True(t, found)
+	require.Equal(t, "2", hostsFail.Value)
+	require.Equal(t, "control-2_smt", findings[1].Target.TargetId)
+	require.Equal(t, "satisfied", findings[1].Target.Status.State)
+	hostsPass, found := extensions.GetTrestleProp("hosts-pass", *findings[1].Props)
+	require.True(t, found)
+	require.Equal(t, "2", hostsPass.Value)
+}
+
+func TestMergeAssessmentResultsSkipped(t *testing.T) {
+	skippedPlan := &oscalTypes.AssessmentPlan{
+		LocalDefinitions: &oscalTypes.LocalDefinitions{
+			Activities: &[]oscalTypes.Activity{
+				testActivity("rule-1", "control-1"),
+				testActivity("rule-2", "control-2"),
+				testActivity("rule-4", "control-3"),
+			},
+		},
+	}
+	start := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	web1 := testHostResults("web1", start, map[string]string{"rule-1": "pass", "rule-2": "waived"})
+	web2 := testHostResults("web2", start, map[string]string{"rule-1": "pass", "rule-2": "pass"})
+
+	merged, err := MergeAssessmentResults(skippedPlan, []*oscalTypes.AssessmentResults{web1, web2})
+	require.NoError(t, err)
+	require.NoError(t, validation.NewSchemaValidator().Validate(oscalTypes.OscalModels{AssessmentResults: merged}))
+
+	// No host assessed control-3, so it is neither satisfied nor not satisfied
+	findings := *merged.Results[0].Findings
+	require.Len(t, findings, 2)
+	require.Equal(t, "control-1_smt", findings[0].Target.TargetId)
+	require.Equal(t, "satisfied", findings[0].Target.Status.State)
+	require.Equal(t, "control-2_smt", findings[1].Target.TargetId)
+	require.Equal(t, "not-satisfied", findings[1].Target.Status.State)
+}
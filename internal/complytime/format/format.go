@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package format reads OSCAL content in the JSON, YAML, and XML formats. Content is converted
+// to the OSCAL JSON format, which is the format used by the OSCAL models and validators.
+package format
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Format is a serialization format of OSCAL content.
+type Format string
+
+const (
+	JSON Format = "json"
+	YAML Format = "yaml"
+	XML  Format = "xml"
+)
+
+// Extensions are the file extensions of OSCAL content files.
+var Extensions = []string{".json", ".yaml", ".yml", ".xml"}
+
+// ErrNotOSCAL is returned when XML content is not an OSCAL model.
+var ErrNotOSCAL = errors.New("not OSCAL content")
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// HasExtension returns whether the file name has the extension of an OSCAL content file.
+func HasExtension(name string) bool {
+	return slices.Contains(Extensions, strings.ToLower(filepath.Ext(name)))
+}
+
+// TrimExtension returns the file name without the extension of an OSCAL content file.
+func TrimExtension(name string) string {
+	if !HasExtension(name) {
+		return name
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// Detect returns the format of the content from its first character. XML content starts with
+// an element or declaration, and JSON content with an object or array. Anything else is YAML.
+func Detect(data []byte) Format {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(data, utf8BOM), " \t\r\n")
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return XML
+	case bytes.HasPrefix(trimmed, []byte("{")), bytes.HasPrefix(trimmed, []byte("[")):
+		return JSON
+	default:
+		return YAML
+	}
+}
+
+// ToJSON converts OSCAL content in any supported format to the OSCAL JSON format.
+func ToJSON(data []byte) ([]byte, error) {
+	switch Detect(data) {
+	case XML:
+		return xmlToJSON(data)
+	case YAML:
+		converted, err := yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		return converted, nil
+	default:
+		return bytes.TrimPrefix(data, utf8BOM), nil
+	}
+}
+
+// ReadFile reads the OSCAL content file at the given path and returns it in the OSCAL JSON format.
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	converted, err := ToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	return converted, nil
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Format
+	}{
+		{name: "XML", data: `<?xml version="1.0"?><catalog/>`, want: XML},
+		{name: "XMLWithBOM", data: "\xef\xbb\xbf\n<catalog/>", want: XML},
+		{name: "JSON", data: "\n  {\"catalog\": {}}", want: JSON},
+		{name: "YAML", data: "catalog:\n  uuid: abc\n", want: YAML},
+		{name: "YAMLDocument", data: "---\ncatalog: {}\n", want: YAML},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Detect([]byte(tt.data)))
+		})
+	}
+}
+
+func TestHasExtension(t *testing.T) {
+	require.True(t, HasExtension("anssi-component-definition.yml"))
+	require.True(t, HasExtension("catalog.XML"))
+	require.False(t, HasExtension("openscap-plugin"))
+	require.Equal(t, "anssi-component-definition", TrimExtension("anssi-component-definition.yaml"))
+	require.Equal(t, "README.md", TrimExtension("README.md"))
+}
+
+func TestReadFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		wantPath string
+	}{
+		{name: "Valid/XML", path: "testdata/catalog.xml", wantPath: "testdata/catalog.json"},
+		{name: "Valid/YAML", path: "testdata/profile.yaml", wantPath: "testdata/profile.json"},
+		{name: "Valid/JSON", path: "testdata/profile.json", wantPath: "testdata/profile.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadFile(tt.path)
+			require.NoError(t, err)
+			want, err := os.ReadFile(tt.wantPath)
+			require.NoError(t, err)
+			require.JSONEq(t, string(want), string(got))
+
+			var models oscalTypes.OscalModels
+			require.NoError(t, json.Unmarshal(got, &models))
+			require.NoError(t, validation.NewSchemaValidator().Validate(models))
+		})
+	}
+}
+
+func TestXMLToJSONErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "Invalid/Namespace",
+			data:    `<Benchmark xmlns="http://checklists.nist.gov/xccdf/1.2"/>`,
+			wantErr: "not OSCAL content: root element Benchmark is not in the http://csrc.nist.gov/ns/oscal/1.0 namespace",
+		},
+		{
+			name:    "Invalid/Model",
+			data:    `<mapping-collection xmlns="http://csrc.nist.gov/ns/oscal/1.0"/>`,
+			wantErr: "not OSCAL content: unsupported model mapping-collection",
+		},
+		{
+			name:    "Invalid/Element",
+			data:    `<catalog xmlns="http://csrc.nist.gov/ns/oscal/1.0"><metadata><owner/></metadata></catalog>`,
+			wantErr: "unexpected element owner at /catalog/metadata",
+		},
+		{
+			name:    "Invalid/Attribute",
+			data:    `<catalog xmlns="http://csrc.nist.gov/ns/oscal/1.0" id="c1"/>`,
+			wantErr: "unexpected attribute id at /catalog",
+		},
+		{
+			name:    "Invalid/Syntax",
+			data:    `<catalog xmlns="http://csrc.nist.gov/ns/oscal/1.0">`,
+			wantErr: "invalid XML: XML syntax error on line 1: unexpected EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ToJSON([]byte(tt.data))
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,418 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+)
+
+// oscalNamespace is the XML namespace of OSCAL models.
+const oscalNamespace = "http://csrc.nist.gov/ns/oscal/1.0"
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	whitespace = regexp.MustCompile(`\s+`)
+)
+
+// xmlItemNames are the XML element names of array items that are not the singular of the
+// JSON array name.
+var xmlItemNames = map[string]string{
+	"exclude-controls": "exclude-controls",
+	"include-controls": "include-controls",
+	"insert-controls":  "insert-controls",
+	"remediations":     "response",
+}
+
+// textFields are the JSON names of the fields holding the text content of XML elements with
+// attributes, such as <hash algorithm="sha-256">value</hash>.
+var textFields = []string{"value", "identifier", "number"}
+
+// blockElements are the markup elements of multiline markup.
+var blockElements = map[string]bool{
+	"p": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "pre": true, "blockquote": true, "table": true, "hr": true,
+}
+
+// xmlNode is an element of an XML document. Text is represented by nodes without a name.
+type xmlNode struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*xmlNode
+	text     string
+}
+
+// elements returns the child elements of the node.
+func (n *xmlNode) elements() []*xmlNode {
+	var elements []*xmlNode
+	for _, child := range n.children {
+		if child.name.Local != "" {
+			elements = append(elements, child)
+		}
+	}
+	return elements
+}
+
+// textContent returns the concatenated text of the node and its descendants.
+func (n *xmlNode) textContent() string {
+	if n.name.Local == "" {
+		return n.text
+	}
+	var text strings.Builder
+	for _, child := range n.children {
+		text.WriteString(child.textContent())
+	}
+	return text.String()
+}
+
+// attr returns the value of the attribute with the given name.
+func (n *xmlNode) attr(name string) string {
+	for _, attr := range n.attrs {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// parseXML returns the root element of the XML document.
+func parseXML(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var (
+		root  *xmlNode
+		stack []*xmlNode
+	)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XML: %w", err)
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: token.Name, attrs: token.Attr}
+			if len(stack) == 0 {
+				root = node
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, &xmlNode{text: string(token)})
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("invalid XML: no root element")
+	}
+	return root, nil
+}
+
+// xmlToJSON converts an OSCAL model in the OSCAL XML format to the OSCAL JSON format. The
+// structure of the JSON document is derived from the OSCAL model types.
+func xmlToJSON(data []byte) ([]byte, error) {
+	root, err := parseXML(data)
+	if err != nil {
+		return nil, err
+	}
+	if root.name.Space != oscalNamespace {
+		return nil, fmt.Errorf("%w: root element %s is not in the %s namespace", ErrNotOSCAL, root.name.Local, oscalNamespace)
+	}
+	field, found := jsonFields(reflect.TypeOf(oscalTypes.OscalModels{}))[root.name.Local]
+	if !found {
+		return nil, fmt.Errorf("%w: unsupported model %s", ErrNotOSCAL, root.name.Local)
+	}
+	value, err := convertNode(root, field.Type, "/"+root.name.Local)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]interface{}{root.name.Local: value})
+}
+
+// jsonFields returns the fields of the struct type by JSON name.
+func jsonFields(structType reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = field
+		}
+	}
+	return fields
+}
+
+// xmlItemName returns the XML element name of the items of a JSON array.
+func xmlItemName(arrayName string) string {
+	if name, found := xmlItemNames[arrayName]; found {
+		return name
+	}
+	switch {
+	case strings.HasSuffix(arrayName, "ies"):
+		return strings.TrimSuffix(arrayName, "ies") + "y"
+	case strings.HasSuffix(arrayName, "sses"), strings.HasSuffix(arrayName, "shes"):
+		return strings.TrimSuffix(arrayName, "es")
+	default:
+		return strings.TrimSuffix(arrayName, "s")
+	}
+}
+
+// indirect returns the type pointed to by pointer types.
+func indirect(valueType reflect.Type) reflect.Type {
+	for valueType.Kind() == reflect.Pointer {
+		valueType = valueType.Elem()
+	}
+	return valueType
+}
+
+// convertNode converts the element to the JSON value of the given type.
+func convertNode(node *xmlNode, valueType reflect.Type, location string) (interface{}, error) {
+	valueType = indirect(valueType)
+	switch {
+	case valueType == timeType:
+		return strings.TrimSpace(node.textContent()), nil
+	case valueType.Kind() == reflect.Struct:
+		return convertStruct(node, valueType, location)
+	case valueType.Kind() == reflect.Map:
+		// Elements without content, such as <include-all/>
+		return map[string]interface{}{}, nil
+	case valueType.Kind() == reflect.String:
+		for _, child := range node.elements() {
+			if blockElements[child.name.Local] {
+				return renderBlocks(node.children), nil
+			}
+		}
+		return strings.TrimSpace(renderInline(node.children)), nil
+	default:
+		return convertScalar(strings.TrimSpace(node.textContent()), valueType, location)
+	}
+}
+
+// convertScalar converts the text of an attribute or element to the JSON value of the given type.
+func convertScalar(text string, valueType reflect.Type, location string) (interface{}, error) {
+	switch indirect(valueType).Kind() {
+	case reflect.Bool:
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean %q at %s", text, location)
+		}
+		return value, nil
+	case reflect.Int:
+		value, err := strconv.Atoi(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q at %s", text, location)
+		}
+		return value, nil
+	default:
+		return text, nil
+	}
+}
+
+// convertStruct converts the element to a JSON object with the fields of the struct type. Attributes
+// and child elements are matched with the fields by name. Repeated child elements are gathered in
+// the JSON array of the field named after their plural.
+func convertStruct(node *xmlNode, structType reflect.Type, location string) (map[string]interface{}, error) {
+	fields := jsonFields(structType)
+	arrays := make(map[string]string)
+	for name, field := range fields {
+		if indirect(field.Type).Kind() == reflect.Slice {
+			arrays[xmlItemName(name)] = name
+		}
+	}
+
+	object := make(map[string]interface{})
+	for _, attr := range node.attrs {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		field, found := fields[attr.Name.Local]
+		if !found {
+			return nil, fmt.Errorf("unexpected attribute %s at %s", attr.Name.Local, location)
+		}
+		value, err := convertScalar(attr.Value, field.Type, location+"/@"+attr.Name.Local)
+		if err != nil {
+			return nil, err
+		}
+		object[attr.Name.Local] = value
+	}
+
+	var (
+		text  strings.Builder
+		prose []*xmlNode
+	)
+	for _, child := range node.children {
+		name := child.name.Local
+		childLocation := location + "/" + name
+		switch {
+		case name == "":
+			text.WriteString(child.text)
+		case arrays[name] != "":
+			arrayName := arrays[name]
+			item, err := convertNode(child, indirect(fields[arrayName].Type).Elem(), childLocation)
+			if err != nil {
+				return nil, err
+			}
+			items, _ := object[arrayName].([]interface{})
+			object[arrayName] = append(items, item)
+		case fields[name].Type != nil && indirect(fields[name].Type).Kind() == reflect.Slice:
+			// Arrays grouped in a wrapper element, such as <revisions><revision/></revisions>
+			itemName := xmlItemName(name)
+			items := []interface{}{}
+			for _, grandchild := range child.elements() {
+				if grandchild.name.Local != itemName {
+					return nil, fmt.Errorf("unexpected element %s at %s", grandchild.name.Local, childLocation)
+				}
+				item, err := convertNode(grandchild, indirect(fields[name].Type).Elem(), childLocation+"/"+itemName)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			}
+			object[name] = items
+		case fields[name].Type != nil:
+			value, err := convertNode(child, fields[name].Type, childLocation)
+			if err != nil {
+				return nil, err
+			}
+			object[name] = value
+		case blockElements[name] && fields["prose"].Type != nil:
+			// Prose is not wrapped in an element, such as in <part><p>text</p></part>
+			prose = append(prose, child)
+		default:
+			return nil, fmt.Errorf("unexpected element %s at %s", name, location)
+		}
+	}
+
+	if len(prose) > 0 {
+		object["prose"] = renderBlocks(prose)
+	}
+	if trimmed := strings.TrimSpace(text.String()); trimmed != "" {
+		textField := ""
+		for _, name := range textFields {
+			if field, found := fields[name]; found && indirect(field.Type).Kind() == reflect.String {
+				textField = name
+				break
+			}
+		}
+		if textField == "" {
+			return nil, fmt.Errorf("unexpected text %q at %s", trimmed, location)
+		}
+		object[textField] = trimmed
+	}
+	return object, nil
+}
+
+// renderBlocks renders multiline markup as Markdown, the representation of markup in OSCAL JSON.
+func renderBlocks(nodes []*xmlNode) string {
+	var (
+		blocks []string
+		inline []*xmlNode
+	)
+	flushInline := func() {
+		if rendered := strings.TrimSpace(renderInline(inline)); rendered != "" {
+			blocks = append(blocks, rendered)
+		}
+		inline = nil
+	}
+	for _, node := range nodes {
+		if !blockElements[node.name.Local] {
+			inline = append(inline, node)
+			continue
+		}
+		flushInline()
+		blocks = append(blocks, renderBlock(node))
+	}
+	flushInline()
+	return strings.Join(blocks, "\n\n")
+}
+
+// renderBlock renders a block markup element as Markdown.
+func renderBlock(node *xmlNode) string {
+	switch name := node.name.Local; name {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level := int(name[1] - '0')
+		return strings.Repeat("#", level) + " " + strings.TrimSpace(renderInline(node.children))
+	case "ul", "ol":
+		var lines []string
+		for i, item := range node.elements() {
+			marker := "-"
+			if name == "ol" {
+				marker = strconv.Itoa(i+1) + "."
+			}
+			rendered := renderBlocks(item.children)
+			lines = append(lines, marker+" "+strings.ReplaceAll(rendered, "\n", "\n  "))
+		}
+		return strings.Join(lines, "\n")
+	case "pre":
+		return "```\n" + strings.Trim(node.textContent(), "\n") + "\n```"
+	case "blockquote":
+		return "> " + strings.ReplaceAll(renderBlocks(node.children), "\n", "\n> ")
+	case "table":
+		var rows []string
+		for _, row := range node.elements() {
+			var cells []string
+			for _, cell := range row.elements() {
+				cells = append(cells, strings.TrimSpace(renderInline(cell.children)))
+			}
+			rows = append(rows, "| "+strings.Join(cells, " | ")+" |")
+			if len(rows) == 1 {
+				rows = append(rows, "|"+strings.Repeat(" --- |", len(cells)))
+			}
+		}
+		return strings.Join(rows, "\n")
+	case "hr":
+		return "---"
+	default:
+		return strings.TrimSpace(renderInline(node.children))
+	}
+}
+
+// renderInline renders inline markup as Markdown. Whitespace is collapsed as in HTML.
+func renderInline(nodes []*xmlNode) string {
+	var rendered strings.Builder
+	for _, node := range nodes {
+		switch node.name.Local {
+		case "":
+			rendered.WriteString(node.text)
+		case "em", "i":
+			rendered.WriteString("*" + renderInline(node.children) + "*")
+		case "strong", "b":
+			rendered.WriteString("**" + renderInline(node.children) + "**")
+		case "code":
+			rendered.WriteString("`" + node.textContent() + "`")
+		case "q":
+			rendered.WriteString(`"` + renderInline(node.children) + `"`)
+		case "sub":
+			rendered.WriteString("~" + renderInline(node.children) + "~")
+		case "sup":
+			rendered.WriteString("^" + renderInline(node.children) + "^")
+		case "a":
+			rendered.WriteString("[" + renderInline(node.children) + "](" + node.attr("href") + ")")
+		case "img":
+			rendered.WriteString("![" + node.attr("alt") + "](" + node.attr("src") + ")")
+		case "insert":
+			rendered.WriteString("{{ insert: " + node.attr("type") + ", " + node.attr("id-ref") + " }}")
+		default:
+			rendered.WriteString(renderInline(node.children))
+		}
+	}
+	return whitespace.ReplaceAllString(rendered.String(), " ")
+}
@@ -0,0 +1,397 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adrg/xdg"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/models"
+	"github.com/oscal-compass/oscal-sdk-go/models/components"
+	"github.com/oscal-compass/oscal-sdk-go/settings"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+)
+
+// IndexVersion is the version of the content index format. Indexes with another version are rebuilt.
+const IndexVersion = 1
+
+// ContentIndex is a cache of the content found in a content path. It holds the validated component
+// definitions and the information derived from them and their control sources, so read-only commands
+// do not need to read, convert, and validate every content file.
+//
+// The index is stored in the XDG cache directory and rebuilt when a component definition is added or
+// removed, a control source resolves to another file, or an indexed file changes. Files with a
+// different modification time are compared by digest.
+type ContentIndex struct {
+	// Version is the version of the index format.
+	Version int `json:"version"`
+	// ContentPath is the content path the index was built from.
+	ContentPath string `json:"content-path"`
+	// Files are the content files read to build the index.
+	Files []IndexedFile `json:"files"`
+	// ComponentDefinitions are the component definitions of the content path, ordered by file name.
+	ComponentDefinitions []oscalTypes.ComponentDefinition `json:"component-definitions"`
+	// Frameworks are the frameworks implemented by the component definitions, ordered by ID.
+	Frameworks []Framework `json:"frameworks"`
+	// Controls maps framework IDs to the titles of the controls of their catalogs by control ID.
+	Controls map[string]map[string]string `json:"controls"`
+	// Parameters maps framework IDs to the values set for their parameters by parameter ID.
+	Parameters map[string]map[string][]string `json:"parameters"`
+	// RulePlugins maps rule IDs to the title of the validation component checking the rule.
+	RulePlugins map[string]string `json:"rule-plugins"`
+}
+
+// IndexedFile is a content file read to build the content index.
+type IndexedFile struct {
+	// Path is the location of the file.
+	Path string `json:"path"`
+	// Source is the control source resolved to the file. It is empty for component definitions.
+	Source string `json:"source,omitempty"`
+	// Size is the size of the file in bytes.
+	Size int64 `json:"size"`
+	// ModTime is the modification time of the file.
+	ModTime time.Time `json:"mod-time"`
+	// Digest is the hex encoded SHA256 checksum of the file.
+	Digest string `json:"digest"`
+}
+
+// ContentIndexPath returns the location of the content index of the content path in the XDG cache
+// directory. Each content path has its own index.
+func ContentIndexPath(contentPath ContentPath) (string, error) {
+	sum := sha256.Sum256([]byte(contentPath.String()))
+	name := fmt.Sprintf("content-index-%s.json", hex.EncodeToString(sum[:8]))
+	return xdg.CacheFile(filepath.Join(ApplicationDir, name))
+}
+
+// LoadContentIndex returns the content index of the content path from the cache directory. The index
+// is built and stored if it is missing or out of date.
+func LoadContentIndex(contentPath ContentPath, validator validation.Validator) (*ContentIndex, error) {
+	indexPath, err := ContentIndexPath(contentPath)
+	if err == nil {
+		if index, err := ReadContentIndex(indexPath); err == nil {
+			if current, changed := index.check(contentPath); current {
+				if changed {
+					// Record the new modification times of files with unchanged content
+					_ = index.Write(indexPath)
+				}
+				return index, nil
+			}
+		}
+	}
+
+	index, err := BuildContentIndex(contentPath, validator)
+	if err != nil {
+		return nil, err
+	}
+	if indexPath != "" {
+		// The index is only a cache, content is still served when it cannot be stored
+		_ = index.Write(indexPath)
+	}
+	return index, nil
+}
+
+// ReadContentIndex reads a content index from the given path.
+func ReadContentIndex(indexPath string) (*ContentIndex, error) {
+	data, err := os.ReadFile(filepath.Clean(indexPath))
+	if err != nil {
+		return nil, err
+	}
+	var index ContentIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("error reading content index %s: %w", indexPath, err)
+	}
+	return &index, nil
+}
+
+// Write stores the content index at the given path. The index is written to a temporary file that
+// replaces the previous index, so concurrent readers never see a partial index.
+func (i *ContentIndex) Write(indexPath string) error {
+	data, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(indexPath), filepath.Base(indexPath)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), indexPath)
+}
+
+// BuildContentIndex reads and validates the component definitions of the content path and their
+// control sources, and returns their index.
+func BuildContentIndex(contentPath ContentPath, validator validation.Validator) (*ContentIndex, error) {
+	files, err := contentPath.ComponentDefinitionFiles()
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("directories %s: %w", strings.Join(contentPath.BundleDirs(), ", "), ErrNoComponentDefinitionsFound)
+	}
+
+	index := &ContentIndex{
+		Version:     IndexVersion,
+		ContentPath: contentPath.String(),
+		Controls:    make(map[string]map[string]string),
+		Parameters:  make(map[string]map[string][]string),
+		RulePlugins: make(map[string]string),
+	}
+	for _, file := range files {
+		definition, err := loadComponentDefinition(file.Path, validator)
+		if err != nil {
+			return nil, err
+		}
+		index.ComponentDefinitions = append(index.ComponentDefinitions, *definition)
+		if err := index.addFile(file.Path, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	loader := newContentLoader(contentPath, validator)
+	index.Frameworks, err = loader.frameworks(files, index.ComponentDefinitions)
+	if err != nil {
+		return nil, err
+	}
+	for _, definition := range index.ComponentDefinitions {
+		if definition.Components == nil {
+			continue
+		}
+		for _, component := range *definition.Components {
+			index.addComponent(loader, component)
+		}
+	}
+
+	for source, path := range loader.sources {
+		if err := index.addFile(path, source); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(index.Files, func(a, b int) bool { return index.Files[a].Path < index.Files[b].Path })
+	return index, nil
+}
+
+// addComponent indexes the rules of a validation component, or the controls and parameters of the
+// frameworks implemented by a target component.
+func (i *ContentIndex) addComponent(loader *contentLoader, component oscalTypes.DefinedComponent) {
+	if component.Type == string(components.Validation) {
+		if component.Props != nil {
+			for _, prop := range *component.Props {
+				if prop.Name == extensions.RuleIdProp {
+					i.RulePlugins[prop.Value] = component.Title
+				}
+			}
+		}
+		return
+	}
+	if component.ControlImplementations == nil {
+		return
+	}
+	for _, implementation := range *component.ControlImplementations {
+		frameworkID, found := settings.GetFrameworkShortName(implementation)
+		if !found {
+			continue
+		}
+		if implementation.SetParameters != nil {
+			for _, parameter := range *implementation.SetParameters {
+				if parameter.ParamId == "" || len(parameter.Values) == 0 {
+					continue
+				}
+				if i.Parameters[frameworkID] == nil {
+					i.Parameters[frameworkID] = make(map[string][]string)
+				}
+				i.Parameters[frameworkID][parameter.ParamId] = parameter.Values
+			}
+		}
+
+		// Profiles were loaded with the frameworks. Catalogs that cannot be loaded are skipped,
+		// leaving their controls without a title.
+		profile, err := loader.profile(implementation.Source)
+		if err != nil {
+			continue
+		}
+		for _, imp := range profile.Imports {
+			catalog, err := loader.catalog(imp.Href)
+			if err != nil {
+				continue
+			}
+			if i.Controls[frameworkID] == nil {
+				i.Controls[frameworkID] = make(map[string]string)
+			}
+			addControlTitles(i.Controls[frameworkID], catalog)
+		}
+	}
+}
+
+// addControlTitles adds the titles of all controls of the catalog, including controls in groups
+// and control enhancements.
+func addControlTitles(titles map[string]string, catalog *oscalTypes.Catalog) {
+	var addControls func(controls *[]oscalTypes.Control)
+	addControls = func(controls *[]oscalTypes.Control) {
+		if controls == nil {
+			return
+		}
+		for _, control := range *controls {
+			if control.Title != "" {
+				titles[control.ID] = control.Title
+			}
+			addControls(control.Controls)
+		}
+	}
+	var addGroups func(groups *[]oscalTypes.Group)
+	addGroups = func(groups *[]oscalTypes.Group) {
+		if groups == nil {
+			return
+		}
+		for _, group := range *groups {
+			addControls(group.Controls)
+			addGroups(group.Groups)
+		}
+	}
+	addControls(catalog.Controls)
+	addGroups(catalog.Groups)
+}
+
+// addFile records a content file read to build the index.
+func (i *ContentIndex) addFile(path, source string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	digest, err := fileChecksum(path)
+	if err != nil {
+		return err
+	}
+	i.Files = append(i.Files, IndexedFile{Path: path, Source: source, Size: info.Size(), ModTime: info.ModTime(), Digest: digest})
+	return nil
+}
+
+// check returns whether the index is current for the content path, and whether the modification
+// time of an indexed file with unchanged content was updated.
+func (i *ContentIndex) check(contentPath ContentPath) (current bool, changed bool) {
+	if i.Version != IndexVersion || i.ContentPath != contentPath.String() {
+		return false, false
+	}
+
+	files, err := contentPath.ComponentDefinitionFiles()
+	if err != nil {
+		return false, false
+	}
+	indexed := make(map[string]bool)
+	for _, file := range i.Files {
+		if file.Source == "" {
+			indexed[file.Path] = true
+		}
+	}
+	if len(files) != len(indexed) {
+		return false, false
+	}
+	for _, file := range files {
+		if !indexed[file.Path] {
+			return false, false
+		}
+	}
+
+	for n := range i.Files {
+		file := &i.Files[n]
+		if file.Source != "" {
+			// A content root with higher precedence can provide the control source
+			if path, err := controlSourcePath(contentPath, file.Source); err != nil || path != file.Path {
+				return false, false
+			}
+		}
+		info, err := os.Stat(file.Path)
+		if err != nil || info.Size() != file.Size {
+			return false, false
+		}
+		if info.ModTime().Equal(file.ModTime) {
+			continue
+		}
+		if digest, err := fileChecksum(file.Path); err != nil || digest != file.Digest {
+			return false, false
+		}
+		file.ModTime = info.ModTime()
+		changed = true
+	}
+	return true, changed
+}
+
+// contentLoader loads the control sources of a content path. Each control source is read once,
+// and the files read are recorded.
+type contentLoader struct {
+	contentPath ContentPath
+	validator   validation.Validator
+	profiles    map[string]*oscalTypes.Profile
+	catalogs    map[string]*oscalTypes.Catalog
+	// sources maps the control sources read to the location of their file.
+	sources map[string]string
+}
+
+func newContentLoader(contentPath ContentPath, validator validation.Validator) *contentLoader {
+	return &contentLoader{
+		contentPath: contentPath,
+		validator:   validator,
+		profiles:    make(map[string]*oscalTypes.Profile),
+		catalogs:    make(map[string]*oscalTypes.Catalog),
+		sources:     make(map[string]string),
+	}
+}
+
+// profile returns the profile of the control source.
+func (l *contentLoader) profile(source string) (*oscalTypes.Profile, error) {
+	if profile, found := l.profiles[source]; found {
+		return profile, nil
+	}
+	reader, err := findControlSource(l.contentPath, source)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := models.NewProfile(reader, l.validator)
+	if err != nil {
+		return nil, err
+	}
+	l.record(source)
+	l.profiles[source] = profile
+	return profile, nil
+}
+
+// catalog returns the catalog of the control source.
+func (l *contentLoader) catalog(source string) (*oscalTypes.Catalog, error) {
+	if catalog, found := l.catalogs[source]; found {
+		return catalog, nil
+	}
+	reader, err := findControlSource(l.contentPath, source)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := models.NewCatalog(reader, l.validator)
+	if err != nil {
+		return nil, err
+	}
+	l.record(source)
+	l.catalogs[source] = catalog
+	return catalog, nil
+}
+
+// record records the file of a control source that was read.
+func (l *contentLoader) record(source string) {
+	if path, err := controlSourcePath(l.contentPath, source); err == nil {
+		l.sources[source] = path
+	}
+}
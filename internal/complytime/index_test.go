@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+)
+
+// testIndexedContentPath returns a content path with a copy of the test content and an empty
+// cache directory.
+func testIndexedContentPath(t *testing.T) ContentPath {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+	rootDir := t.TempDir()
+	require.NoError(t, os.CopyFS(rootDir, os.DirFS("testdata")))
+	return testContentPath(t, rootDir)
+}
+
+func TestBuildContentIndex(t *testing.T) {
+	index, err := BuildContentIndex(testContentPath(t, "testdata"), validation.NoopValidator{})
+	require.NoError(t, err)
+	require.Equal(t, []Framework{
+		{
+			Title:               "Example Profile (low)",
+			ID:                  "example",
+			SupportedComponents: []string{"My Software"},
+			Roots:               []string{"testdata/complytime"},
+		},
+	}, index.Frameworks)
+	require.Len(t, index.ComponentDefinitions, 1)
+	require.Equal(t, map[string]string{"r1": "Hardware Support"}, index.Controls["example"])
+	require.Equal(t, map[string][]string{"param-1": {"value-2"}}, index.Parameters["example"])
+	require.Equal(t, "myplugin", index.RulePlugins["rule-1"])
+
+	var paths []string
+	for _, file := range index.Files {
+		paths = append(paths, file.Path)
+	}
+	require.Equal(t, []string{
+		"testdata/complytime/bundles/example-component-definition.json",
+		"testdata/complytime/controls/sample-catalog.json",
+		"testdata/complytime/controls/sample-profile.json",
+	}, paths)
+
+	_, err = BuildContentIndex(testContentPath(t, t.TempDir()), validation.NoopValidator{})
+	require.ErrorIs(t, err, ErrNoComponentDefinitionsFound)
+}
+
+func TestLoadContentIndex(t *testing.T) {
+	contentPath := testIndexedContentPath(t)
+	indexPath, err := ContentIndexPath(contentPath)
+	require.NoError(t, err)
+
+	index, err := LoadContentIndex(contentPath, validation.NoopValidator{})
+	require.NoError(t, err)
+	require.FileExists(t, indexPath)
+	require.Equal(t, "Example Profile (low)", index.Frameworks[0].Title)
+
+	// A stored index is served as long as the content does not change
+	current, changed := index.check(contentPath)
+	require.True(t, current)
+	require.False(t, changed)
+
+	// Touched files with the same content keep the index current
+	profilePath := filepath.Join(contentPath[0].ControlDir(), "sample-profile.json")
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(profilePath, later, later))
+	index, err = ReadContentIndex(indexPath)
+	require.NoError(t, err)
+	current, changed = index.check(contentPath)
+	require.True(t, current)
+	require.True(t, changed)
+
+	// Changed files rebuild the index
+	data, err := os.ReadFile(profilePath)
+	require.NoError(t, err)
+	data = []byte(string(data[:len(data)-1]) + " ")
+	require.NoError(t, os.WriteFile(profilePath, data, 0600))
+	index, err = ReadContentIndex(indexPath)
+	require.NoError(t, err)
+	current, _ = index.check(contentPath)
+	require.False(t, current)
+
+	// New component definitions rebuild the index
+	index, err = LoadContentIndex(contentPath, validation.NoopValidator{})
+	require.NoError(t, err)
+	compDef, err := os.ReadFile(filepath.Join(contentPath[0].BundleDir(), "example-component-definition.json"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(contentPath[0].BundleDir(), "other-component-definition.json"), compDef, 0600))
+	current, _ = index.check(contentPath)
+	require.False(t, current)
+
+	// Control sources provided by a content root with higher precedence rebuild the index
+	require.NoError(t, os.Remove(filepath.Join(contentPath[0].BundleDir(), "other-component-definition.json")))
+	override := testContentPath(t, t.TempDir())
+	require.NoError(t, os.MkdirAll(override[0].ControlDir(), 0700))
+	profile, err := os.ReadFile(profilePath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(override[0].ControlDir(), "sample-profile.json"), profile, 0600))
+	contentPath = append(contentPath, override...)
+	index.ContentPath = contentPath.String()
+	current, _ = index.check(contentPath)
+	require.False(t, current)
+}
@@ -0,0 +1,331 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/models/components"
+	"github.com/oscal-compass/oscal-sdk-go/settings"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/complytime/complyctl/internal/complytime/format"
+)
+
+// Lint checks performed on OSCAL content.
+const (
+	LintCheckParse         = "parse"
+	LintCheckSchema        = "schema"
+	LintCheckFramework     = "framework"
+	LintCheckControlSource = "control-source"
+	LintCheckRule          = "rule"
+	LintCheckParameter     = "parameter"
+)
+
+// LintFinding is a problem found in OSCAL content.
+type LintFinding struct {
+	// File is the path of the content file relative to the linted directory.
+	File string `json:"file"`
+	// Pointer is the JSON pointer to the location of the problem in the file. For YAML and XML
+	// files, it points into the OSCAL JSON representation of the file.
+	Pointer string `json:"pointer"`
+	// Check is the lint check that found the problem.
+	Check string `json:"check"`
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+func (f LintFinding) String() string {
+	pointer := f.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%s#%s: [%s] %s", f.File, pointer, f.Check, f.Message)
+}
+
+// lintDocument is an OSCAL content file loaded for linting.
+type lintDocument struct {
+	file   string
+	models oscalTypes.OscalModels
+}
+
+// LintContent checks all OSCAL component definitions, catalogs, and profiles below the root directory
+// with the validator and with complyctl specific semantic checks. Relative control sources are
+// resolved from the root directory, as they are from the application directory.
+func LintContent(root string, validator validation.Validator) ([]LintFinding, error) {
+	var (
+		findings  []LintFinding
+		documents []lintDocument
+	)
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !format.HasExtension(path) {
+			return nil
+		}
+		file, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		data, err = format.ToJSON(data)
+		if errors.Is(err, format.ErrNotOSCAL) {
+			// XML documents of other formats, such as SCAP data streams
+			return nil
+		}
+		if err != nil {
+			findings = append(findings, LintFinding{File: file, Check: LintCheckParse, Message: err.Error()})
+			return nil
+		}
+		var models oscalTypes.OscalModels
+		if err := json.Unmarshal(data, &models); err != nil {
+			findings = append(findings, LintFinding{File: file, Check: LintCheckParse, Message: err.Error()})
+			return nil
+		}
+		modelKey := contentModelKey(models)
+		if modelKey == "" {
+			// Not OSCAL content, such as plugin manifests
+			return nil
+		}
+		if err := validator.Validate(models); err != nil {
+			findings = append(findings, schemaFindings(file, modelKey, err)...)
+		}
+		documents = append(documents, lintDocument{file: file, models: models})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading content in %s: %w", root, err)
+	}
+
+	rules, parameters := declaredRulesAndParameters(documents)
+	for _, document := range documents {
+		switch {
+		case document.models.ComponentDefinition != nil:
+			findings = append(findings, lintComponentDefinition(root, document.file, *document.models.ComponentDefinition, rules, parameters)...)
+		case document.models.Profile != nil:
+			for i, imp := range document.models.Profile.Imports {
+				if message := checkContentSource(root, imp.Href); message != "" {
+					findings = append(findings, LintFinding{
+						File:    document.file,
+						Pointer: jsonPointer("profile", "imports", i, "href"),
+						Check:   LintCheckControlSource,
+						Message: message,
+					})
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// contentModelKey returns the top-level key of the OSCAL content model, or an empty string
+// for models that are not linted.
+func contentModelKey(models oscalTypes.OscalModels) string {
+	switch {
+	case models.ComponentDefinition != nil:
+		return "component-definition"
+	case models.Catalog != nil:
+		return "catalog"
+	case models.Profile != nil:
+		return "profile"
+	default:
+		return ""
+	}
+}
+
+// schemaFindings returns a finding for each schema violation in the validation error. Schema
+// violations reported for other OSCAL model types are ignored when violations are found in the
+// model of the file.
+func schemaFindings(file, modelKey string, err error) []LintFinding {
+	var validationErr *jsonschema.ValidationError
+	if errValidation, ok := err.(*validation.ErrValidation); ok {
+		err = errValidation.Err
+	}
+	if !errors.As(err, &validationErr) {
+		return []LintFinding{{File: file, Check: LintCheckSchema, Message: err.Error()}}
+	}
+
+	printer := message.NewPrinter(language.English)
+	var inModel, outsideModel []LintFinding
+	var collect func(*jsonschema.ValidationError)
+	collect = func(validationErr *jsonschema.ValidationError) {
+		if len(validationErr.Causes) > 0 {
+			for _, cause := range validationErr.Causes {
+				collect(cause)
+			}
+			return
+		}
+		segments := make([]any, len(validationErr.InstanceLocation))
+		for i, segment := range validationErr.InstanceLocation {
+			segments[i] = segment
+		}
+		finding := LintFinding{
+			File:    file,
+			Pointer: jsonPointer(segments...),
+			Check:   LintCheckSchema,
+			Message: validationErr.ErrorKind.LocalizedString(printer),
+		}
+		if len(validationErr.InstanceLocation) > 0 && validationErr.InstanceLocation[0] == modelKey {
+			inModel = append(inModel, finding)
+		} else {
+			outsideModel = append(outsideModel, finding)
+		}
+	}
+	collect(validationErr)
+	if len(inModel) > 0 {
+		return inModel
+	}
+	return outsideModel
+}
+
+// declaredRulesAndParameters returns the rule IDs declared by validation components and the
+// parameter IDs declared by any component in the component definitions.
+func declaredRulesAndParameters(documents []lintDocument) (map[string]bool, map[string]bool) {
+	rules := make(map[string]bool)
+	parameters := make(map[string]bool)
+	for _, document := range documents {
+		compDef := document.models.ComponentDefinition
+		if compDef == nil || compDef.Components == nil {
+			continue
+		}
+		for _, component := range *compDef.Components {
+			if component.Props == nil {
+				continue
+			}
+			isValidation := components.ComponentType(component.Type) == components.Validation
+			for _, prop := range *component.Props {
+				switch {
+				case prop.Name == extensions.RuleIdProp && isValidation:
+					rules[prop.Value] = true
+				case prop.Name == extensions.ParameterIdProp:
+					parameters[prop.Value] = true
+				}
+			}
+		}
+	}
+	return rules, parameters
+}
+
+// lintComponentDefinition runs the semantic checks on the control implementations of a component definition.
+func lintComponentDefinition(root, file string, compDef oscalTypes.ComponentDefinition, rules, parameters map[string]bool) []LintFinding {
+	if compDef.Components == nil {
+		return nil
+	}
+	var findings []LintFinding
+	for c, component := range *compDef.Components {
+		if component.ControlImplementations == nil {
+			continue
+		}
+		for i, implementation := range *component.ControlImplementations {
+			implementationPath := []any{"component-definition", "components", c, "control-implementations", i}
+			at := func(segments ...any) string {
+				return jsonPointer(append(append([]any{}, implementationPath...), segments...)...)
+			}
+
+			if _, found := settings.GetFrameworkShortName(implementation); !found {
+				findings = append(findings, LintFinding{
+					File:    file,
+					Pointer: at(),
+					Check:   LintCheckFramework,
+					Message: fmt.Sprintf("no %s property found for implementation %q", extensions.FrameworkProp, implementation.Description),
+				})
+			}
+			if message := checkContentSource(root, implementation.Source); message != "" {
+				findings = append(findings, LintFinding{File: file, Pointer: at("source"), Check: LintCheckControlSource, Message: message})
+			}
+			findings = append(findings, lintSetParameters(file, implementation.SetParameters, parameters, at)...)
+
+			for r, requirement := range implementation.ImplementedRequirements {
+				if requirement.Props != nil {
+					for p, prop := range *requirement.Props {
+						if prop.Name == extensions.RuleIdProp && !rules[prop.Value] {
+							findings = append(findings, LintFinding{
+								File:    file,
+								Pointer: at("implemented-requirements", r, "props", p),
+								Check:   LintCheckRule,
+								Message: fmt.Sprintf("rule %q for control %q is not declared by a validation component", prop.Value, requirement.ControlId),
+							})
+						}
+					}
+				}
+				findings = append(findings, lintSetParameters(file, requirement.SetParameters, parameters, func(segments ...any) string {
+					return at(append([]any{"implemented-requirements", r}, segments...)...)
+				})...)
+			}
+		}
+	}
+	return findings
+}
+
+// lintSetParameters checks that each set parameter is declared by a rule.
+func lintSetParameters(file string, setParameters *[]oscalTypes.SetParameter, parameters map[string]bool, at func(segments ...any) string) []LintFinding {
+	if setParameters == nil {
+		return nil
+	}
+	var findings []LintFinding
+	for s, setParameter := range *setParameters {
+		if parameters[setParameter.ParamId] {
+			continue
+		}
+		findings = append(findings, LintFinding{
+			File:    file,
+			Pointer: at("set-parameters", s),
+			Check:   LintCheckParameter,
+			Message: fmt.Sprintf("parameter %q is not declared by any rule", setParameter.ParamId),
+		})
+	}
+	return findings
+}
+
+// checkContentSource returns a problem description when a control source cannot be resolved
+// from the content root directory, or an empty string when the source exists.
+func checkContentSource(root, source string) string {
+	uri, err := url.ParseRequestURI(source)
+	if err != nil {
+		return fmt.Sprintf("invalid control source %q: %v", source, err)
+	}
+	path := uri.Host + uri.Path
+	if !filepath.IsAbs(path) {
+		if !strings.HasPrefix(path, ControlsDir+string(os.PathSeparator)) {
+			return fmt.Sprintf("control source %q is expected to be under path %s", source, ControlsDir)
+		}
+		path = filepath.Join(root, path)
+	}
+	if _, err := os.Stat(filepath.Clean(path)); err != nil {
+		return fmt.Sprintf("control source %q not found: %v", source, err)
+	}
+	return ""
+}
+
+// jsonPointer returns the RFC 6901 JSON pointer for the given path segments.
+func jsonPointer(segments ...any) string {
+	var pointer strings.Builder
+	for _, segment := range segments {
+		pointer.WriteString("/")
+		switch segment := segment.(type) {
+		case int:
+			pointer.WriteString(strconv.Itoa(segment))
+		default:
+			escaped := strings.ReplaceAll(fmt.Sprint(segment), "~", "~0")
+			pointer.WriteString(strings.ReplaceAll(escaped, "/", "~1"))
+		}
+	}
+	return pointer.String()
+}
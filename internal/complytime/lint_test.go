@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+)
+
+const lintTestComponentDefinition = `{
+  "component-definition": {
+    "uuid": "7791eb3a-764a-41e0-8cd3-8d775c9e95bf",
+    "metadata": {"title": "Broken", "last-modified": "2023-02-21T06:53:42+00:00", "version": "0.1.0", "oscal-version": "1.1.2"},
+    "components": [
+      {
+        "uuid": "7390f05c-d2b9-41d5-bf5f-3e6b17032d25",
+        "type": "validation",
+        "title": "openscap",
+        "description": "Validation component.",
+        "props": [
+          {"name": "Rule_Id", "value": "rule-1"},
+          {"name": "Parameter_Id", "value": "param-1"}
+        ]
+      },
+      {
+        "uuid": "4e19131e-b361-4f0e-8262-02bf4456202e",
+        "type": "software",
+        "title": "My Software",
+        "description": "Target software.",
+        "control-implementations": [
+          {
+            "uuid": "bb6420f5-146c-44c0-b708-79b96e7a009e",
+            "source": "file://profile.json",
+            "description": "Broken implementation.",
+            "set-parameters": [{"param-id": "param-2", "values": ["value"]}],
+            "implemented-requirements": [
+              {
+                "uuid": "ed2ac4e9-d16a-4fc5-bd3a-13484b6d8fef",
+                "control-id": "example-1",
+                "description": "Requirement.",
+                "props": [
+                  {"name": "Rule_Id", "value": "rule-1"},
+                  {"name": "Rule_Id", "value": "rule-2"}
+                ]
+              }
+            ]
+          }
+        ]
+      }
+    ]
+  }
+}`
+
+func TestLintContent(t *testing.T) {
+	findings, err := LintContent("testdata/complytime", validation.NoopValidator{})
+	require.NoError(t, err)
+	require.Empty(t, findings)
+	findings, err = LintContent("testdata/formats/complytime", validation.NoopValidator{})
+	require.NoError(t, err)
+	require.Empty(t, findings)
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "broken.json"), []byte(lintTestComponentDefinition), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "invalid.json"), []byte("{"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "manifest.json"), []byte(`{"metadata": {"id": "openscap"}}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "invalid.yaml"), []byte("profile: ["), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "ssg-ds.xml"), []byte(`<data-stream-collection xmlns="http://scap.nist.gov/schema/scap/source/1.2"/>`), 0600))
+
+	findings, err = LintContent(root, validation.NoopValidator{})
+	require.NoError(t, err)
+
+	implementation := "/component-definition/components/1/control-implementations/0"
+	var got []LintFinding
+	for _, finding := range findings {
+		if finding.Check == LintCheckParse {
+			require.Contains(t, []string{"invalid.json", "invalid.yaml"}, finding.File)
+			continue
+		}
+		finding.Message = ""
+		got = append(got, finding)
+	}
+	require.Equal(t, []LintFinding{
+		{File: "broken.json", Pointer: implementation, Check: LintCheckFramework},
+		{File: "broken.json", Pointer: implementation + "/source", Check: LintCheckControlSource},
+		{File: "broken.json", Pointer: implementation + "/set-parameters/0", Check: LintCheckParameter},
+		{File: "broken.json", Pointer: implementation + "/implemented-requirements/0/props/1", Check: LintCheckRule},
+	}, got)
+	require.Len(t, findings, len(got)+2)
+
+	_, err = LintContent(filepath.Join(root, "missing"), validation.NoopValidator{})
+	require.Error(t, err)
+}
+
+func TestJSONPointer(t *testing.T) {
+	require.Equal(t, "", jsonPointer())
+	require.Equal(t, "/catalog/groups/0", jsonPointer("catalog", "groups", 0))
+	require.Equal(t, "/a~1b/c~0d", jsonPointer("a/b", "c~d"))
+}
+
+func TestLintFindingString(t *testing.T) {
+	finding := LintFinding{File: "bundles/cd.json", Check: LintCheckParse, Message: "unexpected end of JSON input"}
+	require.Equal(t, "bundles/cd.json#/: [parse] unexpected end of JSON input", finding.String())
+	finding.Pointer = "/component-definition"
+	require.Equal(t, "bundles/cd.json#/component-definition: [parse] unexpected end of JSON input", finding.String())
+}
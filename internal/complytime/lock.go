@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/models/components"
+	"github.com/oscal-compass/oscal-sdk-go/settings"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+
+	"github.com/complytime/complyctl/internal/complytime/plan"
+)
+
+// PlanLockVersion is the version of the plan lock format.
+const PlanLockVersion = 1
+
+// Kinds of the content files recorded in a plan lock.
+const (
+	LockedComponentDefinition = "component-definition"
+	LockedProfile             = "profile"
+	LockedCatalog             = "catalog"
+)
+
+// PlanLock records the inputs of an assessment plan: the planned frameworks, the applied assessment
+// scopes, and the content files used. The same inputs reproduce the same assessment plan.
+type PlanLock struct {
+	// Version is the version of the plan lock format.
+	Version int `json:"version"`
+	// Frameworks are the planned frameworks, in the order they were given.
+	Frameworks []string `json:"frameworks"`
+	// Generated is the time the assessment plan was generated, used as its last modified time.
+	Generated time.Time `json:"generated"`
+	// Scopes are the assessment scopes applied to the assessment plan.
+	Scopes []plan.AssessmentScope `json:"scopes,omitempty"`
+	// Content are the component definitions of the content path and the profiles and catalogs
+	// of the planned frameworks, ordered by path and kind.
+	Content []LockedFile `json:"content"`
+}
+
+// LockedFile is a content file recorded in a plan lock.
+type LockedFile struct {
+	// Path is the slash separated location of the file relative to its content root.
+	// Files outside of the content roots keep their absolute location.
+	Path string `json:"path"`
+	// Kind is the kind of the file, one of component-definition, profile, or catalog.
+	Kind string `json:"kind"`
+	// Digest is the hex encoded SHA256 checksum of the file.
+	Digest string `json:"digest"`
+	// Version is the metadata version of the OSCAL model in the file.
+	Version string `json:"version"`
+}
+
+// NewPlanLock returns the plan lock of an assessment plan generated for the given frameworks and
+// scopes from the indexed content of the content path.
+func NewPlanLock(contentPath ContentPath, index *ContentIndex, frameworkIDs []string, scopes []plan.AssessmentScope) (*PlanLock, error) {
+	files, err := contentPath.ComponentDefinitionFiles()
+	if err != nil {
+		return nil, err
+	}
+	// The index holds the component definitions in the order of their files
+	if len(files) != len(index.ComponentDefinitions) {
+		return nil, fmt.Errorf("content index of %s is out of date", contentPath)
+	}
+
+	lock := &PlanLock{
+		Version:    PlanLockVersion,
+		Frameworks: frameworkIDs,
+		Generated:  time.Now().UTC().Truncate(time.Second),
+		Scopes:     scopes,
+	}
+	for i, file := range files {
+		if err := lock.addFile(contentPath, file.Path, LockedComponentDefinition, index.ComponentDefinitions[i].Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	// Content was validated when it was indexed
+	loader := newContentLoader(contentPath, validation.NoopValidator{})
+	for _, definition := range index.ComponentDefinitions {
+		if definition.Components == nil {
+			continue
+		}
+		for _, component := range *definition.Components {
+			if component.Type == string(components.Validation) || component.ControlImplementations == nil {
+				continue
+			}
+			for _, implementation := range *component.ControlImplementations {
+				if err := lock.addControlSources(loader, implementation, frameworkIDs); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	sort.Slice(lock.Content, func(i, j int) bool {
+		if lock.Content[i].Path != lock.Content[j].Path {
+			return lock.Content[i].Path < lock.Content[j].Path
+		}
+		return lock.Content[i].Kind < lock.Content[j].Kind
+	})
+	return lock, nil
+}
+
+// addControlSources records the profile and catalogs of a control implementation of a planned framework.
+func (l *PlanLock) addControlSources(loader *contentLoader, implementation oscalTypes.ControlImplementationSet, frameworkIDs []string) error {
+	frameworkID, found := settings.GetFrameworkShortName(implementation)
+	if !found || !slices.Contains(frameworkIDs, frameworkID) {
+		return nil
+	}
+	profile, err := loader.profile(implementation.Source)
+	if err != nil {
+		return fmt.Errorf("error loading control source %s for framework %s: %w", implementation.Source, frameworkID, err)
+	}
+	if err := l.addFile(loader.contentPath, loader.sources[implementation.Source], LockedProfile, profile.Metadata); err != nil {
+		return err
+	}
+	for _, imp := range profile.Imports {
+		catalog, err := loader.catalog(imp.Href)
+		if err != nil {
+			return fmt.Errorf("error loading control source %s for framework %s: %w", imp.Href, frameworkID, err)
+		}
+		if err := l.addFile(loader.contentPath, loader.sources[imp.Href], LockedCatalog, catalog.Metadata); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addFile records a content file, once for each kind.
+func (l *PlanLock) addFile(contentPath ContentPath, path, kind string, metadata oscalTypes.Metadata) error {
+	lockedPath := contentRelativePath(contentPath, path)
+	for _, file := range l.Content {
+		if file.Path == lockedPath && file.Kind == kind {
+			return nil
+		}
+	}
+	digest, err := fileChecksum(path)
+	if err != nil {
+		return err
+	}
+	l.Content = append(l.Content, LockedFile{Path: lockedPath, Kind: kind, Digest: digest, Version: metadata.Version})
+	return nil
+}
+
+// contentRelativePath returns the slash separated path of a file relative to the closest content
+// root containing it, or the path itself for files outside of the content roots.
+func contentRelativePath(contentPath ContentPath, path string) string {
+	relativePath := ""
+	for _, root := range contentPath {
+		rel, err := filepath.Rel(root.AppDir(), path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+			continue
+		}
+		if relativePath == "" || len(rel) < len(relativePath) {
+			relativePath = rel
+		}
+	}
+	if relativePath == "" {
+		return path
+	}
+	return filepath.ToSlash(relativePath)
+}
+
+// Seed returns a digest of the frameworks, scopes, and content of the lock. Assessment plans
+// generated from the same inputs derive their UUIDs from the same seed.
+func (l *PlanLock) Seed() string {
+	inputs := struct {
+		Frameworks []string               `json:"frameworks"`
+		Scopes     []plan.AssessmentScope `json:"scopes"`
+		Content    []LockedFile           `json:"content"`
+	}{l.Frameworks, l.Scopes, l.Content}
+	// The inputs only hold strings and cannot fail to marshal
+	data, _ := json.Marshal(inputs)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Diff returns the differences of the content of the current lock from the content of the lock,
+// ordered by path.
+func (l *PlanLock) Diff(current *PlanLock) []string {
+	key := func(file LockedFile) string { return file.Kind + " " + file.Path }
+	locked := make(map[string]LockedFile)
+	for _, file := range l.Content {
+		locked[key(file)] = file
+	}
+	var diffs []string
+	for _, file := range current.Content {
+		lockedFile, found := locked[key(file)]
+		delete(locked, key(file))
+		switch {
+		case !found:
+			diffs = append(diffs, fmt.Sprintf("%s added", key(file)))
+		case lockedFile.Digest != file.Digest:
+			diffs = append(diffs, fmt.Sprintf("%s changed from %s to %s", key(file), lockedFile.describe(), file.describe()))
+		}
+	}
+	for _, file := range locked {
+		diffs = append(diffs, fmt.Sprintf("%s removed", key(file)))
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return strings.SplitN(diffs[i], " ", 2)[1] < strings.SplitN(diffs[j], " ", 2)[1]
+	})
+	return diffs
+}
+
+func (f LockedFile) describe() string {
+	digest := f.Digest
+	if len(digest) > 12 {
+		digest = digest[:12]
+	}
+	return fmt.Sprintf("version %s (sha256:%s)", f.Version, digest)
+}
+
+// ReadPlanLock reads a plan lock from the given path.
+func ReadPlanLock(lockPath string) (*PlanLock, error) {
+	data, err := os.ReadFile(filepath.Clean(lockPath))
+	if err != nil {
+		return nil, err
+	}
+	var lock PlanLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("error reading plan lock %s: %w", lockPath, err)
+	}
+	if lock.Version != PlanLockVersion {
+		return nil, fmt.Errorf("plan lock %s has unsupported version %d", lockPath, lock.Version)
+	}
+	return &lock, nil
+}
+
+// Write stores the plan lock at the given path.
+func (l *PlanLock) Write(lockPath string) error {
+	data, err := json.MarshalIndent(l, "", " ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(lockPath, append(data, '\n'), 0600)
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime/plan"
+)
+
+func TestNewPlanLock(t *testing.T) {
+	contentPath := testIndexedContentPath(t)
+	index, err := LoadContentIndex(contentPath, validation.NoopValidator{})
+	require.NoError(t, err)
+
+	scopes := []plan.AssessmentScope{{FrameworkID: "example", IncludeControls: []plan.ControlEntry{{ControlID: "r1", Rules: []string{plan.IncludeAllRules}}}}}
+	lock, err := NewPlanLock(contentPath, index, []string{"example"}, scopes)
+	require.NoError(t, err)
+	var files []LockedFile
+	for _, file := range lock.Content {
+		require.Len(t, file.Digest, 64)
+		file.Digest = ""
+		files = append(files, file)
+	}
+	require.Equal(t, []LockedFile{
+		{Path: "bundles/example-component-definition.json", Kind: LockedComponentDefinition, Version: "0.1.0"},
+		{Path: "controls/sample-catalog.json", Kind: LockedCatalog, Version: "REPLACE_ME"},
+		{Path: "controls/sample-profile.json", Kind: LockedProfile, Version: "REPLACE_ME"},
+	}, files)
+
+	// Locks round trip through the workspace
+	lockPath := filepath.Join(t.TempDir(), "plan-lock.json")
+	require.NoError(t, lock.Write(lockPath))
+	readLock, err := ReadPlanLock(lockPath)
+	require.NoError(t, err)
+	require.Equal(t, lock, readLock)
+	require.Equal(t, lock.Seed(), readLock.Seed())
+
+	// Content of other frameworks is not locked
+	otherLock, err := NewPlanLock(contentPath, index, []string{"other"}, nil)
+	require.NoError(t, err)
+	require.Len(t, otherLock.Content, 1)
+	require.NotEqual(t, lock.Seed(), otherLock.Seed())
+
+	// Changed content is reported
+	require.Empty(t, lock.Diff(lock))
+	profilePath := filepath.Join(contentPath[0].ControlDir(), "sample-profile.json")
+	data, err := os.ReadFile(profilePath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(profilePath, append(data, ' '), 0600))
+	current, err := NewPlanLock(contentPath, index, []string{"example"}, scopes)
+	require.NoError(t, err)
+	diffs := lock.Diff(current)
+	require.Len(t, diffs, 1)
+	require.Regexp(t, `^profile controls/sample-profile.json changed from version REPLACE_ME \(sha256:\w{12}\) to version REPLACE_ME \(sha256:\w{12}\)$`, diffs[0])
+	require.Equal(t, []string{
+		"component-definition bundles/example-component-definition.json removed",
+		"catalog controls/sample-catalog.json removed",
+		"profile controls/sample-profile.json removed",
+	}, lock.Diff(&PlanLock{}))
+}
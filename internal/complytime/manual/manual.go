@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package manual reads and writes the attestations of the manual checks of a workspace. Manual checks
+// verify controls that cannot be assessed automatically, such as policies, training, and physical
+// security. Each is answered by a person, and the answer is reported by the manual plugin.
+package manual
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+const (
+	// PluginID is the ID of the manual plugin, and the title of the validation component of the manual checks.
+	PluginID = "manual"
+	// AttestationsLocation is the location of the attestations file in a workspace.
+	AttestationsLocation = "attestations.yml"
+	// DefaultValidDays is the number of days an attestation is valid for by default.
+	DefaultValidDays = 365
+)
+
+// Answers of an attestation.
+const (
+	// AnswerPass attests that the check is satisfied.
+	AnswerPass = "pass"
+	// AnswerFail attests that the check is not satisfied.
+	AnswerFail = "fail"
+)
+
+// Attestation is the answer of a person to a manual check, valid until it expires.
+type Attestation struct {
+	// Check is the ID of the attested check.
+	Check string `yaml:"check"`
+	// Rule is the ID of the rule of the check.
+	Rule string `yaml:"rule,omitempty"`
+	// Answer is pass when the check is satisfied, or fail.
+	Answer string `yaml:"answer"`
+	// AttestedBy is the person answering the check.
+	AttestedBy string `yaml:"attested-by"`
+	// AttestedAt is the time of the answer.
+	AttestedAt time.Time `yaml:"attested-at"`
+	// Expires is the date the attestation expires on, formatted as YYYY-MM-DD.
+	Expires string `yaml:"expires"`
+	// Comment explains the answer.
+	Comment string `yaml:"comment,omitempty"`
+	// Evidence are the paths of the evidence files, or their URLs.
+	Evidence []string `yaml:"evidence,omitempty"`
+}
+
+// Attestations is the content of the attestations file of a workspace.
+type Attestations struct {
+	Attestations []Attestation `yaml:"attestations"`
+}
+
+// Expiry returns the start of the expiry date of the attestation in the local time zone.
+func (a Attestation) Expiry() (time.Time, error) {
+	expiry, err := time.ParseInLocation(time.DateOnly, a.Expires, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expiry date %q for attestation of check %s, expected YYYY-MM-DD", a.Expires, a.Check)
+	}
+	return expiry, nil
+}
+
+// Expired returns whether the attestation expired at the given time. Attestations expire at the start of their expiry date.
+func (a Attestation) Expired(now time.Time) bool {
+	expiry, err := a.Expiry()
+	return err != nil || !now.Before(expiry)
+}
+
+// Validate checks that the attestation names a check and has a valid answer, an attester, an attestation
+// time, and a valid expiry date.
+func (a Attestation) Validate() error {
+	switch {
+	case a.Check == "":
+		return errors.New("attestation must name a check")
+	case a.Answer != AnswerPass && a.Answer != AnswerFail:
+		return fmt.Errorf("attestation of check %s has answer %q, expected %s or %s", a.Check, a.Answer, AnswerPass, AnswerFail)
+	case a.AttestedBy == "":
+		return fmt.Errorf("attestation of check %s has no attester", a.Check)
+	case a.AttestedAt.IsZero():
+		return fmt.Errorf("attestation of check %s has no attestation time", a.Check)
+	}
+	for _, evidence := range a.Evidence {
+		if strings.TrimSpace(evidence) == "" {
+			return fmt.Errorf("attestation of check %s has an empty evidence link", a.Check)
+		}
+	}
+	_, err := a.Expiry()
+	return err
+}
+
+// EvidenceLinks returns the links of the evidence of the attestation. Evidence files are linked with
+// file URLs, and relative paths are resolved from the given directory.
+func (a Attestation) EvidenceLinks(dir string) []string {
+	links := make([]string, 0, len(a.Evidence))
+	for _, evidence := range a.Evidence {
+		switch {
+		case strings.Contains(evidence, "://"):
+			links = append(links, evidence)
+		case filepath.IsAbs(evidence):
+			links = append(links, "file://"+evidence)
+		default:
+			links = append(links, "file://"+filepath.Join(dir, evidence))
+		}
+	}
+	return links
+}
+
+// ReadAttestations reads the attestations file at the given path. A missing file has no attestations.
+func ReadAttestations(attestationsLocation string) (*Attestations, error) {
+	data, err := os.ReadFile(filepath.Clean(attestationsLocation))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Attestations{}, nil
+		}
+		return nil, err
+	}
+	var attestations Attestations
+	if err := yaml.Unmarshal(data, &attestations); err != nil {
+		return nil, fmt.Errorf("failed to load attestations from %s: %w", attestationsLocation, err)
+	}
+	checks := make(map[string]bool, len(attestations.Attestations))
+	for _, attestation := range attestations.Attestations {
+		if err := attestation.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid attestation in %s: %w", attestationsLocation, err)
+		}
+		if checks[attestation.Check] {
+			return nil, fmt.Errorf("invalid attestation in %s: check %s is attested more than once", attestationsLocation, attestation.Check)
+		}
+		checks[attestation.Check] = true
+	}
+	return &attestations, nil
+}
+
+// Write stores the attestations file at the given path.
+func (a *Attestations) Write(attestationsLocation string) error {
+	data, err := yaml.Marshal(a)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(attestationsLocation), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(attestationsLocation, data, 0600)
+}
+
+// Find returns the attestation of the check.
+func (a *Attestations) Find(checkID string) (Attestation, bool) {
+	for _, attestation := range a.Attestations {
+		if attestation.Check == checkID {
+			return attestation, true
+		}
+	}
+	return Attestation{}, false
+}
+
+// Set adds the attestation, replacing the previous attestation of its check. The attestations are kept
+// sorted by check.
+func (a *Attestations) Set(attestation Attestation) error {
+	if err := attestation.Validate(); err != nil {
+		return err
+	}
+	for i := range a.Attestations {
+		if a.Attestations[i].Check == attestation.Check {
+			a.Attestations[i] = attestation
+			return nil
+		}
+	}
+	a.Attestations = append(a.Attestations, attestation)
+	sort.Slice(a.Attestations, func(i, j int) bool {
+		return a.Attestations[i].Check < a.Attestations[j].Check
+	})
+	return nil
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package manual
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAttestation(check string) Attestation {
+	return Attestation{
+		Check:      check,
+		Rule:       "security_awareness_training",
+		Answer:     AnswerPass,
+		AttestedBy: "Jane Doe",
+		AttestedAt: time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC),
+		Expires:    "2027-10-01",
+		Evidence:   []string{"/srv/evidence/training.pdf", "reports/roster.csv", "https://example.com/lms/report"},
+	}
+}
+
+func TestAttestationValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Attestation)
+		wantErr string
+	}{
+		{
+			name:   "Valid/Attestation",
+			modify: func(*Attestation) {},
+		},
+		{
+			name:    "Invalid/NoCheck",
+			modify:  func(a *Attestation) { a.Check = "" },
+			wantErr: "attestation must name a check",
+		},
+		{
+			name:    "Invalid/Answer",
+			modify:  func(a *Attestation) { a.Answer = "yes" },
+			wantErr: `attestation of check training_completed has answer "yes", expected pass or fail`,
+		},
+		{
+			name:    "Invalid/NoAttester",
+			modify:  func(a *Attestation) { a.AttestedBy = "" },
+			wantErr: "attestation of check training_completed has no attester",
+		},
+		{
+			name:    "Invalid/NoTime",
+			modify:  func(a *Attestation) { a.AttestedAt = time.Time{} },
+			wantErr: "attestation of check training_completed has no attestation time",
+		},
+		{
+			name:    "Invalid/Expires",
+			modify:  func(a *Attestation) { a.Expires = "next year" },
+			wantErr: `invalid expiry date "next year" for attestation of check training_completed, expected YYYY-MM-DD`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attestation := testAttestation("training_completed")
+			tt.modify(&attestation)
+			err := attestation.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestAttestationExpired(t *testing.T) {
+	attestation := testAttestation("training_completed")
+	assert.False(t, attestation.Expired(time.Date(2027, 9, 30, 23, 0, 0, 0, time.Local)))
+	assert.True(t, attestation.Expired(time.Date(2027, 10, 1, 0, 0, 0, 0, time.Local)))
+	attestation.Expires = "invalid"
+	assert.True(t, attestation.Expired(time.Now()))
+}
+
+func TestEvidenceLinks(t *testing.T) {
+	assert.Equal(t, []string{
+		"file:///srv/evidence/training.pdf",
+		"file:///workspace/reports/roster.csv",
+		"https://example.com/lms/report",
+	}, testAttestation("training_completed").EvidenceLinks("/workspace"))
+}
+
+func TestAttestationsFile(t *testing.T) {
+	attestationsPath := filepath.Join(t.TempDir(), "workspace", AttestationsLocation)
+	attestations, err := ReadAttestations(attestationsPath)
+	require.NoError(t, err)
+	require.Empty(t, attestations.Attestations)
+
+	require.NoError(t, attestations.Set(testAttestation("visitor_log_reviewed")))
+	require.NoError(t, attestations.Set(testAttestation("training_completed")))
+	updated := testAttestation("visitor_log_reviewed")
+	updated.Answer = AnswerFail
+	require.NoError(t, attestations.Set(updated))
+	require.Error(t, attestations.Set(Attestation{Check: "invalid"}))
+	require.Len(t, attestations.Attestations, 2)
+	require.NoError(t, attestations.Write(attestationsPath))
+
+	read, err := ReadAttestations(attestationsPath)
+	require.NoError(t, err)
+	require.Len(t, read.Attestations, 2)
+	assert.Equal(t, "training_completed", read.Attestations[0].Check)
+	visitorLog, found := read.Find("visitor_log_reviewed")
+	require.True(t, found)
+	assert.Equal(t, AnswerFail, visitorLog.Answer)
+	assert.True(t, updated.AttestedAt.Equal(visitorLog.AttestedAt))
+	_, found = read.Find("missing")
+	assert.False(t, found)
+
+	read.Attestations = append(read.Attestations, read.Attestations[0])
+	require.NoError(t, read.Write(attestationsPath))
+	_, err = ReadAttestations(attestationsPath)
+	require.ErrorContains(t, err, "check training_completed is attested more than once")
+
+	require.NoError(t, os.WriteFile(attestationsPath, []byte("attestations:\n  - check: no_answer\n"), 0600))
+	_, err = ReadAttestations(attestationsPath)
+	require.ErrorContains(t, err, `invalid attestation in `+attestationsPath+`: attestation of check no_answer has answer ""`)
+}
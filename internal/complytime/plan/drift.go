@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+)
+
+// ChangeType describes how an item of the assessment plan differs from the current content.
+type ChangeType string
+
+const (
+	// ChangeAdded is used for items in the current content that are not in the assessment plan.
+	ChangeAdded ChangeType = "added"
+	// ChangeRemoved is used for items of the assessment plan that are no longer in the current content.
+	ChangeRemoved ChangeType = "removed"
+	// ChangeChanged is used for items that differ between the assessment plan and the current content.
+	ChangeChanged ChangeType = "changed"
+)
+
+// Kinds of assessment plan items compared with the current content.
+const (
+	KindFramework = "framework"
+	KindRule      = "rule"
+	KindParameter = "parameter"
+)
+
+// Change is a difference between an assessment plan and the current content.
+type Change struct {
+	// Type is how the item differs.
+	Type ChangeType `json:"type"`
+	// Kind is the kind of the item, one of framework, rule, or parameter.
+	Kind string `json:"kind"`
+	// ID identifies the item. Parameters are identified by rule and parameter ID.
+	ID string `json:"id"`
+	// Detail describes a changed item.
+	Detail string `json:"detail,omitempty"`
+}
+
+func (c Change) String() string {
+	if c.Detail == "" {
+		return fmt.Sprintf("%s %s %s", c.Kind, c.ID, c.Type)
+	}
+	return fmt.Sprintf("%s %s %s: %s", c.Kind, c.ID, c.Type, c.Detail)
+}
+
+// Validate compares the activities, rules, and parameters of the assessment plan with an
+// assessment plan generated from the given component definitions for the same frameworks.
+// The values of the parameters selected by the given scopes, the scopes applied to the
+// assessment plan, are not compared with the defaults. The differences are returned sorted
+// by kind and ID.
+func Validate(ctx context.Context, assessmentPlan *oscalTypes.AssessmentPlan, cds []oscalTypes.ComponentDefinition, scopes []AssessmentScope) ([]Change, error) {
+	frameworkIDs := Frameworks(assessmentPlan)
+	if len(frameworkIDs) == 0 {
+		return nil, fmt.Errorf("assessment plan has no %s property", extensions.FrameworkProp)
+	}
+
+	var changes []Change
+	var currentFrameworks []string
+	for _, frameworkID := range frameworkIDs {
+		if _, err := NewAssessmentPlan(ctx, cds, frameworkID); err != nil {
+			changes = append(changes, Change{Type: ChangeRemoved, Kind: KindFramework, ID: frameworkID, Detail: err.Error()})
+			continue
+		}
+		currentFrameworks = append(currentFrameworks, frameworkID)
+	}
+	if len(currentFrameworks) == 0 {
+		return changes, nil
+	}
+	currentPlan, err := NewAssessmentPlan(ctx, cds, currentFrameworks...)
+	if err != nil {
+		return nil, err
+	}
+	if len(frameworkIDs) > 1 && len(currentFrameworks) == 1 {
+		// Plans of a single framework have untagged control selections
+		tagControlSelections(currentPlan, currentFrameworks[0])
+	}
+
+	selectedParameters := make(map[string]bool)
+	for _, scope := range scopes {
+		for _, entry := range scope.SelectParameters {
+			selectedParameters[entry.Name] = true
+		}
+	}
+	planned := activitiesByRule(assessmentPlan)
+	current := activitiesByRule(currentPlan)
+	for ruleID, plannedActivity := range planned {
+		currentActivity, found := current[ruleID]
+		if !found {
+			changes = append(changes, Change{Type: ChangeRemoved, Kind: KindRule, ID: ruleID})
+			continue
+		}
+		changes = append(changes, compareActivities(ruleID, plannedActivity, currentActivity, currentFrameworks, selectedParameters)...)
+	}
+	for ruleID := range current {
+		if _, found := planned[ruleID]; !found {
+			changes = append(changes, Change{Type: ChangeAdded, Kind: KindRule, ID: ruleID})
+		}
+	}
+
+	kindOrder := []string{KindFramework, KindRule, KindParameter}
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return slices.Index(kindOrder, changes[i].Kind) < slices.Index(kindOrder, changes[j].Kind)
+		}
+		return changes[i].ID < changes[j].ID
+	})
+	return changes, nil
+}
+
+// activitiesByRule returns the first activity of each rule in the assessment plan.
+func activitiesByRule(assessmentPlan *oscalTypes.AssessmentPlan) map[string]oscalTypes.Activity {
+	activities := make(map[string]oscalTypes.Activity)
+	if assessmentPlan.LocalDefinitions == nil || assessmentPlan.LocalDefinitions.Activities == nil {
+		return activities
+	}
+	for _, activity := range *assessmentPlan.LocalDefinitions.Activities {
+		if _, found := activities[activity.Title]; !found {
+			activities[activity.Title] = activity
+		}
+	}
+	return activities
+}
+
+// compareActivities returns the changes between the planned and current activity of a rule.
+// The values of the selected parameters are not compared, as the scope sets them.
+func compareActivities(ruleID string, planned, current oscalTypes.Activity, frameworkIDs []string, selectedParameters map[string]bool) []Change {
+	var changes []Change
+	if planned.Description != current.Description {
+		changes = append(changes, Change{Type: ChangeChanged, Kind: KindRule, ID: ruleID, Detail: "description changed"})
+	}
+	plannedChecks, currentChecks := activityChecks(planned), activityChecks(current)
+	if !slices.Equal(plannedChecks, currentChecks) {
+		changes = append(changes, Change{
+			Type:   ChangeChanged,
+			Kind:   KindRule,
+			ID:     ruleID,
+			Detail: fmt.Sprintf("checks changed from %s to %s", listOrNone(plannedChecks), listOrNone(currentChecks)),
+		})
+	}
+
+	// Controls removed by a scope are not in the plan, so only controls that are no longer
+	// implemented by the rule are reported.
+	for _, frameworkID := range frameworkIDs {
+		currentControls := activityControls(current, frameworkID)
+		var removed []string
+		for _, controlID := range activityControls(planned, frameworkID) {
+			if !slices.Contains(currentControls, controlID) {
+				removed = append(removed, controlID)
+			}
+		}
+		if len(removed) > 0 {
+			changes = append(changes, Change{
+				Type:   ChangeChanged,
+				Kind:   KindRule,
+				ID:     ruleID,
+				Detail: fmt.Sprintf("no longer implements %s controls %s", frameworkID, strings.Join(removed, ", ")),
+			})
+		}
+	}
+
+	plannedParameters, currentParameters := activityParameters(planned), activityParameters(current)
+	for name, plannedValue := range plannedParameters {
+		currentValue, found := currentParameters[name]
+		switch {
+		case !found:
+			changes = append(changes, Change{Type: ChangeRemoved, Kind: KindParameter, ID: ruleID + "/" + name})
+		case plannedValue != currentValue && !selectedParameters[name]:
+			changes = append(changes, Change{
+				Type:   ChangeChanged,
+				Kind:   KindParameter,
+				ID:     ruleID + "/" + name,
+				Detail: fmt.Sprintf("default value changed from %q to %q", plannedValue, currentValue),
+			})
+		}
+	}
+	for name, value := range currentParameters {
+		if _, found := plannedParameters[name]; !found {
+			changes = append(changes, Change{Type: ChangeAdded, Kind: KindParameter, ID: ruleID + "/" + name, Detail: fmt.Sprintf("default value %q", value)})
+		}
+	}
+	return changes
+}
+
+// activityChecks returns the sorted check IDs of the activity steps.
+func activityChecks(activity oscalTypes.Activity) []string {
+	var checks []string
+	if activity.Steps == nil {
+		return checks
+	}
+	for _, step := range *activity.Steps {
+		checks = append(checks, step.Title)
+	}
+	sort.Strings(checks)
+	return checks
+}
+
+// activityControls returns the sorted controls of the activity for the given framework.
+func activityControls(activity oscalTypes.Activity, frameworkID string) []string {
+	var controls []string
+	if activity.RelatedControls == nil {
+		return controls
+	}
+	for _, controlSelection := range frameworkSelections(activity.RelatedControls.ControlSelections, frameworkID) {
+		if controlSelection.IncludeControls == nil {
+			continue
+		}
+		for _, control := range *controlSelection.IncludeControls {
+			controls = append(controls, control.ControlId)
+		}
+	}
+	sort.Strings(controls)
+	return controls
+}
+
+// activityParameters returns the values of the activity test parameters by name.
+func activityParameters(activity oscalTypes.Activity) map[string]string {
+	parameters := make(map[string]string)
+	if activity.Props == nil {
+		return parameters
+	}
+	for _, prop := range *activity.Props {
+		if prop.Class == extensions.TestParameterClass {
+			parameters[prop.Name] = prop.Value
+		}
+	}
+	return parameters
+}
+
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
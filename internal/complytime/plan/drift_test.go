@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package plan
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	planPath := filepath.Join(t.TempDir(), "assessment-plan.json")
+	assessmentPlan, err := NewAssessmentPlan(context.Background(), testComponentDefinitions(t), "example", "baseline")
+	require.NoError(t, err)
+	require.NoError(t, WritePlan(assessmentPlan, []string{"example", "baseline"}, planPath))
+	assessmentPlan, err = ReadPlan(planPath, validation.NoopValidator{})
+	require.NoError(t, err)
+
+	changes, err := Validate(context.Background(), assessmentPlan, testComponentDefinitions(t), nil)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	changes, err = Validate(context.Background(), assessmentPlan, readComponentDefinitions(t, "testdata/updated-component-definition.json"), nil)
+	require.NoError(t, err)
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.String())
+	}
+	require.Equal(t, []string{
+		"rule rule-1 changed: description changed",
+		"rule rule-1 changed: no longer implements baseline controls base-1",
+		"rule rule-2 removed",
+		"rule rule-3 added",
+		`parameter rule-1/param-1 added: default value "value-1"`,
+	}, got)
+
+	// Frameworks removed from the content are reported
+	single, err := NewAssessmentPlan(context.Background(), testComponentDefinitions(t), "baseline")
+	require.NoError(t, err)
+	require.NoError(t, WritePlan(single, []string{"baseline"}, planPath))
+	single, err = ReadPlan(planPath, validation.NoopValidator{})
+	require.NoError(t, err)
+	cds := testComponentDefinitions(t)
+	implementations := *(*cds[0].Components)[0].ControlImplementations
+	*(*cds[0].Components)[0].ControlImplementations = implementations[:1]
+	changes, err = Validate(context.Background(), single, cds, nil)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, Change{
+		Type:   ChangeRemoved,
+		Kind:   KindFramework,
+		ID:     "baseline",
+		Detail: "cannot transform definitions for framework baseline: framework baseline is not in control implementations",
+	}, changes[0])
+}
+
+func TestValidateParameterDefaults(t *testing.T) {
+	planPath := filepath.Join(t.TempDir(), "assessment-plan.json")
+	cds := readComponentDefinitions(t, "testdata/updated-component-definition.json")
+	assessmentPlan, err := NewAssessmentPlan(context.Background(), cds, "example")
+	require.NoError(t, err)
+	require.NoError(t, WritePlan(assessmentPlan, []string{"example"}, planPath))
+	assessmentPlan, err = ReadPlan(planPath, validation.NoopValidator{})
+	require.NoError(t, err)
+
+	// Change the default value of param-1 in the current content
+	props := *(*cds[0].Components)[0].Props
+	for i := range props {
+		if props[i].Name == extensions.ParameterDefaultProp {
+			props[i].Value = "value-2"
+		}
+	}
+	changes, err := Validate(context.Background(), assessmentPlan, cds, nil)
+	require.NoError(t, err)
+	require.Equal(t, []Change{{
+		Type:   ChangeChanged,
+		Kind:   KindParameter,
+		ID:     "rule-1/param-1",
+		Detail: `default value changed from "value-1" to "value-2"`,
+	}}, changes)
+
+	// Values selected by the scope of the plan are not defaults
+	scopes := []AssessmentScope{{FrameworkID: "example", SelectParameters: []ParameterEntry{{Name: "param-1", Value: "value-1"}}}}
+	changes, err = Validate(context.Background(), assessmentPlan, cds, scopes)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
@@ -0,0 +1,368 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/transformers"
+)
+
+// NewAssessmentPlan generates an OSCAL Assessment Plan from the given OSCAL Component Definitions
+// for one or more frameworks. When several frameworks are given, the plans of each framework are
+// merged so each rule activity is assessed once. Each control selection of a merged plan is tagged
+// with the framework it was generated for. A rule with different parameter values in two frameworks
+// cannot be assessed once, so it is an error.
+func NewAssessmentPlan(ctx context.Context, cds []oscalTypes.ComponentDefinition, frameworkIDs ...string) (*oscalTypes.AssessmentPlan, error) {
+	if len(frameworkIDs) == 0 {
+		return nil, errors.New("at least one framework is required")
+	}
+	var assessmentPlan *oscalTypes.AssessmentPlan
+	for _, frameworkID := range frameworkIDs {
+		frameworkPlan, err := transformers.ComponentDefinitionsToAssessmentPlan(ctx, cds, frameworkID)
+		if err != nil {
+			return nil, err
+		}
+		if len(frameworkIDs) == 1 {
+			return frameworkPlan, nil
+		}
+		tagControlSelections(frameworkPlan, frameworkID)
+		if assessmentPlan == nil {
+			assessmentPlan = frameworkPlan
+			continue
+		}
+		if err := mergePlan(assessmentPlan, frameworkPlan, frameworkID); err != nil {
+			return nil, err
+		}
+	}
+	return assessmentPlan, nil
+}
+
+// Frameworks returns the framework identifiers recorded in the assessment plan metadata.
+func Frameworks(assessmentPlan *oscalTypes.AssessmentPlan) []string {
+	if assessmentPlan.Metadata.Props == nil {
+		return nil
+	}
+	var frameworkIDs []string
+	for _, prop := range extensions.FindAllProps(*assessmentPlan.Metadata.Props, extensions.WithName(extensions.FrameworkProp)) {
+		frameworkIDs = append(frameworkIDs, prop.Value)
+	}
+	return frameworkIDs
+}
+
+// ForFramework returns a copy of the assessment plan with only the control selections of the given
+// framework. Activities without controls of the framework are removed. Control selections without a
+// framework property belong to every framework of the plan.
+func ForFramework(assessmentPlan *oscalTypes.AssessmentPlan, frameworkID string) (*oscalTypes.AssessmentPlan, error) {
+	data, err := json.Marshal(assessmentPlan)
+	if err != nil {
+		return nil, fmt.Errorf("error copying assessment plan: %w", err)
+	}
+	var frameworkPlan oscalTypes.AssessmentPlan
+	if err := json.Unmarshal(data, &frameworkPlan); err != nil {
+		return nil, fmt.Errorf("error copying assessment plan: %w", err)
+	}
+
+	if frameworkPlan.Metadata.Props != nil {
+		var props []oscalTypes.Property
+		for _, prop := range *frameworkPlan.Metadata.Props {
+			if prop.Name == extensions.FrameworkProp && prop.Value != frameworkID {
+				continue
+			}
+			props = append(props, prop)
+		}
+		frameworkPlan.Metadata.Props = &props
+	}
+
+	frameworkPlan.ReviewedControls.ControlSelections = frameworkSelections(frameworkPlan.ReviewedControls.ControlSelections, frameworkID)
+
+	if frameworkPlan.LocalDefinitions == nil || frameworkPlan.LocalDefinitions.Activities == nil {
+		return &frameworkPlan, nil
+	}
+	removed := make(map[string]struct{})
+	var activities []oscalTypes.Activity
+	for _, activity := range *frameworkPlan.LocalDefinitions.Activities {
+		if activity.RelatedControls != nil {
+			activity.RelatedControls.ControlSelections = frameworkSelections(activity.RelatedControls.ControlSelections, frameworkID)
+			if len(activity.RelatedControls.ControlSelections) == 0 {
+				removed[activity.UUID] = struct{}{}
+				continue
+			}
+		}
+		activities = append(activities, activity)
+	}
+	frameworkPlan.LocalDefinitions.Activities = &activities
+
+	if frameworkPlan.Tasks != nil {
+		for taskI := range *frameworkPlan.Tasks {
+			task := &(*frameworkPlan.Tasks)[taskI]
+			if task.AssociatedActivities == nil {
+				continue
+			}
+			var associated []oscalTypes.AssociatedActivity
+			for _, associatedActivity := range *task.AssociatedActivities {
+				if _, found := removed[associatedActivity.ActivityUuid]; !found {
+					associated = append(associated, associatedActivity)
+				}
+			}
+			task.AssociatedActivities = &associated
+		}
+	}
+	return &frameworkPlan, nil
+}
+
+// selectionFramework returns the framework a control selection was generated for.
+func selectionFramework(controlSelection oscalTypes.AssessedControls) (string, bool) {
+	if controlSelection.Props == nil {
+		return "", false
+	}
+	frameworkProp, found := extensions.GetTrestleProp(extensions.FrameworkProp, *controlSelection.Props)
+	if !found {
+		return "", false
+	}
+	return frameworkProp.Value, true
+}
+
+// selectionInFramework returns whether the control selection belongs to the given framework.
+func selectionInFramework(controlSelection oscalTypes.AssessedControls, frameworkID string) bool {
+	selectionFrameworkID, found := selectionFramework(controlSelection)
+	return !found || selectionFrameworkID == frameworkID
+}
+
+func frameworkSelections(controlSelections []oscalTypes.AssessedControls, frameworkID string) []oscalTypes.AssessedControls {
+	var selections []oscalTypes.AssessedControls
+	for _, controlSelection := range controlSelections {
+		if selectionInFramework(controlSelection, frameworkID) {
+			selections = append(selections, controlSelection)
+		}
+	}
+	return selections
+}
+
+// tagControlSelections adds the framework property to the control selections of the assessment plan.
+func tagControlSelections(assessmentPlan *oscalTypes.AssessmentPlan, frameworkID string) {
+	tag := func(controlSelections []oscalTypes.AssessedControls) {
+		for i := range controlSelections {
+			controlSelection := &controlSelections[i]
+			if controlSelection.Props == nil {
+				controlSelection.Props = &[]oscalTypes.Property{}
+			}
+			*controlSelection.Props = append(*controlSelection.Props, oscalTypes.Property{
+				Name:  extensions.FrameworkProp,
+				Value: frameworkID,
+				Ns:    extensions.TrestleNameSpace,
+			})
+		}
+	}
+	tag(assessmentPlan.ReviewedControls.ControlSelections)
+	if assessmentPlan.LocalDefinitions == nil || assessmentPlan.LocalDefinitions.Activities == nil {
+		return
+	}
+	for _, activity := range *assessmentPlan.LocalDefinitions.Activities {
+		if activity.RelatedControls != nil {
+			tag(activity.RelatedControls.ControlSelections)
+		}
+	}
+}
+
+// mergePlan merges the activities, subjects, and control selections of the src assessment plan
+// generated for the given framework into the dst assessment plan. Activities for rules already in
+// dst gain the control selections of src instead of being added again, and must have the same
+// test parameter values.
+func mergePlan(dst, src *oscalTypes.AssessmentPlan, frameworkID string) error {
+	dst.ReviewedControls.ControlSelections = append(dst.ReviewedControls.ControlSelections, src.ReviewedControls.ControlSelections...)
+
+	if dst.LocalDefinitions == nil {
+		dst.LocalDefinitions = &oscalTypes.LocalDefinitions{}
+	}
+	if dst.LocalDefinitions.Activities == nil {
+		dst.LocalDefinitions.Activities = &[]oscalTypes.Activity{}
+	}
+	activityByTitle := make(map[string]string)
+	for _, activity := range *dst.LocalDefinitions.Activities {
+		if _, found := activityByTitle[activity.Title]; !found {
+			activityByTitle[activity.Title] = activity.UUID
+		}
+	}
+
+	// Map each src activity to the dst activity assessing the same rule
+	activityUUIDs := make(map[string]string)
+	mergedRules := make(map[string]struct{})
+	if src.LocalDefinitions != nil && src.LocalDefinitions.Activities != nil {
+		for _, activity := range *src.LocalDefinitions.Activities {
+			dstUUID, found := activityByTitle[activity.Title]
+			if !found {
+				*dst.LocalDefinitions.Activities = append(*dst.LocalDefinitions.Activities, activity)
+				activityByTitle[activity.Title] = activity.UUID
+				activityUUIDs[activity.UUID] = activity.UUID
+				mergedRules[activity.Title] = struct{}{}
+				continue
+			}
+			activityUUIDs[activity.UUID] = dstUUID
+			if err := checkParameters(dst, dstUUID, activity, frameworkID); err != nil {
+				return err
+			}
+			if _, merged := mergedRules[activity.Title]; merged || activity.RelatedControls == nil {
+				continue
+			}
+			mergedRules[activity.Title] = struct{}{}
+			for i := range *dst.LocalDefinitions.Activities {
+				dstActivity := &(*dst.LocalDefinitions.Activities)[i]
+				if dstActivity.Title != activity.Title {
+					continue
+				}
+				if dstActivity.RelatedControls == nil {
+					dstActivity.RelatedControls = &oscalTypes.ReviewedControls{}
+				}
+				dstActivity.RelatedControls.ControlSelections = append(dstActivity.RelatedControls.ControlSelections, activity.RelatedControls.ControlSelections...)
+			}
+		}
+		if src.LocalDefinitions.Components != nil {
+			if dst.LocalDefinitions.Components == nil {
+				dst.LocalDefinitions.Components = &[]oscalTypes.SystemComponent{}
+			}
+			known := make(map[string]struct{})
+			for _, component := range *dst.LocalDefinitions.Components {
+				known[component.UUID] = struct{}{}
+			}
+			for _, component := range *src.LocalDefinitions.Components {
+				if _, found := known[component.UUID]; !found {
+					*dst.LocalDefinitions.Components = append(*dst.LocalDefinitions.Components, component)
+				}
+			}
+		}
+	}
+
+	if dst.AssessmentSubjects != nil && src.AssessmentSubjects != nil {
+		*dst.AssessmentSubjects = mergeSubjects(*dst.AssessmentSubjects, *src.AssessmentSubjects)
+	}
+	if dst.Tasks == nil || src.Tasks == nil || len(*dst.Tasks) == 0 {
+		return nil
+	}
+	// Plans generated from component definitions have a single task for the rule activities
+	dstTask := &(*dst.Tasks)[0]
+	for _, srcTask := range *src.Tasks {
+		if srcTask.Subjects != nil {
+			if dstTask.Subjects == nil {
+				dstTask.Subjects = &[]oscalTypes.AssessmentSubject{}
+			}
+			*dstTask.Subjects = mergeSubjects(*dstTask.Subjects, *srcTask.Subjects)
+		}
+		if srcTask.AssociatedActivities == nil {
+			continue
+		}
+		if dstTask.AssociatedActivities == nil {
+			dstTask.AssociatedActivities = &[]oscalTypes.AssociatedActivity{}
+		}
+		associated := make(map[string]struct{})
+		for _, associatedActivity := range *dstTask.AssociatedActivities {
+			associated[associationKey(associatedActivity)] = struct{}{}
+		}
+		for _, associatedActivity := range *srcTask.AssociatedActivities {
+			associatedActivity.ActivityUuid = activityUUIDs[associatedActivity.ActivityUuid]
+			key := associationKey(associatedActivity)
+			if _, found := associated[key]; found {
+				continue
+			}
+			associated[key] = struct{}{}
+			*dstTask.AssociatedActivities = append(*dstTask.AssociatedActivities, associatedActivity)
+		}
+	}
+	return nil
+}
+
+// checkParameters returns an error when the src activity, generated for the given framework, sets a
+// test parameter to another value than the dst activity with the given UUID assessing the same rule.
+func checkParameters(dst *oscalTypes.AssessmentPlan, dstUUID string, activity oscalTypes.Activity, frameworkID string) error {
+	for _, dstActivity := range *dst.LocalDefinitions.Activities {
+		if dstActivity.UUID != dstUUID {
+			continue
+		}
+		dstParameters := activityParameters(dstActivity)
+		srcParameters := activityParameters(activity)
+		names := make([]string, 0, len(srcParameters))
+		for name := range srcParameters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			dstValue, found := dstParameters[name]
+			if !found || dstValue == srcParameters[name] {
+				continue
+			}
+			dstFrameworkID := "a previous framework"
+			if dstActivity.RelatedControls != nil && len(dstActivity.RelatedControls.ControlSelections) > 0 {
+				if selectionFrameworkID, found := selectionFramework(dstActivity.RelatedControls.ControlSelections[0]); found {
+					dstFrameworkID = "framework " + selectionFrameworkID
+				}
+			}
+			return fmt.Errorf("rule %s sets parameter %s to %q in %s and %q in framework %s",
+				activity.Title, name, dstValue, dstFrameworkID, srcParameters[name], frameworkID)
+		}
+	}
+	return nil
+}
+
+// mergeSubjects adds the subjects selected in src to the matching subjects of dst by type.
+// The result does not share included subjects with dst.
+func mergeSubjects(dst, src []oscalTypes.AssessmentSubject) []oscalTypes.AssessmentSubject {
+	merged := make([]oscalTypes.AssessmentSubject, len(dst))
+	for i, subject := range dst {
+		if subject.IncludeSubjects != nil {
+			includeSubjects := append([]oscalTypes.SelectSubjectById{}, *subject.IncludeSubjects...)
+			subject.IncludeSubjects = &includeSubjects
+		}
+		merged[i] = subject
+	}
+	for _, srcSubject := range src {
+		i := 0
+		for ; i < len(merged); i++ {
+			if merged[i].Type == srcSubject.Type {
+				break
+			}
+		}
+		if i == len(merged) {
+			merged = append(merged, srcSubject)
+			continue
+		}
+		if srcSubject.IncludeSubjects == nil {
+			continue
+		}
+		if merged[i].IncludeSubjects == nil {
+			merged[i].IncludeSubjects = &[]oscalTypes.SelectSubjectById{}
+		}
+		for _, selector := range *srcSubject.IncludeSubjects {
+			found := false
+			for _, existing := range *merged[i].IncludeSubjects {
+				if existing.SubjectUuid == selector.SubjectUuid {
+					found = true
+					break
+				}
+			}
+			if !found {
+				*merged[i].IncludeSubjects = append(*merged[i].IncludeSubjects, selector)
+			}
+		}
+	}
+	return merged
+}
+
+// associationKey identifies an associated activity by its activity and subjects.
+func associationKey(associatedActivity oscalTypes.AssociatedActivity) string {
+	key := associatedActivity.ActivityUuid
+	for _, subject := range associatedActivity.Subjects {
+		if subject.IncludeSubjects == nil {
+			continue
+		}
+		for _, selector := range *subject.IncludeSubjects {
+			key += "/" + selector.SubjectUuid
+		}
+	}
+	return key
+}
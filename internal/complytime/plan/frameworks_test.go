@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/transformers"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+)
+
+func testComponentDefinitions(t *testing.T) []oscalTypes.ComponentDefinition {
+	return readComponentDefinitions(t, "testdata/multi-framework-component-definition.json")
+}
+
+func readComponentDefinitions(t *testing.T, path string) []oscalTypes.ComponentDefinition {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var models oscalTypes.OscalModels
+	require.NoError(t, json.Unmarshal(data, &models))
+	return []oscalTypes.ComponentDefinition{*models.ComponentDefinition}
+}
+
+// selectedControls returns the selected controls of each control selection by framework.
+func selectedControls(controlSelections []oscalTypes.AssessedControls) map[string][]string {
+	controls := make(map[string][]string)
+	for _, controlSelection := range controlSelections {
+		frameworkID, _ := selectionFramework(controlSelection)
+		if controlSelection.IncludeControls == nil {
+			continue
+		}
+		for _, control := range *controlSelection.IncludeControls {
+			controls[frameworkID] = append(controls[frameworkID], control.ControlId)
+		}
+		sort.Strings(controls[frameworkID])
+	}
+	return controls
+}
+
+func planActivityControls(assessmentPlan *oscalTypes.AssessmentPlan) map[string]map[string][]string {
+	controls := make(map[string]map[string][]string)
+	for _, activity := range *assessmentPlan.LocalDefinitions.Activities {
+		if activity.RelatedControls == nil {
+			controls[activity.Title] = nil
+			continue
+		}
+		controls[activity.Title] = selectedControls(activity.RelatedControls.ControlSelections)
+	}
+	return controls
+}
+
+func TestNewAssessmentPlan(t *testing.T) {
+	cds := testComponentDefinitions(t)
+
+	_, err := NewAssessmentPlan(context.Background(), cds)
+	require.EqualError(t, err, "at least one framework is required")
+
+	single, err := NewAssessmentPlan(context.Background(), cds, "example")
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string][]string{
+		"rule-1": {"": {"example-1"}},
+	}, planActivityControls(single))
+
+	assessmentPlan, err := NewAssessmentPlan(context.Background(), cds, "example", "baseline")
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string][]string{
+		"rule-1": {"example": {"example-1"}, "baseline": {"base-1"}},
+		"rule-2": {"baseline": {"base-2"}},
+	}, planActivityControls(assessmentPlan))
+	require.Equal(t, map[string][]string{
+		"example":  {"example-1"},
+		"baseline": {"base-1", "base-2"},
+	}, selectedControls(assessmentPlan.ReviewedControls.ControlSelections))
+
+	// Each rule is assessed once for the component
+	require.Len(t, *(*assessmentPlan.Tasks)[0].AssociatedActivities, 2)
+	require.Len(t, *(*assessmentPlan.AssessmentSubjects)[0].IncludeSubjects, 1)
+	require.Len(t, *assessmentPlan.LocalDefinitions.Components, 1)
+
+	planPath := filepath.Join(t.TempDir(), "assessment-plan.json")
+	require.NoError(t, WritePlan(assessmentPlan, []string{"example", "baseline"}, planPath))
+	assessmentPlan, err = ReadPlan(planPath, validation.NewSchemaValidator())
+	require.NoError(t, err)
+	require.Equal(t, []string{"example", "baseline"}, Frameworks(assessmentPlan))
+
+	_, err = NewAssessmentPlan(context.Background(), cds, "example", "unknown")
+	require.ErrorContains(t, err, "cannot transform definitions for framework unknown")
+}
+
+func TestMergePlanParameters(t *testing.T) {
+	cds := testComponentDefinitions(t)
+	frameworkPlan := func(frameworkID, value string) *oscalTypes.AssessmentPlan {
+		assessmentPlan, err := transformers.ComponentDefinitionsToAssessmentPlan(context.Background(), cds, frameworkID)
+		require.NoError(t, err)
+		tagControlSelections(assessmentPlan, frameworkID)
+		for i := range *assessmentPlan.LocalDefinitions.Activities {
+			activity := &(*assessmentPlan.LocalDefinitions.Activities)[i]
+			if activity.Title == "rule-1" {
+				*activity.Props = append(*activity.Props, oscalTypes.Property{
+					Name:  "param-1",
+					Value: value,
+					Ns:    extensions.TrestleNameSpace,
+					Class: extensions.TestParameterClass,
+				})
+			}
+		}
+		return assessmentPlan
+	}
+
+	require.NoError(t, mergePlan(frameworkPlan("example", "value-1"), frameworkPlan("baseline", "value-1"), "baseline"))
+	err := mergePlan(frameworkPlan("example", "value-1"), frameworkPlan("baseline", "value-2"), "baseline")
+	require.EqualError(t, err, `rule rule-1 sets parameter param-1 to "value-1" in framework example and "value-2" in framework baseline`)
+}
+
+func TestForFramework(t *testing.T) {
+	assessmentPlan, err := NewAssessmentPlan(context.Background(), testComponentDefinitions(t), "example", "baseline")
+	require.NoError(t, err)
+	require.NoError(t, WritePlan(assessmentPlan, []string{"example", "baseline"}, filepath.Join(t.TempDir(), "assessment-plan.json")))
+
+	examplePlan, err := ForFramework(assessmentPlan, "example")
+	require.NoError(t, err)
+	require.Equal(t, []string{"example"}, Frameworks(examplePlan))
+	require.Equal(t, map[string]map[string][]string{
+		"rule-1": {"example": {"example-1"}},
+	}, planActivityControls(examplePlan))
+	require.Equal(t, map[string][]string{
+		"example": {"example-1"},
+	}, selectedControls(examplePlan.ReviewedControls.ControlSelections))
+	require.Len(t, *(*examplePlan.Tasks)[0].AssociatedActivities, 1)
+
+	// The original plan is not altered
+	require.Len(t, *assessmentPlan.LocalDefinitions.Activities, 2)
+	require.Equal(t, []string{"example", "baseline"}, Frameworks(assessmentPlan))
+}
+
+func TestAssessmentScope_ApplyScopeFrameworks(t *testing.T) {
+	assessmentPlan, err := NewAssessmentPlan(context.Background(), testComponentDefinitions(t), "example", "baseline")
+	require.NoError(t, err)
+
+	// Scoping out controls of one framework keeps the rule in scope for the other framework
+	scope := AssessmentScope{
+		FrameworkID: "baseline",
+		IncludeControls: []ControlEntry{
+			{ControlID: "base-2", Rules: []string{IncludeAllRules}},
+		},
+	}
+	scope.ApplyScope(assessmentPlan, hclog.NewNullLogger())
+	require.Equal(t, map[string]map[string][]string{
+		"rule-1": {"example": {"example-1"}},
+		"rule-2": {"baseline": {"base-2"}},
+	}, planActivityControls(assessmentPlan))
+	require.Equal(t, map[string][]string{
+		"example":  {"example-1"},
+		"baseline": {"base-2"},
+	}, selectedControls(assessmentPlan.ReviewedControls.ControlSelections))
+
+	// Rules are skipped once no framework includes them
+	scope = AssessmentScope{
+		FrameworkID:     "example",
+		IncludeControls: []ControlEntry{},
+	}
+	scope.ApplyScope(assessmentPlan, hclog.NewNullLogger())
+	require.Equal(t, map[string]map[string][]string{
+		"rule-1": nil,
+		"rule-2": {"baseline": {"base-2"}},
+	}, planActivityControls(assessmentPlan))
+}
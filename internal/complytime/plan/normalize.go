@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package plan
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/defenseunicorns/go-oscal/src/pkg/uuid"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+)
+
+// Normalize makes a generated assessment plan reproducible. Activities, steps, test parameters, and
+// selected controls are sorted, the UUIDs generated for the plan, its tasks, activities, steps, and
+// assessment platforms are derived from the seed, and the last modified time is set to the given time.
+// Plans generated from the same content and scope and normalized with the same seed and time are identical.
+func Normalize(assessmentPlan *oscalTypes.AssessmentPlan, seed string, lastModified time.Time) {
+	newUUID := func(format string, args ...any) string {
+		return uuid.NewUUIDWithSource(seed + "/" + fmt.Sprintf(format, args...))
+	}
+	assessmentPlan.UUID = newUUID("assessment-plan")
+	assessmentPlan.Metadata.LastModified = lastModified
+	sortControlSelections(assessmentPlan.ReviewedControls.ControlSelections)
+
+	activityUUIDs := make(map[string]string)
+	if assessmentPlan.LocalDefinitions != nil && assessmentPlan.LocalDefinitions.Activities != nil {
+		activities := *assessmentPlan.LocalDefinitions.Activities
+		// Activities of the same rule for several components keep their component order
+		sort.SliceStable(activities, func(i, j int) bool { return activities[i].Title < activities[j].Title })
+		for i := range activities {
+			activity := &activities[i]
+			activityUUIDs[activity.UUID] = newUUID("activity/%d", i)
+			activity.UUID = activityUUIDs[activity.UUID]
+			if activity.Steps != nil {
+				steps := *activity.Steps
+				sort.SliceStable(steps, func(a, b int) bool { return steps[a].Title < steps[b].Title })
+				for j := range steps {
+					steps[j].UUID = newUUID("activity/%d/step/%d", i, j)
+				}
+			}
+			if activity.Props != nil {
+				sortTestParameters(*activity.Props)
+			}
+			if activity.RelatedControls != nil {
+				sortControlSelections(activity.RelatedControls.ControlSelections)
+			}
+		}
+	}
+
+	if assessmentPlan.Tasks != nil {
+		for i := range *assessmentPlan.Tasks {
+			task := &(*assessmentPlan.Tasks)[i]
+			task.UUID = newUUID("task/%d", i)
+			if task.AssociatedActivities == nil {
+				continue
+			}
+			associatedActivities := *task.AssociatedActivities
+			for j := range associatedActivities {
+				if activityUUID, found := activityUUIDs[associatedActivities[j].ActivityUuid]; found {
+					associatedActivities[j].ActivityUuid = activityUUID
+				}
+			}
+			// Activity UUIDs follow the activity order
+			sort.SliceStable(associatedActivities, func(a, b int) bool {
+				return activityIndex(assessmentPlan, associatedActivities[a].ActivityUuid) < activityIndex(assessmentPlan, associatedActivities[b].ActivityUuid)
+			})
+		}
+	}
+
+	if assessmentPlan.AssessmentAssets != nil {
+		for i := range assessmentPlan.AssessmentAssets.AssessmentPlatforms {
+			assessmentPlan.AssessmentAssets.AssessmentPlatforms[i].UUID = newUUID("assessment-platform/%d", i)
+		}
+	}
+}
+
+// activityIndex returns the position of the activity in the assessment plan, or -1 if it is not found.
+func activityIndex(assessmentPlan *oscalTypes.AssessmentPlan, activityUUID string) int {
+	if assessmentPlan.LocalDefinitions == nil || assessmentPlan.LocalDefinitions.Activities == nil {
+		return -1
+	}
+	for i, activity := range *assessmentPlan.LocalDefinitions.Activities {
+		if activity.UUID == activityUUID {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortControlSelections sorts the included controls of each control selection by ID.
+func sortControlSelections(controlSelections []oscalTypes.AssessedControls) {
+	for _, controlSelection := range controlSelections {
+		if controlSelection.IncludeControls == nil {
+			continue
+		}
+		controls := *controlSelection.IncludeControls
+		sort.SliceStable(controls, func(i, j int) bool { return controls[i].ControlId < controls[j].ControlId })
+	}
+}
+
+// sortTestParameters sorts the test parameter properties by name after the other properties.
+func sortTestParameters(props []oscalTypes.Property) {
+	sort.SliceStable(props, func(i, j int) bool {
+		iParameter, jParameter := props[i].Class == extensions.TestParameterClass, props[j].Class == extensions.TestParameterClass
+		if iParameter != jParameter {
+			return jParameter
+		}
+		return iParameter && props[i].Name < props[j].Name
+	})
+}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package plan
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	generated := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	writeNormalizedPlan := func(seed string) []byte {
+		assessmentPlan, err := NewAssessmentPlan(context.Background(), testComponentDefinitions(t), "example", "baseline")
+		require.NoError(t, err)
+		Normalize(assessmentPlan, seed, generated)
+		planPath := filepath.Join(t.TempDir(), "assessment-plan.json")
+		require.NoError(t, WritePlan(assessmentPlan, []string{"example", "baseline"}, planPath))
+		data, err := os.ReadFile(planPath)
+		require.NoError(t, err)
+		return data
+	}
+
+	// Plans generated from the same content with the same seed are identical
+	data := writeNormalizedPlan("seed")
+	require.Equal(t, string(data), string(writeNormalizedPlan("seed")))
+	require.NotEqual(t, string(data), string(writeNormalizedPlan("other")))
+
+	assessmentPlan, err := NewAssessmentPlan(context.Background(), testComponentDefinitions(t), "example", "baseline")
+	require.NoError(t, err)
+	Normalize(assessmentPlan, "seed", generated)
+	require.Equal(t, generated, assessmentPlan.Metadata.LastModified)
+	activities := *assessmentPlan.LocalDefinitions.Activities
+	require.Equal(t, "rule-1", activities[0].Title)
+	require.Equal(t, "rule-2", activities[1].Title)
+
+	// Associated activities refer to the activities by their new UUID
+	associatedActivities := *(*assessmentPlan.Tasks)[0].AssociatedActivities
+	require.Equal(t, activities[0].UUID, associatedActivities[0].ActivityUuid)
+	require.Equal(t, activities[1].UUID, associatedActivities[1].ActivityUuid)
+}
@@ -3,6 +3,7 @@
 package plan
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,26 +15,30 @@ import (
 	"github.com/oscal-compass/oscal-sdk-go/models"
 	"github.com/oscal-compass/oscal-sdk-go/settings"
 	"github.com/oscal-compass/oscal-sdk-go/validation"
+
+	"github.com/complytime/complyctl/internal/complytime/format"
 )
 
-// WritePlan writes an AssessmentPlan to a given path location with consistency.
-func WritePlan(plan *oscalTypes.AssessmentPlan, frameworkId string, planLocation string) error {
+// WritePlan writes an AssessmentPlan for the given frameworks to a given path location with consistency.
+func WritePlan(plan *oscalTypes.AssessmentPlan, frameworkIDs []string, planLocation string) error {
 	// Ensure UserWorkspace exists before writing the plan
 	userWorkspace := filepath.Dir(planLocation)
 	if err := os.MkdirAll(userWorkspace, 0700); err != nil {
 		return err
 	}
 
-	// Add the framework property needed for ComplyTime
+	// Add the framework properties needed for ComplyTime
 	if plan.Metadata.Props == nil {
 		plan.Metadata.Props = &[]oscalTypes.Property{}
 	}
-	frameworkProperty := oscalTypes.Property{
-		Name:  extensions.FrameworkProp,
-		Value: frameworkId,
-		Ns:    extensions.TrestleNameSpace,
+	for _, frameworkID := range frameworkIDs {
+		frameworkProperty := oscalTypes.Property{
+			Name:  extensions.FrameworkProp,
+			Value: frameworkID,
+			Ns:    extensions.TrestleNameSpace,
+		}
+		*plan.Metadata.Props = append(*plan.Metadata.Props, frameworkProperty)
 	}
-	*plan.Metadata.Props = append(*plan.Metadata.Props, frameworkProperty)
 
 	// To ensure we can easily read the plan once written, include under
 	// OSCAL Model type to include the top-level "assessment-plan" key.
@@ -48,15 +53,14 @@ func WritePlan(plan *oscalTypes.AssessmentPlan, frameworkId string, planLocation
 	return os.WriteFile(planLocation, assessmentPlanData, 0600)
 }
 
-// ReadPlan reads an assessment plans from a given file path.
+// ReadPlan reads an assessment plans from a given file path in the OSCAL JSON, YAML, or XML format.
 func ReadPlan(assessmentPlanPath string, validator validation.Validator) (*oscalTypes.AssessmentPlan, error) {
-	file, err := os.Open(assessmentPlanPath)
+	data, err := format.ReadFile(assessmentPlanPath)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	plan, err := models.NewAssessmentPlan(file, validator)
+	plan, err := models.NewAssessmentPlan(bytes.NewReader(data), validator)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load assessment plan from %s: %w", assessmentPlanPath, err)
 	}
@@ -28,7 +28,7 @@ func TestPlan(t *testing.T) {
 		},
 	}
 
-	err := WritePlan(&testPlan, "testid", testPlanPath)
+	err := WritePlan(&testPlan, []string{"testid"}, testPlanPath)
 	require.NoError(t, err)
 
 	ap, err := ReadPlan(testPlanPath, validation.NoopValidator{})
@@ -50,7 +50,7 @@ func TestPlan(t *testing.T) {
 	}
 	testPlan.LocalDefinitions = &localDefs
 
-	err = WritePlan(&testPlan, "testid", testPlanPath)
+	err = WritePlan(&testPlan, []string{"testid"}, testPlanPath)
 	require.NoError(t, err)
 
 	// read plan to ensure it has the expected props
@@ -11,10 +11,30 @@ import (
 	"github.com/oscal-compass/oscal-sdk-go/extensions"
 )
 
+// IncludeAllRules is the ControlEntry rule selection that includes every
+// rule associated with the control.
+const IncludeAllRules = "*"
+
 // ControlEntry represents a control in the assessment scope
 type ControlEntry struct {
-	ControlID string   `yaml:"controlId"`
-	Rules     []string `yaml:"includeRules"`
+	ControlID string   `yaml:"controlId" json:"controlId"`
+	Rules     []string `yaml:"includeRules" json:"includeRules"`
+}
+
+// IncludesRule returns whether the given rule is in scope for the control.
+func (c ControlEntry) IncludesRule(ruleID string) bool {
+	for _, rule := range c.Rules {
+		if rule == IncludeAllRules || rule == ruleID {
+			return true
+		}
+	}
+	return false
+}
+
+// ParameterEntry represents a rule parameter value in the assessment scope
+type ParameterEntry struct {
+	Name  string `yaml:"name" json:"name"`
+	Value string `yaml:"value" json:"value"`
 }
 
 // AssessmentScope sets up the yaml mapping type for writing to config file.
@@ -22,10 +42,13 @@ type ControlEntry struct {
 type AssessmentScope struct {
 	// FrameworkID is the identifier for the control set
 	// in the Assessment Plan.
-	FrameworkID string `yaml:"frameworkId"`
+	FrameworkID string `yaml:"frameworkId" json:"frameworkId"`
 	// IncludeControls defines controls that are in scope
 	// of an assessment.
-	IncludeControls []ControlEntry `yaml:"includeControls"`
+	IncludeControls []ControlEntry `yaml:"includeControls" json:"includeControls"`
+	// SelectParameters overrides the values of rule parameters
+	// in the Assessment Plan.
+	SelectParameters []ParameterEntry `yaml:"selectParameters,omitempty" json:"selectParameters,omitempty"`
 }
 
 // NewAssessmentScope creates an AssessmentScope struct for a given framework id.
@@ -75,7 +98,7 @@ func NewAssessmentScopeFromCDs(frameworkId string, cds ...oscalTypes.ComponentDe
 	for i, id := range controlIDs {
 		scope.IncludeControls[i] = ControlEntry{
 			ControlID: id,
-			Rules:     []string{"*"}, // by default, include all rules
+			Rules:     []string{IncludeAllRules}, // by default, include all rules
 		}
 	}
 	sort.Slice(scope.IncludeControls, func(i, j int) bool {
@@ -88,13 +111,13 @@ func NewAssessmentScopeFromCDs(frameworkId string, cds ...oscalTypes.ComponentDe
 // ApplyScope alters the given OSCAL Assessment Plan based on the AssessmentScope.
 func (a AssessmentScope) ApplyScope(assessmentPlan *oscalTypes.AssessmentPlan, logger hclog.Logger) {
 
-	// This is a thin wrapper right now, but the goal to expand to different areas
-	// of customization.
 	a.applyControlScope(assessmentPlan, logger)
+	a.applyParameterScope(assessmentPlan, logger)
 }
 
 // applyControlScope alters the AssessedControls of the given OSCAL Assessment Plan by the AssessmentScope
-// IncludeControls.
+// IncludeControls. Activities only keep the controls that include the activity rule. Only the control
+// selections of the AssessmentScope framework are altered.
 func (a AssessmentScope) applyControlScope(assessmentPlan *oscalTypes.AssessmentPlan, logger hclog.Logger) {
 	// "Any control specified within exclude-controls must first be within a range of explicitly
 	// included controls, via include-controls or include-all."
@@ -108,23 +131,20 @@ func (a AssessmentScope) applyControlScope(assessmentPlan *oscalTypes.Assessment
 		if assessmentPlan.LocalDefinitions.Activities != nil {
 			for activityI := range *assessmentPlan.LocalDefinitions.Activities {
 				activity := &(*assessmentPlan.LocalDefinitions.Activities)[activityI]
+				ruleControls := a.includedControlsForRule(activity.Title)
 				if activity.RelatedControls != nil && activity.RelatedControls.ControlSelections != nil {
-					controlSelections := activity.RelatedControls.ControlSelections
-					for controlSelectionI := range controlSelections {
-						controlSelection := &controlSelections[controlSelectionI]
-						filterControlSelection(controlSelection, includedControls)
-						if controlSelection.IncludeControls == nil {
-							activity.RelatedControls = nil
-							if activity.Props == nil {
-								activity.Props = &[]oscalTypes.Property{}
-							}
-							skippedActivity := oscalTypes.Property{
-								Name:  "skipped",
-								Value: "true",
-								Ns:    extensions.TrestleNameSpace,
-							}
-							*activity.Props = append(*activity.Props, skippedActivity)
+					activity.RelatedControls.ControlSelections = a.filterControlSelections(activity.RelatedControls.ControlSelections, ruleControls)
+					if activity.RelatedControls.ControlSelections == nil {
+						activity.RelatedControls = nil
+						if activity.Props == nil {
+							activity.Props = &[]oscalTypes.Property{}
+						}
+						skippedActivity := oscalTypes.Property{
+							Name:  "skipped",
+							Value: "true",
+							Ns:    extensions.TrestleNameSpace,
 						}
+						*activity.Props = append(*activity.Props, skippedActivity)
 					}
 				}
 
@@ -137,23 +157,21 @@ func (a AssessmentScope) applyControlScope(assessmentPlan *oscalTypes.Assessment
 						if step.ReviewedControls.ControlSelections == nil {
 							continue
 						}
-						controlSelections := step.ReviewedControls.ControlSelections
-						for controlSelectionI := range controlSelections {
-							controlSelection := &controlSelections[controlSelectionI]
-							filterControlSelection(controlSelection, includedControls)
-							if controlSelection.IncludeControls == nil {
+						step.ReviewedControls.ControlSelections = a.filterControlSelections(step.ReviewedControls.ControlSelections, ruleControls)
+						if step.ReviewedControls.ControlSelections == nil {
+							if activity.RelatedControls != nil {
 								activity.RelatedControls.ControlSelections = nil
-								step.ReviewedControls = nil
-								if step.Props == nil {
-									step.Props = &[]oscalTypes.Property{}
-								}
-								skipped := oscalTypes.Property{
-									Name:  "skipped",
-									Value: "true",
-									Ns:    extensions.TrestleNameSpace,
-								}
-								*step.Props = append(*step.Props, skipped)
 							}
+							step.ReviewedControls = nil
+							if step.Props == nil {
+								step.Props = &[]oscalTypes.Property{}
+							}
+							skipped := oscalTypes.Property{
+								Name:  "skipped",
+								Value: "true",
+								Ns:    extensions.TrestleNameSpace,
+							}
+							*step.Props = append(*step.Props, skipped)
 						}
 					}
 				}
@@ -163,11 +181,99 @@ func (a AssessmentScope) applyControlScope(assessmentPlan *oscalTypes.Assessment
 	if assessmentPlan.ReviewedControls.ControlSelections != nil {
 		for controlSelectionI := range assessmentPlan.ReviewedControls.ControlSelections {
 			controlSelection := &assessmentPlan.ReviewedControls.ControlSelections[controlSelectionI]
-			filterControlSelection(controlSelection, includedControls)
+			if selectionInFramework(*controlSelection, a.FrameworkID) {
+				filterControlSelection(controlSelection, includedControls)
+			}
 		}
 	}
 }
 
+// filterControlSelections filters the control selections of the AssessmentScope framework by the
+// included controls. Selections left without controls are removed, and nil is returned when no
+// selections remain.
+func (a AssessmentScope) filterControlSelections(controlSelections []oscalTypes.AssessedControls, includedControls includeControlsSet) []oscalTypes.AssessedControls {
+	var filtered []oscalTypes.AssessedControls
+	for _, controlSelection := range controlSelections {
+		if selectionInFramework(controlSelection, a.FrameworkID) {
+			filterControlSelection(&controlSelection, includedControls)
+			if controlSelection.IncludeControls == nil {
+				continue
+			}
+		}
+		filtered = append(filtered, controlSelection)
+	}
+	return filtered
+}
+
+// includedControlsForRule returns the controls of the AssessmentScope that include the given rule.
+func (a AssessmentScope) includedControlsForRule(ruleID string) includeControlsSet {
+	includedControls := includeControlsSet{}
+	for _, entry := range a.IncludeControls {
+		if entry.IncludesRule(ruleID) {
+			includedControls.Add(entry.ControlID)
+		}
+	}
+	return includedControls
+}
+
+// applyParameterScope sets the values of activity test parameters of the given OSCAL Assessment Plan
+// to the AssessmentScope SelectParameters.
+func (a AssessmentScope) applyParameterScope(assessmentPlan *oscalTypes.AssessmentPlan, logger hclog.Logger) {
+	if len(a.SelectParameters) == 0 {
+		return
+	}
+	if assessmentPlan.LocalDefinitions == nil || assessmentPlan.LocalDefinitions.Activities == nil {
+		return
+	}
+	selectedValues := make(map[string]string, len(a.SelectParameters))
+	for _, entry := range a.SelectParameters {
+		selectedValues[entry.Name] = entry.Value
+	}
+	for activityI := range *assessmentPlan.LocalDefinitions.Activities {
+		activity := &(*assessmentPlan.LocalDefinitions.Activities)[activityI]
+		if activity.Props == nil {
+			continue
+		}
+		for propI := range *activity.Props {
+			prop := &(*activity.Props)[propI]
+			if prop.Class != extensions.TestParameterClass {
+				continue
+			}
+			if value, found := selectedValues[prop.Name]; found {
+				logger.Debug("Setting parameter value", "rule", activity.Title, "parameter", prop.Name, "value", value)
+				prop.Value = value
+			}
+		}
+	}
+}
+
+// ParametersFromPlan returns the test parameters and their values from the activities
+// of the given OSCAL Assessment Plan, sorted by name.
+func ParametersFromPlan(assessmentPlan *oscalTypes.AssessmentPlan) []ParameterEntry {
+	if assessmentPlan.LocalDefinitions == nil || assessmentPlan.LocalDefinitions.Activities == nil {
+		return nil
+	}
+	values := make(map[string]string)
+	for _, activity := range *assessmentPlan.LocalDefinitions.Activities {
+		if activity.Props == nil {
+			continue
+		}
+		for _, prop := range *activity.Props {
+			if prop.Class == extensions.TestParameterClass {
+				values[prop.Name] = prop.Value
+			}
+		}
+	}
+	parameters := make([]ParameterEntry, 0, len(values))
+	for name, value := range values {
+		parameters = append(parameters, ParameterEntry{Name: name, Value: value})
+	}
+	sort.Slice(parameters, func(i, j int) bool {
+		return parameters[i].Name < parameters[j].Name
+	})
+	return parameters
+}
+
 func filterControlSelection(controlSelection *oscalTypes.AssessedControls, includedControls includeControlsSet) {
 	// The new included controls should be the intersection of
 	// the originally included controls and the newly included controls.
@@ -195,3 +195,56 @@ func TestAssessmentScope_ApplyScope(t *testing.T) {
 		})
 	}
 }
+
+func TestAssessmentScope_ApplyScopeActivities(t *testing.T) {
+	testLogger := hclog.NewNullLogger()
+
+	newActivity := func(ruleID string, paramValue string) oscalTypes.Activity {
+		return oscalTypes.Activity{
+			Title: ruleID,
+			Props: &[]oscalTypes.Property{
+				{Name: "param-1", Value: paramValue, Ns: extensions.TrestleNameSpace, Class: extensions.TestParameterClass},
+			},
+			RelatedControls: &oscalTypes.ReviewedControls{
+				ControlSelections: []oscalTypes.AssessedControls{
+					{
+						IncludeControls: &[]oscalTypes.AssessedControlsSelectControlById{
+							{ControlId: "example-1"},
+						},
+					},
+				},
+			},
+		}
+	}
+	basePlan := &oscalTypes.AssessmentPlan{
+		LocalDefinitions: &oscalTypes.LocalDefinitions{
+			Activities: &[]oscalTypes.Activity{
+				newActivity("rule-1", "default"),
+				newActivity("rule-2", "default"),
+			},
+		},
+	}
+	scope := AssessmentScope{
+		FrameworkID: "test",
+		IncludeControls: []ControlEntry{
+			{ControlID: "example-1", Rules: []string{"rule-1"}},
+		},
+		SelectParameters: []ParameterEntry{
+			{Name: "param-1", Value: "custom"},
+		},
+	}
+	scope.ApplyScope(basePlan, testLogger)
+
+	activities := *basePlan.LocalDefinitions.Activities
+	require.NotNil(t, activities[0].RelatedControls)
+	require.Equal(t, "custom", (*activities[0].Props)[0].Value)
+
+	require.Nil(t, activities[1].RelatedControls)
+	skipped, found := extensions.GetTrestleProp("skipped", *activities[1].Props)
+	require.True(t, found)
+	require.Equal(t, "true", skipped.Value)
+
+	wantParameters := []ParameterEntry{{Name: "param-1", Value: "custom"}}
+	require.Equal(t, wantParameters, ParametersFromPlan(basePlan))
+	require.Empty(t, ParametersFromPlan(&oscalTypes.AssessmentPlan{}))
+}
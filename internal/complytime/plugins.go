@@ -3,17 +3,21 @@
 package complytime
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/framework"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/framework/actions"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+
+	"github.com/complytime/complyctl/pkg/trace"
 )
 
 // PluginOptions defines global options all complytime plugins should
@@ -28,6 +32,9 @@ type PluginOptions struct {
 	// UserConfigRoot is the root directory where users customize
 	// plugin configuration options
 	UserConfigRoot string `config:"userconfigroot"`
+	// Overrides are option values of each plugin taking precedence over
+	// the user customized plugin configuration, indexed by plugin ID.
+	Overrides map[string]map[string]string
 }
 
 // NewPluginOptions created a new PluginOptions struct.
@@ -67,7 +74,7 @@ func (p PluginOptions) ToMap(pluginId string, logger hclog.Logger) (map[string]s
 		if err != nil {
 			if os.IsNotExist(err) {
 				logger.Debug(fmt.Sprintf("Plugin manifest file does not exist: %s", configPath))
-				return selections, nil
+				return p.applyOverrides(pluginId, selections), nil
 			}
 			return selections, fmt.Errorf("failed to open plugin config file: %w", err)
 		}
@@ -97,22 +104,52 @@ func (p PluginOptions) ToMap(pluginId string, logger hclog.Logger) (map[string]s
 
 		}
 	}
-	return selections, nil
+	return p.applyOverrides(pluginId, selections), nil
+}
+
+// applyOverrides sets the overridden option values of the plugin in the selections.
+// The workspace and profile options cannot be overridden.
+func (p PluginOptions) applyOverrides(pluginId string, selections map[string]string) map[string]string {
+	for name, value := range p.Overrides[pluginId] {
+		if name == "workspace" || name == "profile" {
+			continue
+		}
+		selections[name] = value
+	}
+	return selections
 }
 
-// Plugins launches and configures plugins with the given complytime global options. This function returns the plugin map with the
-// launched plugins, a plugin cleanup function, and an error. The cleanup function should be used if it is not nil.
-func Plugins(manager *framework.PluginManager, inputs *actions.InputContext, selections PluginOptions, logger hclog.Logger) (map[plugin.ID]policy.Provider, func(), error) {
-	manifests, err := manager.FindRequestedPlugins(inputs.RequestedProviders())
+// Plugins launches and configures plugins found in the content path with the given complytime global options. This function returns the
+// plugin map with the launched plugins, a plugin cleanup function, and an error. The cleanup function should be used if it is not nil.
+func Plugins(ctx context.Context, manager *framework.PluginManager, contentPath ContentPath, inputs *actions.InputContext, selections PluginOptions, logger hclog.Logger) (_ map[plugin.ID]policy.Provider, _ func(), err error) {
+	_, span := trace.Start(ctx, "complytime.Plugins")
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	manifests, err := contentPath.FindPlugins(inputs.RequestedProviders())
 	if err != nil {
 		return nil, nil, err
 	}
+	pluginIDs := make([]string, 0, len(manifests))
+	for pluginId := range manifests {
+		pluginIDs = append(pluginIDs, pluginId.String())
+	}
+	sort.Strings(pluginIDs)
+	span.SetAttributes(trace.Strings("complytime.plugin_ids", pluginIDs))
 
 	if selections.UserConfigRoot == "" {
 		if _, err := os.Stat(DefaultPluginConfigDir); err == nil {
 			selections.UserConfigRoot = DefaultPluginConfigDir
 		}
 	}
+	return LaunchPlugins(manager, manifests, selections, logger)
+}
+
+// LaunchPlugins launches and configures the plugins of the manifests with the given complytime global options. This function returns
+// the plugin map with the launched plugins, a plugin cleanup function, and an error. The cleanup function should be used if it is not nil.
+func LaunchPlugins(manager *framework.PluginManager, manifests plugin.Manifests, selections PluginOptions, logger hclog.Logger) (map[plugin.ID]policy.Provider, func(), error) {
 	if err := selections.Validate(); err != nil {
 		return nil, nil, fmt.Errorf("failed plugin config validation: %w", err)
 	}
@@ -44,6 +44,23 @@ func TestPluginOptions(t *testing.T) {
 				"results":   "results_test.xml",
 			},
 		},
+		{
+			name: "Valid/Overrides",
+			selections: PluginOptions{
+				Workspace:      "testworkspace",
+				Profile:        "testprofile",
+				UserConfigRoot: testPluginConfigRoot,
+				Overrides: map[string]map[string]string{
+					"openscap": {"results": "results_override.xml", "profile": "otherprofile"},
+					"other":    {"results": "results_other.xml"},
+				},
+			},
+			wantMap: map[string]string{
+				"workspace": "testworkspace",
+				"profile":   "testprofile",
+				"results":   "results_override.xml",
+			},
+		},
 		{
 			name:       "Invalid/MissingOptions",
 			selections: PluginOptions{},
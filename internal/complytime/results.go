@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"sort"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+)
+
+// Normalized rule result values used when summarizing assessment results.
+const (
+	ResultPass    = "pass"
+	ResultFail    = "fail"
+	ResultError   = "error"
+	ResultSkipped = "skipped"
+	// ResultWaived is the result of failures accepted by an active waiver.
+	ResultWaived = "waived"
+)
+
+// ResultStatuses lists all normalized rule result values.
+var ResultStatuses = []string{ResultPass, ResultFail, ResultError, ResultSkipped, ResultWaived}
+
+// resultSeverity orders normalized results so that the worst result
+// across subjects is reported for a rule.
+var resultSeverity = map[string]int{
+	ResultSkipped: 0,
+	ResultPass:    1,
+	ResultWaived:  2,
+	ResultFail:    3,
+	ResultError:   4,
+}
+
+// normalizeResult maps the result values reported by plugins to the
+// normalized rule result values.
+func normalizeResult(value string) string {
+	switch value {
+	case ResultPass:
+		return ResultPass
+	case ResultFail, "warning":
+		return ResultFail
+	default:
+		return ResultError
+	}
+}
+
+// SubjectResult returns the normalized result recorded on an observation subject.
+func SubjectResult(subject oscalTypes.SubjectReference) (string, bool) {
+	if subject.Props == nil {
+		return "", false
+	}
+	result, found := extensions.GetTrestleProp("result", *subject.Props)
+	if !found {
+		return "", false
+	}
+	return normalizeResult(result.Value), true
+}
+
+// ObservationRuleID returns the rule ID an observation was collected for.
+func ObservationRuleID(observation oscalTypes.Observation) (string, bool) {
+	if observation.Props == nil {
+		return "", false
+	}
+	rule, found := extensions.GetTrestleProp(extensions.AssessmentRuleIdProp, *observation.Props)
+	if !found {
+		return "", false
+	}
+	return rule.Value, true
+}
+
+// RuleResults returns the worst normalized result across all subjects for each
+// rule observed in the assessment results, indexed by rule ID. Failures of
+// observations related to an accepted risk are waived.
+func RuleResults(assessmentResults *oscalTypes.AssessmentResults) map[string]string {
+	ruleResults := make(map[string]string)
+	forEachSubjectResult(assessmentResults, func(ruleID string, _ oscalTypes.SubjectReference, subjectResult string) {
+		current, seen := ruleResults[ruleID]
+		if !seen || resultSeverity[subjectResult] > resultSeverity[current] {
+			ruleResults[ruleID] = subjectResult
+		}
+	})
+	return ruleResults
+}
+
+// forEachSubjectResult calls fn with the rule ID, subject, and normalized result of each
+// observation subject in the assessment results. Failures of observations related to an
+// accepted risk are waived.
+func forEachSubjectResult(assessmentResults *oscalTypes.AssessmentResults, fn func(ruleID string, subject oscalTypes.SubjectReference, subjectResult string)) {
+	if assessmentResults == nil {
+		return
+	}
+	for _, result := range assessmentResults.Results {
+		if result.Observations == nil {
+			continue
+		}
+		accepted := acceptedObservations(result)
+		for _, observation := range *result.Observations {
+			ruleID, found := ObservationRuleID(observation)
+			if !found || observation.Subjects == nil {
+				continue
+			}
+			for _, subject := range *observation.Subjects {
+				subjectResult, found := SubjectResult(subject)
+				if !found {
+					continue
+				}
+				if subjectResult == ResultFail && accepted[observation.UUID] {
+					subjectResult = ResultWaived
+				}
+				fn(ruleID, subject, subjectResult)
+			}
+		}
+	}
+}
+
+// ControlsByRule returns the in-scope controls related to each rule activity
+// in the assessment plan, indexed by rule ID. Activities skipped by an
+// assessment scope have no related controls and are not included.
+func ControlsByRule(assessmentPlan *oscalTypes.AssessmentPlan) map[string][]string {
+	controlsByRule := make(map[string][]string)
+	if assessmentPlan == nil || assessmentPlan.LocalDefinitions == nil || assessmentPlan.LocalDefinitions.Activities == nil {
+		return controlsByRule
+	}
+	for _, activity := range *assessmentPlan.LocalDefinitions.Activities {
+		if activity.RelatedControls == nil {
+			continue
+		}
+		controls := make(map[string]struct{})
+		for _, selection := range activity.RelatedControls.ControlSelections {
+			if selection.IncludeControls == nil {
+				continue
+			}
+			for _, control := range *selection.IncludeControls {
+				controls[control.ControlId] = struct{}{}
+			}
+		}
+		if len(controls) == 0 {
+			continue
+		}
+		controlIDs := make([]string, 0, len(controls))
+		for controlID := range controls {
+			controlIDs = append(controlIDs, controlID)
+		}
+		sort.Strings(controlIDs)
+		controlsByRule[activity.Title] = controlIDs
+	}
+	return controlsByRule
+}
+
+// ControlRuleCounts returns the number of rules per normalized result for each
+// control in the assessment plan. In-scope rules without observations in the
+// assessment results are counted as skipped.
+func ControlRuleCounts(assessmentPlan *oscalTypes.AssessmentPlan, assessmentResults *oscalTypes.AssessmentResults) map[string]map[string]int {
+	ruleResults := RuleResults(assessmentResults)
+	counts := make(map[string]map[string]int)
+	for ruleID, controls := range ControlsByRule(assessmentPlan) {
+		result, found := ruleResults[ruleID]
+		if !found {
+			result = ResultSkipped
+		}
+		for _, controlID := range controls {
+			if counts[controlID] == nil {
+				counts[controlID] = make(map[string]int)
+			}
+			counts[controlID][result]++
+		}
+	}
+	return counts
+}
+
+// RuleCounts returns the number of in-scope rules of the assessment plan per
+// normalized result. In-scope rules without observations in the assessment
+// results are counted as skipped.
+func RuleCounts(assessmentPlan *oscalTypes.AssessmentPlan, assessmentResults *oscalTypes.AssessmentResults) map[string]int {
+	ruleResults := RuleResults(assessmentResults)
+	counts := make(map[string]int)
+	for ruleID := range ControlsByRule(assessmentPlan) {
+		result, found := ruleResults[ruleID]
+		if !found {
+			result = ResultSkipped
+		}
+		counts[result]++
+	}
+	return counts
+}
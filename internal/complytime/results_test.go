@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/require"
+)
+
+func testActivity(ruleID string, controlIDs ...string) oscalTypes.Activity {
+	activity := oscalTypes.Activity{Title: ruleID}
+	if len(controlIDs) == 0 {
+		return activity
+	}
+	var includeControls []oscalTypes.AssessedControlsSelectControlById
+	for _, controlID := range controlIDs {
+		includeControls = append(includeControls, oscalTypes.AssessedControlsSelectControlById{ControlId: controlID})
+	}
+	activity.RelatedControls = &oscalTypes.ReviewedControls{
+		ControlSelections: []oscalTypes.AssessedControls{{IncludeControls: &includeControls}},
+	}
+	return activity
+}
+
+func testObservation(ruleID string, results ...string) oscalTypes.Observation {
+	var subjects []oscalTypes.SubjectReference
+	for _, result := range results {
+		subjects = append(subjects, oscalTypes.SubjectReference{
+			Props: &[]oscalTypes.Property{{Name: "result", Value: result, Ns: extensions.TrestleNameSpace}},
+		})
+	}
+	return oscalTypes.Observation{
+		Props:    &[]oscalTypes.Property{{Name: extensions.AssessmentRuleIdProp, Value: ruleID, Ns: extensions.TrestleNameSpace}},
+		Subjects: &subjects,
+	}
+}
+
+func TestControlRuleCounts(t *testing.T) {
+	testPlan := &oscalTypes.AssessmentPlan{
+		LocalDefinitions: &oscalTypes.LocalDefinitions{
+			Activities: &[]oscalTypes.Activity{
+				testActivity("rule-1", "control-1"),
+				testActivity("rule-2", "control-1", "control-2"),
+				testActivity("rule-3", "control-2"),
+				testActivity("rule-4", "control-2"),
+				// Skipped by scope
+				testActivity("rule-5"),
+			},
+		},
+	}
+	testResults := &oscalTypes.AssessmentResults{
+		Results: []oscalTypes.Result{
+			{
+				Observations: &[]oscalTypes.Observation{
+					testObservation("rule-1", "pass"),
+					testObservation("rule-2", "pass", "fail"),
+					testObservation("rule-3", "INVALID"),
+					testObservation("rule-5", "pass"),
+				},
+			},
+		},
+	}
+
+	require.Equal(t, map[string]string{
+		"rule-1": ResultPass,
+		"rule-2": ResultFail,
+		"rule-3": ResultError,
+		"rule-5": ResultPass,
+	}, RuleResults(testResults))
+
+	require.Equal(t, map[string][]string{
+		"rule-1": {"control-1"},
+		"rule-2": {"control-1", "control-2"},
+		"rule-3": {"control-2"},
+		"rule-4": {"control-2"},
+	}, ControlsByRule(testPlan))
+
+	wantCounts := map[string]map[string]int{
+		"control-1": {ResultPass: 1, ResultFail: 1},
+		"control-2": {ResultFail: 1, ResultError: 1, ResultSkipped: 1},
+	}
+	require.Equal(t, wantCounts, ControlRuleCounts(testPlan, testResults))
+	require.Equal(t, map[string]int{
+		ResultPass:    1,
+		ResultFail:    1,
+		ResultError:   1,
+		ResultSkipped: 1,
+	}, RuleCounts(testPlan, testResults))
+}
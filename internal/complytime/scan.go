@@ -4,9 +4,12 @@ package complytime
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/models"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
 )
 
 // WriteAssessmentResults writes AssessmentResults as a JSON file to a given path location.
@@ -24,3 +27,18 @@ func WriteAssessmentResults(assessmentResults *oscalTypes.AssessmentResults, ass
 	return os.WriteFile(assessmentResultsLocation, assessmentResultsJson, 0600)
 
 }
+
+// ReadAssessmentResults reads AssessmentResults from a given file path.
+func ReadAssessmentResults(assessmentResultsLocation string, validator validation.Validator) (*oscalTypes.AssessmentResults, error) {
+	file, err := os.Open(assessmentResultsLocation)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	assessmentResults, err := models.NewAssessmentResults(file, validator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load assessment results from %s: %w", assessmentResultsLocation, err)
+	}
+	return assessmentResults, nil
+}
@@ -46,4 +46,11 @@ func TestWriteAssessmentResults(t *testing.T) {
 	loadedAssessmentResults, err := models.NewAssessmentResults(file, validation.NoopValidator{})
 	require.NoError(t, err)
 	require.Equal(t, loadedAssessmentResults.Metadata.Title, testAssessmentResults.Metadata.Title)
+
+	readAssessmentResults, err := ReadAssessmentResults(testResultsPath, validation.NoopValidator{})
+	require.NoError(t, err)
+	require.Equal(t, testAssessmentResults.UUID, readAssessmentResults.UUID)
+
+	_, err = ReadAssessmentResults(filepath.Join(tmpDir, "doesnotexist.json"), validation.NoopValidator{})
+	require.ErrorIs(t, err, os.ErrNotExist)
 }
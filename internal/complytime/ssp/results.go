@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ssp
+
+import (
+	"fmt"
+
+	"github.com/defenseunicorns/go-oscal/src/pkg/uuid"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// resultLinkRel is the relation of the links from component implementations to the observations
+// of their rules in the assessment results.
+const resultLinkRel = "assessment-result"
+
+// LinkAssessmentResults links the component implementations of the system security plan to the
+// observations of their rules in the assessment results found at the given location. The assessment
+// results are added as a back-matter resource, and each link names the result of the rule.
+func LinkAssessmentResults(systemPlan *oscalTypes.SystemSecurityPlan, assessmentResults *oscalTypes.AssessmentResults, href string) {
+	resource := oscalTypes.Resource{
+		UUID:        uuid.NewUUID(),
+		Title:       "Assessment Results",
+		Description: fmt.Sprintf("Latest assessment results of %s.", systemPlan.SystemCharacteristics.SystemName),
+		Rlinks:      &[]oscalTypes.ResourceLink{{Href: href, MediaType: "application/json"}},
+	}
+	if systemPlan.BackMatter == nil {
+		systemPlan.BackMatter = &oscalTypes.BackMatter{}
+	}
+	if systemPlan.BackMatter.Resources == nil {
+		systemPlan.BackMatter.Resources = &[]oscalTypes.Resource{}
+	}
+	*systemPlan.BackMatter.Resources = append(*systemPlan.BackMatter.Resources, resource)
+
+	ruleResults := complytime.RuleResults(assessmentResults)
+	ruleObservations := make(map[string]string)
+	for _, result := range assessmentResults.Results {
+		if result.Observations == nil {
+			continue
+		}
+		for _, observation := range *result.Observations {
+			ruleID, found := complytime.ObservationRuleID(observation)
+			if _, linked := ruleObservations[ruleID]; found && !linked {
+				ruleObservations[ruleID] = observation.UUID
+			}
+		}
+	}
+
+	for i := range systemPlan.ControlImplementation.ImplementedRequirements {
+		requirement := &systemPlan.ControlImplementation.ImplementedRequirements[i]
+		if requirement.ByComponents == nil {
+			continue
+		}
+		for j := range *requirement.ByComponents {
+			byComponent := &(*requirement.ByComponents)[j]
+			if byComponent.Props == nil {
+				continue
+			}
+			for _, prop := range *byComponent.Props {
+				if prop.Name != extensions.RuleIdProp {
+					continue
+				}
+				observationUUID, found := ruleObservations[prop.Value]
+				if !found {
+					continue
+				}
+				text := fmt.Sprintf("Rule %s", prop.Value)
+				if result, found := ruleResults[prop.Value]; found {
+					text = fmt.Sprintf("Rule %s: %s", prop.Value, result)
+				}
+				if byComponent.Links == nil {
+					byComponent.Links = &[]oscalTypes.Link{}
+				}
+				*byComponent.Links = append(*byComponent.Links, oscalTypes.Link{
+					Href:             "#" + resource.UUID,
+					Rel:              resultLinkRel,
+					ResourceFragment: observationUUID,
+					Text:             text,
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,355 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ssp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/defenseunicorns/go-oscal/src/pkg/uuid"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/models"
+	"github.com/oscal-compass/oscal-sdk-go/settings"
+)
+
+const (
+	// implementationStatusProp is the implemented requirement property holding its implementation status.
+	implementationStatusProp = "implementation-status"
+	// thisSystemType is the component type of the system described by the system security plan.
+	thisSystemType = "this-system"
+	// defaultState is the operational state of the system and its components when not configured.
+	defaultState = "operational"
+)
+
+// SystemConfig holds the system characteristics of the system security plan.
+type SystemConfig struct {
+	// SystemName is the full name of the system.
+	SystemName string `yaml:"systemName"`
+	// SystemNameShort is the short name of the system.
+	SystemNameShort string `yaml:"systemNameShort,omitempty"`
+	// SystemID identifies the system, defaulting to the short name or name of the system.
+	SystemID string `yaml:"systemId,omitempty"`
+	// Description describes the system.
+	Description string `yaml:"description"`
+	// Status is the operational state of the system, defaulting to operational.
+	Status string `yaml:"status,omitempty"`
+	// AuthorizationBoundary describes the authorization boundary of the system.
+	AuthorizationBoundary string `yaml:"authorizationBoundary,omitempty"`
+	// SecuritySensitivityLevel is the overall sensitivity categorization of the system.
+	SecuritySensitivityLevel string `yaml:"securitySensitivityLevel,omitempty"`
+	// SecurityImpactLevel is the impact level of the system for each security objective.
+	SecurityImpactLevel *SecurityImpactLevel `yaml:"securityImpactLevel,omitempty"`
+	// InformationTypes are the types of information processed by the system.
+	InformationTypes []InformationType `yaml:"informationTypes,omitempty"`
+	// Users are the types of users of the system.
+	Users []User `yaml:"users,omitempty"`
+}
+
+// SecurityImpactLevel is the impact level for each security objective.
+type SecurityImpactLevel struct {
+	Confidentiality string `yaml:"confidentiality"`
+	Integrity       string `yaml:"integrity"`
+	Availability    string `yaml:"availability"`
+}
+
+// InformationType is a type of information processed by the system and its base impact levels.
+type InformationType struct {
+	Title           string `yaml:"title"`
+	Description     string `yaml:"description"`
+	Confidentiality string `yaml:"confidentiality,omitempty"`
+	Integrity       string `yaml:"integrity,omitempty"`
+	Availability    string `yaml:"availability,omitempty"`
+}
+
+// User is a type of user of the system.
+type User struct {
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description,omitempty"`
+	RoleIDs     []string `yaml:"roleIds,omitempty"`
+}
+
+// ReadSystemConfig reads the system characteristics from the given YAML file.
+func ReadSystemConfig(path string) (*SystemConfig, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("error reading system config: %w", err)
+	}
+	var config SystemConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("error unmarshaling system config %s: %w", path, err)
+	}
+	if config.SystemName == "" {
+		return nil, fmt.Errorf("system config %s has no systemName", path)
+	}
+	if config.Description == "" {
+		return nil, fmt.Errorf("system config %s has no description", path)
+	}
+	return &config, nil
+}
+
+// NewSystemSecurityPlan returns a system security plan for the framework implemented by the given
+// target components. Each control implemented by the components has an implemented requirement
+// describing the implementation of each component, its implementation status, rules, parameters,
+// and statements, taken from the component definitions. Missing required information is set to
+// REPLACE_ME, so the plan is a skeleton to complete.
+func NewSystemSecurityPlan(frameworkID string, config SystemConfig, components []oscalTypes.DefinedComponent) (*oscalTypes.SystemSecurityPlan, error) {
+	if len(components) == 0 {
+		return nil, fmt.Errorf("no components found for framework %s", frameworkID)
+	}
+
+	metadata := models.NewSampleMetadata()
+	metadata.Title = fmt.Sprintf("System Security Plan for %s", config.SystemName)
+	metadata.Props = &[]oscalTypes.Property{
+		{Name: extensions.FrameworkProp, Value: frameworkID, Ns: extensions.TrestleNameSpace},
+	}
+
+	systemPlan := &oscalTypes.SystemSecurityPlan{
+		UUID:                  uuid.NewUUID(),
+		Metadata:              metadata,
+		SystemCharacteristics: systemCharacteristics(config),
+		SystemImplementation: oscalTypes.SystemImplementation{
+			Components: []oscalTypes.SystemComponent{
+				{
+					UUID:        uuid.NewUUID(),
+					Type:        thisSystemType,
+					Title:       config.SystemName,
+					Description: config.Description,
+					Status:      oscalTypes.SystemComponentStatus{State: orDefault(config.Status, defaultState)},
+				},
+			},
+			Users: systemUsers(config.Users),
+		},
+		ControlImplementation: oscalTypes.ControlImplementation{
+			Description: fmt.Sprintf("Controls of the %s framework implemented by the components of %s.", frameworkID, config.SystemName),
+		},
+	}
+
+	requirements := make(map[string]*oscalTypes.ImplementedRequirement)
+	parameters := make(map[string]oscalTypes.SetParameter)
+	for _, component := range components {
+		systemPlan.SystemImplementation.Components = append(systemPlan.SystemImplementation.Components, oscalTypes.SystemComponent{
+			UUID:        component.UUID,
+			Type:        component.Type,
+			Title:       component.Title,
+			Description: component.Description,
+			Purpose:     component.Purpose,
+			Status:      oscalTypes.SystemComponentStatus{State: defaultState},
+		})
+		if component.ControlImplementations == nil {
+			continue
+		}
+		for _, implementation := range *component.ControlImplementations {
+			implementationFramework, found := settings.GetFrameworkShortName(implementation)
+			if !found || implementationFramework != frameworkID {
+				continue
+			}
+			if systemPlan.ImportProfile.Href == "" {
+				systemPlan.ImportProfile.Href = implementation.Source
+			}
+			if implementation.SetParameters != nil {
+				for _, parameter := range *implementation.SetParameters {
+					if _, found := parameters[parameter.ParamId]; !found {
+						parameters[parameter.ParamId] = parameter
+					}
+				}
+			}
+			for _, implementedRequirement := range implementation.ImplementedRequirements {
+				requirement, found := requirements[implementedRequirement.ControlId]
+				if !found {
+					requirement = &oscalTypes.ImplementedRequirement{
+						UUID:      uuid.NewUUID(),
+						ControlId: implementedRequirement.ControlId,
+					}
+					requirements[implementedRequirement.ControlId] = requirement
+				}
+				addComponentImplementation(requirement, component.UUID, implementedRequirement)
+			}
+		}
+	}
+	if systemPlan.ImportProfile.Href == "" {
+		return nil, fmt.Errorf("no control implementations found for framework %s", frameworkID)
+	}
+
+	controlIDs := make([]string, 0, len(requirements))
+	for controlID := range requirements {
+		controlIDs = append(controlIDs, controlID)
+	}
+	sort.Strings(controlIDs)
+	for _, controlID := range controlIDs {
+		systemPlan.ControlImplementation.ImplementedRequirements = append(systemPlan.ControlImplementation.ImplementedRequirements, *requirements[controlID])
+	}
+	if len(parameters) > 0 {
+		parameterIDs := make([]string, 0, len(parameters))
+		for parameterID := range parameters {
+			parameterIDs = append(parameterIDs, parameterID)
+		}
+		sort.Strings(parameterIDs)
+		setParameters := make([]oscalTypes.SetParameter, 0, len(parameters))
+		for _, parameterID := range parameterIDs {
+			setParameters = append(setParameters, parameters[parameterID])
+		}
+		systemPlan.ControlImplementation.SetParameters = &setParameters
+	}
+	return systemPlan, nil
+}
+
+// addComponentImplementation adds the implementation of the control by a component to the
+// implemented requirement and its statements.
+func addComponentImplementation(requirement *oscalTypes.ImplementedRequirement, componentUUID string, implementedRequirement oscalTypes.ImplementedRequirementControlImplementation) {
+	byComponent := oscalTypes.ByComponent{
+		UUID:          uuid.NewUUID(),
+		ComponentUuid: componentUUID,
+		Description:   orDefault(implementedRequirement.Description, models.SampleRequiredString),
+		SetParameters: implementedRequirement.SetParameters,
+		Remarks:       implementedRequirement.Remarks,
+	}
+	if implementedRequirement.Props != nil {
+		var props []oscalTypes.Property
+		for _, prop := range *implementedRequirement.Props {
+			if prop.Name == implementationStatusProp {
+				byComponent.ImplementationStatus = &oscalTypes.ImplementationStatus{State: prop.Value}
+				continue
+			}
+			props = append(props, prop)
+		}
+		if len(props) > 0 {
+			byComponent.Props = &props
+		}
+	}
+	if requirement.ByComponents == nil {
+		requirement.ByComponents = &[]oscalTypes.ByComponent{}
+	}
+	*requirement.ByComponents = append(*requirement.ByComponents, byComponent)
+
+	if implementedRequirement.Statements == nil {
+		return
+	}
+	for _, componentStatement := range *implementedRequirement.Statements {
+		if requirement.Statements == nil {
+			requirement.Statements = &[]oscalTypes.Statement{}
+		}
+		var statement *oscalTypes.Statement
+		for i := range *requirement.Statements {
+			if (*requirement.Statements)[i].StatementId == componentStatement.StatementId {
+				statement = &(*requirement.Statements)[i]
+			}
+		}
+		if statement == nil {
+			*requirement.Statements = append(*requirement.Statements, oscalTypes.Statement{
+				UUID:         uuid.NewUUID(),
+				StatementId:  componentStatement.StatementId,
+				ByComponents: &[]oscalTypes.ByComponent{},
+			})
+			statement = &(*requirement.Statements)[len(*requirement.Statements)-1]
+		}
+		*statement.ByComponents = append(*statement.ByComponents, oscalTypes.ByComponent{
+			UUID:          uuid.NewUUID(),
+			ComponentUuid: componentUUID,
+			Description:   orDefault(componentStatement.Description, models.SampleRequiredString),
+			Props:         componentStatement.Props,
+			Remarks:       componentStatement.Remarks,
+		})
+	}
+}
+
+// systemCharacteristics returns the system characteristics of the system config.
+func systemCharacteristics(config SystemConfig) oscalTypes.SystemCharacteristics {
+	systemID := config.SystemID
+	if systemID == "" {
+		systemID = orDefault(config.SystemNameShort, config.SystemName)
+	}
+	characteristics := oscalTypes.SystemCharacteristics{
+		SystemIds:                []oscalTypes.SystemId{{ID: systemID}},
+		SystemName:               config.SystemName,
+		SystemNameShort:          config.SystemNameShort,
+		Description:              config.Description,
+		SecuritySensitivityLevel: config.SecuritySensitivityLevel,
+		Status:                   oscalTypes.Status{State: orDefault(config.Status, defaultState)},
+		AuthorizationBoundary: oscalTypes.AuthorizationBoundary{
+			Description: orDefault(config.AuthorizationBoundary, models.SampleRequiredString),
+		},
+	}
+	if config.SecurityImpactLevel != nil {
+		characteristics.SecurityImpactLevel = &oscalTypes.SecurityImpactLevel{
+			SecurityObjectiveConfidentiality: config.SecurityImpactLevel.Confidentiality,
+			SecurityObjectiveIntegrity:       config.SecurityImpactLevel.Integrity,
+			SecurityObjectiveAvailability:    config.SecurityImpactLevel.Availability,
+		}
+	}
+
+	informationTypes := config.InformationTypes
+	if len(informationTypes) == 0 {
+		informationTypes = []InformationType{{Title: models.SampleRequiredString, Description: models.SampleRequiredString}}
+	}
+	for _, informationType := range informationTypes {
+		characteristics.SystemInformation.InformationTypes = append(characteristics.SystemInformation.InformationTypes, oscalTypes.InformationType{
+			UUID:                  uuid.NewUUID(),
+			Title:                 informationType.Title,
+			Description:           informationType.Description,
+			ConfidentialityImpact: impact(informationType.Confidentiality),
+			IntegrityImpact:       impact(informationType.Integrity),
+			AvailabilityImpact:    impact(informationType.Availability),
+		})
+	}
+	return characteristics
+}
+
+// systemUsers returns the system users of the configured users. A system security plan has at
+// least one user.
+func systemUsers(users []User) []oscalTypes.SystemUser {
+	if len(users) == 0 {
+		users = []User{{Title: models.SampleRequiredString}}
+	}
+	systemUsers := make([]oscalTypes.SystemUser, 0, len(users))
+	for _, user := range users {
+		systemUser := oscalTypes.SystemUser{
+			UUID:        uuid.NewUUID(),
+			Title:       user.Title,
+			Description: user.Description,
+		}
+		if len(user.RoleIDs) > 0 {
+			roleIDs := user.RoleIDs
+			systemUser.RoleIds = &roleIDs
+		}
+		systemUsers = append(systemUsers, systemUser)
+	}
+	return systemUsers
+}
+
+func impact(base string) *oscalTypes.Impact {
+	if base == "" {
+		return nil
+	}
+	return &oscalTypes.Impact{Base: base}
+}
+
+func orDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// WriteSystemSecurityPlan writes the system security plan to the given path.
+func WriteSystemSecurityPlan(systemPlan *oscalTypes.SystemSecurityPlan, path string) error {
+	if systemPlan == nil {
+		return errors.New("system security plan is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	oscalModels := oscalTypes.OscalModels{
+		SystemSecurityPlan: systemPlan,
+	}
+	data, err := json.MarshalIndent(oscalModels, "", " ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
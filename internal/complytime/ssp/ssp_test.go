@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ssp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+)
+
+var testSystemConfig = SystemConfig{
+	SystemName:  "Example System",
+	Description: "An example system.",
+	SecurityImpactLevel: &SecurityImpactLevel{
+		Confidentiality: "fips-199-moderate",
+		Integrity:       "fips-199-moderate",
+		Availability:    "fips-199-low",
+	},
+	Users: []User{{Title: "Administrator", RoleIDs: []string{"admin"}}},
+}
+
+func testComponents(t *testing.T) []oscalTypes.DefinedComponent {
+	data, err := os.ReadFile("../plan/testdata/multi-framework-component-definition.json")
+	require.NoError(t, err)
+	var models oscalTypes.OscalModels
+	require.NoError(t, json.Unmarshal(data, &models))
+	var components []oscalTypes.DefinedComponent
+	for _, component := range *models.ComponentDefinition.Components {
+		if component.Type != "validation" {
+			components = append(components, component)
+		}
+	}
+	return components
+}
+
+func requireValid(t *testing.T, systemPlan *oscalTypes.SystemSecurityPlan) {
+	validator := validation.NewSchemaValidator()
+	require.NoError(t, validator.Validate(oscalTypes.OscalModels{SystemSecurityPlan: systemPlan}))
+}
+
+func TestReadSystemConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "system.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte("systemName: Example System\n"), 0600))
+	_, err := ReadSystemConfig(configPath)
+	require.ErrorContains(t, err, "description")
+
+	require.NoError(t, os.WriteFile(configPath, []byte("systemName: Example System\ndescription: An example system.\nusers:\n- title: Administrator\n  roleIds: [admin]\n"), 0600))
+	config, err := ReadSystemConfig(configPath)
+	require.NoError(t, err)
+	require.Equal(t, "Example System", config.SystemName)
+	require.Equal(t, []string{"admin"}, config.Users[0].RoleIDs)
+}
+
+func TestNewSystemSecurityPlan(t *testing.T) {
+	components := testComponents(t)
+
+	_, err := NewSystemSecurityPlan("missing", testSystemConfig, components)
+	require.EqualError(t, err, "no control implementations found for framework missing")
+	_, err = NewSystemSecurityPlan("baseline", testSystemConfig, nil)
+	require.EqualError(t, err, "no components found for framework baseline")
+
+	systemPlan, err := NewSystemSecurityPlan("baseline", testSystemConfig, components)
+	require.NoError(t, err)
+	requireValid(t, systemPlan)
+
+	require.Equal(t, "System Security Plan for Example System", systemPlan.Metadata.Title)
+	require.Equal(t, "file://controls/baseline-profile.json", systemPlan.ImportProfile.Href)
+	require.Equal(t, "Example System", systemPlan.SystemCharacteristics.SystemName)
+	require.Len(t, systemPlan.SystemImplementation.Components, 2)
+	require.Equal(t, "this-system", systemPlan.SystemImplementation.Components[0].Type)
+	require.Equal(t, components[0].UUID, systemPlan.SystemImplementation.Components[1].UUID)
+
+	requirements := systemPlan.ControlImplementation.ImplementedRequirements
+	require.Len(t, requirements, 2)
+	require.Equal(t, "base-1", requirements[0].ControlId)
+	require.Equal(t, "base-2", requirements[1].ControlId)
+	byComponents := *requirements[1].ByComponents
+	require.Len(t, byComponents, 1)
+	require.Equal(t, components[0].UUID, byComponents[0].ComponentUuid)
+	rule, found := extensions.GetTrestleProp(extensions.RuleIdProp, *byComponents[0].Props)
+	require.True(t, found)
+	require.Equal(t, "rule-2", rule.Value)
+}
+
+func TestLinkAssessmentResults(t *testing.T) {
+	systemPlan, err := NewSystemSecurityPlan("baseline", testSystemConfig, testComponents(t))
+	require.NoError(t, err)
+
+	assessmentResults := &oscalTypes.AssessmentResults{
+		Results: []oscalTypes.Result{
+			{
+				Start: time.Now(),
+				Observations: &[]oscalTypes.Observation{
+					{
+						UUID: "obs-1",
+						Props: &[]oscalTypes.Property{
+							{Name: extensions.AssessmentRuleIdProp, Value: "rule-1", Ns: extensions.TrestleNameSpace},
+						},
+						Subjects: &[]oscalTypes.SubjectReference{
+							{
+								Props: &[]oscalTypes.Property{
+									{Name: "result", Value: "fail", Ns: extensions.TrestleNameSpace},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	LinkAssessmentResults(systemPlan, assessmentResults, "file:///workspace/assessment-results.json")
+	requireValid(t, systemPlan)
+
+	resources := *systemPlan.BackMatter.Resources
+	require.Len(t, resources, 1)
+	require.Equal(t, "file:///workspace/assessment-results.json", (*resources[0].Rlinks)[0].Href)
+
+	requirements := systemPlan.ControlImplementation.ImplementedRequirements
+	links := *(*requirements[0].ByComponents)[0].Links
+	require.Len(t, links, 1)
+	require.Equal(t, "#"+resources[0].UUID, links[0].Href)
+	require.Equal(t, "obs-1", links[0].ResourceFragment)
+	require.Equal(t, "Rule rule-1: fail", links[0].Text)
+	// Rules without observations are not linked
+	require.Nil(t, (*requirements[1].ByComponents)[0].Links)
+}
+
+func TestWriteSystemSecurityPlan(t *testing.T) {
+	systemPlan, err := NewSystemSecurityPlan("example", testSystemConfig, testComponents(t))
+	require.NoError(t, err)
+	sspPath := filepath.Join(t.TempDir(), "system-security-plan.json")
+	require.NoError(t, WriteSystemSecurityPlan(systemPlan, sspPath))
+
+	data, err := os.ReadFile(sspPath)
+	require.NoError(t, err)
+	var models oscalTypes.OscalModels
+	require.NoError(t, json.Unmarshal(data, &models))
+	require.Equal(t, systemPlan.UUID, models.SystemSecurityPlan.UUID)
+	require.Equal(t, "example-1", models.SystemSecurityPlan.ControlImplementation.ImplementedRequirements[0].ControlId)
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ScanStatus records run information about the latest scan that is not
+// captured in the OSCAL Assessment Results, such as per-plugin timing and errors.
+type ScanStatus struct {
+	// StartedAt is the time the scan started.
+	StartedAt time.Time `json:"startedAt"`
+	// CompletedAt is the time the scan finished, successfully or not.
+	CompletedAt time.Time `json:"completedAt"`
+	// LastSuccess is the completion time of the latest scan where
+	// all plugins returned results. It is carried over from previous
+	// status files when a scan fails.
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+	// Plugins contains the status of each plugin run during the scan.
+	Plugins []PluginStatus `json:"plugins"`
+}
+
+// PluginStatus records the outcome of a single plugin during a scan.
+type PluginStatus struct {
+	// ID is the plugin identifier.
+	ID string `json:"id"`
+	// DurationSeconds is the time taken by the plugin to return results.
+	DurationSeconds float64 `json:"durationSeconds"`
+	// Error is the error message returned by the plugin, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// NewScanStatus returns a ScanStatus for a scan starting at the given time.
+// The latest successful scan time is carried over from the previous status, if any.
+func NewScanStatus(startedAt time.Time, previous *ScanStatus) *ScanStatus {
+	status := &ScanStatus{
+		StartedAt: startedAt,
+	}
+	if previous != nil {
+		status.LastSuccess = previous.LastSuccess
+	}
+	return status
+}
+
+// Record adds the outcome of a plugin run to the ScanStatus.
+func (s *ScanStatus) Record(pluginID string, duration time.Duration, err error) {
+	pluginStatus := PluginStatus{
+		ID:              pluginID,
+		DurationSeconds: duration.Seconds(),
+	}
+	if err != nil {
+		pluginStatus.Error = err.Error()
+	}
+	s.Plugins = append(s.Plugins, pluginStatus)
+}
+
+// Complete marks the scan as finished at the given time. The scan is
+// recorded as successful when no plugin reported an error.
+func (s *ScanStatus) Complete(completedAt time.Time) {
+	s.CompletedAt = completedAt
+	for _, pluginStatus := range s.Plugins {
+		if pluginStatus.Error != "" {
+			return
+		}
+	}
+	s.LastSuccess = &completedAt
+}
+
+// WriteScanStatus writes a ScanStatus as a JSON file to a given path location.
+func WriteScanStatus(status *ScanStatus, statusLocation string) error {
+	statusJson, err := json.MarshalIndent(status, "", " ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(statusLocation, statusJson, 0600)
+}
+
+// ReadScanStatus reads a ScanStatus from a given file path. A nil status is
+// returned without error if the file does not exist.
+func ReadScanStatus(statusLocation string) (*ScanStatus, error) {
+	statusJson, err := os.ReadFile(statusLocation)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var status ScanStatus
+	if err := json.Unmarshal(statusJson, &status); err != nil {
+		return nil, fmt.Errorf("failed to load scan status from %s: %w", statusLocation, err)
+	}
+	return &status, nil
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanStatus(t *testing.T) {
+	tmpDir := t.TempDir()
+	testStatusPath := filepath.Join(tmpDir, "scan-status.json")
+
+	status, err := ReadScanStatus(testStatusPath)
+	require.NoError(t, err)
+	require.Nil(t, status)
+
+	start := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Minute)
+
+	// Successful scan
+	status = NewScanStatus(start, nil)
+	status.Record("openscap", 30*time.Second, nil)
+	status.Complete(end)
+	require.NotNil(t, status.LastSuccess)
+	require.Equal(t, end, *status.LastSuccess)
+	require.NoError(t, WriteScanStatus(status, testStatusPath))
+
+	previous, err := ReadScanStatus(testStatusPath)
+	require.NoError(t, err)
+	require.Equal(t, []PluginStatus{{ID: "openscap", DurationSeconds: 30}}, previous.Plugins)
+
+	// Failed scan keeps the previous successful scan time
+	status = NewScanStatus(end, previous)
+	status.Record("openscap", time.Second, errors.New("oscap not found"))
+	status.Complete(end.Add(time.Minute))
+	require.NotNil(t, status.LastSuccess)
+	require.True(t, end.Equal(*status.LastSuccess))
+	require.Equal(t, "oscap not found", status.Plugins[0].Error)
+}
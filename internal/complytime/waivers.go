@@ -0,0 +1,322 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/defenseunicorns/go-oscal/src/pkg/uuid"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+)
+
+// Statuses of the risks recorded for waivers in the assessment results.
+const (
+	// RiskAccepted is the status of the risk of failures waived by an active waiver.
+	RiskAccepted = "deviation-approved"
+	// RiskOpen is the status of the risk of failures matched by an expired waiver.
+	RiskOpen = "open"
+)
+
+// approverProp is the name of the risk property holding the approver of a waiver.
+const approverProp = "approver"
+
+// Waiver accepts the risk of failures of a rule, or of the rules of a control, until it expires.
+type Waiver struct {
+	// Rule is the ID of the waived rule.
+	Rule string `yaml:"rule,omitempty"`
+	// Control is the ID of the control whose rules are waived.
+	Control string `yaml:"control,omitempty"`
+	// Justification explains why the risk is accepted.
+	Justification string `yaml:"justification"`
+	// Approver is the person accepting the risk.
+	Approver string `yaml:"approver"`
+	// Expires is the date the waiver expires on, formatted as YYYY-MM-DD.
+	Expires string `yaml:"expires"`
+}
+
+// Waivers is the content of the waivers file of a workspace.
+type Waivers struct {
+	Waivers []Waiver `yaml:"waivers"`
+}
+
+// ID returns the ID of the waived rule or control.
+func (w Waiver) ID() string {
+	if w.Rule != "" {
+		return w.Rule
+	}
+	return w.Control
+}
+
+// Kind returns whether the waiver is for a rule or a control.
+func (w Waiver) Kind() string {
+	if w.Rule != "" {
+		return "rule"
+	}
+	return "control"
+}
+
+// Expiry returns the start of the expiry date of the waiver in the local time zone.
+func (w Waiver) Expiry() (time.Time, error) {
+	expiry, err := time.ParseInLocation(time.DateOnly, w.Expires, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expiry date %q for waiver of %s %s, expected YYYY-MM-DD", w.Expires, w.Kind(), w.ID())
+	}
+	return expiry, nil
+}
+
+// Expired returns whether the waiver expired at the given time. Waivers expire at the start of their expiry date.
+func (w Waiver) Expired(now time.Time) bool {
+	expiry, err := w.Expiry()
+	return err != nil || !now.Before(expiry)
+}
+
+// Matches returns whether the waiver applies to the rule with the given controls.
+func (w Waiver) Matches(ruleID string, controlIDs []string) bool {
+	if w.Rule != "" {
+		return w.Rule == ruleID
+	}
+	return slices.Contains(controlIDs, w.Control)
+}
+
+// Validate checks that the waiver names either a rule or a control and has a justification,
+// an approver, and a valid expiry date.
+func (w Waiver) Validate() error {
+	switch {
+	case w.Rule == "" && w.Control == "":
+		return errors.New("waiver must name a rule or a control")
+	case w.Rule != "" && w.Control != "":
+		return fmt.Errorf("waiver names both rule %s and control %s", w.Rule, w.Control)
+	case w.Justification == "":
+		return fmt.Errorf("waiver of %s %s has no justification", w.Kind(), w.ID())
+	case w.Approver == "":
+		return fmt.Errorf("waiver of %s %s has no approver", w.Kind(), w.ID())
+	}
+	_, err := w.Expiry()
+	return err
+}
+
+// ReadWaivers reads the waivers file at the given path. A missing file has no waivers.
+func ReadWaivers(waiversLocation string) (*Waivers, error) {
+	data, err := os.ReadFile(filepath.Clean(waiversLocation))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Waivers{}, nil
+		}
+		return nil, err
+	}
+	var waivers Waivers
+	if err := yaml.Unmarshal(data, &waivers); err != nil {
+		return nil, fmt.Errorf("failed to load waivers from %s: %w", waiversLocation, err)
+	}
+	for _, waiver := range waivers.Waivers {
+		if err := waiver.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid waiver in %s: %w", waiversLocation, err)
+		}
+	}
+	return &waivers, nil
+}
+
+// Write stores the waivers file at the given path.
+func (w *Waivers) Write(waiversLocation string) error {
+	data, err := yaml.Marshal(w)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(waiversLocation), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(waiversLocation, data, 0600)
+}
+
+// Add adds a waiver that has not expired at the given time. Only one active waiver is kept for
+// each rule and control.
+func (w *Waivers) Add(waiver Waiver, now time.Time) error {
+	if err := waiver.Validate(); err != nil {
+		return err
+	}
+	if waiver.Expired(now) {
+		return fmt.Errorf("waiver of %s %s expires on %s, which is not after today", waiver.Kind(), waiver.ID(), waiver.Expires)
+	}
+	for _, existing := range w.Waivers {
+		if existing.Kind() == waiver.Kind() && existing.ID() == waiver.ID() && !existing.Expired(now) {
+			return fmt.Errorf("%s %s already has a waiver expiring on %s", waiver.Kind(), waiver.ID(), existing.Expires)
+		}
+	}
+	w.Waivers = append(w.Waivers, waiver)
+	return nil
+}
+
+// Expire expires the active waivers of the rule or control with the given ID at the given date,
+// and returns the number of expired waivers.
+func (w *Waivers) Expire(id string, now time.Time) (int, error) {
+	expired := 0
+	for i := range w.Waivers {
+		if w.Waivers[i].ID() == id && !w.Waivers[i].Expired(now) {
+			w.Waivers[i].Expires = now.Format(time.DateOnly)
+			expired++
+		}
+	}
+	if expired == 0 {
+		return 0, fmt.Errorf("no active waiver found for %s", id)
+	}
+	return expired, nil
+}
+
+// WaivedRule is a failing rule matched by a waiver in the assessment results.
+type WaivedRule struct {
+	// RuleID is the ID of the failing rule.
+	RuleID string
+	// Waiver is the waiver matching the rule.
+	Waiver Waiver
+	// Expired is set when the waiver expired, and the failures of the rule are reported.
+	Expired bool
+}
+
+// ApplyWaivers records the failures of the assessment results matched by a waiver as risks.
+// Failures matched by an active waiver are accepted risks, which are counted as waived instead
+// of failed. Failures only matched by expired waivers remain failed and are recorded as open risks.
+// The findings of the failures refer to the risks. The waived rules are returned ordered by rule ID.
+func ApplyWaivers(assessmentResults *oscalTypes.AssessmentResults, assessmentPlan *oscalTypes.AssessmentPlan, waivers []Waiver, now time.Time) []WaivedRule {
+	if assessmentResults == nil || len(waivers) == 0 {
+		return nil
+	}
+	controlsByRule := ControlsByRule(assessmentPlan)
+	waivedRules := make(map[string]WaivedRule)
+	for i := range assessmentResults.Results {
+		result := &assessmentResults.Results[i]
+		if result.Observations == nil {
+			continue
+		}
+		// Observations of each matching waiver, in waiver order
+		observations := make(map[int][]string)
+		for _, observation := range *result.Observations {
+			ruleID, found := ObservationRuleID(observation)
+			if !found || !observationFailed(observation) {
+				continue
+			}
+			match := -1
+			for n, waiver := range waivers {
+				if !waiver.Matches(ruleID, controlsByRule[ruleID]) {
+					continue
+				}
+				if !waiver.Expired(now) {
+					match = n
+					break
+				}
+				if match == -1 {
+					match = n
+				}
+			}
+			if match == -1 {
+				continue
+			}
+			observations[match] = append(observations[match], observation.UUID)
+			waivedRules[ruleID] = WaivedRule{RuleID: ruleID, Waiver: waivers[match], Expired: waivers[match].Expired(now)}
+		}
+
+		matched := make([]int, 0, len(observations))
+		for n := range observations {
+			matched = append(matched, n)
+		}
+		sort.Ints(matched)
+		for _, n := range matched {
+			addWaiverRisk(result, waivers[n], observations[n], now)
+		}
+	}
+
+	rules := make([]WaivedRule, 0, len(waivedRules))
+	for _, waivedRule := range waivedRules {
+		rules = append(rules, waivedRule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].RuleID < rules[j].RuleID })
+	return rules
+}
+
+// addWaiverRisk adds the risk of the waiver for the given observations to the result, and refers
+// to the risk from the findings of the observations.
+func addWaiverRisk(result *oscalTypes.Result, waiver Waiver, observationUUIDs []string, now time.Time) {
+	expiry, _ := waiver.Expiry()
+	risk := oscalTypes.Risk{
+		UUID:        uuid.NewUUID(),
+		Title:       fmt.Sprintf("Waiver for %s %s", waiver.Kind(), waiver.ID()),
+		Description: waiver.Justification,
+		Status:      RiskAccepted,
+		Statement:   fmt.Sprintf("Failures are accepted by %s until %s.", waiver.Approver, waiver.Expires),
+		Deadline:    &expiry,
+		Props: &[]oscalTypes.Property{
+			{Name: approverProp, Value: waiver.Approver, Ns: extensions.TrestleNameSpace},
+		},
+	}
+	if waiver.Expired(now) {
+		risk.Status = RiskOpen
+		risk.Statement = fmt.Sprintf("The waiver approved by %s expired on %s, failures are reported.", waiver.Approver, waiver.Expires)
+	}
+	relatedObservations := make([]oscalTypes.RelatedObservation, 0, len(observationUUIDs))
+	for _, observationUUID := range observationUUIDs {
+		relatedObservations = append(relatedObservations, oscalTypes.RelatedObservation{ObservationUuid: observationUUID})
+	}
+	risk.RelatedObservations = &relatedObservations
+
+	if result.Risks == nil {
+		result.Risks = &[]oscalTypes.Risk{}
+	}
+	*result.Risks = append(*result.Risks, risk)
+
+	if result.Findings == nil {
+		return
+	}
+	for i := range *result.Findings {
+		finding := &(*result.Findings)[i]
+		if finding.RelatedObservations == nil {
+			continue
+		}
+		for _, relatedObservation := range *finding.RelatedObservations {
+			if !slices.Contains(observationUUIDs, relatedObservation.ObservationUuid) {
+				continue
+			}
+			if finding.RelatedRisks == nil {
+				finding.RelatedRisks = &[]oscalTypes.AssociatedRisk{}
+			}
+			*finding.RelatedRisks = append(*finding.RelatedRisks, oscalTypes.AssociatedRisk{RiskUuid: risk.UUID})
+			break
+		}
+	}
+}
+
+// observationFailed returns whether a subject of the observation failed.
+func observationFailed(observation oscalTypes.Observation) bool {
+	if observation.Subjects == nil {
+		return false
+	}
+	for _, subject := range *observation.Subjects {
+		if result, found := SubjectResult(subject); found && result == ResultFail {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptedObservations returns the UUIDs of the observations related to accepted risks of the result.
+func acceptedObservations(result oscalTypes.Result) map[string]bool {
+	accepted := make(map[string]bool)
+	if result.Risks == nil {
+		return accepted
+	}
+	for _, risk := range *result.Risks {
+		if risk.Status != RiskAccepted || risk.RelatedObservations == nil {
+			continue
+		}
+		for _, relatedObservation := range *risk.RelatedObservations {
+			accepted[relatedObservation.ObservationUuid] = true
+		}
+	}
+	return accepted
+}
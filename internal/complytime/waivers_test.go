@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaivers(t *testing.T) {
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.Local)
+	waiversPath := filepath.Join(t.TempDir(), "waivers.yml")
+
+	waivers, err := ReadWaivers(waiversPath)
+	require.NoError(t, err)
+	require.Empty(t, waivers.Waivers)
+
+	require.EqualError(t, waivers.Add(Waiver{Justification: "j", Approver: "a", Expires: "2026-12-31"}, now),
+		"waiver must name a rule or a control")
+	require.EqualError(t, waivers.Add(Waiver{Rule: "rule-1", Approver: "a", Expires: "2026-12-31"}, now),
+		"waiver of rule rule-1 has no justification")
+	require.EqualError(t, waivers.Add(Waiver{Rule: "rule-1", Justification: "j", Approver: "a", Expires: "31/12/2026"}, now),
+		"invalid expiry date \"31/12/2026\" for waiver of rule rule-1, expected YYYY-MM-DD")
+	require.EqualError(t, waivers.Add(Waiver{Rule: "rule-1", Justification: "j", Approver: "a", Expires: "2026-06-15"}, now),
+		"waiver of rule rule-1 expires on 2026-06-15, which is not after today")
+
+	waiver := Waiver{Rule: "rule-1", Justification: "Compensating control", Approver: "Jane", Expires: "2026-06-16"}
+	require.NoError(t, waivers.Add(waiver, now))
+	require.EqualError(t, waivers.Add(waiver, now), "rule rule-1 already has a waiver expiring on 2026-06-16")
+	require.NoError(t, waivers.Add(Waiver{Control: "control-2", Justification: "Legacy", Approver: "Sam", Expires: "2027-01-01"}, now))
+	require.False(t, waiver.Expired(now))
+	require.True(t, waiver.Expired(now.Add(12*time.Hour)))
+
+	require.NoError(t, waivers.Write(waiversPath))
+	readWaivers, err := ReadWaivers(waiversPath)
+	require.NoError(t, err)
+	require.Equal(t, waivers, readWaivers)
+
+	expired, err := readWaivers.Expire("rule-1", now)
+	require.NoError(t, err)
+	require.Equal(t, 1, expired)
+	require.Equal(t, "2026-06-15", readWaivers.Waivers[0].Expires)
+	require.True(t, readWaivers.Waivers[0].Expired(now))
+	_, err = readWaivers.Expire("rule-1", now)
+	require.EqualError(t, err, "no active waiver found for rule-1")
+}
+
+func TestApplyWaivers(t *testing.T) {
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.Local)
+	testPlan := &oscalTypes.AssessmentPlan{
+		LocalDefinitions: &oscalTypes.LocalDefinitions{
+			Activities: &[]oscalTypes.Activity{
+				testActivity("rule-1", "control-1"),
+				testActivity("rule-2", "control-2"),
+				testActivity("rule-3", "control-3"),
+				testActivity("rule-4", "control-2"),
+			},
+		},
+	}
+	observations := []oscalTypes.Observation{
+		testObservation("rule-1", "fail"),
+		testObservation("rule-2", "pass", "fail"),
+		testObservation("rule-3", "fail"),
+		testObservation("rule-4", "pass"),
+	}
+	for i := range observations {
+		observations[i].UUID = fmt.Sprintf("observation-%d", i+1)
+	}
+	testResults := &oscalTypes.AssessmentResults{
+		Results: []oscalTypes.Result{{
+			Observations: &observations,
+			Findings: &[]oscalTypes.Finding{{
+				UUID:                "finding-1",
+				RelatedObservations: &[]oscalTypes.RelatedObservation{{ObservationUuid: observations[0].UUID}},
+			}},
+		}},
+	}
+	waivers := []Waiver{
+		{Rule: "rule-1", Justification: "Compensating control", Approver: "Jane", Expires: "2026-12-31"},
+		{Control: "control-2", Justification: "Legacy system", Approver: "Sam", Expires: "2027-01-01"},
+		{Rule: "rule-3", Justification: "Migration", Approver: "Sam", Expires: "2026-06-01"},
+	}
+
+	waivedRules := ApplyWaivers(testResults, testPlan, waivers, now)
+	require.Equal(t, []WaivedRule{
+		{RuleID: "rule-1", Waiver: waivers[0]},
+		{RuleID: "rule-2", Waiver: waivers[1]},
+		{RuleID: "rule-3", Waiver: waivers[2], Expired: true},
+	}, waivedRules)
+
+	risks := *testResults.Results[0].Risks
+	require.Len(t, risks, 3)
+	require.Equal(t, "Waiver for rule rule-1", risks[0].Title)
+	require.Equal(t, "Compensating control", risks[0].Description)
+	require.Equal(t, RiskAccepted, risks[0].Status)
+	require.Equal(t, []oscalTypes.RelatedObservation{{ObservationUuid: observations[0].UUID}}, *risks[0].RelatedObservations)
+	require.Equal(t, "Waiver for control control-2", risks[1].Title)
+	require.Equal(t, RiskOpen, risks[2].Status)
+	require.Equal(t, []oscalTypes.AssociatedRisk{{RiskUuid: risks[0].UUID}}, *(*testResults.Results[0].Findings)[0].RelatedRisks)
+
+	// Waived failures are not counted as failed, failures of expired waivers are
+	require.Equal(t, map[string]string{
+		"rule-1": ResultWaived,
+		"rule-2": ResultWaived,
+		"rule-3": ResultFail,
+		"rule-4": ResultPass,
+	}, RuleResults(testResults))
+	require.Equal(t, map[string]int{ResultWaived: 2, ResultFail: 1, ResultPass: 1}, RuleCounts(testPlan, testResults))
+}
@@ -0,0 +1,274 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/adrg/xdg"
+	"github.com/goccy/go-yaml"
+)
+
+const (
+	// ConfigEnv is the environment variable overriding the location of the configuration file.
+	ConfigEnv = "COMPLYCTL_CONFIG"
+	// EnvPrefix is the prefix of the environment variables of flags.
+	EnvPrefix = "COMPLYCTL_"
+	// PluginsKey is the key of the plugin option overrides of a context.
+	PluginsKey = "plugins"
+	// configLocation is the location of the configuration file below the XDG config directory.
+	configLocation = "complyctl/config.yaml"
+)
+
+// Config is the configuration of complyctl, a set of named contexts and the context in use.
+type Config struct {
+	// CurrentContext is the name of the context used when no context is selected.
+	CurrentContext string `yaml:"current-context,omitempty"`
+	// Contexts are the contexts of the configuration, indexed by name.
+	Contexts map[string]Context `yaml:"contexts,omitempty"`
+}
+
+// Context is a named set of settings, such as a workspace, content directories, plugin
+// configuration, output formats, and scan thresholds. Settings are the values of command flags
+// indexed by flag name, and the plugin option overrides under the plugins key, indexed by plugin
+// ID and option name.
+type Context map[string]any
+
+// DefaultPath returns the location of the configuration file, set by the COMPLYCTL_CONFIG
+// environment variable or else in the XDG config directory.
+func DefaultPath() string {
+	if path := os.Getenv(ConfigEnv); path != "" {
+		return path
+	}
+	return filepath.Join(xdg.ConfigHome, configLocation)
+}
+
+// EnvName returns the name of the environment variable of a flag, such as COMPLYCTL_PLUGIN_CONFIG
+// for --plugin-config.
+func EnvName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// Read reads the configuration file at the given path. A missing file is an empty configuration.
+func Read(configLocation string) (*Config, error) {
+	config := &Config{}
+	data, err := os.ReadFile(filepath.Clean(configLocation))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return config, nil
+		}
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("error reading configuration %s: %w", configLocation, err)
+	}
+	for name, context := range config.Contexts {
+		if err := context.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid context %s in %s: %w", name, configLocation, err)
+		}
+	}
+	return config, nil
+}
+
+// Write stores the configuration at the given path.
+func (c *Config) Write(configLocation string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(configLocation), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(configLocation, data, 0600)
+}
+
+// Context returns the context with the given name, or the current context when the name is
+// empty. No context is returned when no name is given and there is no current context.
+func (c *Config) Context(name string) (string, Context, error) {
+	if name == "" {
+		name = c.CurrentContext
+	}
+	if name == "" {
+		return "", nil, nil
+	}
+	context, found := c.Contexts[name]
+	if !found {
+		return "", nil, fmt.Errorf("context %q does not exist", name)
+	}
+	return name, context, nil
+}
+
+// ContextNames returns the names of the contexts in order.
+func (c *Config) ContextNames() []string {
+	names := make([]string, 0, len(c.Contexts))
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UseContext makes the context with the given name the current context.
+func (c *Config) UseContext(name string) error {
+	if _, found := c.Contexts[name]; !found {
+		return fmt.Errorf("context %q does not exist", name)
+	}
+	c.CurrentContext = name
+	return nil
+}
+
+// Set sets the value of a setting of the named context, creating the context if it does not
+// exist. Plugin option overrides are set with the plugins.<plugin-id>.<option> key.
+func (c *Config) Set(name, key string, value any) error {
+	if name == "" {
+		return errors.New("context name must be set")
+	}
+	context := c.Contexts[name]
+	if context == nil {
+		context = make(Context)
+	}
+	if pluginID, option, isPlugin, err := parsePluginKey(key); err != nil {
+		return err
+	} else if isPlugin {
+		plugins := context.Plugins()
+		if plugins[pluginID] == nil {
+			plugins[pluginID] = make(map[string]string)
+		}
+		plugins[pluginID][option] = fmt.Sprint(value)
+		context.setPlugins(plugins)
+	} else {
+		context[key] = value
+	}
+	if c.Contexts == nil {
+		c.Contexts = make(map[string]Context)
+	}
+	c.Contexts[name] = context
+	return nil
+}
+
+// Unset removes a setting of the named context. It returns whether the setting was set.
+func (c *Config) Unset(name, key string) (bool, error) {
+	context, found := c.Contexts[name]
+	if !found {
+		return false, fmt.Errorf("context %q does not exist", name)
+	}
+	pluginID, option, isPlugin, err := parsePluginKey(key)
+	if err != nil {
+		return false, err
+	}
+	if !isPlugin {
+		_, found = context[key]
+		delete(context, key)
+		return found, nil
+	}
+	plugins := context.Plugins()
+	if _, found = plugins[pluginID][option]; !found {
+		return false, nil
+	}
+	delete(plugins[pluginID], option)
+	if len(plugins[pluginID]) == 0 {
+		delete(plugins, pluginID)
+	}
+	context.setPlugins(plugins)
+	return true, nil
+}
+
+// Get returns the value of a setting of the context and whether it is set.
+func (c Context) Get(key string) (any, bool, error) {
+	pluginID, option, isPlugin, err := parsePluginKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	if !isPlugin {
+		value, found := c[key]
+		return value, found, nil
+	}
+	value, found := c.Plugins()[pluginID][option]
+	return value, found, nil
+}
+
+// Plugins returns the plugin option overrides of the context, indexed by plugin ID and option name.
+func (c Context) Plugins() map[string]map[string]string {
+	plugins := make(map[string]map[string]string)
+	pluginSettings, _ := c[PluginsKey].(map[string]any)
+	for pluginID, options := range pluginSettings {
+		optionSettings, _ := options.(map[string]any)
+		plugins[pluginID] = make(map[string]string, len(optionSettings))
+		for option, value := range optionSettings {
+			plugins[pluginID][option] = fmt.Sprint(value)
+		}
+	}
+	return plugins
+}
+
+// setPlugins stores plugin option overrides in the context as they are decoded from YAML.
+func (c Context) setPlugins(plugins map[string]map[string]string) {
+	if len(plugins) == 0 {
+		delete(c, PluginsKey)
+		return
+	}
+	pluginSettings := make(map[string]any, len(plugins))
+	for pluginID, options := range plugins {
+		optionSettings := make(map[string]any, len(options))
+		for option, value := range options {
+			optionSettings[option] = value
+		}
+		pluginSettings[pluginID] = optionSettings
+	}
+	c[PluginsKey] = pluginSettings
+}
+
+// Validate checks that the plugin option overrides of the context are maps of option values.
+func (c Context) Validate() error {
+	value, found := c[PluginsKey]
+	if !found {
+		return nil
+	}
+	plugins, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s must map plugin IDs to options", PluginsKey)
+	}
+	for pluginID, options := range plugins {
+		if _, ok := options.(map[string]any); !ok {
+			return fmt.Errorf("%s.%s must map option names to values", PluginsKey, pluginID)
+		}
+	}
+	return nil
+}
+
+// Values returns the flag values of a setting. A list sets a repeatable flag once per item.
+func Values(setting any) []string {
+	items, ok := setting.([]any)
+	if !ok {
+		if values, ok := setting.([]string); ok {
+			return values
+		}
+		return []string{fmt.Sprint(setting)}
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		values = append(values, fmt.Sprint(item))
+	}
+	return values
+}
+
+// parsePluginKey splits a plugins.<plugin-id>.<option> key, and returns whether the key is a
+// plugin option override.
+func parsePluginKey(key string) (string, string, bool, error) {
+	if key == "" {
+		return "", "", false, errors.New("setting key must be set")
+	}
+	if key != PluginsKey && !strings.HasPrefix(key, PluginsKey+".") {
+		return "", "", false, nil
+	}
+	parts := strings.SplitN(key, ".", 3)
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return "", "", false, fmt.Errorf("invalid plugin option key %q, expected %s.<plugin-id>.<option>", key, PluginsKey)
+	}
+	return parts[1], parts[2], true, nil
+}
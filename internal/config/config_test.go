@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrg/xdg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvName(t *testing.T) {
+	require.Equal(t, "COMPLYCTL_WORKSPACE", EnvName("workspace"))
+	require.Equal(t, "COMPLYCTL_PLUGIN_CONFIG", EnvName("plugin-config"))
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv(ConfigEnv, "/etc/complyctl.yaml")
+	require.Equal(t, "/etc/complyctl.yaml", DefaultPath())
+	t.Setenv(ConfigEnv, "")
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+	require.Equal(t, filepath.Join(configHome, "complyctl", "config.yaml"), DefaultPath())
+}
+
+func TestReadMissing(t *testing.T) {
+	config, err := Read(filepath.Join(t.TempDir(), "config.yaml"))
+	require.NoError(t, err)
+	name, context, err := config.Context("")
+	require.NoError(t, err)
+	require.Empty(t, name)
+	require.Nil(t, context)
+}
+
+func TestReadContexts(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`current-context: prod
+contexts:
+  prod:
+    workspace: /srv/complytime/prod
+    content-dir:
+      - /srv/content
+      - /srv/extra-content
+    with-md: true
+    max-failures: 0
+    plugins:
+      openscap:
+        datastream: /usr/share/xml/scap/ssg/content/ssg-rhel9-ds.xml
+  dev:
+    workspace: ./complytime
+`), 0600))
+	config, err := Read(configPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{"dev", "prod"}, config.ContextNames())
+
+	name, context, err := config.Context("")
+	require.NoError(t, err)
+	require.Equal(t, "prod", name)
+	require.Equal(t, []string{"/srv/complytime/prod"}, Values(context["workspace"]))
+	require.Equal(t, []string{"/srv/content", "/srv/extra-content"}, Values(context["content-dir"]))
+	require.Equal(t, []string{"true"}, Values(context["with-md"]))
+	require.Equal(t, []string{"0"}, Values(context["max-failures"]))
+	require.Equal(t, map[string]map[string]string{
+		"openscap": {"datastream": "/usr/share/xml/scap/ssg/content/ssg-rhel9-ds.xml"},
+	}, context.Plugins())
+
+	name, _, err = config.Context("dev")
+	require.NoError(t, err)
+	require.Equal(t, "dev", name)
+	_, _, err = config.Context("staging")
+	require.EqualError(t, err, `context "staging" does not exist`)
+}
+
+func TestReadInvalidPlugins(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("contexts:\n  prod:\n    plugins:\n      openscap: ssg-rhel9-ds.xml\n"), 0600))
+	_, err := Read(configPath)
+	require.ErrorContains(t, err, "invalid context prod")
+	require.ErrorContains(t, err, "plugins.openscap must map option names to values")
+}
+
+func TestSetWriteRead(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "complyctl", "config.yaml")
+	config := &Config{}
+	require.NoError(t, config.Set("prod", "workspace", "/srv/complytime/prod"))
+	require.NoError(t, config.Set("prod", "content-dir", []string{"/srv/content"}))
+	require.NoError(t, config.Set("prod", "with-md", true))
+	require.NoError(t, config.Set("prod", "plugins.openscap.datastream", "ssg-rhel9-ds.xml"))
+	require.NoError(t, config.Set("prod", "plugins.openscap.policy", "tailoring.xml"))
+	require.EqualError(t, config.Set("prod", "plugins.openscap", "ssg-rhel9-ds.xml"), `invalid plugin option key "plugins.openscap", expected plugins.<plugin-id>.<option>`)
+	require.EqualError(t, config.Set("", "workspace", "/srv"), "context name must be set")
+	require.EqualError(t, config.UseContext("dev"), `context "dev" does not exist`)
+	require.NoError(t, config.UseContext("prod"))
+	require.NoError(t, config.Write(configPath))
+
+	read, err := Read(configPath)
+	require.NoError(t, err)
+	require.Equal(t, "prod", read.CurrentContext)
+	_, context, err := read.Context("")
+	require.NoError(t, err)
+	value, found, err := context.Get("content-dir")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, []string{"/srv/content"}, Values(value))
+	value, found, err = context.Get("plugins.openscap.policy")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "tailoring.xml", value)
+	_, found, err = context.Get("plugins.openscap.arf")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	found, err = read.Unset("prod", "plugins.openscap.datastream")
+	require.NoError(t, err)
+	require.True(t, found)
+	found, err = read.Unset("prod", "plugins.openscap.policy")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.NotContains(t, read.Contexts["prod"], PluginsKey)
+	found, err = read.Unset("prod", "with-md")
+	require.NoError(t, err)
+	require.True(t, found)
+	found, err = read.Unset("prod", "with-md")
+	require.NoError(t, err)
+	require.False(t, found)
+	_, err = read.Unset("dev", "with-md")
+	require.EqualError(t, err, `context "dev" does not exist`)
+}
@@ -0,0 +1,365 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package content
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
+	"github.com/oscal-compass/oscal-sdk-go/settings"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/complytime/format"
+)
+
+// ManifestFile is the file in the content root that tracks the installed bundles.
+const ManifestFile = "installed-bundles.json"
+
+// ErrBundleNotFound is returned when a bundle is not installed in the content root.
+var ErrBundleNotFound = errors.New("bundle not found")
+
+// Bundle is a content bundle installed in a content root.
+type Bundle struct {
+	// Name identifies the bundle in the content root.
+	Name string `json:"name"`
+	// Version is the version of the bundle, if known.
+	Version string `json:"version,omitempty"`
+	// Digest is the sha256 digest of the archive or OCI image manifest the bundle was installed from.
+	Digest string `json:"digest"`
+	// Source is the path the bundle was installed from.
+	Source string `json:"source"`
+	// Frameworks are the IDs of the frameworks implemented by the component definitions of the bundle.
+	Frameworks []string `json:"frameworks"`
+	// Files are the installed files by path relative to the content root.
+	Files []string `json:"files"`
+	// InstalledAt is the time the bundle was installed.
+	InstalledAt time.Time `json:"installedAt"`
+}
+
+// Manifest tracks the bundles installed in a content root.
+type Manifest struct {
+	Bundles []Bundle `json:"bundles"`
+}
+
+// find returns the index of the bundle with the given name, or -1.
+func (m Manifest) find(name string) int {
+	for i, bundle := range m.Bundles {
+		if bundle.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// ReadManifest reads the manifest of the installed bundles in the content root. A content
+// root without installed bundles has an empty manifest.
+func ReadManifest(appDir complytime.ApplicationDirectory) (Manifest, error) {
+	var manifest Manifest
+	manifestPath := filepath.Join(appDir.AppDir(), ManifestFile)
+	data, err := os.ReadFile(filepath.Clean(manifestPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return manifest, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("error reading bundle manifest %s: %w", manifestPath, err)
+	}
+	return manifest, nil
+}
+
+// writeManifest writes the manifest to the content root, or removes it when no bundles are installed.
+func writeManifest(appDir complytime.ApplicationDirectory, manifest Manifest) error {
+	manifestPath := filepath.Join(appDir.AppDir(), ManifestFile)
+	if len(manifest.Bundles) == 0 {
+		if err := os.Remove(manifestPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	sort.Slice(manifest.Bundles, func(i, j int) bool { return manifest.Bundles[i].Name < manifest.Bundles[j].Name })
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(manifestPath, data, 0644)
+}
+
+// Install installs the bundle files into the content root and records the bundle in the manifest.
+// Installing a bundle with the name of an installed bundle upgrades it, removing the files that
+// are no longer part of the bundle. The installation fails when a framework of the bundle is
+// implemented by another bundle or by component definitions that were not installed from a
+// bundle, or when a file of the bundle exists and belongs to another bundle or to no bundle.
+func Install(appDir complytime.ApplicationDirectory, source *Source, validator validation.Validator) (*Bundle, error) {
+	if len(source.Files) == 0 {
+		return nil, fmt.Errorf("no content found in %s", source.Location)
+	}
+	frameworks, versions, err := checkFiles(source.Files, validator)
+	if err != nil {
+		return nil, err
+	}
+	version := source.Version
+	if version == "" && len(versions) == 1 {
+		version = versions[0]
+	}
+
+	manifest, err := ReadManifest(appDir)
+	if err != nil {
+		return nil, err
+	}
+	previous := manifest.find(source.Name)
+	owned := make(map[string]bool)
+	if previous >= 0 {
+		for _, file := range manifest.Bundles[previous].Files {
+			owned[file] = true
+		}
+	}
+	if err := checkConflicts(appDir, manifest, source, frameworks, owned); err != nil {
+		return nil, err
+	}
+
+	bundle := Bundle{
+		Name:        source.Name,
+		Version:     version,
+		Digest:      source.Digest,
+		Source:      source.Location,
+		Frameworks:  frameworks,
+		Files:       sortedFiles(source.Files),
+		InstalledAt: time.Now().UTC(),
+	}
+
+	var written []string
+	for _, file := range bundle.Files {
+		if err := writeFile(appDir, file, source.Files[file]); err != nil {
+			// Leave the content root as it was, except for files overwritten by the upgrade
+			for _, writtenFile := range written {
+				if !owned[writtenFile] {
+					_ = removeFile(appDir, writtenFile)
+				}
+			}
+			return nil, fmt.Errorf("error installing %s: %w", file, err)
+		}
+		written = append(written, file)
+	}
+
+	if previous >= 0 {
+		for file := range owned {
+			if _, found := source.Files[file]; !found {
+				if err := removeFile(appDir, file); err != nil {
+					return nil, err
+				}
+			}
+		}
+		manifest.Bundles[previous] = bundle
+	} else {
+		manifest.Bundles = append(manifest.Bundles, bundle)
+	}
+	if err := writeManifest(appDir, manifest); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// Remove removes the files of the installed bundle from the content root, along with the
+// directories left empty, and removes the bundle from the manifest.
+func Remove(appDir complytime.ApplicationDirectory, name string) (*Bundle, error) {
+	manifest, err := ReadManifest(appDir)
+	if err != nil {
+		return nil, err
+	}
+	index := manifest.find(name)
+	if index < 0 {
+		return nil, fmt.Errorf("%w: %s is not installed in %s", ErrBundleNotFound, name, appDir.AppDir())
+	}
+	bundle := manifest.Bundles[index]
+	for _, file := range bundle.Files {
+		if err := removeFile(appDir, file); err != nil {
+			return nil, err
+		}
+	}
+	manifest.Bundles = append(manifest.Bundles[:index], manifest.Bundles[index+1:]...)
+	if err := writeManifest(appDir, manifest); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// sortedFiles returns the paths of the bundle files in order.
+func sortedFiles(files map[string][]byte) []string {
+	paths := make([]string, 0, len(files))
+	for file := range files {
+		paths = append(paths, file)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// checkFiles validates the bundle files and returns the IDs of the frameworks implemented by
+// the component definitions and the distinct versions of the component definitions.
+func checkFiles(files map[string][]byte, validator validation.Validator) ([]string, []string, error) {
+	frameworks := make(map[string]bool)
+	versions := make(map[string]bool)
+	for _, file := range sortedFiles(files) {
+		data := files[file]
+		if strings.HasPrefix(file, complytime.PluginDir+"/") {
+			var manifest plugin.Manifest
+			if err := json.Unmarshal(data, &manifest); err != nil {
+				return nil, nil, fmt.Errorf("error reading plugin manifest %s: %w", file, err)
+			}
+			expected := strings.TrimSuffix(strings.TrimPrefix(path.Base(file), complytime.PluginManifestPrefix), complytime.PluginManifestSuffix)
+			if manifest.ID.String() != expected {
+				return nil, nil, fmt.Errorf("plugin manifest %s has id %q, expected %q", file, manifest.ID, expected)
+			}
+			continue
+		}
+		data, err := format.ToJSON(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading %s: %w", file, err)
+		}
+		var models oscalTypes.OscalModels
+		if err := json.Unmarshal(data, &models); err != nil {
+			return nil, nil, fmt.Errorf("error reading %s: %w", file, err)
+		}
+		if err := validator.Validate(models); err != nil {
+			return nil, nil, fmt.Errorf("invalid content in %s: %w", file, err)
+		}
+		if strings.HasPrefix(file, complytime.BundlesDir+"/") {
+			if models.ComponentDefinition == nil {
+				return nil, nil, fmt.Errorf("%s is not a component definition", file)
+			}
+			for _, framework := range frameworkIDs(*models.ComponentDefinition) {
+				frameworks[framework] = true
+			}
+			versions[models.ComponentDefinition.Metadata.Version] = true
+		}
+	}
+	return sortedKeys(frameworks), sortedKeys(versions), nil
+}
+
+// checkConflicts checks that the frameworks and files of the bundle are not provided by other
+// bundles or by content that was not installed from a bundle. Files owned by a previous version
+// of the bundle can be replaced.
+func checkConflicts(appDir complytime.ApplicationDirectory, manifest Manifest, source *Source, frameworks []string, owned map[string]bool) error {
+	providers := make(map[string]string)
+	managed := make(map[string]string)
+	for _, bundle := range manifest.Bundles {
+		for _, file := range bundle.Files {
+			managed[file] = bundle.Name
+		}
+		if bundle.Name == source.Name {
+			continue
+		}
+		for _, framework := range bundle.Frameworks {
+			providers[framework] = fmt.Sprintf("bundle %q", bundle.Name)
+		}
+	}
+
+	// Component definitions installed by packages or by hand
+	files, err := complytime.ContentPath{{ApplicationDirectory: appDir}}.ComponentDefinitionFiles()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if _, found := managed[path.Join(complytime.BundlesDir, file.Name)]; found {
+			continue
+		}
+		data, err := os.ReadFile(file.Path)
+		if err != nil {
+			return err
+		}
+		var models oscalTypes.OscalModels
+		if data, err = format.ToJSON(data); err != nil {
+			continue
+		}
+		if err := json.Unmarshal(data, &models); err != nil || models.ComponentDefinition == nil {
+			continue
+		}
+		for _, framework := range frameworkIDs(*models.ComponentDefinition) {
+			providers[framework] = file.Path
+		}
+	}
+
+	for _, framework := range frameworks {
+		if provider, found := providers[framework]; found {
+			return fmt.Errorf("framework %q is already provided by %s", framework, provider)
+		}
+	}
+
+	for _, file := range sortedFiles(source.Files) {
+		if owned[file] {
+			continue
+		}
+		if bundleName, found := managed[file]; found {
+			return fmt.Errorf("file %s is already installed by bundle %q", file, bundleName)
+		}
+		if _, err := os.Stat(filepath.Join(appDir.AppDir(), filepath.FromSlash(file))); err == nil {
+			return fmt.Errorf("file %s already exists in %s and was not installed from a bundle", file, appDir.AppDir())
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of the set in order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// frameworkIDs returns the IDs of the frameworks implemented by the component definition.
+func frameworkIDs(compDef oscalTypes.ComponentDefinition) []string {
+	var ids []string
+	if compDef.Components == nil {
+		return ids
+	}
+	for _, component := range *compDef.Components {
+		if component.ControlImplementations == nil {
+			continue
+		}
+		for _, implementation := range *component.ControlImplementations {
+			if id, found := settings.GetFrameworkShortName(implementation); found {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// writeFile writes a bundle file below the content root.
+func writeFile(appDir complytime.ApplicationDirectory, file string, data []byte) error {
+	filePath := filepath.Join(appDir.AppDir(), filepath.FromSlash(file))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// removeFile removes a bundle file from the content root along with the parent directories
+// left empty, up to the bundles, controls, and plugins directories.
+func removeFile(appDir complytime.ApplicationDirectory, file string) error {
+	filePath := filepath.Join(appDir.AppDir(), filepath.FromSlash(file))
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for dir := path.Dir(file); strings.Contains(dir, "/"); dir = path.Dir(dir) {
+		if err := os.Remove(filepath.Join(appDir.AppDir(), filepath.FromSlash(dir))); err != nil {
+			// The directory is not empty or was already removed
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package content
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrg/xdg"
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// testComponentDefinition returns a component definition implementing the framework.
+func testComponentDefinition(framework, version string) []byte {
+	return []byte(fmt.Sprintf(`{
+  "component-definition": {
+    "uuid": "7791eb3a-764a-41e0-8cd3-8d775c9e95bf",
+    "metadata": {"title": "Test", "last-modified": "2023-02-21T06:53:42+00:00", "version": %q, "oscal-version": "1.1.2"},
+    "components": [
+      {
+        "uuid": "4e19131e-b361-4f0e-8262-02bf4456202e",
+        "type": "software",
+        "title": "My Software",
+        "description": "Target software.",
+        "control-implementations": [
+          {
+            "uuid": "bb6420f5-146c-44c0-b708-79b96e7a009e",
+            "source": "file://controls/profile.json",
+            "description": "Implementation.",
+            "props": [{"name": "Framework_Short_Name", "ns": "https://oscal-compass.github.io/compliance-trestle/schemas/oscal/cd", "value": %q}],
+            "implemented-requirements": []
+          }
+        ]
+      }
+    ]
+  }
+}`, version, framework))
+}
+
+func testSource(name, framework string) *Source {
+	return &Source{
+		Name:     name,
+		Digest:   "sha256:0000",
+		Location: name + ".tar.gz",
+		Files: map[string][]byte{
+			"bundles/" + framework + "-component-definition.json": testComponentDefinition(framework, "1.0.0"),
+			"controls/" + framework + "/profile.json":             []byte(`{"profile": {}}`),
+			"plugins/c2p-" + framework + "-manifest.json":         []byte(fmt.Sprintf(`{"metadata": {"id": %q}}`, framework)),
+		},
+	}
+}
+
+func testContentRoot(t *testing.T) complytime.ApplicationDirectory {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+	contentRoot, err := complytime.NewContentRoot(false)
+	require.NoError(t, err)
+	return contentRoot
+}
+
+func TestInstallAndRemove(t *testing.T) {
+	contentRoot := testContentRoot(t)
+	validator := validation.NoopValidator{}
+
+	bundle, err := Install(contentRoot, testSource("anssi-content", "anssi"), validator)
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", bundle.Version)
+	require.Equal(t, []string{"anssi"}, bundle.Frameworks)
+	require.Equal(t, []string{
+		"bundles/anssi-component-definition.json",
+		"controls/anssi/profile.json",
+		"plugins/c2p-anssi-manifest.json",
+	}, bundle.Files)
+	require.FileExists(t, filepath.Join(contentRoot.ControlDir(), "anssi", "profile.json"))
+
+	_, err = Install(contentRoot, testSource("other-content", "cis"), validator)
+	require.NoError(t, err)
+
+	manifest, err := ReadManifest(contentRoot)
+	require.NoError(t, err)
+	require.Len(t, manifest.Bundles, 2)
+	require.Equal(t, "anssi-content", manifest.Bundles[0].Name)
+	require.Equal(t, "other-content", manifest.Bundles[1].Name)
+
+	// Upgrading removes the files that are no longer part of the bundle
+	upgrade := testSource("anssi-content", "anssi")
+	upgrade.Version = "2.0.0"
+	delete(upgrade.Files, "controls/anssi/profile.json")
+	bundle, err = Install(contentRoot, upgrade, validator)
+	require.NoError(t, err)
+	require.Equal(t, "2.0.0", bundle.Version)
+	require.NoDirExists(t, filepath.Join(contentRoot.ControlDir(), "anssi"))
+
+	_, err = Remove(contentRoot, "anssi-content")
+	require.NoError(t, err)
+	require.NoFileExists(t, filepath.Join(contentRoot.BundleDir(), "anssi-component-definition.json"))
+	require.FileExists(t, filepath.Join(contentRoot.BundleDir(), "cis-component-definition.json"))
+
+	_, err = Remove(contentRoot, "anssi-content")
+	require.ErrorIs(t, err, ErrBundleNotFound)
+
+	_, err = Remove(contentRoot, "other-content")
+	require.NoError(t, err)
+	require.NoFileExists(t, filepath.Join(contentRoot.AppDir(), ManifestFile))
+	require.DirExists(t, contentRoot.ControlDir())
+	entries, err := os.ReadDir(contentRoot.ControlDir())
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestInstallFormats(t *testing.T) {
+	contentRoot := testContentRoot(t)
+
+	definition, err := yaml.JSONToYAML(testComponentDefinition("cis", "1.0.0"))
+	require.NoError(t, err)
+	source := &Source{
+		Name:     "cis-content",
+		Location: "cis-content.tar.gz",
+		Files: map[string][]byte{
+			"bundles/cis/cis-component-definition.yaml": definition,
+			"controls/cis/profile.xml":                  []byte(`<profile xmlns="http://csrc.nist.gov/ns/oscal/1.0"/>`),
+		},
+	}
+	bundle, err := Install(contentRoot, source, validation.NoopValidator{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"cis"}, bundle.Frameworks)
+	require.FileExists(t, filepath.Join(contentRoot.BundleDir(), "cis", "cis-component-definition.yaml"))
+
+	// Frameworks of component definitions installed by hand in subdirectories are detected
+	definition, err = yaml.JSONToYAML(testComponentDefinition("fedora", "1.0.0"))
+	require.NoError(t, err)
+	handInstalled := filepath.Join(contentRoot.BundleDir(), "fedora", "fedora-component-definition.yml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(handInstalled), 0700))
+	require.NoError(t, os.WriteFile(handInstalled, definition, 0600))
+	_, err = Install(contentRoot, testSource("fedora-content", "fedora"), validation.NoopValidator{})
+	require.EqualError(t, err, `framework "fedora" is already provided by `+handInstalled)
+}
+
+func TestInstallConflicts(t *testing.T) {
+	contentRoot := testContentRoot(t)
+	validator := validation.NoopValidator{}
+
+	_, err := Install(contentRoot, testSource("anssi-content", "anssi"), validator)
+	require.NoError(t, err)
+
+	_, err = Install(contentRoot, testSource("anssi-copy", "anssi"), validator)
+	require.EqualError(t, err, `framework "anssi" is already provided by bundle "anssi-content"`)
+
+	shared := testSource("cis-content", "cis")
+	shared.Files["controls/anssi/profile.json"] = []byte(`{"profile": {}}`)
+	_, err = Install(contentRoot, shared, validator)
+	require.EqualError(t, err, `file controls/anssi/profile.json is already installed by bundle "anssi-content"`)
+
+	// Content installed by packages or by hand is not replaced
+	require.NoError(t, os.WriteFile(filepath.Join(contentRoot.BundleDir(), "fedora-component-definition.json"),
+		testComponentDefinition("fedora", "1.0.0"), 0600))
+	_, err = Install(contentRoot, testSource("fedora-content", "fedora"), validator)
+	require.ErrorContains(t, err, `framework "fedora" is already provided by `+contentRoot.BundleDir())
+
+	require.NoError(t, os.WriteFile(filepath.Join(contentRoot.PluginManifestDir(), "c2p-rhel-manifest.json"), []byte("{}"), 0600))
+	_, err = Install(contentRoot, testSource("rhel-content", "rhel"), validator)
+	require.ErrorContains(t, err, "file plugins/c2p-rhel-manifest.json already exists")
+
+	manifest, err := ReadManifest(contentRoot)
+	require.NoError(t, err)
+	require.Len(t, manifest.Bundles, 1)
+}
+
+func TestInstallInvalidContent(t *testing.T) {
+	contentRoot := testContentRoot(t)
+
+	tests := []struct {
+		name    string
+		files   map[string][]byte
+		wantErr string
+	}{
+		{
+			name:    "Invalid/NoContent",
+			files:   map[string][]byte{},
+			wantErr: "no content found in bundle.tar.gz",
+		},
+		{
+			name:    "Invalid/ManifestID",
+			files:   map[string][]byte{"plugins/c2p-openscap-manifest.json": []byte(`{"metadata": {"id": "other"}}`)},
+			wantErr: `plugin manifest plugins/c2p-openscap-manifest.json has id "other", expected "openscap"`,
+		},
+		{
+			name:    "Invalid/NotComponentDefinition",
+			files:   map[string][]byte{"bundles/cis-component-definition.json": []byte(`{"catalog": {}}`)},
+			wantErr: "bundles/cis-component-definition.json is not a component definition",
+		},
+		{
+			name:    "Invalid/JSON",
+			files:   map[string][]byte{"controls/catalog.json": []byte(`{`)},
+			wantErr: "error reading controls/catalog.json: unexpected end of JSON input",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &Source{Name: "bundle", Location: "bundle.tar.gz", Files: tt.files}
+			_, err := Install(contentRoot, source, validation.NoopValidator{})
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestReadManifest(t *testing.T) {
+	contentRoot := testContentRoot(t)
+
+	manifest, err := ReadManifest(contentRoot)
+	require.NoError(t, err)
+	require.Empty(t, manifest.Bundles)
+
+	require.NoError(t, os.MkdirAll(contentRoot.AppDir(), 0700))
+	data, err := json.Marshal(Manifest{Bundles: []Bundle{{Name: "anssi-content"}}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(contentRoot.AppDir(), ManifestFile), data, 0600))
+	manifest, err = ReadManifest(contentRoot)
+	require.NoError(t, err)
+	require.Equal(t, "anssi-content", manifest.Bundles[0].Name)
+
+	require.NoError(t, os.WriteFile(filepath.Join(contentRoot.AppDir(), ManifestFile), []byte("{"), 0600))
+	_, err = ReadManifest(contentRoot)
+	require.ErrorContains(t, err, "error reading bundle manifest")
+}
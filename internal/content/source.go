@@ -0,0 +1,280 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package content
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/complytime/format"
+)
+
+const (
+	// maxFileSize limits the size of a single content file read from a bundle.
+	maxFileSize = 64 << 20
+
+	ociLayoutFile        = "oci-layout"
+	ociIndexFile         = "index.json"
+	ociBlobsDir          = "blobs"
+	ociManifestType      = "application/vnd.oci.image.manifest.v1+json"
+	ociRefAnnotation     = "org.opencontainers.image.ref.name"
+	ociTitleAnnotation   = "org.opencontainers.image.title"
+	ociVersionAnnotation = "org.opencontainers.image.version"
+)
+
+// Source is a content bundle read from a tarball or from a local OCI image layout.
+type Source struct {
+	// Name is the bundle name from the OCI title annotation or the archive file name.
+	Name string
+	// Version is the bundle version from the OCI version annotation, if any.
+	Version string
+	// Digest is the sha256 digest of the tarball or of the OCI image manifest.
+	Digest string
+	// Location is the path the bundle was read from.
+	Location string
+	// Files are the content files by path relative to the content root.
+	Files map[string][]byte
+}
+
+// ociDescriptor describes a blob in an OCI image layout.
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ociIndex is the index.json of an OCI image layout.
+type ociIndex struct {
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+// ociManifest is an OCI image manifest.
+type ociManifest struct {
+	MediaType   string            `json:"mediaType"`
+	Layers      []ociDescriptor   `json:"layers"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ReadSource reads a content bundle from a tarball, optionally gzip compressed, or from an
+// OCI image layout directory. The tag selects the image in an OCI image layout with several
+// images. Archives contain the content root layout, with component definitions in bundles/,
+// catalogs and profiles in controls/, and plugin manifests in plugins/. Other files are ignored.
+func ReadSource(location, tag string) (*Source, error) {
+	info, err := os.Stat(location)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return readOCILayout(location, tag)
+	}
+	if tag != "" {
+		return nil, fmt.Errorf("a tag can only be used with an OCI image layout, %s is a file", location)
+	}
+	return readTarball(location)
+}
+
+func readTarball(location string) (*Source, error) {
+	data, err := os.ReadFile(filepath.Clean(location))
+	if err != nil {
+		return nil, err
+	}
+	source := &Source{
+		Name:     archiveName(location),
+		Digest:   digestOf(data),
+		Location: location,
+		Files:    make(map[string][]byte),
+	}
+	if err := readLayer(bytes.NewReader(data), source.Files); err != nil {
+		return nil, fmt.Errorf("error reading archive %s: %w", location, err)
+	}
+	return source, nil
+}
+
+// archiveName returns the archive file name without the archive extensions.
+func archiveName(location string) string {
+	name := filepath.Base(location)
+	for _, ext := range []string{".gz", ".tgz", ".tar"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name
+}
+
+func readOCILayout(layoutDir, tag string) (*Source, error) {
+	if _, err := os.Stat(filepath.Join(layoutDir, ociLayoutFile)); err != nil {
+		return nil, fmt.Errorf("directory %s is not an OCI image layout: %w", layoutDir, err)
+	}
+	indexData, err := os.ReadFile(filepath.Join(layoutDir, ociIndexFile))
+	if err != nil {
+		return nil, err
+	}
+	var index ociIndex
+	if err := json.Unmarshal(indexData, &index); err != nil {
+		return nil, fmt.Errorf("error reading OCI image index in %s: %w", layoutDir, err)
+	}
+	descriptor, err := selectManifest(index, tag)
+	if err != nil {
+		return nil, fmt.Errorf("OCI image layout %s: %w", layoutDir, err)
+	}
+
+	manifestData, err := readBlob(layoutDir, descriptor.Digest)
+	if err != nil {
+		return nil, err
+	}
+	var manifest ociManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("error reading OCI image manifest %s: %w", descriptor.Digest, err)
+	}
+
+	source := &Source{
+		Name:     manifest.Annotations[ociTitleAnnotation],
+		Version:  manifest.Annotations[ociVersionAnnotation],
+		Digest:   descriptor.Digest,
+		Location: layoutDir,
+		Files:    make(map[string][]byte),
+	}
+	if source.Name == "" {
+		source.Name = filepath.Base(filepath.Clean(layoutDir))
+	}
+	for _, layer := range manifest.Layers {
+		layerData, err := readBlob(layoutDir, layer.Digest)
+		if err != nil {
+			return nil, err
+		}
+		if err := readLayer(bytes.NewReader(layerData), source.Files); err != nil {
+			return nil, fmt.Errorf("error reading layer %s: %w", layer.Digest, err)
+		}
+	}
+	return source, nil
+}
+
+// selectManifest returns the image manifest with the given tag, or the only image manifest
+// of the index when no tag is given.
+func selectManifest(index ociIndex, tag string) (ociDescriptor, error) {
+	var manifests []ociDescriptor
+	for _, descriptor := range index.Manifests {
+		if descriptor.MediaType != ociManifestType {
+			continue
+		}
+		if tag == "" || descriptor.Annotations[ociRefAnnotation] == tag {
+			manifests = append(manifests, descriptor)
+		}
+	}
+	switch {
+	case len(manifests) == 1:
+		return manifests[0], nil
+	case len(manifests) == 0 && tag != "":
+		return ociDescriptor{}, fmt.Errorf("no image manifest found with tag %q", tag)
+	case len(manifests) == 0:
+		return ociDescriptor{}, errors.New("no image manifest found")
+	default:
+		return ociDescriptor{}, errors.New("several image manifests found, select one with a tag")
+	}
+}
+
+// readBlob reads a blob from the OCI image layout and verifies its digest.
+func readBlob(layoutDir, digest string) ([]byte, error) {
+	algorithm, encoded, found := strings.Cut(digest, ":")
+	if !found || algorithm != "sha256" {
+		return nil, fmt.Errorf("unsupported digest %q", digest)
+	}
+	if _, err := hex.DecodeString(encoded); err != nil || len(encoded) != sha256.Size*2 {
+		return nil, fmt.Errorf("invalid digest %q", digest)
+	}
+	data, err := os.ReadFile(filepath.Join(layoutDir, ociBlobsDir, algorithm, encoded))
+	if err != nil {
+		return nil, err
+	}
+	if digestOf(data) != digest {
+		return nil, fmt.Errorf("blob %s does not match its digest", digest)
+	}
+	return data, nil
+}
+
+// readLayer reads the content files from a tar stream, which may be gzip compressed.
+func readLayer(reader io.Reader, files map[string][]byte) error {
+	buffered := bufio.NewReader(reader)
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	} else {
+		reader = buffered
+	}
+
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name, err := contentPath(header.Name)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			continue
+		}
+		if header.Size > maxFileSize {
+			return fmt.Errorf("file %s exceeds the maximum size of %d bytes", header.Name, maxFileSize)
+		}
+		data, err := io.ReadAll(io.LimitReader(tarReader, maxFileSize))
+		if err != nil {
+			return err
+		}
+		files[name] = data
+	}
+}
+
+// contentPath returns the cleaned path of an archive entry when it is a content file, or an
+// empty string when the entry is not installed. Entries escaping the archive root are rejected.
+func contentPath(name string) (string, error) {
+	cleaned := path.Clean(name)
+	if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("archive entry %q is outside of the archive root", name)
+	}
+	dir, base := path.Split(cleaned)
+	switch {
+	case strings.TrimSuffix(dir, "/") == complytime.PluginDir:
+		if strings.HasPrefix(base, complytime.PluginManifestPrefix) && strings.HasSuffix(base, complytime.PluginManifestSuffix) {
+			return cleaned, nil
+		}
+	// Component definitions, catalogs, and profiles can be organized in subdirectories, such as controls/anssi/
+	case strings.HasPrefix(dir, complytime.BundlesDir+"/"):
+		if complytime.IsComponentDefinitionFile(base) {
+			return cleaned, nil
+		}
+	case strings.HasPrefix(dir, complytime.ControlsDir+"/"):
+		if format.HasExtension(base) {
+			return cleaned, nil
+		}
+	}
+	return "", nil
+}
+
+// digestOf returns the sha256 digest of the data in OCI digest format.
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
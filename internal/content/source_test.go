@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package content
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testTarball returns a tar archive with the given files, gzip compressed if requested.
+func testTarball(t *testing.T, files map[string]string, compress bool) []byte {
+	var buffer bytes.Buffer
+	var tarWriter *tar.Writer
+	var gzipWriter *gzip.Writer
+	if compress {
+		gzipWriter = gzip.NewWriter(&buffer)
+		tarWriter = tar.NewWriter(gzipWriter)
+	} else {
+		tarWriter = tar.NewWriter(&buffer)
+	}
+	for name, data := range files {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), Typeflag: tar.TypeReg}))
+		_, err := tarWriter.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	if compress {
+		require.NoError(t, gzipWriter.Close())
+	}
+	return buffer.Bytes()
+}
+
+// writeBlob writes the data as a blob of the OCI image layout and returns its descriptor.
+func writeBlob(t *testing.T, layoutDir, mediaType string, data []byte, annotations map[string]string) ociDescriptor {
+	digest := digestOf(data)
+	blobPath := filepath.Join(layoutDir, ociBlobsDir, "sha256", strings.TrimPrefix(digest, "sha256:"))
+	require.NoError(t, os.MkdirAll(filepath.Dir(blobPath), 0700))
+	require.NoError(t, os.WriteFile(blobPath, data, 0600))
+	return ociDescriptor{MediaType: mediaType, Digest: digest, Annotations: annotations}
+}
+
+// testOCILayout writes an OCI image layout with an image for each tag.
+func testOCILayout(t *testing.T, tags ...string) string {
+	layoutDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(layoutDir, ociLayoutFile), []byte(`{"imageLayoutVersion": "1.0.0"}`), 0600))
+	var index ociIndex
+	for _, tag := range tags {
+		layer := testTarball(t, map[string]string{"controls/" + tag + ".json": "{}"}, true)
+		manifest := ociManifest{
+			MediaType: ociManifestType,
+			Layers:    []ociDescriptor{writeBlob(t, layoutDir, "application/vnd.oci.image.layer.v1.tar+gzip", layer, nil)},
+			Annotations: map[string]string{
+				ociTitleAnnotation:   "anssi-content",
+				ociVersionAnnotation: tag,
+			},
+		}
+		data, err := json.Marshal(manifest)
+		require.NoError(t, err)
+		index.Manifests = append(index.Manifests, writeBlob(t, layoutDir, ociManifestType, data, map[string]string{ociRefAnnotation: tag}))
+	}
+	data, err := json.Marshal(index)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(layoutDir, ociIndexFile), data, 0600))
+	return layoutDir
+}
+
+func TestReadSourceTarball(t *testing.T) {
+	files := map[string]string{
+		"bundles/anssi-component-definition.json":  "{}",
+		"./controls/anssi/profile.json":            "{}",
+		"bundles/cis/cis-component-definition.yml": "component-definition: {}",
+		"controls/cis/catalog.xml":                 "<catalog/>",
+		"plugins/c2p-openscap-manifest.json":       "{}",
+		"plugins/openscap-plugin":                  "binary",
+		"README.md":                                "readme",
+	}
+	for _, compress := range []bool{true, false} {
+		archive := filepath.Join(t.TempDir(), "anssi-content.tar.gz")
+		data := testTarball(t, files, compress)
+		require.NoError(t, os.WriteFile(archive, data, 0600))
+
+		source, err := ReadSource(archive, "")
+		require.NoError(t, err)
+		require.Equal(t, "anssi-content", source.Name)
+		require.Equal(t, digestOf(data), source.Digest)
+		require.Len(t, source.Files, 5)
+		require.Contains(t, source.Files, "controls/anssi/profile.json")
+	}
+
+	archive := filepath.Join(t.TempDir(), "anssi-content.tar")
+	require.NoError(t, os.WriteFile(archive, testTarball(t, map[string]string{"../controls/profile.json": "{}"}, false), 0600))
+	_, err := ReadSource(archive, "")
+	require.ErrorContains(t, err, `archive entry "../controls/profile.json" is outside of the archive root`)
+
+	_, err = ReadSource(archive, "1.0")
+	require.ErrorContains(t, err, "a tag can only be used with an OCI image layout")
+}
+
+func TestReadSourceOCILayout(t *testing.T) {
+	layoutDir := testOCILayout(t, "1.0")
+	source, err := ReadSource(layoutDir, "")
+	require.NoError(t, err)
+	require.Equal(t, "anssi-content", source.Name)
+	require.Equal(t, "1.0", source.Version)
+	require.Contains(t, source.Files, "controls/1.0.json")
+
+	layoutDir = testOCILayout(t, "1.0", "2.0")
+	_, err = ReadSource(layoutDir, "")
+	require.ErrorContains(t, err, "several image manifests found, select one with a tag")
+	source, err = ReadSource(layoutDir, "2.0")
+	require.NoError(t, err)
+	require.Equal(t, "2.0", source.Version)
+	_, err = ReadSource(layoutDir, "3.0")
+	require.ErrorContains(t, err, `no image manifest found with tag "3.0"`)
+
+	_, err = ReadSource(t.TempDir(), "")
+	require.ErrorContains(t, err, "is not an OCI image layout")
+}
+
+func TestReadBlob(t *testing.T) {
+	layoutDir := t.TempDir()
+	descriptor := writeBlob(t, layoutDir, ociManifestType, []byte("data"), nil)
+	data, err := readBlob(layoutDir, descriptor.Digest)
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), data)
+
+	blobPath := filepath.Join(layoutDir, ociBlobsDir, "sha256", strings.TrimPrefix(descriptor.Digest, "sha256:"))
+	require.NoError(t, os.WriteFile(blobPath, []byte("changed"), 0600))
+	_, err = readBlob(layoutDir, descriptor.Digest)
+	require.ErrorContains(t, err, "does not match its digest")
+
+	_, err = readBlob(layoutDir, "sha256:../../etc/passwd")
+	require.ErrorContains(t, err, "invalid digest")
+	_, err = readBlob(layoutDir, "md5:abc")
+	require.ErrorContains(t, err, "unsupported digest")
+}
@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package evidence
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+const (
+	// InTotoPayloadType is the DSSE payload type of in-toto statements.
+	InTotoPayloadType = "application/vnd.in-toto+json"
+	// StatementType is the type of in-toto statements.
+	StatementType = "https://in-toto.io/Statement/v1"
+	// ScanPredicateType is the predicate type of the in-toto statements of scans.
+	ScanPredicateType = "https://github.com/complytime/complyctl/attestation/scan/v1"
+)
+
+// Control statuses of a scan predicate, as the finding states of assessment results.
+const (
+	ControlSatisfied    = "satisfied"
+	ControlNotSatisfied = "not-satisfied"
+)
+
+// digestPattern matches the digests of subjects given as name@algorithm:hex.
+var digestPattern = regexp.MustCompile(`^(sha256:[a-f0-9]{64}|sha512:[a-f0-9]{128})$`)
+
+// Statement is an in-toto statement, attesting the predicate about its subjects.
+// See https://github.com/in-toto/attestation/blob/main/spec/v1/statement.md.
+type Statement struct {
+	// Type is the type of the statement.
+	Type string `json:"_type"`
+	// Subject are the artifacts the predicate applies to.
+	Subject []ResourceDescriptor `json:"subject"`
+	// PredicateType is the type of the predicate.
+	PredicateType string `json:"predicateType"`
+	// Predicate holds the attested information.
+	Predicate json.RawMessage `json:"predicate"`
+}
+
+// ResourceDescriptor describes an artifact by name, location, and digests.
+type ResourceDescriptor struct {
+	// Name is the name of the artifact.
+	Name string `json:"name,omitempty"`
+	// URI is the location of the artifact.
+	URI string `json:"uri,omitempty"`
+	// Digest are the digests of the artifact, indexed by algorithm.
+	Digest map[string]string `json:"digest"`
+}
+
+// ScanPredicate is the predicate of the in-toto statement of a scan.
+type ScanPredicate struct {
+	// ScannedAt is the time of the scan.
+	ScannedAt time.Time `json:"scannedAt"`
+	// Frameworks summarize the results of each framework of the assessment plan.
+	Frameworks []FrameworkSummary `json:"frameworks"`
+	// Plan is the assessment plan of the scan.
+	Plan ResourceDescriptor `json:"plan"`
+	// Results are the assessment results of the scan.
+	Results ResourceDescriptor `json:"results"`
+	// Plugins are the plugins that produced the results.
+	Plugins []Plugin `json:"plugins,omitempty"`
+}
+
+// FrameworkSummary summarizes the results of a framework.
+type FrameworkSummary struct {
+	// ID is the framework identifier.
+	ID string `json:"id"`
+	// Rules are the number of in-scope rules per result.
+	Rules map[string]int `json:"rules"`
+	// Controls summarize the results of each control, ordered by control ID.
+	Controls []ControlSummary `json:"controls"`
+}
+
+// ControlSummary summarizes the results of the rules of a control.
+type ControlSummary struct {
+	// ID is the control identifier.
+	ID string `json:"id"`
+	// Status is satisfied, or not-satisfied when a rule of the control failed or errored.
+	Status string `json:"status"`
+	// Rules are the number of rules of the control per result.
+	Rules map[string]int `json:"rules"`
+}
+
+// NewFrameworkSummary returns the summary of a framework from the number of rules per result of
+// the framework and of each of its controls.
+func NewFrameworkSummary(frameworkID string, ruleCounts map[string]int, controlRuleCounts map[string]map[string]int) FrameworkSummary {
+	summary := FrameworkSummary{ID: frameworkID, Rules: ruleCounts, Controls: make([]ControlSummary, 0, len(controlRuleCounts))}
+	for controlID, counts := range controlRuleCounts {
+		status := ControlSatisfied
+		if counts[complytime.ResultFail] > 0 || counts[complytime.ResultError] > 0 {
+			status = ControlNotSatisfied
+		}
+		summary.Controls = append(summary.Controls, ControlSummary{ID: controlID, Status: status, Rules: counts})
+	}
+	sort.Slice(summary.Controls, func(i, j int) bool { return summary.Controls[i].ID < summary.Controls[j].ID })
+	return summary
+}
+
+// FileDescriptor returns the descriptor of a local file with its sha256 digest.
+func FileDescriptor(path string) (ResourceDescriptor, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return ResourceDescriptor{}, err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return ResourceDescriptor{}, err
+	}
+	return ResourceDescriptor{
+		Name:   filepath.Base(path),
+		URI:    "file://" + path,
+		Digest: map[string]string{"sha256": hex.EncodeToString(hash.Sum(nil))},
+	}, nil
+}
+
+// HostSubject returns the subject of a scanned host. Hosts have no content to digest, so the
+// digest is the sha256 digest of the host name, and the subject is matched by its name.
+func HostSubject(host string) ResourceDescriptor {
+	return ResourceDescriptor{Name: host, Digest: map[string]string{"sha256": digestOf([]byte(host))}}
+}
+
+// ParseSubject parses a subject given as name@algorithm:hex, such as the digest reference of a
+// container image, registry.example.com/app@sha256:....
+func ParseSubject(value string) (ResourceDescriptor, error) {
+	index := strings.LastIndex(value, "@")
+	if index <= 0 || !digestPattern.MatchString(value[index+1:]) {
+		return ResourceDescriptor{}, fmt.Errorf("invalid subject %q, expected name@sha256:digest", value)
+	}
+	algorithm, digest, _ := strings.Cut(value[index+1:], ":")
+	return ResourceDescriptor{Name: value[:index], Digest: map[string]string{algorithm: digest}}, nil
+}
+
+// NewScanStatement returns the in-toto statement attesting the scan predicate about the subjects.
+func NewScanStatement(subjects []ResourceDescriptor, predicate ScanPredicate) (*Statement, error) {
+	if len(subjects) == 0 {
+		return nil, errors.New("statement has no subjects")
+	}
+	predicateData, err := json.Marshal(predicate)
+	if err != nil {
+		return nil, err
+	}
+	return &Statement{
+		Type:          StatementType,
+		Subject:       subjects,
+		PredicateType: ScanPredicateType,
+		Predicate:     predicateData,
+	}, nil
+}
+
+// Sign returns the DSSE envelope of the statement signed with the key.
+func (s *Statement) Sign(key ed25519.PrivateKey) (*Envelope, error) {
+	payload, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return SignEnvelope(InTotoPayloadType, payload, key), nil
+}
+
+// ReadStatement returns the in-toto statement of a verified DSSE envelope payload.
+func ReadStatement(payloadType string, payload []byte) (*Statement, error) {
+	if payloadType != InTotoPayloadType {
+		return nil, fmt.Errorf("envelope payload type is %q, expected %q", payloadType, InTotoPayloadType)
+	}
+	var statement Statement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return nil, fmt.Errorf("invalid in-toto statement: %w", err)
+	}
+	if statement.Type != StatementType {
+		return nil, fmt.Errorf("statement type is %q, expected %q", statement.Type, StatementType)
+	}
+	return &statement, nil
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package evidence
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestNewFrameworkSummary(t *testing.T) {
+	summary := NewFrameworkSummary("anssi_bp28_minimal", map[string]int{complytime.ResultPass: 2, complytime.ResultFail: 1},
+		map[string]map[string]int{
+			"r2": {complytime.ResultPass: 1, complytime.ResultFail: 1},
+			"r1": {complytime.ResultPass: 1, complytime.ResultSkipped: 1},
+		})
+	require.Equal(t, FrameworkSummary{
+		ID:    "anssi_bp28_minimal",
+		Rules: map[string]int{complytime.ResultPass: 2, complytime.ResultFail: 1},
+		Controls: []ControlSummary{
+			{ID: "r1", Status: ControlSatisfied, Rules: map[string]int{complytime.ResultPass: 1, complytime.ResultSkipped: 1}},
+			{ID: "r2", Status: ControlNotSatisfied, Rules: map[string]int{complytime.ResultPass: 1, complytime.ResultFail: 1}},
+		},
+	}, summary)
+}
+
+func TestParseSubject(t *testing.T) {
+	digest := strings.Repeat("ab", 32)
+	subject, err := ParseSubject("registry.example.com:5000/app@sha256:" + digest)
+	require.NoError(t, err)
+	require.Equal(t, ResourceDescriptor{Name: "registry.example.com:5000/app", Digest: map[string]string{"sha256": digest}}, subject)
+
+	for _, value := range []string{"app", "app@sha256:abc", "@sha256:" + digest, "app@md5:" + digest, "app@sha256:" + strings.ToUpper(digest)} {
+		_, err := ParseSubject(value)
+		require.EqualError(t, err, `invalid subject "`+value+`", expected name@sha256:digest`)
+	}
+}
+
+func TestScanStatement(t *testing.T) {
+	_, err := NewScanStatement(nil, ScanPredicate{})
+	require.EqualError(t, err, "statement has no subjects")
+
+	resultsPath := filepath.Join(t.TempDir(), "assessment-results.json")
+	require.NoError(t, os.WriteFile(resultsPath, []byte("results"), 0600))
+	results, err := FileDescriptor(resultsPath)
+	require.NoError(t, err)
+	require.Equal(t, ResourceDescriptor{
+		Name:   "assessment-results.json",
+		URI:    "file://" + resultsPath,
+		Digest: map[string]string{"sha256": digestOf([]byte("results"))},
+	}, results)
+
+	predicate := ScanPredicate{
+		ScannedAt:  time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC),
+		Frameworks: []FrameworkSummary{NewFrameworkSummary("anssi_bp28_minimal", map[string]int{complytime.ResultPass: 1}, nil)},
+		Results:    results,
+	}
+	statement, err := NewScanStatement([]ResourceDescriptor{HostSubject("web1")}, predicate)
+	require.NoError(t, err)
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	envelope, err := statement.Sign(privateKey)
+	require.NoError(t, err)
+	require.Equal(t, InTotoPayloadType, envelope.PayloadType)
+	payload, err := envelope.Verify(publicKey)
+	require.NoError(t, err)
+
+	readStatement, err := ReadStatement(envelope.PayloadType, payload)
+	require.NoError(t, err)
+	require.Equal(t, StatementType, readStatement.Type)
+	require.Equal(t, ScanPredicateType, readStatement.PredicateType)
+	require.Equal(t, []ResourceDescriptor{{Name: "web1", Digest: map[string]string{"sha256": digestOf([]byte("web1"))}}}, readStatement.Subject)
+	var readPredicate ScanPredicate
+	require.NoError(t, json.Unmarshal(readStatement.Predicate, &readPredicate))
+	require.Equal(t, predicate, readPredicate)
+
+	_, err = ReadStatement("application/json", payload)
+	require.EqualError(t, err, `envelope payload type is "application/json", expected "application/vnd.in-toto+json"`)
+}
@@ -0,0 +1,380 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package evidence
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+)
+
+// BundleVersion is the version of the evidence bundle format.
+const BundleVersion = 1
+
+// Files of an evidence bundle next to the bundled files.
+const (
+	// MetadataFile describes the bundled files, the plugins, and the signing key.
+	MetadataFile = "bundle.json"
+	// ChecksumsFile lists the sha256 checksum of each file of the bundle in the sha256sum format.
+	ChecksumsFile = "SHA256SUMS"
+	// SignatureFile is the base64 encoded Ed25519 signature of the checksums file.
+	SignatureFile = "SHA256SUMS.sig"
+)
+
+// maxMetadataSize is the maximum size of the metadata, checksums, and signature files read from a bundle.
+const maxMetadataSize = 16 << 20
+
+// Metadata describes the content of an evidence bundle.
+type Metadata struct {
+	// Version is the version of the evidence bundle format.
+	Version int `json:"version"`
+	// Created is the time the bundle was created.
+	Created time.Time `json:"created"`
+	// KeyID identifies the public key of the key signing the bundle.
+	KeyID string `json:"keyId"`
+	// Plugins are the plugins that produced the bundled results.
+	Plugins []Plugin `json:"plugins,omitempty"`
+	// Files are the bundled files, in the order they were added.
+	Files []File `json:"files"`
+}
+
+// Plugin is a plugin that produced the bundled results.
+type Plugin struct {
+	// ID is the plugin identifier.
+	ID string `json:"id"`
+	// Version is the version of the plugin in its manifest.
+	Version string `json:"version"`
+	// Checksum is the sha256 checksum of the plugin executable in its manifest.
+	Checksum string `json:"sha256,omitempty"`
+}
+
+// File is a file of an evidence bundle.
+type File struct {
+	// Name is the slash separated location of the file in the bundle.
+	Name string `json:"name"`
+	// Source is the location the file was bundled from.
+	Source string `json:"source"`
+}
+
+// Bundle collects the files and plugins of an evidence bundle.
+type Bundle struct {
+	Files   []File
+	Plugins []Plugin
+}
+
+// Add adds the file at the source path to the bundle under the given name.
+func (b *Bundle) Add(name, source string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	for _, file := range b.Files {
+		if file.Name == name {
+			return fmt.Errorf("file %s is already in the bundle", name)
+		}
+	}
+	b.Files = append(b.Files, File{Name: name, Source: source})
+	return nil
+}
+
+// validateName checks that the name of a bundled file stays in the archive root, does not
+// clash with the files describing the bundle, and can be listed in the checksums file.
+func validateName(name string) error {
+	cleaned := path.Clean(name)
+	if cleaned != name || path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+		return fmt.Errorf("invalid bundle file name %q", name)
+	}
+	if strings.ContainsAny(name, "\\\n\r") {
+		return fmt.Errorf("invalid bundle file name %q", name)
+	}
+	switch name {
+	case MetadataFile, ChecksumsFile, SignatureFile:
+		return fmt.Errorf("bundle file name %q is reserved", name)
+	}
+	return nil
+}
+
+// Write writes the bundle as a tar archive at the given path, signed with the key. The files
+// are bundled with their checksums, the metadata, and the signature of the checksums.
+func (b *Bundle) Write(bundlePath string, key ed25519.PrivateKey, created time.Time) error {
+	metadata := Metadata{
+		Version: BundleVersion,
+		Created: created.UTC(),
+		KeyID:   KeyID(key.Public().(ed25519.PublicKey)),
+		Plugins: b.Plugins,
+		Files:   b.Files,
+	}
+	metadataData, err := json.MarshalIndent(metadata, "", " ")
+	if err != nil {
+		return err
+	}
+	metadataData = append(metadataData, '\n')
+
+	// The archive is written next to the bundle and renamed once complete
+	tmpFile, err := os.CreateTemp(filepath.Dir(bundlePath), filepath.Base(bundlePath)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	var checksums bytes.Buffer
+	tarWriter := tar.NewWriter(tmpFile)
+	for _, file := range b.Files {
+		digest, err := writeFileEntry(tarWriter, file, metadata.Created)
+		if err != nil {
+			_ = tmpFile.Close()
+			return err
+		}
+		fmt.Fprintf(&checksums, "%s  %s\n", digest, file.Name)
+	}
+	fmt.Fprintf(&checksums, "%s  %s\n", digestOf(metadataData), MetadataFile)
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, checksums.Bytes())) + "\n"
+
+	for _, entry := range []struct {
+		name string
+		data []byte
+	}{
+		{MetadataFile, metadataData},
+		{ChecksumsFile, checksums.Bytes()},
+		{SignatureFile, []byte(signature)},
+	} {
+		if err := writeEntry(tarWriter, entry.name, entry.data, metadata.Created); err != nil {
+			_ = tmpFile.Close()
+			return err
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), bundlePath)
+}
+
+// writeFileEntry copies a bundled file into the archive and returns its hex encoded sha256 checksum.
+func writeFileEntry(tarWriter *tar.Writer, file File, modTime time.Time) (string, error) {
+	source, err := os.Open(filepath.Clean(file.Source))
+	if err != nil {
+		return "", fmt.Errorf("failed to bundle %s: %w", file.Name, err)
+	}
+	defer source.Close()
+	info, err := source.Stat()
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("failed to bundle %s: %s is not a regular file", file.Name, file.Source)
+	}
+	header := &tar.Header{Name: file.Name, Mode: 0600, Size: info.Size(), ModTime: modTime, Typeflag: tar.TypeReg}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(tarWriter, io.TeeReader(source, hash)); err != nil {
+		return "", fmt.Errorf("failed to bundle %s: %w", file.Name, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func writeEntry(tarWriter *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), ModTime: modTime, Typeflag: tar.TypeReg}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tarWriter.Write(data)
+	return err
+}
+
+// digestOf returns the hex encoded sha256 checksum of the data.
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Verification is the outcome of the verification of an evidence bundle.
+type Verification struct {
+	// Metadata is the metadata of the bundle, when it could be read.
+	Metadata *Metadata
+	// KeyID identifies the public key the signature was verified with.
+	KeyID string
+	// SignatureValid is whether the checksums file is signed by the key.
+	SignatureValid bool
+	// Verified are the names of the files matching their checksums.
+	Verified []string
+	// Problems describe the files that were modified, added, or removed, and signature failures.
+	Problems []string
+}
+
+// Valid returns whether the bundle is signed by the key and its files are unmodified.
+func (v *Verification) Valid() bool {
+	return v.SignatureValid && len(v.Problems) == 0
+}
+
+func (v *Verification) addProblem(format string, args ...any) {
+	v.Problems = append(v.Problems, fmt.Sprintf(format, args...))
+}
+
+// Verify verifies the integrity of the evidence bundle at the given path and its signature with
+// the public key. An error is returned when the bundle cannot be read, problems found in a
+// readable bundle are reported in the verification.
+func Verify(bundlePath string, publicKey ed25519.PublicKey) (*Verification, error) {
+	file, err := os.Open(filepath.Clean(bundlePath))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	verification := &Verification{KeyID: KeyID(publicKey)}
+	digests := make(map[string]string)
+	var names []string
+	var checksums, signature, metadataData []byte
+	tarReader := tar.NewReader(bufio.NewReader(file))
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read evidence bundle %s: %w", bundlePath, err)
+		}
+		// Directories are added when bundles are extracted and archived again
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+		if header.Typeflag != tar.TypeReg {
+			verification.addProblem("unexpected entry %s is not a regular file", header.Name)
+			continue
+		}
+		if _, found := digests[header.Name]; found {
+			verification.addProblem("file %s appears more than once", header.Name)
+			continue
+		}
+		hash := sha256.New()
+		var data bytes.Buffer
+		reader := io.TeeReader(tarReader, hash)
+		switch header.Name {
+		case ChecksumsFile, SignatureFile, MetadataFile:
+			if header.Size > maxMetadataSize {
+				return nil, fmt.Errorf("file %s of evidence bundle %s exceeds the maximum size of %d bytes", header.Name, bundlePath, maxMetadataSize)
+			}
+			_, err = io.Copy(&data, reader)
+		default:
+			_, err = io.Copy(io.Discard, reader)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read evidence bundle %s: %w", bundlePath, err)
+		}
+		switch header.Name {
+		case ChecksumsFile:
+			checksums = data.Bytes()
+		case SignatureFile:
+			signature = data.Bytes()
+		case MetadataFile:
+			metadataData = data.Bytes()
+		}
+		digests[header.Name] = hex.EncodeToString(hash.Sum(nil))
+		names = append(names, header.Name)
+	}
+
+	if checksums == nil {
+		verification.addProblem("bundle has no %s file", ChecksumsFile)
+		return verification, nil
+	}
+	if metadataData != nil {
+		var metadata Metadata
+		if err := json.Unmarshal(metadataData, &metadata); err != nil {
+			verification.addProblem("invalid %s: %v", MetadataFile, err)
+		} else {
+			verification.Metadata = &metadata
+		}
+	}
+	verification.verifySignature(checksums, signature, publicKey)
+
+	listed := map[string]bool{ChecksumsFile: true, SignatureFile: true}
+	for i, line := range strings.Split(strings.TrimSuffix(string(checksums), "\n"), "\n") {
+		digest, name, found := strings.Cut(line, "  ")
+		if !found || len(digest) != sha256.Size*2 {
+			verification.addProblem("%s line %d is malformed", ChecksumsFile, i+1)
+			continue
+		}
+		listed[name] = true
+		actual, found := digests[name]
+		switch {
+		case !found:
+			verification.addProblem("file %s is missing", name)
+		case !strings.EqualFold(actual, digest):
+			verification.addProblem("file %s was modified: sha256 %s, expected %s", name, actual, digest)
+		default:
+			verification.Verified = append(verification.Verified, name)
+		}
+	}
+	for _, name := range names {
+		if !listed[name] {
+			verification.addProblem("file %s is not listed in %s", name, ChecksumsFile)
+		}
+	}
+	return verification, nil
+}
+
+// verifySignature verifies the signature of the checksums with the public key.
+func (v *Verification) verifySignature(checksums, signature []byte, publicKey ed25519.PublicKey) {
+	if signature == nil {
+		v.addProblem("bundle has no %s file", SignatureFile)
+		return
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		v.addProblem("invalid %s: %v", SignatureFile, err)
+		return
+	}
+	if !ed25519.Verify(publicKey, checksums, decoded) {
+		if v.Metadata != nil && v.Metadata.KeyID != v.KeyID {
+			v.addProblem("signature of %s was not made with key %s, the bundle names key %s", ChecksumsFile, v.KeyID, v.Metadata.KeyID)
+			return
+		}
+		v.addProblem("signature of %s was not made with key %s", ChecksumsFile, v.KeyID)
+		return
+	}
+	v.SignatureValid = true
+}
+
+// ReferencedFiles returns the paths of the local files referenced by file:// links in the relevant evidence of the
+// observations of the assessment results, in the order they are referenced.
+func ReferencedFiles(assessmentResults *oscalTypes.AssessmentResults) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, result := range assessmentResults.Results {
+		if result.Observations == nil {
+			continue
+		}
+		for _, observation := range *result.Observations {
+			if observation.RelevantEvidence == nil {
+				continue
+			}
+			for _, evidence := range *observation.RelevantEvidence {
+				file, found := strings.CutPrefix(evidence.Href, "file://")
+				if !found || file == "" || seen[file] {
+					continue
+				}
+				seen[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+	return files
+}
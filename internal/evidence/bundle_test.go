@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package evidence
+
+import (
+	"archive/tar"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/stretchr/testify/require"
+)
+
+// testBundle writes a bundle of a plan, results, and an evidence file, and returns its path.
+func testBundle(t *testing.T, key ed25519.PrivateKey) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		"assessment-plan.json":              `{"assessment-plan": {}}`,
+		"assessment-results.json":           `{"assessment-results": {}}`,
+		"evidence/openscap/results/arf.xml": "<arf/>",
+	}
+	bundle := &Bundle{Plugins: []Plugin{{ID: "openscap", Version: "0.0.1", Checksum: "abc"}}}
+	for _, name := range []string{"assessment-plan.json", "assessment-results.json", "evidence/openscap/results/arf.xml"} {
+		source := filepath.Join(dir, filepath.Base(name))
+		require.NoError(t, os.WriteFile(source, []byte(files[name]), 0600))
+		require.NoError(t, bundle.Add(name, source))
+	}
+	bundlePath := filepath.Join(dir, "bundle.tar")
+	require.NoError(t, bundle.Write(bundlePath, key, time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)))
+	return bundlePath
+}
+
+// rewriteBundle copies the bundle, replacing or dropping entries, and adding new entries.
+func rewriteBundle(t *testing.T, bundlePath string, replace map[string]*string) string {
+	source, err := os.Open(bundlePath)
+	require.NoError(t, err)
+	defer source.Close()
+	rewrittenPath := filepath.Join(t.TempDir(), "rewritten.tar")
+	target, err := os.Create(rewrittenPath)
+	require.NoError(t, err)
+	defer target.Close()
+
+	tarReader := tar.NewReader(source)
+	tarWriter := tar.NewWriter(target)
+	seen := make(map[string]bool)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tarReader)
+		require.NoError(t, err)
+		seen[header.Name] = true
+		if replacement, found := replace[header.Name]; found {
+			if replacement == nil {
+				continue
+			}
+			data = []byte(*replacement)
+		}
+		header.Size = int64(len(data))
+		require.NoError(t, tarWriter.WriteHeader(header))
+		_, err = tarWriter.Write(data)
+		require.NoError(t, err)
+	}
+	for name, data := range replace {
+		if seen[name] || data == nil {
+			continue
+		}
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(*data)), Typeflag: tar.TypeReg}))
+		_, err = tarWriter.Write([]byte(*data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	return rewrittenPath
+}
+
+func TestBundleVerify(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	bundlePath := testBundle(t, privateKey)
+
+	verification, err := Verify(bundlePath, publicKey)
+	require.NoError(t, err)
+	require.True(t, verification.Valid())
+	require.Empty(t, verification.Problems)
+	require.Equal(t, []string{"assessment-plan.json", "assessment-results.json", "evidence/openscap/results/arf.xml", MetadataFile}, verification.Verified)
+	require.NotNil(t, verification.Metadata)
+	require.Equal(t, KeyID(publicKey), verification.Metadata.KeyID)
+	require.Equal(t, []Plugin{{ID: "openscap", Version: "0.0.1", Checksum: "abc"}}, verification.Metadata.Plugins)
+	require.Len(t, verification.Metadata.Files, 3)
+
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	verification, err = Verify(bundlePath, otherKey)
+	require.NoError(t, err)
+	require.False(t, verification.Valid())
+	require.Equal(t, []string{
+		"signature of SHA256SUMS was not made with key " + KeyID(otherKey) + ", the bundle names key " + KeyID(publicKey),
+	}, verification.Problems)
+
+	modified := `{"assessment-results": {"edited": true}}`
+	added := "added"
+	verification, err = Verify(rewriteBundle(t, bundlePath, map[string]*string{
+		"assessment-results.json":           &modified,
+		"evidence/openscap/results/arf.xml": nil,
+		"notes.txt":                         &added,
+	}), publicKey)
+	require.NoError(t, err)
+	require.False(t, verification.Valid())
+	require.True(t, verification.SignatureValid)
+	require.Len(t, verification.Problems, 3)
+	require.Contains(t, verification.Problems[0], "file assessment-results.json was modified: sha256 ")
+	require.Equal(t, "file evidence/openscap/results/arf.xml is missing", verification.Problems[1])
+	require.Equal(t, "file notes.txt is not listed in SHA256SUMS", verification.Problems[2])
+
+	// Checksums cannot be updated without the signing key
+	forged := "0000000000000000000000000000000000000000000000000000000000000000  assessment-plan.json\n"
+	verification, err = Verify(rewriteBundle(t, bundlePath, map[string]*string{ChecksumsFile: &forged}), publicKey)
+	require.NoError(t, err)
+	require.False(t, verification.SignatureValid)
+	require.Contains(t, verification.Problems, "signature of SHA256SUMS was not made with key "+KeyID(publicKey))
+
+	verification, err = Verify(rewriteBundle(t, bundlePath, map[string]*string{SignatureFile: nil}), publicKey)
+	require.NoError(t, err)
+	require.False(t, verification.Valid())
+	require.Equal(t, []string{"bundle has no SHA256SUMS.sig file"}, verification.Problems)
+}
+
+func TestBundleAdd(t *testing.T) {
+	bundle := &Bundle{}
+	require.NoError(t, bundle.Add("evidence/arf.xml", "arf.xml"))
+	require.EqualError(t, bundle.Add("evidence/arf.xml", "other.xml"), "file evidence/arf.xml is already in the bundle")
+	require.EqualError(t, bundle.Add("../arf.xml", "arf.xml"), `invalid bundle file name "../arf.xml"`)
+	require.EqualError(t, bundle.Add("/arf.xml", "arf.xml"), `invalid bundle file name "/arf.xml"`)
+	require.EqualError(t, bundle.Add("evidence/./arf.xml", "arf.xml"), `invalid bundle file name "evidence/./arf.xml"`)
+	require.EqualError(t, bundle.Add(ChecksumsFile, "sums"), `bundle file name "SHA256SUMS" is reserved`)
+}
+
+func TestReferencedFiles(t *testing.T) {
+	observation := func(hrefs ...string) oscalTypes.Observation {
+		var evidences []oscalTypes.RelevantEvidence
+		for _, href := range hrefs {
+			evidences = append(evidences, oscalTypes.RelevantEvidence{Href: href, Description: "evidence"})
+		}
+		return oscalTypes.Observation{RelevantEvidence: &evidences}
+	}
+	assessmentResults := &oscalTypes.AssessmentResults{
+		Results: []oscalTypes.Result{{
+			Observations: &[]oscalTypes.Observation{
+				observation("file:///workspace/openscap/results/arf.xml", "https://example.com/evidence"),
+				observation("file:///workspace/openscap/results/arf.xml", "file:///workspace/openscap/results/web1/arf.xml"),
+				{},
+			},
+		}},
+	}
+	require.Equal(t, []string{"/workspace/openscap/results/arf.xml", "/workspace/openscap/results/web1/arf.xml"},
+		ReferencedFiles(assessmentResults))
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package evidence
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Envelope is a DSSE envelope, holding a payload and its signatures.
+// See https://github.com/secure-systems-lab/dsse.
+type Envelope struct {
+	// PayloadType is the media type of the payload.
+	PayloadType string `json:"payloadType"`
+	// Payload is the base64 encoded payload.
+	Payload string `json:"payload"`
+	// Signatures are the signatures of the pre-authentication encoding of the payload.
+	Signatures []Signature `json:"signatures"`
+}
+
+// Signature is a signature of a DSSE envelope.
+type Signature struct {
+	// KeyID identifies the public key of the key making the signature.
+	KeyID string `json:"keyid"`
+	// Sig is the base64 encoded signature.
+	Sig string `json:"sig"`
+}
+
+// pae returns the DSSE pre-authentication encoding of the payload, the signed message.
+func pae(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// SignEnvelope returns a DSSE envelope of the payload signed with the key.
+func SignEnvelope(payloadType string, payload []byte, key ed25519.PrivateKey) *Envelope {
+	signature := ed25519.Sign(key, pae(payloadType, payload))
+	return &Envelope{
+		PayloadType: payloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures: []Signature{{
+			KeyID: KeyID(key.Public().(ed25519.PublicKey)),
+			Sig:   base64.StdEncoding.EncodeToString(signature),
+		}},
+	}
+}
+
+// Verify returns the payload of the envelope when one of its signatures was made with the key.
+func (e *Envelope) Verify(publicKey ed25519.PublicKey) ([]byte, error) {
+	payload, err := base64.StdEncoding.DecodeString(e.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid envelope payload: %w", err)
+	}
+	if len(e.Signatures) == 0 {
+		return nil, errors.New("envelope has no signatures")
+	}
+	message := pae(e.PayloadType, payload)
+	for _, signature := range e.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(signature.Sig)
+		if err != nil {
+			continue
+		}
+		if ed25519.Verify(publicKey, message, sig) {
+			return payload, nil
+		}
+	}
+	return nil, fmt.Errorf("no signature of the envelope was made with key %s", KeyID(publicKey))
+}
+
+// ReadEnvelope reads a DSSE envelope from the given path.
+func ReadEnvelope(envelopePath string) (*Envelope, error) {
+	data, err := os.ReadFile(filepath.Clean(envelopePath))
+	if err != nil {
+		return nil, err
+	}
+	var envelope Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("error reading envelope %s: %w", envelopePath, err)
+	}
+	return &envelope, nil
+}
+
+// Write stores the envelope at the given path as a single line of JSON, as in-toto attestation bundles do.
+func (e *Envelope) Write(envelopePath string) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(envelopePath, append(data, '\n'), 0600)
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package evidence
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPAE(t *testing.T) {
+	// Example of the DSSE protocol specification
+	require.Equal(t, "DSSEv1 29 http://example.com/HelloWorld 11 hello world", string(pae("http://example.com/HelloWorld", []byte("hello world"))))
+}
+
+func TestEnvelope(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	envelope := SignEnvelope(InTotoPayloadType, []byte(`{"_type": "statement"}`), privateKey)
+	require.Len(t, envelope.Signatures, 1)
+	require.Equal(t, KeyID(publicKey), envelope.Signatures[0].KeyID)
+
+	envelopePath := filepath.Join(t.TempDir(), "scan.intoto.jsonl")
+	require.NoError(t, envelope.Write(envelopePath))
+	readEnvelope, err := ReadEnvelope(envelopePath)
+	require.NoError(t, err)
+	require.Equal(t, envelope, readEnvelope)
+
+	payload, err := readEnvelope.Verify(publicKey)
+	require.NoError(t, err)
+	require.Equal(t, `{"_type": "statement"}`, string(payload))
+
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, err = readEnvelope.Verify(otherKey)
+	require.EqualError(t, err, "no signature of the envelope was made with key "+KeyID(otherKey))
+
+	// The payload type is signed
+	readEnvelope.PayloadType = "application/json"
+	_, err = readEnvelope.Verify(publicKey)
+	require.Error(t, err)
+
+	readEnvelope.PayloadType = InTotoPayloadType
+	readEnvelope.Payload = base64.StdEncoding.EncodeToString([]byte(`{"_type": "edited"}`))
+	_, err = readEnvelope.Verify(publicKey)
+	require.Error(t, err)
+
+	readEnvelope.Signatures = nil
+	_, err = readEnvelope.Verify(publicKey)
+	require.EqualError(t, err, "envelope has no signatures")
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package evidence
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+)
+
+const (
+	// signingKeyLocation is the location of the default signing key below the XDG config directory.
+	signingKeyLocation = "complytime/evidence-signing.key"
+	// PublicKeySuffix is appended to the path of a signing key to store its public key.
+	PublicKeySuffix = ".pub"
+
+	privateKeyType = "PRIVATE KEY"
+	publicKeyType  = "PUBLIC KEY"
+)
+
+// DefaultSigningKeyPath returns the path of the default signing key of the user.
+func DefaultSigningKeyPath() string {
+	return filepath.Join(xdg.ConfigHome, signingKeyLocation)
+}
+
+// GenerateSigningKey generates an Ed25519 signing key at the given path, with its public key
+// next to it. Existing keys are not overwritten.
+func GenerateSigningKey(keyPath string) (ed25519.PrivateKey, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0700); err != nil {
+		return nil, err
+	}
+	if err := writeNewFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: privateKeyType, Bytes: privateDER}), 0600); err != nil {
+		return nil, err
+	}
+	if err := writeNewFile(keyPath+PublicKeySuffix, pem.EncodeToMemory(&pem.Block{Type: publicKeyType, Bytes: publicDER}), 0644); err != nil {
+		return nil, err
+	}
+	return privateKey, nil
+}
+
+// writeNewFile writes the data to a file that must not exist.
+func writeNewFile(name string, data []byte, perm os.FileMode) error {
+	file, err := os.OpenFile(filepath.Clean(name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// ReadSigningKey reads a PEM encoded Ed25519 private key.
+func ReadSigningKey(keyPath string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != privateKeyType {
+		return nil, fmt.Errorf("key %s is a %s, expected a %s", keyPath, block.Type, privateKeyType)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key %s: %w", keyPath, err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not an Ed25519 key", keyPath)
+	}
+	return privateKey, nil
+}
+
+// ReadPublicKey reads a PEM encoded Ed25519 public key. The public key of a PEM encoded
+// private key is also accepted.
+func ReadPublicKey(keyPath string) (ed25519.PublicKey, error) {
+	block, err := readPEM(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	switch block.Type {
+	case privateKeyType:
+		privateKey, err := ReadSigningKey(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		return privateKey.Public().(ed25519.PublicKey), nil
+	case publicKeyType:
+	default:
+		return nil, fmt.Errorf("key %s is a %s, expected a %s", keyPath, block.Type, publicKeyType)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key %s: %w", keyPath, err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an Ed25519 key", keyPath)
+	}
+	return publicKey, nil
+}
+
+// readPEM reads the first PEM block of a key file.
+func readPEM(keyPath string) (*pem.Block, error) {
+	data, err := os.ReadFile(filepath.Clean(keyPath))
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s is not PEM encoded", keyPath)
+	}
+	return block, nil
+}
+
+// KeyID returns the identifier of a public key, the sha256 digest of its PKIX encoding.
+func KeyID(publicKey ed25519.PublicKey) string {
+	// Ed25519 public keys cannot fail to marshal
+	der, _ := x509.MarshalPKIXPublicKey(publicKey)
+	sum := sha256.Sum256(der)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
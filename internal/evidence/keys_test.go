@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package evidence
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningKeys(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "keys", "evidence-signing.key")
+	privateKey, err := GenerateSigningKey(keyPath)
+	require.NoError(t, err)
+
+	info, err := os.Stat(keyPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	readKey, err := ReadSigningKey(keyPath)
+	require.NoError(t, err)
+	require.True(t, privateKey.Equal(readKey))
+
+	publicKey, err := ReadPublicKey(keyPath + PublicKeySuffix)
+	require.NoError(t, err)
+	require.True(t, publicKey.Equal(privateKey.Public()))
+	require.True(t, strings.HasPrefix(KeyID(publicKey), "sha256:"))
+
+	// The public key is derived from a private key
+	derivedKey, err := ReadPublicKey(keyPath)
+	require.NoError(t, err)
+	require.Equal(t, KeyID(publicKey), KeyID(derivedKey))
+
+	// Existing keys are kept
+	_, err = GenerateSigningKey(keyPath)
+	require.ErrorIs(t, err, os.ErrExist)
+
+	_, err = ReadSigningKey(keyPath + PublicKeySuffix)
+	require.EqualError(t, err, "key "+keyPath+PublicKeySuffix+" is a PUBLIC KEY, expected a PRIVATE KEY")
+
+	notPEM := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(notPEM, []byte("key"), 0600))
+	_, err = ReadPublicKey(notPEM)
+	require.EqualError(t, err, "key "+notPEM+" is not PEM encoded")
+}
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"sort"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/complytime/plan"
+)
+
+var (
+	controlRulesDesc = prometheus.NewDesc(
+		"complyctl_control_rules",
+		"Number of rules assessed for a control in the latest scan by result status.",
+		[]string{"framework", "control", "status"}, nil,
+	)
+	frameworkRulesDesc = prometheus.NewDesc(
+		"complyctl_framework_rules",
+		"Number of rules assessed for a framework in the latest scan by result status.",
+		[]string{"framework", "status"}, nil,
+	)
+	pluginDurationDesc = prometheus.NewDesc(
+		"complyctl_plugin_scan_duration_seconds",
+		"Time taken by a plugin to return results in the latest scan.",
+		[]string{"plugin"}, nil,
+	)
+	pluginErrorDesc = prometheus.NewDesc(
+		"complyctl_plugin_error",
+		"Whether a plugin returned an error in the latest scan (1) or not (0).",
+		[]string{"plugin"}, nil,
+	)
+	lastSuccessDesc = prometheus.NewDesc(
+		"complyctl_last_successful_scan_timestamp_seconds",
+		"Unix time of the latest scan where all plugins returned results.",
+		nil, nil,
+	)
+)
+
+// Source contains the workspace state used to produce compliance posture metrics.
+type Source struct {
+	// Plan is the assessment plan used for the latest scan.
+	Plan *oscalTypes.AssessmentPlan
+	// Results are the assessment results of the latest scan. These may be nil
+	// when no scan has completed successfully.
+	Results *oscalTypes.AssessmentResults
+	// Status contains run information about the latest scan. This may be nil
+	// when no scan has been run.
+	Status *complytime.ScanStatus
+}
+
+// Loader returns the current Source for metrics collection.
+type Loader func() (Source, error)
+
+// Collector is a prometheus.Collector that exposes compliance posture
+// metrics. The Source is loaded on every collection, so metrics always
+// reflect the latest workspace state.
+type Collector struct {
+	load Loader
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector returns a Collector that loads its Source with the given Loader.
+func NewCollector(load Loader) *Collector {
+	return &Collector{load: load}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- controlRulesDesc
+	ch <- frameworkRulesDesc
+	ch <- pluginDurationDesc
+	ch <- pluginErrorDesc
+	ch <- lastSuccessDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	source, err := c.load()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(controlRulesDesc, err)
+		return
+	}
+	if source.Plan != nil {
+		if err := collectPosture(ch, source.Plan, source.Results); err != nil {
+			ch <- prometheus.NewInvalidMetric(frameworkRulesDesc, err)
+			return
+		}
+	}
+	if source.Status != nil {
+		collectStatus(ch, source.Status)
+	}
+}
+
+// collectPosture sends rule counts by status per control and framework for each
+// framework of the given assessment plan. Plans that record no framework have no
+// posture metrics, as the series would have an empty framework label.
+func collectPosture(ch chan<- prometheus.Metric, assessmentPlan *oscalTypes.AssessmentPlan, assessmentResults *oscalTypes.AssessmentResults) error {
+	for _, frameworkID := range plan.Frameworks(assessmentPlan) {
+		frameworkPlan, err := plan.ForFramework(assessmentPlan, frameworkID)
+		if err != nil {
+			return err
+		}
+		collectFrameworkPosture(ch, frameworkID, frameworkPlan, assessmentResults)
+	}
+	return nil
+}
+
+// collectFrameworkPosture sends rule counts by status per control and for the
+// framework of the given assessment plan.
+func collectFrameworkPosture(ch chan<- prometheus.Metric, framework string, assessmentPlan *oscalTypes.AssessmentPlan, assessmentResults *oscalTypes.AssessmentResults) {
+	counts := complytime.ControlRuleCounts(assessmentPlan, assessmentResults)
+
+	controlIDs := make([]string, 0, len(counts))
+	for controlID := range counts {
+		controlIDs = append(controlIDs, controlID)
+	}
+	sort.Strings(controlIDs)
+
+	for _, controlID := range controlIDs {
+		for _, status := range complytime.ResultStatuses {
+			ch <- prometheus.MustNewConstMetric(controlRulesDesc, prometheus.GaugeValue,
+				float64(counts[controlID][status]), framework, controlID, status)
+		}
+	}
+
+	frameworkCounts := complytime.RuleCounts(assessmentPlan, assessmentResults)
+	for _, status := range complytime.ResultStatuses {
+		ch <- prometheus.MustNewConstMetric(frameworkRulesDesc, prometheus.GaugeValue,
+			float64(frameworkCounts[status]), framework, status)
+	}
+}
+
+// collectStatus sends plugin run and scan timing information from the ScanStatus.
+func collectStatus(ch chan<- prometheus.Metric, status *complytime.ScanStatus) {
+	for _, pluginStatus := range status.Plugins {
+		ch <- prometheus.MustNewConstMetric(pluginDurationDesc, prometheus.GaugeValue,
+			pluginStatus.DurationSeconds, pluginStatus.ID)
+		pluginError := 0.0
+		if pluginStatus.Error != "" {
+			pluginError = 1.0
+		}
+		ch <- prometheus.MustNewConstMetric(pluginErrorDesc, prometheus.GaugeValue,
+			pluginError, pluginStatus.ID)
+	}
+	if status.LastSuccess != nil {
+		ch <- prometheus.MustNewConstMetric(lastSuccessDesc, prometheus.GaugeValue,
+			float64(status.LastSuccess.Unix()))
+	}
+}
@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestCollector(t *testing.T) {
+	lastSuccess := time.Unix(1746093600, 0)
+	testSource := Source{
+		Plan: &oscalTypes.AssessmentPlan{
+			Metadata: oscalTypes.Metadata{
+				Props: &[]oscalTypes.Property{
+					{Name: extensions.FrameworkProp, Value: "example", Ns: extensions.TrestleNameSpace},
+				},
+			},
+			LocalDefinitions: &oscalTypes.LocalDefinitions{
+				Activities: &[]oscalTypes.Activity{
+					{
+						Title: "rule-1",
+						RelatedControls: &oscalTypes.ReviewedControls{
+							ControlSelections: []oscalTypes.AssessedControls{
+								{IncludeControls: &[]oscalTypes.AssessedControlsSelectControlById{{ControlId: "control-1"}}},
+							},
+						},
+					},
+				},
+			},
+		},
+		Results: &oscalTypes.AssessmentResults{
+			Results: []oscalTypes.Result{
+				{
+					Observations: &[]oscalTypes.Observation{
+						{
+							Props: &[]oscalTypes.Property{
+								{Name: extensions.AssessmentRuleIdProp, Value: "rule-1", Ns: extensions.TrestleNameSpace},
+							},
+							Subjects: &[]oscalTypes.SubjectReference{
+								{Props: &[]oscalTypes.Property{{Name: "result", Value: "fail", Ns: extensions.TrestleNameSpace}}},
+							},
+						},
+					},
+				},
+			},
+		},
+		Status: &complytime.ScanStatus{
+			LastSuccess: &lastSuccess,
+			Plugins: []complytime.PluginStatus{
+				{ID: "openscap", DurationSeconds: 12.5},
+				{ID: "other", DurationSeconds: 1, Error: "failed"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		loader   Loader
+		wantText string
+		wantErr  string
+	}{
+		{
+			name:     "Valid/Source",
+			loader:   func() (Source, error) { return testSource, nil },
+			wantText: wantMetrics,
+		},
+		{
+			name: "Valid/NoFramework",
+			loader: func() (Source, error) {
+				return Source{Plan: &oscalTypes.AssessmentPlan{LocalDefinitions: testSource.Plan.LocalDefinitions}, Status: testSource.Status}, nil
+			},
+			wantText: wantStatusMetrics,
+		},
+		{
+			name:   "Valid/EmptySource",
+			loader: func() (Source, error) { return Source{}, nil },
+		},
+		{
+			name:    "Invalid/LoaderError",
+			loader:  func() (Source, error) { return Source{}, errors.New("no results") },
+			wantErr: "no results",
+		},
+	}
+
+	for _, c := range tests {
+		t.Run(c.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(NewCollector(c.loader))
+			families, err := registry.Gather()
+			if c.wantErr != "" {
+				require.ErrorContains(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			out := bytes.NewBuffer(nil)
+			encoder := expfmt.NewEncoder(out, expfmt.NewFormat(expfmt.TypeTextPlain))
+			for _, family := range families {
+				require.NoError(t, encoder.Encode(family))
+			}
+			require.Equal(t, c.wantText, out.String())
+		})
+	}
+}
+
+func TestCollectorFrameworks(t *testing.T) {
+	frameworkSelection := func(frameworkID, controlID string) oscalTypes.AssessedControls {
+		return oscalTypes.AssessedControls{
+			Props:           &[]oscalTypes.Property{{Name: extensions.FrameworkProp, Value: frameworkID, Ns: extensions.TrestleNameSpace}},
+			IncludeControls: &[]oscalTypes.AssessedControlsSelectControlById{{ControlId: controlID}},
+		}
+	}
+	testSource := Source{
+		Plan: &oscalTypes.AssessmentPlan{
+			Metadata: oscalTypes.Metadata{
+				Props: &[]oscalTypes.Property{
+					{Name: extensions.FrameworkProp, Value: "example", Ns: extensions.TrestleNameSpace},
+					{Name: extensions.FrameworkProp, Value: "baseline", Ns: extensions.TrestleNameSpace},
+				},
+			},
+			LocalDefinitions: &oscalTypes.LocalDefinitions{
+				Activities: &[]oscalTypes.Activity{
+					{
+						Title: "rule-1",
+						RelatedControls: &oscalTypes.ReviewedControls{
+							ControlSelections: []oscalTypes.AssessedControls{
+								frameworkSelection("example", "control-1"),
+								frameworkSelection("baseline", "base-1"),
+							},
+						},
+					},
+					{
+						Title: "rule-2",
+						RelatedControls: &oscalTypes.ReviewedControls{
+							ControlSelections: []oscalTypes.AssessedControls{
+								frameworkSelection("baseline", "base-2"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewCollector(func() (Source, error) { return testSource, nil }))
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	out := bytes.NewBuffer(nil)
+	encoder := expfmt.NewEncoder(out, expfmt.NewFormat(expfmt.TypeTextPlain))
+	for _, family := range families {
+		require.NoError(t, encoder.Encode(family))
+	}
+	require.Contains(t, out.String(), `complyctl_control_rules{control="control-1",framework="example",status="skipped"} 1`)
+	require.NotContains(t, out.String(), `complyctl_control_rules{control="base-1",framework="example"`)
+	require.Contains(t, out.String(), `complyctl_framework_rules{framework="example",status="skipped"} 1`)
+	require.Contains(t, out.String(), `complyctl_framework_rules{framework="baseline",status="skipped"} 2`)
+}
+
+var wantMetrics = `# HELP complyctl_control_rules Number of rules assessed for a control in the latest scan by result status.
+# TYPE complyctl_control_rules gauge
+complyctl_control_rules{control="control-1",framework="example",status="error"} 0
+complyctl_control_rules{control="control-1",framework="example",status="fail"} 1
+complyctl_control_rules{control="control-1",framework="example",status="pass"} 0
+complyctl_control_rules{control="control-1",framework="example",status="skipped"} 0
+complyctl_control_rules{control="control-1",framework="example",status="waived"} 0
+# HELP complyctl_framework_rules Number of rules assessed for a framework in the latest scan by result status.
+# TYPE complyctl_framework_rules gauge
+complyctl_framework_rules{framework="example",status="error"} 0
+complyctl_framework_rules{framework="example",status="fail"} 1
+complyctl_framework_rules{framework="example",status="pass"} 0
+complyctl_framework_rules{framework="example",status="skipped"} 0
+complyctl_framework_rules{framework="example",status="waived"} 0
+` + wantStatusMetrics
+
+var wantStatusMetrics = `# HELP complyctl_last_successful_scan_timestamp_seconds Unix time of the latest scan where all plugins returned results.
+# TYPE complyctl_last_successful_scan_timestamp_seconds gauge
+complyctl_last_successful_scan_timestamp_seconds 1.7460936e+09
+# HELP complyctl_plugin_error Whether a plugin returned an error in the latest scan (1) or not (0).
+# TYPE complyctl_plugin_error gauge
+complyctl_plugin_error{plugin="openscap"} 0
+complyctl_plugin_error{plugin="other"} 1
+# HELP complyctl_plugin_scan_duration_seconds Time taken by a plugin to return results in the latest scan.
+# TYPE complyctl_plugin_scan_duration_seconds gauge
+complyctl_plugin_scan_duration_seconds{plugin="openscap"} 12.5
+complyctl_plugin_scan_duration_seconds{plugin="other"} 1
+`
@@ -5,11 +5,13 @@ package terminal
 import (
 	"fmt"
 	"io"
+	"os"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
 )
 
 var (
@@ -25,12 +27,41 @@ type Model struct {
 	HeaderMsg string
 	//displayed below table
 	HelpMsg string
+	// Interactive keeps the model running and forwards key presses
+	// to the table for scrolling until the user quits.
+	Interactive bool
 }
 
 func (m Model) Init() tea.Cmd { return nil }
 
-func (m Model) Update(_ tea.Msg) (tea.Model, tea.Cmd) {
-	return m, tea.Quit
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if !m.Interactive {
+		return m, tea.Quit
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && IsQuitKey(keyMsg) {
+		return m, tea.Quit
+	}
+	var cmd tea.Cmd
+	m.Table, cmd = m.Table.Update(msg)
+	return m, cmd
+}
+
+// IsQuitKey returns whether the key press should exit an interactive program.
+func IsQuitKey(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return true
+	}
+	return false
+}
+
+// IsTerminal returns whether the writer is connected to a terminal.
+func IsTerminal(writer io.Writer) bool {
+	file, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+	return term.IsTerminal(int(file.Fd()))
 }
 
 func (m Model) View() string {
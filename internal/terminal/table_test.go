@@ -3,9 +3,11 @@
 package terminal
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/require"
 )
 
@@ -46,6 +48,30 @@ func TestModelView(t *testing.T) {
 	}
 }
 
+func TestModelUpdate(t *testing.T) {
+	model := Model{Table: table.New(
+		table.WithColumns([]table.Column{{Title: "Column A", Width: 10}}),
+		table.WithRows([]table.Row{{"Row A"}, {"Row B"}}),
+		table.WithFocused(true),
+		table.WithHeight(3),
+	)}
+
+	// Static models quit on the first message
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	require.Equal(t, tea.Quit(), cmd())
+
+	// Interactive models scroll until the user quits
+	model.Interactive = true
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	require.Equal(t, table.Row{"Row B"}, updated.(Model).Table.SelectedRow())
+	_, cmd = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	require.Equal(t, tea.Quit(), cmd())
+}
+
+func TestIsTerminal(t *testing.T) {
+	require.False(t, IsTerminal(bytes.NewBuffer(nil)))
+}
+
 var (
 	testTable = `┌────────────┐
 │ Column A   │
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package doctor reports diagnostic checks of complyctl and its plugins. Plugins serve the checks
+// of their external dependencies with ServePluginChecks when run with the PluginCheckArg argument.
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// Status is the outcome of a diagnostic check.
+type Status string
+
+const (
+	Pass Status = "pass"
+	Warn Status = "warn"
+	Fail Status = "fail"
+)
+
+// PluginCheckArg is the argument used to run a plugin executable in diagnostic
+// mode instead of serving the plugin. In this mode, plugins read the plugin
+// configuration selections as a JSON object from stdin and write the
+// results of their dependency checks as a JSON array of Check to stdout.
+const PluginCheckArg = "doctor"
+
+// detailIndent aligns details written below a check with the check name.
+const detailIndent = "         "
+
+// Check is the result of a single diagnostic check.
+type Check struct {
+	// Name describes what was checked.
+	Name string `json:"name"`
+	// Status is the outcome of the check.
+	Status Status `json:"status"`
+	// Message gives details about the outcome.
+	Message string `json:"message,omitempty"`
+	// Fix suggests how to resolve a warning or failure.
+	Fix string `json:"fix,omitempty"`
+}
+
+// Passed returns a passing Check.
+func Passed(name, message string) Check {
+	return Check{Name: name, Status: Pass, Message: message}
+}
+
+// Warned returns a Check with a warning and a suggested fix.
+func Warned(name, message, fix string) Check {
+	return Check{Name: name, Status: Warn, Message: message, Fix: fix}
+}
+
+// Failed returns a failing Check with a suggested fix.
+func Failed(name, message, fix string) Check {
+	return Check{Name: name, Status: Fail, Message: message, Fix: fix}
+}
+
+// Section is a titled group of checks.
+type Section struct {
+	Title  string
+	Checks []Check
+}
+
+// Count returns the number of checks with the given status in all sections.
+func Count(sections []Section, status Status) int {
+	count := 0
+	for _, section := range sections {
+		for _, check := range section.Checks {
+			if check.Status == status {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// WriteChecklist writes the sections as a checklist with the suggested fix
+// below each warning or failure.
+func WriteChecklist(out io.Writer, sections []Section) {
+	for i, section := range sections {
+		if i > 0 {
+			_, _ = fmt.Fprintln(out)
+		}
+		_, _ = fmt.Fprintln(out, section.Title)
+		for _, check := range section.Checks {
+			line := fmt.Sprintf("  [%s] %s", strings.ToUpper(string(check.Status)), check.Name)
+			if check.Message != "" {
+				// Indent multi-line messages, such as schema validation errors, below the check
+				line += ": " + strings.ReplaceAll(check.Message, "\n", "\n"+detailIndent)
+			}
+			_, _ = fmt.Fprintln(out, line)
+			if check.Fix != "" && check.Status != Pass {
+				_, _ = fmt.Fprintf(out, "%sfix: %s\n", detailIndent, check.Fix)
+			}
+		}
+	}
+	_, _ = fmt.Fprintf(out, "\n%d passed, %d warnings, %d failed\n",
+		Count(sections, Pass), Count(sections, Warn), Count(sections, Fail))
+}
+
+// ServePluginChecks implements the plugin side of the diagnostic mode. The
+// configuration selections are read from in and the checks returned by the
+// check function are written to out.
+func ServePluginChecks(in io.Reader, out io.Writer, check func(selections map[string]string) []Check) error {
+	selections := make(map[string]string)
+	if err := json.NewDecoder(in).Decode(&selections); err != nil && err != io.EOF {
+		return fmt.Errorf("error reading plugin configuration: %w", err)
+	}
+	return json.NewEncoder(out).Encode(check(selections))
+}
+
+// RunPluginChecks runs the plugin executable in diagnostic mode with the given
+// configuration selections and returns the checks reported by the plugin.
+func RunPluginChecks(ctx context.Context, executablePath string, selections map[string]string) ([]Check, error) {
+	input, err := json.Marshal(selections)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, executablePath, PluginCheckArg)
+	cmd.Stdin = bytes.NewReader(input)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error running %s in diagnostic mode: %w", executablePath, err)
+	}
+	var checks []Check
+	if err := json.Unmarshal(output, &checks); err != nil {
+		return nil, fmt.Errorf("error reading diagnostic output of %s: %w", executablePath, err)
+	}
+	return checks, nil
+}
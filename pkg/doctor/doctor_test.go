@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteChecklist(t *testing.T) {
+	sections := []Section{
+		{
+			Title: "Plugins",
+			Checks: []Check{
+				Passed("plugin example", "version 0.0.1"),
+				Warned("plugin example dependencies", "not reported", "Run a scan"),
+				Failed("validation component \"other\"", "no plugin\nfound", "Install the plugin"),
+			},
+		},
+		{
+			Title:  "Workspace",
+			Checks: []Check{Passed("./complytime", "writable")},
+		},
+	}
+	wantOutput := `Plugins
+  [PASS] plugin example: version 0.0.1
+  [WARN] plugin example dependencies: not reported
+         fix: Run a scan
+  [FAIL] validation component "other": no plugin
+         found
+         fix: Install the plugin
+
+Workspace
+  [PASS] ./complytime: writable
+
+2 passed, 1 warnings, 1 failed
+`
+	out := bytes.NewBuffer(nil)
+	WriteChecklist(out, sections)
+	require.Equal(t, wantOutput, out.String())
+	require.Equal(t, 1, Count(sections, Fail))
+}
+
+func TestPluginChecks(t *testing.T) {
+	checkFn := func(selections map[string]string) []Check {
+		return []Check{Passed("datastream", selections["datastream"])}
+	}
+	out := bytes.NewBuffer(nil)
+	require.NoError(t, ServePluginChecks(strings.NewReader(`{"datastream":"ds.xml"}`), out, checkFn))
+	require.JSONEq(t, `[{"name":"datastream","status":"pass","message":"ds.xml"}]`, out.String())
+
+	// Run a plugin executable that implements the diagnostic mode
+	tmpDir := t.TempDir()
+	executable := filepath.Join(tmpDir, "example-plugin")
+	script := "#!/bin/sh\n[ \"$1\" = \"doctor\" ] || exit 1\ncat > /dev/null\necho '" + strings.TrimSpace(out.String()) + "'\n"
+	require.NoError(t, os.WriteFile(executable, []byte(script), 0700))
+	checks, err := RunPluginChecks(context.Background(), executable, map[string]string{"datastream": "ds.xml"})
+	require.NoError(t, err)
+	require.Equal(t, []Check{Passed("datastream", "ds.xml")}, checks)
+
+	// Plugins without a diagnostic mode fail to run
+	unsupported := filepath.Join(tmpDir, "unsupported-plugin")
+	require.NoError(t, os.WriteFile(unsupported, []byte("#!/bin/sh\necho 'This binary is a plugin.'\nexit 1\n"), 0700))
+	_, err = RunPluginChecks(context.Background(), unsupported, nil)
+	require.ErrorContains(t, err, "diagnostic mode")
+}
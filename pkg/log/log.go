@@ -3,11 +3,14 @@
 package log
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 
@@ -15,6 +18,37 @@ import (
 	charmlog "github.com/charmbracelet/log"
 )
 
+// Formats of the logs.
+const (
+	// FormatText writes logs as styled text for terminals.
+	FormatText = "text"
+	// FormatJSON writes logs as one JSON object per line.
+	FormatJSON = "json"
+)
+
+// Keys of the fields of JSON logs.
+const (
+	// RunIDKey is the key of the identifier of a run of complyctl and of its plugins.
+	RunIDKey = "run_id"
+	// CommandKey is the key of the command being run.
+	CommandKey = "command"
+	// WorkspaceKey is the key of the workspace of the command.
+	WorkspaceKey = "workspace"
+	// PluginIDKey is the key of the ID of the plugin a log line is from.
+	PluginIDKey = "plugin_id"
+)
+
+// RunIDEnv is the environment variable holding the run ID, inherited by plugin subprocesses.
+const RunIDEnv = "COMPLYCTL_RUN_ID"
+
+// NewRunID returns a new random run ID.
+func NewRunID() string {
+	id := make([]byte, 8)
+	// Reading random bytes does not fail on supported platforms
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
 // Initializing the colors for the charm logger.
 var (
 	DebugColor = lipgloss.AdaptiveColor{Light: "63", Dark: "63"}
@@ -68,14 +102,41 @@ func defaultStyles() *charmlog.Styles {
 // NewLogger initializes a new wrapped logger with default styles
 func NewLogger(o io.Writer) hclog.Logger {
 	c := charmlog.NewWithOptions(o, *defaultOptions())
-	l := &CharmHclog{c}
+	l := &CharmHclog{logger: c}
 	l.logger.SetStyles(defaultStyles())
 	return l
 }
 
+// NewJSONLogger initializes a new wrapped logger writing one JSON object per line, with the
+// time, level, message, and key/value pairs of each log. The plugin manager names the logger
+// of each plugin by its ID, so the first name of the logger is recorded in the plugin_id field.
+func NewJSONLogger(o io.Writer) hclog.Logger {
+	options := defaultOptions()
+	options.ReportTimestamp = true
+	options.TimeFormat = time.RFC3339Nano
+	options.Formatter = charmlog.JSONFormatter
+	return &CharmHclog{logger: charmlog.NewWithOptions(o, *options), json: true}
+}
+
+// NewLoggerWithFormat initializes a new wrapped logger writing logs in the given format.
+func NewLoggerWithFormat(o io.Writer, format string) (hclog.Logger, error) {
+	switch format {
+	case FormatText:
+		return NewLogger(o), nil
+	case FormatJSON:
+		return NewJSONLogger(o), nil
+	default:
+		return nil, fmt.Errorf("unsupported log format %q, expected %s or %s", format, FormatText, FormatJSON)
+	}
+}
+
 // CharmHclog adapts the charm logger to the hashicorp logger.
 type CharmHclog struct {
 	logger *charmlog.Logger
+	// name is the name of the logger, the names given to Named joined by dots.
+	name string
+	// json records the first name of the logger as a plugin ID field.
+	json bool
 }
 
 // CharmHclog will implement the hclog.Logger.
@@ -101,8 +162,9 @@ var charmHclogLevels = map[charmlog.Level]hclog.Level{
 	charmlog.FatalLevel: hclog.Error, // There is no "fatal" equivalent in go-hclog
 }
 
+// Log keeps the key/value pairs of the arguments, such as the fields of the logs of plugins.
 func (c *CharmHclog) Log(level hclog.Level, msg string, args ...interface{}) {
-	c.logger.Log(hclogCharmLevels[level], fmt.Sprintf(msg, args...))
+	c.logger.Log(hclogCharmLevels[level], msg, args...)
 }
 func (c *CharmHclog) Trace(msg string, args ...interface{}) {
 	c.logger.Debug(msg, args...)
@@ -134,20 +196,27 @@ func (c *CharmHclog) IsError() bool { return false }
 func (c *CharmHclog) ImpliedArgs() []interface{} { return nil }
 
 func (c *CharmHclog) With(args ...interface{}) hclog.Logger {
-	return &CharmHclog{c.logger.With(args...)}
+	return &CharmHclog{logger: c.logger.With(args...), name: c.name, json: c.json}
 }
 
-// The GetPrefix() method will return the prefix of the logger.
-func (c *CharmHclog) Name() string { return c.logger.GetPrefix() }
+// The Name() method will return the name of the logger.
+func (c *CharmHclog) Name() string { return c.name }
 
-// The Named() method appends to the current logger prefix.
+// The Named() method appends to the current logger name, used as prefix.
 func (c *CharmHclog) Named(name string) hclog.Logger {
-	return &CharmHclog{c.logger.WithPrefix(name)}
+	if c.name == "" {
+		return c.ResetNamed(name)
+	}
+	return &CharmHclog{logger: c.logger.WithPrefix(c.name + "." + name), name: c.name + "." + name, json: c.json}
 }
 
-// The ResetNamed() method creates the logger with only the prefix passed.
+// The ResetNamed() method creates the logger with only the name passed.
 func (c *CharmHclog) ResetNamed(name string) hclog.Logger {
-	return &CharmHclog{c.logger.WithPrefix(name)}
+	logger := c.logger.WithPrefix(name)
+	if c.json {
+		logger = logger.With(PluginIDKey, name)
+	}
+	return &CharmHclog{logger: logger, name: name, json: c.json}
 }
 
 // The SetLevel() method enables setting logger level.
@@ -4,10 +4,12 @@ package log
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"testing"
 
 	charmlogger "github.com/charmbracelet/log"
+	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -164,3 +166,49 @@ func TestPrefixMatch(t *testing.T) {
 		})
 	}
 }
+
+// Testing that plugin key/value pairs are kept rather than flattened into the message.
+func TestLogKeyValues(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf)
+	l.Log(hclog.Info, "plugin started", "path", "/usr/libexec/complytime/plugins/openscap-plugin", "pid", 42)
+	assert.Equal(t, "INFO plugin started path=/usr/libexec/complytime/plugins/openscap-plugin pid=42\n", buf.String())
+
+	buf.Reset()
+	l.Named("openscap").Named("openscap-plugin").Warn("scan failed", "err", "exit status 2")
+	assert.Equal(t, "WARN openscap.openscap-plugin: scan failed err=\"exit status 2\"\n", buf.String())
+}
+
+// Testing the fields of JSON logs.
+func TestJSONLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewJSONLogger(&buf).With(RunIDKey, "0123456789abcdef", CommandKey, "complyctl scan")
+	l.Info("Successfully loaded 1 plugin(s).")
+	l.Named("openscap").Named("openscap-plugin").Log(hclog.Info, "running oscap", "profile", "cis")
+
+	var lines []map[string]interface{}
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var fields map[string]interface{}
+		assert.NoError(t, json.Unmarshal(line, &fields))
+		assert.NotEmpty(t, fields["time"])
+		delete(fields, "time")
+		lines = append(lines, fields)
+	}
+	assert.Equal(t, []map[string]interface{}{
+		{"level": "info", "msg": "Successfully loaded 1 plugin(s).", "run_id": "0123456789abcdef", "command": "complyctl scan"},
+		{"level": "info", "msg": "running oscap", "run_id": "0123456789abcdef", "command": "complyctl scan",
+			"prefix": "openscap.openscap-plugin", "plugin_id": "openscap", "profile": "cis"},
+	}, lines)
+}
+
+// Testing the supported log formats.
+func TestNewLoggerWithFormat(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := NewLoggerWithFormat(&buf, FormatText)
+	assert.NoError(t, err)
+	_, err = NewLoggerWithFormat(&buf, FormatJSON)
+	assert.NoError(t, err)
+	_, err = NewLoggerWithFormat(&buf, "xml")
+	assert.EqualError(t, err, `unsupported log format "xml", expected text or json`)
+	assert.Len(t, NewRunID(), 16)
+}
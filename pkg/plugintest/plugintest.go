@@ -0,0 +1,603 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package plugintest checks that a complyctl plugin conforms to the conventions complyctl relies on.
+// The plugin is launched from its manifest as complyctl launches plugins, configured with a workspace
+// and a profile, and called with a fixture policy and with invalid input.
+//
+// Plugin authors can run the checks in a Go test with Test, or with "complyctl plugin test <manifest>".
+package plugintest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/framework"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+const (
+	// DefaultProfile is the profile given to the plugin when none is configured.
+	DefaultProfile = "plugintest"
+	// DefaultTimeout is the time a plugin call can take when no timeout is configured.
+	DefaultTimeout = 10 * time.Minute
+
+	// ResultsDir is the directory of the workspace directory of a plugin holding the files for evidence collection.
+	ResultsDir = "results"
+	// RemediationsDir is the directory of the workspace directory of a plugin holding the files for automated remediation.
+	RemediationsDir = "remediations"
+)
+
+// Names of the checks, in the order they are run.
+const (
+	CheckManifest      = "manifest"
+	CheckOptions       = "configuration options"
+	CheckLaunch        = "launch and configure"
+	CheckGenerate      = "generate"
+	CheckResults       = "results"
+	CheckCheckIDs      = "result check IDs"
+	CheckWorkspace     = "workspace directories"
+	CheckConfigureErr  = "configure error path"
+	CheckGenerateErr   = "generate error path"
+	CheckGetResultsErr = "results error path"
+)
+
+var checkNames = []string{
+	CheckManifest,
+	CheckOptions,
+	CheckLaunch,
+	CheckGenerate,
+	CheckResults,
+	CheckCheckIDs,
+	CheckWorkspace,
+	CheckConfigureErr,
+	CheckGenerateErr,
+	CheckGetResultsErr,
+}
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	Pass Status = "pass"
+	Fail Status = "fail"
+	// Skip is the status of the checks that could not run after a failure.
+	Skip Status = "skip"
+)
+
+// Result is the outcome of a single check.
+type Result struct {
+	// Name is the name of the check.
+	Name string
+	// Status is the outcome of the check.
+	Status Status
+	// Message gives details about the outcome.
+	Message string
+}
+
+// Config configures the checks of a plugin.
+type Config struct {
+	// Manifest is the path of the plugin manifest, named c2p-<plugin id>-manifest.json. The executable
+	// path of the manifest is resolved from the directory of the manifest.
+	Manifest string
+	// Workspace is the workspace directory given to the plugin. A temporary directory is used when empty.
+	Workspace string
+	// Profile is the profile given to the plugin, DefaultProfile when empty.
+	Profile string
+	// Options are option values of the plugin taking precedence over the defaults of the manifest.
+	Options map[string]string
+	// Policy is the policy generated and assessed by the plugin, FixturePolicy when empty.
+	Policy policy.Policy
+	// Timeout is the time a plugin call can take, DefaultTimeout when zero.
+	Timeout time.Duration
+	// Logger receives the logs of the plugin. The logs are discarded when nil.
+	Logger hclog.Logger
+}
+
+// FixturePolicy returns a policy of two rules with one check each. The first rule has a parameter.
+func FixturePolicy() policy.Policy {
+	return policy.Policy{
+		{
+			Rule: extensions.Rule{
+				ID:          "plugintest_rule_1",
+				Description: "First rule of the conformance test policy",
+				Parameters: []extensions.Parameter{
+					{ID: "plugintest_parameter_1", Description: "Parameter of the first rule", Value: "1"},
+				},
+			},
+			Checks: []extensions.Check{
+				{ID: "plugintest_check_1", Description: "Check of the first rule"},
+			},
+		},
+		{
+			Rule: extensions.Rule{
+				ID:          "plugintest_rule_2",
+				Description: "Second rule of the conformance test policy",
+			},
+			Checks: []extensions.Check{
+				{ID: "plugintest_check_2", Description: "Check of the second rule"},
+			},
+		},
+	}
+}
+
+// invalidPolicy returns a policy with a rule without identifier and checks.
+func invalidPolicy() policy.Policy {
+	return policy.Policy{{Rule: extensions.Rule{Description: "Rule without identifier and checks"}}}
+}
+
+// Count returns the number of results with the given status.
+func Count(results []Result, status Status) int {
+	count := 0
+	for _, result := range results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Test runs the checks of the plugin as subtests of the test. The workspace is a temporary directory
+// of the test when it is not configured.
+func Test(t *testing.T, config Config) {
+	t.Helper()
+	if config.Workspace == "" {
+		config.Workspace = t.TempDir()
+	}
+	results, err := Run(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		t.Run(result.Name, func(t *testing.T) {
+			switch result.Status {
+			case Fail:
+				t.Error(result.Message)
+			case Skip:
+				t.Skip(result.Message)
+			default:
+				t.Log(result.Message)
+			}
+		})
+	}
+}
+
+// Run runs the checks of the plugin and returns their results in order. An error is returned when the
+// checks cannot be run, such as when the temporary workspace cannot be created.
+func Run(config Config) ([]Result, error) {
+	if config.Profile == "" {
+		config.Profile = DefaultProfile
+	}
+	if len(config.Policy) == 0 {
+		config.Policy = FixturePolicy()
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.Logger == nil {
+		config.Logger = hclog.NewNullLogger()
+	}
+	if config.Workspace == "" {
+		workspace, err := os.MkdirTemp("", "complyctl-plugintest-")
+		if err != nil {
+			return nil, fmt.Errorf("error creating workspace: %w", err)
+		}
+		defer os.RemoveAll(workspace)
+		config.Workspace = workspace
+	}
+	workspace, err := filepath.Abs(config.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	config.Workspace = workspace
+
+	r := &runner{config: config}
+	r.run()
+	return r.results, nil
+}
+
+// runner records the results of the checks of a plugin.
+type runner struct {
+	config  Config
+	results []Result
+}
+
+func (r *runner) pass(name, format string, args ...any) {
+	r.results = append(r.results, Result{Name: name, Status: Pass, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *runner) fail(name, format string, args ...any) {
+	r.results = append(r.results, Result{Name: name, Status: Fail, Message: fmt.Sprintf(format, args...)})
+}
+
+// skipRemaining skips the checks that did not run after the last failed check.
+func (r *runner) skipRemaining() {
+	ran := make(map[string]bool)
+	failed := ""
+	for _, result := range r.results {
+		ran[result.Name] = true
+		if result.Status == Fail {
+			failed = result.Name
+		}
+	}
+	for _, name := range checkNames {
+		if !ran[name] {
+			r.results = append(r.results, Result{Name: name, Status: Skip, Message: fmt.Sprintf("skipped after the %s check failed", failed)})
+		}
+	}
+}
+
+// call calls the plugin and returns its error, or an error when the call does not return in time.
+func (r *runner) call(method string, fn func() error) error {
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(r.config.Timeout):
+		return &timeoutError{method: method, timeout: r.config.Timeout}
+	}
+}
+
+type timeoutError struct {
+	method  string
+	timeout time.Duration
+}
+
+func (e *timeoutError) Error() string {
+	return fmt.Sprintf("%s did not return within %s", e.method, e.timeout)
+}
+
+// exited returns whether the error reports that the plugin process exited during the call,
+// such as when the plugin panicked, or that the call did not return.
+func exited(err error) bool {
+	var timeoutErr *timeoutError
+	return status.Code(err) == codes.Unavailable || errors.As(err, &timeoutErr)
+}
+
+func (r *runner) run() {
+	manifest, ok := r.checkManifest()
+	if !ok {
+		r.skipRemaining()
+		return
+	}
+	r.checkOptions(manifest)
+
+	workspaceBefore, err := workspaceEntries(r.config.Workspace)
+	if err != nil {
+		r.fail(CheckLaunch, "error reading workspace: %v", err)
+		r.skipRemaining()
+		return
+	}
+	provider, configuration, clean, ok := r.launch(manifest)
+	if clean != nil {
+		defer clean()
+	}
+	if !ok {
+		r.skipRemaining()
+		return
+	}
+
+	pluginID := manifest.ID.String()
+	if err := r.call("Generate", func() error { return provider.Generate(r.config.Policy) }); err != nil {
+		r.fail(CheckGenerate, "Generate returned an error for the fixture policy: %v", err)
+		if exited(err) {
+			r.skipRemaining()
+			return
+		}
+	} else {
+		r.pass(CheckGenerate, "generated the fixture policy")
+	}
+
+	var results policy.PVPResult
+	err = r.call("GetResults", func() (err error) {
+		results, err = provider.GetResults(r.config.Policy)
+		return err
+	})
+	if err != nil {
+		r.fail(CheckResults, "GetResults returned an error for the fixture policy: %v", err)
+		if exited(err) {
+			r.skipRemaining()
+			return
+		}
+	} else {
+		r.pass(CheckResults, "%d observations", len(results.ObservationsByCheck))
+		r.checkCheckIDs(results, r.config.Policy)
+	}
+	r.checkWorkspace(pluginID, workspaceBefore)
+
+	if !r.checkErrorPaths(provider, configuration) {
+		r.skipRemaining()
+	}
+}
+
+// checkManifest checks that the manifest is named after the plugin ID, is a policy validation plugin,
+// and that its executable exists with the checksum of the manifest. The manifest is returned with the
+// resolved executable path.
+func (r *runner) checkManifest() (plugin.Manifest, bool) {
+	manifestPath := r.config.Manifest
+	name := filepath.Base(manifestPath)
+	if !strings.HasPrefix(name, complytime.PluginManifestPrefix) || !strings.HasSuffix(name, complytime.PluginManifestSuffix) {
+		r.fail(CheckManifest, "manifest %s is not named %s<plugin id>%s", manifestPath, complytime.PluginManifestPrefix, complytime.PluginManifestSuffix)
+		return plugin.Manifest{}, false
+	}
+	pluginID := plugin.ID(strings.TrimSuffix(strings.TrimPrefix(name, complytime.PluginManifestPrefix), complytime.PluginManifestSuffix))
+
+	data, err := os.ReadFile(filepath.Clean(manifestPath))
+	if err != nil {
+		r.fail(CheckManifest, "%v", err)
+		return plugin.Manifest{}, false
+	}
+	var manifest plugin.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		r.fail(CheckManifest, "invalid manifest %s: %v", manifestPath, err)
+		return plugin.Manifest{}, false
+	}
+	if manifest.ID != pluginID {
+		r.fail(CheckManifest, "manifest id %q does not match the manifest file name", manifest.ID)
+		return plugin.Manifest{}, false
+	}
+	if !manifest.ID.Validate() {
+		r.fail(CheckManifest, "invalid plugin id %q, expected lowercase letters, digits, and dashes", manifest.ID)
+		return plugin.Manifest{}, false
+	}
+	isPolicyPlugin := false
+	for _, pluginType := range manifest.Types {
+		isPolicyPlugin = isPolicyPlugin || pluginType == plugin.PVPPluginName
+	}
+	if !isPolicyPlugin {
+		r.fail(CheckManifest, "manifest types %v do not include %q", manifest.Types, plugin.PVPPluginName)
+		return plugin.Manifest{}, false
+	}
+
+	// The plugin is found as complyctl finds plugins in a content root
+	manifestDir := filepath.Dir(manifestPath)
+	manifests, err := plugin.FindPlugins(manifestDir, manifestDir,
+		plugin.WithProviderIds([]plugin.ID{pluginID}),
+		plugin.WithPluginType(plugin.PVPPluginName),
+	)
+	if err != nil {
+		r.fail(CheckManifest, "%v", err)
+		return plugin.Manifest{}, false
+	}
+	manifest = manifests[pluginID]
+	checksum, err := fileChecksum(manifest.ExecutablePath)
+	if err != nil {
+		r.fail(CheckManifest, "%v", err)
+		return plugin.Manifest{}, false
+	}
+	if !strings.EqualFold(checksum, manifest.Checksum) {
+		r.fail(CheckManifest, "checksum %s of %s does not match the manifest", checksum, manifest.ExecutablePath)
+		return plugin.Manifest{}, false
+	}
+	r.pass(CheckManifest, "plugin %s version %s at %s", manifest.ID, manifest.Version, manifest.ExecutablePath)
+	return manifest, true
+}
+
+// checkOptions checks that the manifest declares the options complyctl sets, as undeclared
+// options are not passed to the plugin.
+func (r *runner) checkOptions(manifest plugin.Manifest) {
+	declared := make(map[string]bool)
+	for _, option := range manifest.Configuration {
+		declared[option.Name] = true
+	}
+	var missing []string
+	for _, name := range []string{"workspace", "profile"} {
+		if !declared[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		r.fail(CheckOptions, "the manifest does not declare the %s options set by complyctl", strings.Join(missing, " and "))
+		return
+	}
+	r.pass(CheckOptions, "the manifest declares the workspace and profile options")
+}
+
+// launch launches and configures the plugin as complyctl does, and returns the plugin, its resolved
+// configuration, and the cleanup function of the launched plugin.
+func (r *runner) launch(manifest plugin.Manifest) (policy.Provider, map[string]string, func(), bool) {
+	pluginID := manifest.ID.String()
+	selections := complytime.NewPluginOptions()
+	selections.Workspace = r.config.Workspace
+	selections.Profile = r.config.Profile
+	selections.Overrides = map[string]map[string]string{pluginID: r.config.Options}
+	selectionsMap, err := selections.ToMap(pluginID, r.config.Logger)
+	if err != nil {
+		r.fail(CheckLaunch, "%v", err)
+		return nil, nil, nil, false
+	}
+	configuration, err := manifest.ResolveOptions(selectionsMap)
+	if err != nil {
+		r.fail(CheckLaunch, "%v", err)
+		return nil, nil, nil, false
+	}
+
+	cfg := framework.DefaultConfig()
+	cfg.PluginDir = filepath.Dir(manifest.ExecutablePath)
+	cfg.PluginManifestDir = filepath.Dir(r.config.Manifest)
+	cfg.Logger = r.config.Logger
+	manager, err := framework.NewPluginManager(cfg)
+	if err != nil {
+		r.fail(CheckLaunch, "error initializing plugin manager: %v", err)
+		return nil, nil, nil, false
+	}
+	var plugins map[plugin.ID]policy.Provider
+	var clean func()
+	err = r.call("Configure", func() (err error) {
+		plugins, clean, err = complytime.LaunchPlugins(manager, plugin.Manifests{manifest.ID: manifest}, selections, r.config.Logger)
+		return err
+	})
+	if err != nil {
+		r.fail(CheckLaunch, "%v", err)
+		return nil, nil, manager.Clean, false
+	}
+	r.pass(CheckLaunch, "configured with workspace %s and profile %s", r.config.Workspace, r.config.Profile)
+	return plugins[manifest.ID], configuration, clean, true
+}
+
+// checkCheckIDs checks that the observations of the results reference checks of the policy and that
+// their subjects have valid results.
+func (r *runner) checkCheckIDs(results policy.PVPResult, requested policy.Policy) {
+	checkIDs := make(map[string]bool)
+	for _, ruleSet := range requested {
+		for _, check := range ruleSet.Checks {
+			checkIDs[check.ID] = true
+		}
+	}
+	var problems []string
+	for _, observation := range results.ObservationsByCheck {
+		if !checkIDs[observation.CheckID] {
+			problems = append(problems, fmt.Sprintf("observation %q references check %q that was not requested", observation.Title, observation.CheckID))
+		}
+		for _, subject := range observation.Subjects {
+			if subject.Result < policy.ResultFail || subject.Result > policy.ResultWarning {
+				problems = append(problems, fmt.Sprintf("subject %q of observation %q has an invalid result", subject.Title, observation.Title))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		r.fail(CheckCheckIDs, "%s", strings.Join(problems, "\n"))
+		return
+	}
+	r.pass(CheckCheckIDs, "observations reference only requested checks")
+}
+
+// checkWorkspace checks that the plugin has results and remediations directories in its workspace
+// directory, and that it did not write to the workspace outside of it.
+func (r *runner) checkWorkspace(pluginID string, before map[string]bool) {
+	pluginDir := filepath.Join(r.config.Workspace, pluginID)
+	var problems []string
+	for _, dir := range []string{ResultsDir, RemediationsDir} {
+		path := filepath.Join(pluginDir, dir)
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("missing directory %s", path))
+		case !info.IsDir():
+			problems = append(problems, fmt.Sprintf("%s is not a directory", path))
+		}
+	}
+	after, err := workspaceEntries(r.config.Workspace)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("error reading workspace: %v", err))
+	}
+	var unexpected []string
+	for name := range after {
+		if !before[name] && name != pluginID {
+			unexpected = append(unexpected, filepath.Join(r.config.Workspace, name))
+		}
+	}
+	sort.Strings(unexpected)
+	if len(unexpected) > 0 {
+		problems = append(problems, fmt.Sprintf("files written outside of %s: %s", pluginDir, strings.Join(unexpected, ", ")))
+	}
+	if len(problems) > 0 {
+		r.fail(CheckWorkspace, "%s", strings.Join(problems, "\n"))
+		return
+	}
+	r.pass(CheckWorkspace, "%s has the %s and %s directories", pluginDir, ResultsDir, RemediationsDir)
+}
+
+// checkErrorPaths calls the plugin with invalid input. The plugin must reject a configuration without
+// the workspace and profile options, and must return an error or handle a policy with an invalid rule,
+// without exiting. After each call, the plugin must accept its configuration again. It returns false
+// when the plugin can no longer be called.
+func (r *runner) checkErrorPaths(provider policy.Provider, configuration map[string]string) bool {
+	// check records the result of an invalid call, and whether the plugin then accepts its configuration
+	check := func(name string, err error, problem string, message string) bool {
+		if exited(err) {
+			r.fail(name, "the plugin exited, it may have panicked: %v", err)
+			return false
+		}
+		if reconfigureErr := r.call("Configure", func() error { return provider.Configure(configuration) }); reconfigureErr != nil {
+			r.fail(name, "Configure returned an error for the valid configuration after the invalid call: %v", reconfigureErr)
+			return !exited(reconfigureErr)
+		}
+		if problem != "" {
+			r.fail(name, "%s", problem)
+			return true
+		}
+		r.pass(name, "%s", message)
+		return true
+	}
+
+	err := r.call("Configure", func() error { return provider.Configure(map[string]string{}) })
+	problem := ""
+	if err == nil {
+		problem = "Configure returned no error for a configuration without the workspace and profile options"
+	}
+	if !check(CheckConfigureErr, err, problem, "Configure returned an error for an empty configuration: "+outcome(err)) {
+		return false
+	}
+
+	err = r.call("Generate", func() error { return provider.Generate(invalidPolicy()) })
+	if !check(CheckGenerateErr, err, "", "Generate returned for a rule without identifier and checks: "+outcome(err)) {
+		return false
+	}
+
+	var results policy.PVPResult
+	err = r.call("GetResults", func() (err error) {
+		results, err = provider.GetResults(invalidPolicy())
+		return err
+	})
+	problem = ""
+	if err == nil && len(results.ObservationsByCheck) > 0 {
+		problem = fmt.Sprintf("GetResults returned %d observations for a policy without checks", len(results.ObservationsByCheck))
+	}
+	return check(CheckGetResultsErr, err, problem, "GetResults returned for a rule without identifier and checks: "+outcome(err))
+}
+
+// outcome describes the outcome of a plugin call.
+func outcome(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return "no error"
+}
+
+// workspaceEntries returns the names of the entries of the workspace directory.
+func workspaceEntries(workspace string) (map[string]bool, error) {
+	entries, err := os.ReadDir(workspace)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		names[entry.Name()] = true
+	}
+	return names, nil
+}
+
+// fileChecksum returns the hex encoded SHA256 checksum of a file.
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
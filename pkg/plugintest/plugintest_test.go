@@ -0,0 +1,219 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package plugintest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	hplugin "github.com/hashicorp/go-plugin"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePluginEnv makes the test binary serve the fake plugin with the behavior given in the variable.
+const fakePluginEnv = "PLUGINTEST_FAKE_PLUGIN"
+
+const (
+	conformant   = "conformant"
+	panics       = "panics"
+	foreignCheck = "foreign-check"
+	noDirs       = "no-dirs"
+)
+
+func TestMain(m *testing.M) {
+	if behavior := os.Getenv(fakePluginEnv); behavior != "" {
+		plugin.Register(plugin.ServeConfig{
+			PluginSet: map[string]hplugin.Plugin{
+				plugin.PVPPluginName: &plugin.PVPPlugin{Impl: &fakePlugin{behavior: behavior}},
+			},
+			Logger: hclog.NewNullLogger(),
+		})
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakePlugin is a plugin writing to the workspace and returning observations of the requested checks,
+// with behaviors breaking the conventions.
+type fakePlugin struct {
+	behavior  string
+	workspace string
+}
+
+func (f *fakePlugin) Configure(configuration map[string]string) error {
+	if configuration["workspace"] == "" || configuration["profile"] == "" {
+		return errors.New("workspace and profile must be set")
+	}
+	f.workspace = configuration["workspace"]
+	if f.behavior == noDirs {
+		return os.WriteFile(filepath.Join(f.workspace, "results.json"), []byte("{}"), 0600)
+	}
+	for _, dir := range []string{ResultsDir, RemediationsDir} {
+		if err := os.MkdirAll(filepath.Join(f.workspace, "fake", dir), 0750); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakePlugin) Generate(p policy.Policy) error {
+	for _, ruleSet := range p {
+		if ruleSet.Rule.ID == "" {
+			if f.behavior == panics {
+				panic("rule without identifier")
+			}
+			return errors.New("rule without identifier")
+		}
+	}
+	return nil
+}
+
+func (f *fakePlugin) GetResults(p policy.Policy) (policy.PVPResult, error) {
+	var results policy.PVPResult
+	for _, ruleSet := range p {
+		if ruleSet.Rule.ID == "" {
+			return results, errors.New("rule without identifier")
+		}
+		for _, check := range ruleSet.Checks {
+			checkID := check.ID
+			if f.behavior == foreignCheck {
+				checkID = "unrequested_check"
+			}
+			results.ObservationsByCheck = append(results.ObservationsByCheck, policy.ObservationByCheck{
+				Title:     ruleSet.Rule.ID,
+				CheckID:   checkID,
+				Collected: time.Now(),
+				Subjects:  []policy.Subject{{Title: "localhost", Result: policy.ResultPass, EvaluatedOn: time.Now()}},
+			})
+		}
+	}
+	return results, nil
+}
+
+// writeFakePlugin copies the test binary to a plugin directory as the executable of the fake plugin,
+// and returns the path of its manifest.
+func writeFakePlugin(t *testing.T, behavior string) string {
+	t.Helper()
+	t.Setenv(fakePluginEnv, behavior)
+	testBinary, err := os.Executable()
+	require.NoError(t, err)
+	pluginDir := t.TempDir()
+	executablePath := filepath.Join(pluginDir, "fake-plugin")
+	source, err := os.Open(testBinary)
+	require.NoError(t, err)
+	defer source.Close()
+	executable, err := os.OpenFile(executablePath, os.O_CREATE|os.O_WRONLY, 0700)
+	require.NoError(t, err)
+	_, err = io.Copy(executable, source)
+	require.NoError(t, err)
+	require.NoError(t, executable.Close())
+	checksum, err := fileChecksum(executablePath)
+	require.NoError(t, err)
+
+	manifest := plugin.Manifest{
+		Metadata:       plugin.Metadata{ID: "fake", Description: "fake plugin", Version: "0.1.0", Types: []string{plugin.PVPPluginName}},
+		ExecutablePath: "fake-plugin",
+		Checksum:       checksum,
+		Configuration: []plugin.ConfigurationOption{
+			{Name: "workspace", Description: "workspace", Required: true},
+			{Name: "profile", Description: "profile", Required: true},
+		},
+	}
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	manifestPath := filepath.Join(pluginDir, "c2p-fake-manifest.json")
+	require.NoError(t, os.WriteFile(manifestPath, data, 0600))
+	return manifestPath
+}
+
+func resultsByName(results []Result) map[string]Result {
+	byName := make(map[string]Result, len(results))
+	for _, result := range results {
+		byName[result.Name] = result
+	}
+	return byName
+}
+
+func TestConformantPlugin(t *testing.T) {
+	Test(t, Config{Manifest: writeFakePlugin(t, conformant), Timeout: time.Minute})
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		behavior string
+		wantFail map[string]string
+		wantSkip []string
+	}{
+		{
+			name:     "Valid/Conformant",
+			behavior: conformant,
+		},
+		{
+			name:     "Invalid/Panics",
+			behavior: panics,
+			wantFail: map[string]string{CheckGenerateErr: "the plugin exited, it may have panicked"},
+			wantSkip: []string{CheckGetResultsErr},
+		},
+		{
+			name:     "Invalid/ForeignCheck",
+			behavior: foreignCheck,
+			wantFail: map[string]string{CheckCheckIDs: `references check "unrequested_check" that was not requested`},
+		},
+		{
+			name:     "Invalid/NoDirs",
+			behavior: noDirs,
+			wantFail: map[string]string{CheckWorkspace: "results.json"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := Run(Config{Manifest: writeFakePlugin(t, tt.behavior), Workspace: t.TempDir(), Timeout: time.Minute})
+			require.NoError(t, err)
+			require.Len(t, results, len(checkNames))
+			for i, result := range results {
+				assert.Equal(t, checkNames[i], result.Name)
+			}
+			byName := resultsByName(results)
+			for name, message := range tt.wantFail {
+				assert.Equal(t, Fail, byName[name].Status, name)
+				assert.Contains(t, byName[name].Message, message)
+			}
+			for _, name := range tt.wantSkip {
+				assert.Equal(t, Skip, byName[name].Status, name)
+			}
+			assert.Equal(t, len(tt.wantFail), Count(results, Fail), results)
+			assert.Equal(t, len(tt.wantSkip), Count(results, Skip), results)
+		})
+	}
+}
+
+func TestRunInvalidManifest(t *testing.T) {
+	manifestPath := writeFakePlugin(t, conformant)
+
+	renamed := filepath.Join(filepath.Dir(manifestPath), "fake-manifest.json")
+	require.NoError(t, os.Rename(manifestPath, renamed))
+	results, err := Run(Config{Manifest: renamed})
+	require.NoError(t, err)
+	require.Len(t, results, len(checkNames))
+	assert.Equal(t, Fail, results[0].Status)
+	assert.Contains(t, results[0].Message, "is not named c2p-<plugin id>-manifest.json")
+	assert.Equal(t, len(checkNames)-1, Count(results, Skip))
+	assert.Equal(t, "skipped after the manifest check failed", results[1].Message)
+
+	require.NoError(t, os.Rename(renamed, manifestPath))
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(manifestPath), "fake-plugin"), []byte("modified"), 0700))
+	results, err = Run(Config{Manifest: manifestPath})
+	require.NoError(t, err)
+	assert.Equal(t, Fail, results[0].Status)
+	assert.Contains(t, results[0].Message, "does not match the manifest")
+}
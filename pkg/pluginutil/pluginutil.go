@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pluginutil provides helpers shared by complyctl plugins, such as the validation of the
+// policy received from complyctl and the quoting of values in generated shell commands.
+package pluginutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+)
+
+// ValidatePolicy returns an error when a rule or check of the policy has no identifier.
+func ValidatePolicy(p policy.Policy) error {
+	for _, ruleSet := range p {
+		if ruleSet.Rule.ID == "" {
+			return errors.New("policy has a rule without identifier")
+		}
+		for _, check := range ruleSet.Checks {
+			if check.ID == "" {
+				return fmt.Errorf("rule %s has a check without identifier", ruleSet.Rule.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// ShellQuote quotes the value for a POSIX shell.
+func ShellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
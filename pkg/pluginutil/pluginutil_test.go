@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginutil
+
+import (
+	"testing"
+
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePolicy(t *testing.T) {
+	assert.NoError(t, ValidatePolicy(policy.Policy{
+		{Rule: extensions.Rule{ID: "rule"}, Checks: []extensions.Check{{ID: "check"}}},
+	}))
+	assert.EqualError(t, ValidatePolicy(policy.Policy{{Checks: []extensions.Check{{ID: "check"}}}}),
+		"policy has a rule without identifier")
+	assert.EqualError(t, ValidatePolicy(policy.Policy{{Rule: extensions.Rule{ID: "rule"}, Checks: []extensions.Check{{}}}}),
+		"rule rule has a check without identifier")
+}
+
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, `'/tmp/my dir'`, ShellQuote("/tmp/my dir"))
+	assert.Equal(t, `'it'\''s'`, ShellQuote("it's"))
+}
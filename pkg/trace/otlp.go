@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// tracesPath is the path of the OTLP/HTTP traces endpoint.
+	tracesPath = "/v1/traces"
+	// exportTimeout bounds the time to export a batch of spans to a collector.
+	exportTimeout = 5 * time.Second
+
+	spanKindInternal = 1
+	statusCodeError  = 2
+)
+
+// The following types are the OTLP JSON encoding of an ExportTraceServiceRequest.
+// See https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding.
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanData `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type spanData struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            status     `json:"status"`
+}
+
+type status struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string     `json:"stringValue,omitempty"`
+	IntValue    *string     `json:"intValue,omitempty"`
+	BoolValue   *bool       `json:"boolValue,omitempty"`
+	ArrayValue  *arrayValue `json:"arrayValue,omitempty"`
+}
+
+type arrayValue struct {
+	Values []anyValue `json:"values"`
+}
+
+// newResource returns the resource describing the service recording the spans.
+func newResource(config Config) resource {
+	return resource{Attributes: encodeAttributes([]Attribute{
+		String("service.name", config.ServiceName),
+		Int("process.pid", os.Getpid()),
+	})}
+}
+
+// newSpanData returns the OTLP span of an ended span.
+func newSpanData(s *Span, end time.Time) spanData {
+	data := spanData{
+		TraceID:           hex.EncodeToString(s.spanContext.TraceID[:]),
+		SpanID:            hex.EncodeToString(s.spanContext.SpanID[:]),
+		Name:              s.name,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(end.UnixNano(), 10),
+		Attributes:        encodeAttributes(s.attributes),
+	}
+	if s.parentID != [8]byte{} {
+		data.ParentSpanID = hex.EncodeToString(s.parentID[:])
+	}
+	if s.err != nil {
+		data.Status = status{Code: statusCodeError, Message: s.err.Error()}
+	}
+	return data
+}
+
+// encodeSpans returns the export request of a batch of ended spans.
+func encodeSpans(spanResource resource, spans []spanData) ([]byte, error) {
+	return json.Marshal(exportRequest{ResourceSpans: []resourceSpans{{
+		Resource:   spanResource,
+		ScopeSpans: []scopeSpans{{Scope: scope{Name: instrumentationScope}, Spans: spans}},
+	}}})
+}
+
+// encodeAttributes returns the OTLP key/values of the attributes.
+func encodeAttributes(attributes []Attribute) []keyValue {
+	keyValues := make([]keyValue, 0, len(attributes))
+	for _, attribute := range attributes {
+		keyValues = append(keyValues, keyValue{Key: attribute.Key, Value: encodeValue(attribute.Value)})
+	}
+	return keyValues
+}
+
+// encodeValue returns the OTLP value of an attribute value.
+func encodeValue(value any) anyValue {
+	switch v := value.(type) {
+	case int64:
+		intValue := strconv.FormatInt(v, 10)
+		return anyValue{IntValue: &intValue}
+	case bool:
+		return anyValue{BoolValue: &v}
+	case []string:
+		values := make([]anyValue, 0, len(v))
+		for _, item := range v {
+			values = append(values, encodeValue(item))
+		}
+		return anyValue{ArrayValue: &arrayValue{Values: values}}
+	default:
+		stringValue := fmt.Sprint(v)
+		return anyValue{StringValue: &stringValue}
+	}
+}
+
+// exporter sends export requests to a destination.
+type exporter interface {
+	export(request []byte) error
+}
+
+// otlpExporter posts export requests to an OTLP/HTTP collector.
+type otlpExporter struct {
+	url    string
+	client *http.Client
+	failed bool
+}
+
+// newOTLPExporter returns an exporter to the endpoint of a collector. The traces path is
+// appended to endpoints without it.
+func newOTLPExporter(endpoint string) (*otlpExporter, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil || (endpointURL.Scheme != "http" && endpointURL.Scheme != "https") || endpointURL.Host == "" {
+		return nil, fmt.Errorf("invalid trace endpoint %q, expected an http or https URL", endpoint)
+	}
+	if !strings.HasSuffix(endpointURL.Path, tracesPath) {
+		endpointURL.Path = strings.TrimSuffix(endpointURL.Path, "/") + tracesPath
+	}
+	return &otlpExporter{url: endpointURL.String(), client: &http.Client{Timeout: exportTimeout}}, nil
+}
+
+// export posts the request to the collector. After the first failure requests are dropped
+// without being posted, so an unreachable collector delays only the batch that found it
+// unreachable.
+func (o *otlpExporter) export(request []byte) error {
+	if o.failed {
+		return nil
+	}
+	err := o.post(request)
+	o.failed = err != nil
+	return err
+}
+
+func (o *otlpExporter) post(request []byte) error {
+	response, err := o.client.Post(o.url, "application/json", bytes.NewReader(request))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("collector %s returned %s", o.url, response.Status)
+	}
+	return nil
+}
+
+// fileExporter appends export requests to a file, one per line, as the file exporter of the
+// OpenTelemetry collector writes them. complyctl and its plugins append to the same file.
+type fileExporter struct {
+	path string
+	mu   sync.Mutex
+}
+
+func (f *fileExporter) export(request []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.OpenFile(filepath.Clean(f.path), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(request, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
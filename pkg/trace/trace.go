@@ -0,0 +1,389 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package trace records OpenTelemetry spans of complyctl and its plugins and exports them
+// with the OTLP/HTTP JSON encoding, to a collector or to a file. The trace context is
+// propagated to plugin subprocesses in the W3C traceparent format.
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TraceParentEnv is the environment variable carrying the trace context to subprocesses.
+	TraceParentEnv = "TRACEPARENT"
+	// EndpointEnv is the environment variable setting the OTLP/HTTP endpoint spans are exported to.
+	EndpointEnv = "COMPLYCTL_TRACE_ENDPOINT"
+	// FileEnv is the environment variable setting the file spans are appended to.
+	FileEnv = "COMPLYCTL_TRACE_FILE"
+
+	// instrumentationScope is the name of the instrumentation scope of the spans.
+	instrumentationScope = "github.com/complytime/complyctl"
+
+	// FlushTimeout bounds the time to export the queued spans when a process exits.
+	FlushTimeout = time.Second
+
+	// batchInterval is the longest time an ended span waits in the queue before it is exported.
+	batchInterval = time.Second
+	// maxBatchSize is the largest number of spans in an export request.
+	maxBatchSize = 512
+	// maxQueueSize is the largest number of ended spans waiting to be exported. Spans ending
+	// while the queue is full are dropped.
+	maxQueueSize = 2048
+)
+
+// SpanContext identifies a span and the trace it belongs to.
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+}
+
+// IsValid returns whether the trace and span IDs are set.
+func (s SpanContext) IsValid() bool {
+	return s.TraceID != [16]byte{} && s.SpanID != [8]byte{}
+}
+
+// TraceParent returns the span context in the W3C traceparent format.
+func (s SpanContext) TraceParent() string {
+	return fmt.Sprintf("00-%s-%s-01", hex.EncodeToString(s.TraceID[:]), hex.EncodeToString(s.SpanID[:]))
+}
+
+// ParseTraceParent parses a span context in the W3C traceparent format,
+// version-traceid-spanid-flags.
+func ParseTraceParent(value string) (SpanContext, error) {
+	var spanContext SpanContext
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[3]) != 2 {
+		return spanContext, fmt.Errorf("invalid traceparent %q", value)
+	}
+	if len(parts) > 4 && parts[0] == "00" {
+		return spanContext, fmt.Errorf("invalid traceparent %q", value)
+	}
+	traceID, err := hex.DecodeString(parts[1])
+	if err != nil || len(traceID) != len(spanContext.TraceID) {
+		return spanContext, fmt.Errorf("invalid trace ID in traceparent %q", value)
+	}
+	spanID, err := hex.DecodeString(parts[2])
+	if err != nil || len(spanID) != len(spanContext.SpanID) {
+		return spanContext, fmt.Errorf("invalid span ID in traceparent %q", value)
+	}
+	copy(spanContext.TraceID[:], traceID)
+	copy(spanContext.SpanID[:], spanID)
+	if !spanContext.IsValid() {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q", value)
+	}
+	return spanContext, nil
+}
+
+// Attribute is a key/value pair describing a span.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attribute { return Attribute{Key: key, Value: value} }
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute { return Attribute{Key: key, Value: int64(value)} }
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attribute { return Attribute{Key: key, Value: value} }
+
+// Strings returns a string array attribute.
+func Strings(key string, values []string) Attribute { return Attribute{Key: key, Value: values} }
+
+// Span is a timed operation of a trace. Spans started without a tracer are not recorded.
+// A span is not safe for concurrent use.
+type Span struct {
+	tracer      *Tracer
+	spanContext SpanContext
+	parentID    [8]byte
+	name        string
+	start       time.Time
+	attributes  []Attribute
+	err         error
+	ended       bool
+}
+
+// SpanContext returns the span context of the span.
+func (s *Span) SpanContext() SpanContext {
+	return s.spanContext
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attributes ...Attribute) {
+	if s.tracer == nil {
+		return
+	}
+	s.attributes = append(s.attributes, attributes...)
+}
+
+// RecordError sets the status of the span to error when the error is not nil.
+func (s *Span) RecordError(err error) {
+	if s.tracer == nil || err == nil {
+		return
+	}
+	s.err = err
+}
+
+// End ends the span and queues it for export. It does not wait for the span to be exported.
+func (s *Span) End() {
+	if s.tracer == nil || s.ended {
+		return
+	}
+	s.ended = true
+	s.tracer.enqueue(newSpanData(s, time.Now()))
+}
+
+// Config configures a tracer.
+type Config struct {
+	// ServiceName is the name of the service recording the spans.
+	ServiceName string
+	// Endpoint is the OTLP/HTTP endpoint spans are exported to, such as http://localhost:4318.
+	Endpoint string
+	// File is the file spans are appended to, one OTLP JSON export request per line.
+	File string
+	// OnError is called with the first error exporting spans, from the goroutine exporting
+	// them. Spans are no longer sent to a collector that failed, but are still appended to
+	// the file.
+	OnError func(error)
+}
+
+// ConfigFromEnvironment returns the configuration of a tracer of the service from the
+// environment variables set by complyctl for its plugins.
+func ConfigFromEnvironment(serviceName string) Config {
+	return Config{
+		ServiceName: serviceName,
+		Endpoint:    os.Getenv(EndpointEnv),
+		File:        os.Getenv(FileEnv),
+	}
+}
+
+// Tracer records spans and exports them in batches from a background goroutine, so ending
+// a span does not wait for the destinations. Flush exports the queued spans before the
+// process exits.
+type Tracer struct {
+	resource  resource
+	exporters []exporter
+	onError   func(error)
+	queue     chan spanData
+	flushes   chan chan struct{}
+
+	mu     sync.Mutex
+	failed bool
+}
+
+// NewTracer returns a tracer exporting spans to the endpoint and file of the configuration.
+// No tracer is returned when neither is set, so spans are not recorded.
+func NewTracer(config Config) (*Tracer, error) {
+	var exporters []exporter
+	if config.Endpoint != "" {
+		otlp, err := newOTLPExporter(config.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, otlp)
+	}
+	if config.File != "" {
+		exporters = append(exporters, &fileExporter{path: config.File})
+	}
+	if len(exporters) == 0 {
+		return nil, nil
+	}
+	tracer := &Tracer{
+		resource:  newResource(config),
+		exporters: exporters,
+		onError:   config.OnError,
+		queue:     make(chan spanData, maxQueueSize),
+		flushes:   make(chan chan struct{}),
+	}
+	go tracer.run()
+	return tracer, nil
+}
+
+// Flush exports the spans ended before the call and waits for the export, or for the
+// context to be done. The tracer can still record spans after a flush.
+func (t *Tracer) Flush(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	done := make(chan struct{})
+	select {
+	case t.flushes <- done:
+	case <-ctx.Done():
+		return fmt.Errorf("error flushing spans: %w", ctx.Err())
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("error flushing spans: %w", ctx.Err())
+	}
+}
+
+// enqueue queues an ended span for export, or drops it when the queue is full.
+func (t *Tracer) enqueue(span spanData) {
+	select {
+	case t.queue <- span:
+	default:
+		t.reportError(fmt.Errorf("export queue of %d spans is full", maxQueueSize))
+	}
+}
+
+// run exports the queued spans in batches, when a batch is full, at each batch interval, or
+// when the spans are flushed.
+func (t *Tracer) run() {
+	ticker := time.NewTicker(batchInterval)
+	defer ticker.Stop()
+	var batch []spanData
+	for {
+		select {
+		case span := <-t.queue:
+			batch = append(batch, span)
+			if len(batch) >= maxBatchSize {
+				t.export(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			t.export(batch)
+			batch = nil
+		case done := <-t.flushes:
+			for queued := len(t.queue); queued > 0; queued-- {
+				batch = append(batch, <-t.queue)
+			}
+			for len(batch) > 0 {
+				size := min(len(batch), maxBatchSize)
+				t.export(batch[:size])
+				batch = batch[size:]
+			}
+			batch = nil
+			close(done)
+		}
+	}
+}
+
+// export sends a batch of ended spans to all exporters.
+func (t *Tracer) export(batch []spanData) {
+	if len(batch) == 0 {
+		return
+	}
+	request, err := encodeSpans(t.resource, batch)
+	var exportErrs []error
+	if err == nil {
+		for _, exporter := range t.exporters {
+			exportErrs = append(exportErrs, exporter.export(request))
+		}
+		err = errors.Join(exportErrs...)
+	}
+	if err != nil {
+		t.reportError(err)
+	}
+}
+
+// reportError reports the first error exporting spans.
+func (t *Tracer) reportError(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.failed && t.onError != nil {
+		t.onError(fmt.Errorf("error exporting spans: %w", err))
+	}
+	t.failed = true
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultTracer *Tracer
+)
+
+// SetDefault sets the tracer recording the spans started with Start. A nil tracer
+// disables tracing.
+func SetDefault(tracer *Tracer) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultTracer = tracer
+}
+
+// Flush exports the spans ended before the call with the tracer set by SetDefault, and waits
+// for the export or for the context to be done.
+func Flush(ctx context.Context) error {
+	defaultMu.RLock()
+	tracer := defaultTracer
+	defaultMu.RUnlock()
+	return tracer.Flush(ctx)
+}
+
+// Enabled returns whether spans are recorded.
+func Enabled() bool {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultTracer != nil
+}
+
+type spanKey struct{}
+
+type remoteParentKey struct{}
+
+// Start starts a span as a child of the span of the context, or of the remote parent of the
+// context, and returns the context of the new span.
+func Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, *Span) {
+	defaultMu.RLock()
+	tracer := defaultTracer
+	defaultMu.RUnlock()
+	if tracer == nil {
+		return ctx, &Span{}
+	}
+
+	span := &Span{tracer: tracer, name: name, start: time.Now(), attributes: attributes}
+	var parent SpanContext
+	if parentSpan, ok := ctx.Value(spanKey{}).(*Span); ok && parentSpan.spanContext.IsValid() {
+		parent = parentSpan.spanContext
+	} else if remoteParent, ok := ctx.Value(remoteParentKey{}).(SpanContext); ok {
+		parent = remoteParent
+	}
+	if parent.IsValid() {
+		span.spanContext.TraceID = parent.TraceID
+		span.parentID = parent.SpanID
+	} else {
+		_, _ = rand.Read(span.spanContext.TraceID[:])
+	}
+	_, _ = rand.Read(span.spanContext.SpanID[:])
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// TraceParent returns the span context of the span of the context in the W3C traceparent
+// format, or an empty string when no span is recorded.
+func TraceParent(ctx context.Context) string {
+	if span, ok := ctx.Value(spanKey{}).(*Span); ok && span.spanContext.IsValid() {
+		return span.spanContext.TraceParent()
+	}
+	return ""
+}
+
+// ContextWithRemoteParent returns a context whose spans are children of the remote span.
+func ContextWithRemoteParent(ctx context.Context, parent SpanContext) context.Context {
+	return context.WithValue(ctx, remoteParentKey{}, parent)
+}
+
+// FromEnvironment returns a context whose spans are children of the span given in the
+// TRACEPARENT environment variable, such as the span of complyctl launching a plugin.
+// The context is returned unchanged when the variable is not set or invalid.
+//
+// The plugin interface carries no context, so plugins start the spans of their calls from
+// this context. complyctl sets the variable once per command, so the spans of every plugin
+// call are children of the span of the command rather than of the span of the call.
+func FromEnvironment(ctx context.Context) context.Context {
+	parent, err := ParseTraceParent(os.Getenv(TraceParentEnv))
+	if err != nil {
+		return ctx
+	}
+	return ContextWithRemoteParent(ctx, parent)
+}
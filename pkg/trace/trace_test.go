@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	spanContext, err := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+	assert.True(t, spanContext.IsValid())
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", spanContext.TraceParent())
+
+	for _, value := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-zzf067aa0ba902b7-01",
+	} {
+		_, err := ParseTraceParent(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestStartDisabled(t *testing.T) {
+	SetDefault(nil)
+	ctx, span := Start(context.Background(), "disabled")
+	span.SetAttributes(String("key", "value"))
+	span.RecordError(errors.New("error"))
+	span.End()
+	assert.False(t, Enabled())
+	assert.False(t, span.SpanContext().IsValid())
+	assert.Empty(t, TraceParent(ctx))
+
+	tracer, err := NewTracer(Config{ServiceName: "test"})
+	require.NoError(t, err)
+	assert.Nil(t, tracer)
+}
+
+func TestFileExport(t *testing.T) {
+	tracePath := filepath.Join(t.TempDir(), "traces.jsonl")
+	tracer, err := NewTracer(Config{ServiceName: "test", File: tracePath})
+	require.NoError(t, err)
+	SetDefault(tracer)
+	t.Cleanup(func() { SetDefault(nil) })
+
+	parent, err := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+	ctx, span := Start(ContextWithRemoteParent(context.Background(), parent), "parent", String("plugin", "openscap"))
+	_, child := Start(ctx, "child", Int("rules", 2), Bool("remote", true), Strings("command", []string{"oscap", "xccdf"}))
+	child.RecordError(errors.New("oscap failed"))
+	child.End()
+	span.End()
+	span.End()
+	require.NoError(t, Flush(context.Background()))
+
+	assert.Equal(t, parent.TraceID, span.SpanContext().TraceID)
+	assert.Equal(t, parent.TraceID, child.SpanContext().TraceID)
+	assert.Equal(t, span.SpanContext().TraceParent(), TraceParent(ctx))
+
+	file, err := os.Open(tracePath)
+	require.NoError(t, err)
+	defer file.Close()
+	var requests []exportRequest
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var request exportRequest
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &request))
+		requests = append(requests, request)
+	}
+	require.NoError(t, scanner.Err())
+	// Spans ended before the flush are exported in a single request
+	require.Len(t, requests, 1)
+	spans := requests[0].ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 2)
+
+	childData, parentData := spans[0], spans[1]
+	assert.Equal(t, "child", childData.Name)
+	assert.Equal(t, parentData.SpanID, childData.ParentSpanID)
+	assert.Equal(t, "00f067aa0ba902b7", parentData.ParentSpanID)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", childData.TraceID)
+	assert.Equal(t, statusCodeError, childData.Status.Code)
+	assert.Equal(t, "oscap failed", childData.Status.Message)
+	require.Len(t, childData.Attributes, 3)
+	assert.Equal(t, "2", *childData.Attributes[0].Value.IntValue)
+	assert.True(t, *childData.Attributes[1].Value.BoolValue)
+	assert.Len(t, childData.Attributes[2].Value.ArrayValue.Values, 2)
+	assert.Equal(t, "test", *requests[0].ResourceSpans[0].Resource.Attributes[0].Value.StringValue)
+}
+
+func TestOTLPExport(t *testing.T) {
+	var paths []string
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		paths = append(paths, r.URL.Path)
+		bodies = append(bodies, body)
+		if len(paths) > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	var exportErrs []error
+	tracer, err := NewTracer(Config{
+		ServiceName: "test",
+		Endpoint:    server.URL,
+		OnError:     func(err error) { exportErrs = append(exportErrs, err) },
+	})
+	require.NoError(t, err)
+	SetDefault(tracer)
+	t.Cleanup(func() { SetDefault(nil) })
+
+	exportSpans := func(count int) {
+		for i := 0; i < count; i++ {
+			_, span := Start(context.Background(), "export")
+			span.End()
+		}
+		require.NoError(t, tracer.Flush(context.Background()))
+	}
+	exportSpans(3)
+	var request exportRequest
+	require.NoError(t, json.Unmarshal(bodies[0], &request))
+	require.Len(t, request.ResourceSpans[0].ScopeSpans[0].Spans, 3)
+	assert.Equal(t, "export", request.ResourceSpans[0].ScopeSpans[0].Spans[0].Name)
+
+	// Spans are no longer posted after the collector failed
+	exportSpans(1)
+	exportSpans(1)
+	assert.Equal(t, []string{tracesPath, tracesPath}, paths)
+	// Only the first export error is reported
+	require.Len(t, exportErrs, 1)
+	assert.ErrorContains(t, exportErrs[0], "503 Service Unavailable")
+
+	_, err = NewTracer(Config{Endpoint: "localhost:4318"})
+	assert.EqualError(t, err, `invalid trace endpoint "localhost:4318", expected an http or https URL`)
+}
+
+func TestOTLPExportNotResponding(t *testing.T) {
+	var requests atomic.Int32
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	var exportErrs []error
+	tracer, err := NewTracer(Config{
+		ServiceName: "test",
+		Endpoint:    server.URL,
+		OnError:     func(err error) { exportErrs = append(exportErrs, err) },
+	})
+	require.NoError(t, err)
+	tracer.exporters[0].(*otlpExporter).client.Timeout = 100 * time.Millisecond
+	SetDefault(tracer)
+	t.Cleanup(func() { SetDefault(nil) })
+
+	// Ending spans does not wait for the collector
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		_, span := Start(context.Background(), "export")
+		span.End()
+	}
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	// A flush waits for the collector until its deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, tracer.Flush(ctx), context.DeadlineExceeded)
+	require.NoError(t, tracer.Flush(context.Background()))
+	assert.Equal(t, int32(1), requests.Load())
+	require.Len(t, exportErrs, 1)
+	assert.ErrorContains(t, exportErrs[0], "Client.Timeout exceeded")
+}
+
+func TestFromEnvironment(t *testing.T) {
+	t.Setenv(TraceParentEnv, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	parent, ok := FromEnvironment(context.Background()).Value(remoteParentKey{}).(SpanContext)
+	require.True(t, ok)
+	assert.Equal(t, "00f067aa0ba902b7", parent.TraceParent()[36:52])
+
+	t.Setenv(TraceParentEnv, "invalid")
+	ctx := context.Background()
+	assert.Equal(t, ctx, FromEnvironment(ctx))
+}